@@ -20,3 +20,14 @@ func (c *Client) LoginUser(req *contracts.LoginUserRequest) (*contracts.LoginUse
 
 	return &resp, err
 }
+
+func (c *Client) RestoreUser(req *contracts.LoginUserRequest) (*contracts.LoginUserResponse, error) {
+	var resp contracts.LoginUserResponse
+
+	_, err := c.client.R().
+		SetBody(req).
+		SetResult(&resp).
+		Post(c.path("/api/auth/restore"))
+
+	return &resp, err
+}
@@ -0,0 +1,49 @@
+package client
+
+import "github.com/RadkevichAnn/movie-reviews/contracts"
+
+func (c *Client) GetAwards(req *contracts.GetAwardsRequest) (*contracts.PaginatedResponse[contracts.Award], error) {
+	var awards contracts.PaginatedResponse[contracts.Award]
+	_, err := c.client.R().SetResult(&awards).
+		SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/awards"))
+	return &awards, err
+}
+
+func (c *Client) GetAwardByID(id int) (*contracts.AwardDetails, error) {
+	var award contracts.AwardDetails
+	_, err := c.client.R().SetResult(&award).Get(c.path("/api/awards/%d", id))
+	return &award, err
+}
+
+func (c *Client) GetMovieAwards(movieID int) ([]*contracts.MovieAward, error) {
+	var awards []*contracts.MovieAward
+	_, err := c.client.R().SetResult(&awards).Get(c.path("/api/movies/%d/awards", movieID))
+	return awards, err
+}
+
+func (c *Client) GetStarAwards(starID int) ([]*contracts.StarAward, error) {
+	var awards []*contracts.StarAward
+	_, err := c.client.R().SetResult(&awards).Get(c.path("/api/stars/%d/awards", starID))
+	return awards, err
+}
+
+func (c *Client) CreateAward(req *contracts.AuthenticadedRequest[*contracts.CreateAwardRequest]) (*contracts.AwardDetails, error) {
+	var award contracts.AwardDetails
+	_, err := c.client.R().SetResult(&award).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/awards"))
+	return &award, err
+}
+
+func (c *Client) UpdateAward(req *contracts.AuthenticadedRequest[*contracts.UpdateAwardRequest]) (*contracts.AwardDetails, error) {
+	var award contracts.AwardDetails
+	_, err := c.client.R().SetResult(&award).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/awards/%d", req.Request.ID))
+	return &award, err
+}
+
+func (c *Client) DeleteAward(req *contracts.AuthenticadedRequest[*contracts.GetOrDeleteAwardRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/awards/%d", req.Request.ID))
+	return err
+}
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+)
+
+func (c *Client) BulkImportGenres(req *contracts.AuthenticadedRequest[[]*contracts.CreateGenreRequest]) (*contracts.BulkReport, error) {
+	return c.bulkImport("/api/bulk/genres", req.AccessToken, req.Request)
+}
+
+func (c *Client) BulkImportStars(req *contracts.AuthenticadedRequest[[]*contracts.BulkStar]) (*contracts.BulkReport, error) {
+	return c.bulkImport("/api/bulk/stars", req.AccessToken, req.Request)
+}
+
+func (c *Client) BulkImportMovies(req *contracts.AuthenticadedRequest[[]*contracts.CreateMovieRequest]) (*contracts.BulkReport, error) {
+	return c.bulkImport("/api/bulk/movies", req.AccessToken, req.Request)
+}
+
+func (c *Client) bulkImport(path, accessToken string, items any) (*contracts.BulkReport, error) {
+	var report contracts.BulkReport
+	_, err := c.client.R().SetResult(&report).SetAuthToken(accessToken).
+		SetBody(items).Post(c.path(path))
+	return &report, err
+}
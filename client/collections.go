@@ -0,0 +1,38 @@
+package client
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+)
+
+func (c *Client) CreateCollection(req *contracts.AuthenticadedRequest[*contracts.CreateCollectionRequest]) (*contracts.CollectionDetails, error) {
+	var collection contracts.CollectionDetails
+	_, err := c.client.R().SetResult(&collection).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/collections"))
+	return &collection, err
+}
+
+func (c *Client) GetCollectionByID(id int) (*contracts.CollectionDetails, error) {
+	var collection contracts.CollectionDetails
+	_, err := c.client.R().SetResult(&collection).Get(c.path("/api/collections/%d", id))
+	return &collection, err
+}
+
+func (c *Client) GetCollections(req *contracts.GetCollectionsRequest) (*contracts.PaginatedResponse[contracts.Collection], error) {
+	var collections contracts.PaginatedResponse[contracts.Collection]
+	_, err := c.client.R().SetResult(&collections).SetQueryParams(req.ToQueryParams()).Get(c.path("/api/collections"))
+	return &collections, err
+}
+
+func (c *Client) UpdateCollection(req *contracts.AuthenticadedRequest[*contracts.UpdateCollectionRequest]) (*contracts.CollectionDetails, error) {
+	var collection contracts.CollectionDetails
+	_, err := c.client.R().SetResult(&collection).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		Put(c.path("/api/collections/%d", req.Request.ID))
+	return &collection, err
+}
+
+func (c *Client) DeleteCollection(req *contracts.AuthenticadedRequest[*contracts.GetOrDeleteCollectionRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/collections/%d", req.Request.ID))
+	return err
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+)
+
+func (c *Client) ExportCatalog(req *contracts.AuthenticadedRequest[*contracts.ExportCatalogRequest]) (*contracts.Job, error) {
+	var job contracts.Job
+	_, err := c.client.R().SetResult(&job).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/exports"))
+	return &job, err
+}
+
+func (c *Client) ExportUserData(req *contracts.AuthenticadedRequest[*contracts.ExportUserDataRequest]) (*contracts.Job, error) {
+	var job contracts.Job
+	_, err := c.client.R().SetResult(&job).SetAuthToken(req.AccessToken).
+		Post(c.path("/api/users/%d/export", req.Request.UserID))
+	return &job, err
+}
+
+// DownloadExport downloads the export by the download URL of the export job result.
+func (c *Client) DownloadExport(downloadURL string) ([]byte, error) {
+	res, err := c.client.R().Get(c.baseURL + downloadURL)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body(), nil
+}
@@ -0,0 +1,40 @@
+package client
+
+import "github.com/RadkevichAnn/movie-reviews/contracts"
+
+func (c *Client) Follow(req *contracts.AuthenticadedRequest[*contracts.FollowRequest]) (*contracts.Follow, error) {
+	var follow contracts.Follow
+	_, err := c.client.R().SetResult(&follow).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/users/%d/following", req.Request.UserID))
+	return &follow, err
+}
+
+func (c *Client) Unfollow(req *contracts.AuthenticadedRequest[*contracts.UnfollowRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/users/%d/following/%d", req.Request.UserID, req.Request.FolloweeID))
+	return err
+}
+
+func (c *Client) GetFollowers(req *contracts.GetFollowsRequest) (*contracts.PaginatedResponse[contracts.Follow], error) {
+	var follows contracts.PaginatedResponse[contracts.Follow]
+	_, err := c.client.R().SetResult(&follows).
+		SetQueryParams(req.PaginatedRequest.ToQueryParams()).
+		Get(c.path("/api/users/%d/followers", req.UserID))
+	return &follows, err
+}
+
+func (c *Client) GetFollowing(req *contracts.GetFollowsRequest) (*contracts.PaginatedResponse[contracts.Follow], error) {
+	var follows contracts.PaginatedResponse[contracts.Follow]
+	_, err := c.client.R().SetResult(&follows).
+		SetQueryParams(req.PaginatedRequest.ToQueryParams()).
+		Get(c.path("/api/users/%d/following", req.UserID))
+	return &follows, err
+}
+
+func (c *Client) GetFeed(req *contracts.AuthenticadedRequest[*contracts.GetFeedRequest]) (*contracts.PaginatedResponse[contracts.Activity], error) {
+	var activities contracts.PaginatedResponse[contracts.Activity]
+	_, err := c.client.R().SetResult(&activities).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.PaginatedRequest.ToQueryParams()).
+		Get(c.path("/api/feed"))
+	return &activities, err
+}
@@ -42,3 +42,10 @@ func (c *Client) DeleteGenre(req *contracts.AuthenticadedRequest[*contracts.Dele
 		Delete(c.path("/api/genres/%d", req.Request.GenreId))
 	return err
 }
+
+func (c *Client) GetGenreStats(id int) (*contracts.GenreStats, error) {
+	var stats contracts.GenreStats
+
+	_, err := c.client.R().SetResult(&stats).Get(c.path("/api/genres/%d/stats", id))
+	return &stats, err
+}
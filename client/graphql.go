@@ -0,0 +1,23 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+)
+
+// GraphQL executes the query, anonymously if the access token is empty. An invalid query returns
+// an error with the message of the first error of the response.
+func (c *Client) GraphQL(req *contracts.AuthenticadedRequest[*contracts.GraphQLRequest]) (*contracts.GraphQLResponse, error) {
+	var res contracts.GraphQLResponse
+	r := c.client.R().SetResult(&res).SetError(&res).SetBody(req.Request)
+	if req.AccessToken != "" {
+		r.SetAuthToken(req.AccessToken)
+	}
+	_, err := r.Post(c.path("/graphql"))
+	var cerr *Error
+	if errors.As(err, &cerr) && len(res.Errors) > 0 {
+		cerr.Message = res.Errors[0].Message
+	}
+	return &res, err
+}
@@ -0,0 +1,19 @@
+package client
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+)
+
+func (c *Client) EnqueueJob(req *contracts.AuthenticadedRequest[*contracts.EnqueueJobRequest]) (*contracts.Job, error) {
+	var job contracts.Job
+	_, err := c.client.R().SetResult(&job).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/jobs"))
+	return &job, err
+}
+
+func (c *Client) GetJobByID(req *contracts.AuthenticadedRequest[*contracts.GetJobRequest]) (*contracts.Job, error) {
+	var job contracts.Job
+	_, err := c.client.R().SetResult(&job).SetAuthToken(req.AccessToken).
+		Get(c.path("/api/jobs/%d", req.Request.ID))
+	return &job, err
+}
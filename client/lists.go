@@ -0,0 +1,72 @@
+package client
+
+import "github.com/RadkevichAnn/movie-reviews/contracts"
+
+func (c *Client) GetLists(req *contracts.GetListsRequest) (*contracts.PaginatedResponse[contracts.List], error) {
+	var lists contracts.PaginatedResponse[contracts.List]
+	_, err := c.client.R().SetResult(&lists).
+		SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/lists"))
+	return &lists, err
+}
+
+func (c *Client) GetListByID(listID int) (*contracts.ListDetails, error) {
+	var list contracts.ListDetails
+	_, err := c.client.R().SetResult(&list).Get(c.path("/api/lists/%d", listID))
+	return &list, err
+}
+
+func (c *Client) GetListByIDAuthenticated(req *contracts.AuthenticadedRequest[*contracts.GetListRequest]) (*contracts.ListDetails, error) {
+	var list contracts.ListDetails
+	_, err := c.client.R().SetResult(&list).SetAuthToken(req.AccessToken).
+		Get(c.path("/api/lists/%d", req.Request.ListID))
+	return &list, err
+}
+
+func (c *Client) GetUserLists(req *contracts.GetUserListsRequest) (*contracts.PaginatedResponse[contracts.List], error) {
+	var lists contracts.PaginatedResponse[contracts.List]
+	_, err := c.client.R().SetResult(&lists).
+		SetQueryParams(req.PaginatedRequest.ToQueryParams()).
+		Get(c.path("/api/users/%d/lists", req.UserID))
+	return &lists, err
+}
+
+func (c *Client) GetUserListsAuthenticated(req *contracts.AuthenticadedRequest[*contracts.GetUserListsRequest]) (*contracts.PaginatedResponse[contracts.List], error) {
+	var lists contracts.PaginatedResponse[contracts.List]
+	_, err := c.client.R().SetResult(&lists).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.PaginatedRequest.ToQueryParams()).
+		Get(c.path("/api/users/%d/lists", req.Request.UserID))
+	return &lists, err
+}
+
+func (c *Client) CreateList(req *contracts.AuthenticadedRequest[*contracts.CreateListRequest]) (*contracts.ListDetails, error) {
+	var list contracts.ListDetails
+	_, err := c.client.R().SetResult(&list).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/users/%d/lists", req.Request.UserID))
+	return &list, err
+}
+
+func (c *Client) UpdateList(req *contracts.AuthenticadedRequest[*contracts.UpdateListRequest]) (*contracts.ListDetails, error) {
+	var list contracts.ListDetails
+	_, err := c.client.R().SetResult(&list).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/users/%d/lists/%d", req.Request.UserID, req.Request.ListID))
+	return &list, err
+}
+
+func (c *Client) DeleteList(req *contracts.AuthenticadedRequest[*contracts.DeleteListRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/users/%d/lists/%d", req.Request.UserID, req.Request.ListID))
+	return err
+}
+
+func (c *Client) LikeList(req *contracts.AuthenticadedRequest[*contracts.LikeListRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Post(c.path("/api/lists/%d/likes", req.Request.ListID))
+	return err
+}
+
+func (c *Client) UnlikeList(req *contracts.AuthenticadedRequest[*contracts.LikeListRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/lists/%d/likes", req.Request.ListID))
+	return err
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+)
+
+// EventStream reads the events of a live stream. It must be closed.
+type EventStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+}
+
+// StreamMovieEvents opens the live stream of the movie.
+func (c *Client) StreamMovieEvents(req *contracts.StreamMovieEventsRequest) (*EventStream, error) {
+	return c.openEventStream(c.path("/api/movies/%d/events", req.MovieID), req.LastEventID, "")
+}
+
+// StreamEvents opens the live stream of all movies.
+func (c *Client) StreamEvents(req *contracts.AuthenticadedRequest[*contracts.StreamEventsRequest]) (*EventStream, error) {
+	return c.openEventStream(c.path("/api/events"), req.Request.LastEventID, req.AccessToken)
+}
+
+func (c *Client) openEventStream(url, lastEventID, accessToken string) (*EventStream, error) {
+	httpReq, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		httpReq.Header.Set("Last-Event-ID", lastEventID)
+	}
+	if accessToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	res, err := c.client.GetClient().Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		herr := contracts.HttpError{}
+		_ = json.NewDecoder(res.Body).Decode(&herr)
+		return nil, &Error{Code: res.StatusCode, Message: herr.Message}
+	}
+	return &EventStream{body: res.Body, reader: bufio.NewReader(res.Body)}, nil
+}
+
+// Next blocks until the next event is received. Comments, such as heartbeats, are skipped.
+func (s *EventStream) Next() (*contracts.LiveEvent, error) {
+	var (
+		event   contracts.LiveEvent
+		hasData bool
+	)
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if hasData {
+				return &event, nil
+			}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID = value
+		case "event":
+			event.Type = value
+		case "data":
+			event.Data = json.RawMessage(value)
+			hasData = true
+		}
+	}
+}
+
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
@@ -1,6 +1,9 @@
 package client
 
 import (
+	"bytes"
+	"net/url"
+
 	"github.com/RadkevichAnn/movie-reviews/contracts"
 )
 
@@ -17,6 +20,20 @@ func (c *Client) GetMovieByID(id int) (*contracts.MovieDetails, error) {
 	return &movie, err
 }
 
+func (c *Client) GetMovieByExternalID(source, externalID string) (*contracts.MovieDetails, error) {
+	var movie contracts.MovieDetails
+	_, err := c.client.R().SetResult(&movie).
+		Get(c.path("/api/movies/external/%s/%s", url.PathEscape(source), url.PathEscape(externalID)))
+	return &movie, err
+}
+
+func (c *Client) GetSimilarMovies(req *contracts.GetSimilarMoviesRequest) ([]*contracts.SimilarMovie, error) {
+	var movies []*contracts.SimilarMovie
+	_, err := c.client.R().SetResult(&movies).SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/movies/%d/similar", req.ID))
+	return movies, err
+}
+
 func (c *Client) GetMovies(req *contracts.GetMoviesRequest) (*contracts.PaginatedResponse[contracts.Movie], error) {
 	var movies contracts.PaginatedResponse[contracts.Movie]
 
@@ -37,3 +54,17 @@ func (c *Client) DeleteMovie(req *contracts.AuthenticadedRequest[*contracts.GetO
 		Delete(c.path("/api/movies/%d", req.Request.ID))
 	return err
 }
+
+func (c *Client) GetMovieByIDAuthenticated(req *contracts.AuthenticadedRequest[*contracts.GetOrDeleteMovieByIDRequest]) (*contracts.MovieDetails, error) {
+	var movie contracts.MovieDetails
+	_, err := c.client.R().SetResult(&movie).SetAuthToken(req.AccessToken).Get(c.path("/api/movies/%d", req.Request.ID))
+	return &movie, err
+}
+
+func (c *Client) UploadMoviePoster(req *contracts.AuthenticadedRequest[*contracts.UploadImageRequest]) (*contracts.Image, error) {
+	var img contracts.Image
+	_, err := c.client.R().SetResult(&img).SetAuthToken(req.AccessToken).
+		SetFileReader(contracts.ImageFormField, req.Request.FileName, bytes.NewReader(req.Request.Content)).
+		Put(c.path("/api/movies/%d/poster", req.Request.ID))
+	return &img, err
+}
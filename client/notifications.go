@@ -0,0 +1,63 @@
+package client
+
+import (
+	"strconv"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+)
+
+func (c *Client) GetNotifications(req *contracts.AuthenticadedRequest[*contracts.GetNotificationsRequest]) (*contracts.PaginatedResponse[contracts.Notification], error) {
+	var notifications contracts.PaginatedResponse[contracts.Notification]
+	_, err := c.client.R().SetResult(&notifications).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.PaginatedRequest.ToQueryParams()).
+		SetQueryParam("unreadOnly", strconv.FormatBool(req.Request.UnreadOnly)).
+		Get(c.path("/api/notifications"))
+	return &notifications, err
+}
+
+func (c *Client) GetUnreadNotificationsCount(token string) (*contracts.UnreadCount, error) {
+	var count contracts.UnreadCount
+	_, err := c.client.R().SetResult(&count).SetAuthToken(token).
+		Get(c.path("/api/notifications/unread-count"))
+	return &count, err
+}
+
+func (c *Client) MarkNotificationRead(req *contracts.AuthenticadedRequest[*contracts.MarkNotificationReadRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Put(c.path("/api/notifications/%d/read", req.Request.NotificationID))
+	return err
+}
+
+func (c *Client) MarkAllNotificationsRead(token string) (*contracts.UnreadCount, error) {
+	var marked contracts.UnreadCount
+	_, err := c.client.R().SetResult(&marked).SetAuthToken(token).
+		Put(c.path("/api/notifications/read"))
+	return &marked, err
+}
+
+func (c *Client) GetNotificationPreferences(token string) ([]*contracts.NotificationPreference, error) {
+	var preferences []*contracts.NotificationPreference
+	_, err := c.client.R().SetResult(&preferences).SetAuthToken(token).
+		Get(c.path("/api/notifications/preferences"))
+	return preferences, err
+}
+
+func (c *Client) UpdateNotificationPreferences(req *contracts.AuthenticadedRequest[*contracts.UpdateNotificationPreferencesRequest]) ([]*contracts.NotificationPreference, error) {
+	var preferences []*contracts.NotificationPreference
+	_, err := c.client.R().SetResult(&preferences).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/notifications/preferences"))
+	return preferences, err
+}
+
+func (c *Client) SetNotificationWebhook(req *contracts.AuthenticadedRequest[*contracts.SetNotificationWebhookRequest]) (*contracts.NotificationWebhook, error) {
+	var webhook contracts.NotificationWebhook
+	_, err := c.client.R().SetResult(&webhook).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/notifications/webhook"))
+	return &webhook, err
+}
+
+func (c *Client) DeleteNotificationWebhook(token string) error {
+	_, err := c.client.R().SetAuthToken(token).
+		Delete(c.path("/api/notifications/webhook"))
+	return err
+}
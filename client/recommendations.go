@@ -0,0 +1,11 @@
+package client
+
+import "github.com/RadkevichAnn/movie-reviews/contracts"
+
+func (c *Client) GetRecommendations(req *contracts.AuthenticadedRequest[*contracts.GetRecommendationsRequest]) ([]*contracts.Recommendation, error) {
+	var recommendations []*contracts.Recommendation
+	_, err := c.client.R().SetResult(&recommendations).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.ToQueryParams()).
+		Get(c.path("/api/users/%d/recommendations", req.Request.UserID))
+	return recommendations, err
+}
@@ -15,6 +15,13 @@ func (c *Client) GetReviewByID(id int) (*contracts.Review, error) {
 	return &review, err
 }
 
+func (c *Client) GetReviewByIDAuthenticated(req *contracts.AuthenticadedRequest[*contracts.GetReviewRequest]) (*contracts.Review, error) {
+	var review contracts.Review
+	_, err := c.client.R().SetResult(&review).SetAuthToken(req.AccessToken).
+		Get(c.path("/api/reviews/%d", req.Request.ReviewID))
+	return &review, err
+}
+
 func (c *Client) GetAllReviewsPaginated(req *contracts.GetReviewsRequest) (*contracts.PaginatedResponse[contracts.Review], error) {
 	var reviews contracts.PaginatedResponse[contracts.Review]
 
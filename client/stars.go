@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"github.com/RadkevichAnn/movie-reviews/contracts"
 )
 
@@ -37,3 +38,38 @@ func (c *Client) DeleteStar(req *contracts.AuthenticadedRequest[*contracts.GetOr
 		Delete(c.path("/api/stars/%d", req.Request.ID))
 	return err
 }
+
+func (c *Client) GetFilmography(req *contracts.GetFilmographyRequest) ([]*contracts.RoleFilmography, error) {
+	var filmography []*contracts.RoleFilmography
+	_, err := c.client.R().SetResult(&filmography).SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/stars/%d/filmography", req.ID))
+	return filmography, err
+}
+
+func (c *Client) GetCareerStats(id int) (*contracts.CareerStats, error) {
+	var stats contracts.CareerStats
+	_, err := c.client.R().SetResult(&stats).Get(c.path("/api/stars/%d/stats", id))
+	return &stats, err
+}
+
+func (c *Client) GetCollaborators(req *contracts.GetCollaboratorsRequest) (*contracts.PaginatedResponse[contracts.Collaborator], error) {
+	var collaborators contracts.PaginatedResponse[contracts.Collaborator]
+	_, err := c.client.R().SetResult(&collaborators).SetQueryParams(req.PaginatedRequest.ToQueryParams()).
+		Get(c.path("/api/stars/%d/collaborators", req.ID))
+	return &collaborators, err
+}
+
+func (c *Client) GetStarPath(req *contracts.GetStarPathRequest) (*contracts.StarPath, error) {
+	var path contracts.StarPath
+	_, err := c.client.R().SetResult(&path).SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/stars/path"))
+	return &path, err
+}
+
+func (c *Client) UploadStarHeadshot(req *contracts.AuthenticadedRequest[*contracts.UploadImageRequest]) (*contracts.Image, error) {
+	var img contracts.Image
+	_, err := c.client.R().SetResult(&img).SetAuthToken(req.AccessToken).
+		SetFileReader(contracts.ImageFormField, req.Request.FileName, bytes.NewReader(req.Request.Content)).
+		Put(c.path("/api/stars/%d/headshot", req.Request.ID))
+	return &img, err
+}
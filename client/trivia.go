@@ -0,0 +1,68 @@
+package client
+
+import "github.com/RadkevichAnn/movie-reviews/contracts"
+
+func (c *Client) GetMovieTrivia(req *contracts.GetTriviaRequest) (*contracts.PaginatedResponse[contracts.TriviaItem], error) {
+	var items contracts.PaginatedResponse[contracts.TriviaItem]
+	_, err := c.client.R().SetResult(&items).
+		SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/movies/%d/trivia", req.ID))
+	return &items, err
+}
+
+func (c *Client) GetMovieTriviaAuthenticated(req *contracts.AuthenticadedRequest[*contracts.GetTriviaRequest]) (*contracts.PaginatedResponse[contracts.TriviaItem], error) {
+	var items contracts.PaginatedResponse[contracts.TriviaItem]
+	_, err := c.client.R().SetResult(&items).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.ToQueryParams()).
+		Get(c.path("/api/movies/%d/trivia", req.Request.ID))
+	return &items, err
+}
+
+func (c *Client) GetStarTrivia(req *contracts.GetTriviaRequest) (*contracts.PaginatedResponse[contracts.TriviaItem], error) {
+	var items contracts.PaginatedResponse[contracts.TriviaItem]
+	_, err := c.client.R().SetResult(&items).
+		SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/stars/%d/trivia", req.ID))
+	return &items, err
+}
+
+func (c *Client) SubmitMovieTrivia(req *contracts.AuthenticadedRequest[*contracts.SubmitTriviaRequest]) (*contracts.TriviaItem, error) {
+	var item contracts.TriviaItem
+	_, err := c.client.R().SetResult(&item).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/movies/%d/trivia", req.Request.ID))
+	return &item, err
+}
+
+func (c *Client) SubmitStarTrivia(req *contracts.AuthenticadedRequest[*contracts.SubmitTriviaRequest]) (*contracts.TriviaItem, error) {
+	var item contracts.TriviaItem
+	_, err := c.client.R().SetResult(&item).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/stars/%d/trivia", req.Request.ID))
+	return &item, err
+}
+
+func (c *Client) ReviewTrivia(req *contracts.AuthenticadedRequest[*contracts.ReviewTriviaRequest]) (*contracts.TriviaItem, error) {
+	var item contracts.TriviaItem
+	_, err := c.client.R().SetResult(&item).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/trivia/%d/status", req.Request.ItemID))
+	return &item, err
+}
+
+func (c *Client) DeleteTrivia(req *contracts.AuthenticadedRequest[*contracts.TriviaItemRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/trivia/%d", req.Request.ItemID))
+	return err
+}
+
+func (c *Client) VoteTrivia(req *contracts.AuthenticadedRequest[*contracts.VoteTriviaRequest]) (*contracts.TriviaItem, error) {
+	var item contracts.TriviaItem
+	_, err := c.client.R().SetResult(&item).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/trivia/%d/vote", req.Request.ItemID))
+	return &item, err
+}
+
+func (c *Client) UnvoteTrivia(req *contracts.AuthenticadedRequest[*contracts.TriviaItemRequest]) (*contracts.TriviaItem, error) {
+	var item contracts.TriviaItem
+	_, err := c.client.R().SetResult(&item).SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/trivia/%d/vote", req.Request.ItemID))
+	return &item, err
+}
@@ -1,6 +1,10 @@
 package client
 
-import "github.com/RadkevichAnn/movie-reviews/contracts"
+import (
+	"bytes"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+)
 
 func (c *Client) GetUser(userId int) (*contracts.User, error) {
 	var u contracts.User
@@ -10,6 +14,14 @@ func (c *Client) GetUser(userId int) (*contracts.User, error) {
 	return &u, err
 }
 
+func (c *Client) GetUserAuthenticated(req *contracts.AuthenticadedRequest[*contracts.DeleteOrGetRequest]) (*contracts.User, error) {
+	var u contracts.User
+
+	_, err := c.client.R().SetResult(&u).SetAuthToken(req.AccessToken).Get(c.path("/api/users/%d", req.Request.UserId))
+
+	return &u, err
+}
+
 func (c *Client) GetUserByUserName(Username string) (*contracts.User, error) {
 	var u contracts.User
 
@@ -26,6 +38,14 @@ func (c *Client) UpdateUser(req *contracts.AuthenticadedRequest[*contracts.Updat
 	return err
 }
 
+func (c *Client) UploadUserAvatar(req *contracts.AuthenticadedRequest[*contracts.UploadAvatarRequest]) (*contracts.Image, error) {
+	var img contracts.Image
+	_, err := c.client.R().SetResult(&img).SetAuthToken(req.AccessToken).
+		SetFileReader(contracts.ImageFormField, req.Request.FileName, bytes.NewReader(req.Request.Content)).
+		Put(c.path("/api/users/%d/avatar", req.Request.UserID))
+	return &img, err
+}
+
 func (c *Client) Delete(req *contracts.AuthenticadedRequest[*contracts.DeleteOrGetRequest]) error {
 	_, err := c.client.R().SetAuthToken(req.AccessToken).
 		SetHeader("Content-Type", "application/json").
@@ -34,6 +54,15 @@ func (c *Client) Delete(req *contracts.AuthenticadedRequest[*contracts.DeleteOrG
 	return err
 }
 
+func (c *Client) DeleteUser(req *contracts.AuthenticadedRequest[*contracts.DeleteUserRequest]) (*contracts.AccountDeletion, error) {
+	var deletion contracts.AccountDeletion
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		SetResult(&deletion).
+		SetBody(req.Request).
+		Delete(c.path("/api/users/%d", req.Request.UserId))
+	return &deletion, err
+}
+
 func (c *Client) UpdateUserRole(req *contracts.AuthenticadedRequest[*contracts.UpdateRoleRequest]) error {
 	_, err := c.client.R().SetAuthToken(req.AccessToken).Put(c.path("/api/users/%d/role/%s", req.Request.UserID, req.Request.Role))
 	return err
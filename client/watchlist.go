@@ -0,0 +1,57 @@
+package client
+
+import "github.com/RadkevichAnn/movie-reviews/contracts"
+
+func (c *Client) GetWatchlist(req *contracts.AuthenticadedRequest[*contracts.GetWatchlistRequest]) (*contracts.PaginatedResponse[contracts.WatchlistItem], error) {
+	var items contracts.PaginatedResponse[contracts.WatchlistItem]
+	_, err := c.client.R().SetResult(&items).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.ToQueryParams()).
+		Get(c.path("/api/users/%d/watchlist", req.Request.UserID))
+	return &items, err
+}
+
+func (c *Client) AddToWatchlist(req *contracts.AuthenticadedRequest[*contracts.AddToWatchlistRequest]) (*contracts.WatchlistItem, error) {
+	var item contracts.WatchlistItem
+	_, err := c.client.R().SetResult(&item).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/users/%d/watchlist", req.Request.UserID))
+	return &item, err
+}
+
+func (c *Client) UpdateWatchlistItem(req *contracts.AuthenticadedRequest[*contracts.UpdateWatchlistItemRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/users/%d/watchlist/%d", req.Request.UserID, req.Request.MovieID))
+	return err
+}
+
+func (c *Client) DeleteWatchlistItem(req *contracts.AuthenticadedRequest[*contracts.DeleteWatchlistItemRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/users/%d/watchlist/%d", req.Request.UserID, req.Request.MovieID))
+	return err
+}
+
+func (c *Client) ReorderWatchlist(req *contracts.AuthenticadedRequest[*contracts.ReorderWatchlistRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/users/%d/watchlist/order", req.Request.UserID))
+	return err
+}
+
+func (c *Client) GetWatched(req *contracts.AuthenticadedRequest[*contracts.GetWatchedRequest]) (*contracts.PaginatedResponse[contracts.WatchedEntry], error) {
+	var entries contracts.PaginatedResponse[contracts.WatchedEntry]
+	_, err := c.client.R().SetResult(&entries).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.ToQueryParams()).
+		Get(c.path("/api/users/%d/watched", req.Request.UserID))
+	return &entries, err
+}
+
+func (c *Client) AddWatched(req *contracts.AuthenticadedRequest[*contracts.AddWatchedRequest]) (*contracts.WatchedEntry, error) {
+	var entry contracts.WatchedEntry
+	_, err := c.client.R().SetResult(&entry).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/users/%d/watched", req.Request.UserID))
+	return &entry, err
+}
+
+func (c *Client) DeleteWatched(req *contracts.AuthenticadedRequest[*contracts.DeleteWatchedRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/users/%d/watched/%d", req.Request.UserID, req.Request.EntryID))
+	return err
+}
@@ -0,0 +1,77 @@
+package client
+
+import "github.com/RadkevichAnn/movie-reviews/contracts"
+
+func (c *Client) CreateWebhook(req *contracts.AuthenticadedRequest[*contracts.CreateWebhookRequest]) (*contracts.Webhook, error) {
+	var webhook contracts.Webhook
+	_, err := c.client.R().SetResult(&webhook).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Post(c.path("/api/webhooks"))
+	return &webhook, err
+}
+
+func (c *Client) GetWebhooks(token string) ([]*contracts.Webhook, error) {
+	var webhooks []*contracts.Webhook
+	_, err := c.client.R().SetResult(&webhooks).SetAuthToken(token).
+		Get(c.path("/api/webhooks"))
+	return webhooks, err
+}
+
+func (c *Client) GetWebhookByID(req *contracts.AuthenticadedRequest[*contracts.GetWebhookRequest]) (*contracts.Webhook, error) {
+	var webhook contracts.Webhook
+	_, err := c.client.R().SetResult(&webhook).SetAuthToken(req.AccessToken).
+		Get(c.path("/api/webhooks/%d", req.Request.ID))
+	return &webhook, err
+}
+
+func (c *Client) UpdateWebhook(req *contracts.AuthenticadedRequest[*contracts.UpdateWebhookRequest]) (*contracts.Webhook, error) {
+	var webhook contracts.Webhook
+	_, err := c.client.R().SetResult(&webhook).SetAuthToken(req.AccessToken).
+		SetBody(req.Request).Put(c.path("/api/webhooks/%d", req.Request.ID))
+	return &webhook, err
+}
+
+func (c *Client) DeleteWebhook(req *contracts.AuthenticadedRequest[*contracts.GetWebhookRequest]) error {
+	_, err := c.client.R().SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/webhooks/%d", req.Request.ID))
+	return err
+}
+
+func (c *Client) PingWebhook(req *contracts.AuthenticadedRequest[*contracts.GetWebhookRequest]) (*contracts.WebhookDelivery, error) {
+	var delivery contracts.WebhookDelivery
+	_, err := c.client.R().SetResult(&delivery).SetAuthToken(req.AccessToken).
+		Post(c.path("/api/webhooks/%d/ping", req.Request.ID))
+	return &delivery, err
+}
+
+func (c *Client) GetWebhookDeliveries(req *contracts.AuthenticadedRequest[*contracts.GetWebhookDeliveriesRequest]) (*contracts.PaginatedResponse[contracts.WebhookDelivery], error) {
+	var deliveries contracts.PaginatedResponse[contracts.WebhookDelivery]
+	r := c.client.R().SetResult(&deliveries).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.PaginatedRequest.ToQueryParams())
+	if req.Request.Status != nil {
+		r.SetQueryParam("status", *req.Request.Status)
+	}
+	_, err := r.Get(c.path("/api/webhooks/%d/deliveries", req.Request.ID))
+	return &deliveries, err
+}
+
+func (c *Client) GetFailedWebhookDeliveries(req *contracts.AuthenticadedRequest[*contracts.GetFailedWebhookDeliveriesRequest]) (*contracts.PaginatedResponse[contracts.WebhookDelivery], error) {
+	var deliveries contracts.PaginatedResponse[contracts.WebhookDelivery]
+	_, err := c.client.R().SetResult(&deliveries).SetAuthToken(req.AccessToken).
+		SetQueryParams(req.Request.PaginatedRequest.ToQueryParams()).
+		Get(c.path("/api/webhooks/deliveries/failed"))
+	return &deliveries, err
+}
+
+func (c *Client) GetWebhookDelivery(req *contracts.AuthenticadedRequest[*contracts.GetWebhookDeliveryRequest]) (*contracts.WebhookDelivery, error) {
+	var delivery contracts.WebhookDelivery
+	_, err := c.client.R().SetResult(&delivery).SetAuthToken(req.AccessToken).
+		Get(c.path("/api/webhooks/deliveries/%d", req.Request.ID))
+	return &delivery, err
+}
+
+func (c *Client) RedeliverWebhook(req *contracts.AuthenticadedRequest[*contracts.GetWebhookDeliveryRequest]) (*contracts.WebhookDelivery, error) {
+	var delivery contracts.WebhookDelivery
+	_, err := c.client.R().SetResult(&delivery).SetAuthToken(req.AccessToken).
+		Post(c.path("/api/webhooks/deliveries/%d/redeliver", req.Request.ID))
+	return &delivery, err
+}
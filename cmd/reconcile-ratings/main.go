@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/exp/slog"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Only report the drift without fixing it")
+	flag.Parse()
+
+	cfg, err := config.NewConfig()
+	failOnError(err, "parse config")
+
+	logger, err := log.SetupLogger(cfg.Local, cfg.LogLevel)
+	failOnError(err, "setup logger")
+	slog.SetDefault(logger)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	db, err := pgxpool.New(ctx, cfg.DbUrl)
+	failOnError(err, "connect to db")
+	defer db.Close()
+
+	service := reviews.NewService(reviews.NewRepository(db), nil)
+	drifts, err := service.ReconcileRatings(ctx, *dryRun)
+	for _, drift := range drifts {
+		logger.Warn("movie rating drift",
+			"movieId", drift.MovieID,
+			"storedSum", drift.Stored.Sum,
+			"actualSum", drift.Actual.Sum,
+			"storedCount", drift.Stored.Count,
+			"actualCount", drift.Actual.Count,
+			"storedHistogram", drift.Stored.Histogram,
+			"actualHistogram", drift.Actual.Histogram)
+	}
+	failOnError(err, "reconcile ratings")
+
+	logger.Info("rating reconciliation finished", "drifted", len(drifts), "dryRun", *dryRun)
+}
+
+func failOnError(err error, message string) {
+	if err != nil {
+		slog.Error(message, "err", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,96 @@
+package contracts
+
+import (
+	"strconv"
+	"time"
+)
+
+type Award struct {
+	ID        int       `json:"id"`
+	Ceremony  string    `json:"ceremony"`
+	Year      int       `json:"year"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AwardDetails struct {
+	Award
+	Nominations []*Nomination `json:"nominations"`
+}
+
+type Nomination struct {
+	Movie AwardMovie `json:"movie"`
+	Star  *AwardStar `json:"star,omitempty"`
+	Won   bool       `json:"won"`
+}
+
+type AwardMovie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+type AwardStar struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type MovieAward struct {
+	Award Award      `json:"award"`
+	Star  *AwardStar `json:"star,omitempty"`
+	Won   bool       `json:"won"`
+}
+
+type StarAward struct {
+	Award Award      `json:"award"`
+	Movie AwardMovie `json:"movie"`
+	Won   bool       `json:"won"`
+}
+
+type NominationInfo struct {
+	MovieID int  `json:"movie_id" validate:"nonzero"`
+	StarID  *int `json:"star_id,omitempty"`
+	Won     bool `json:"won"`
+}
+
+type GetAwardsRequest struct {
+	PaginatedRequest
+	Ceremony *string `query:"ceremony"`
+	Year     *int    `query:"year"`
+}
+
+func (r *GetAwardsRequest) ToQueryParams() map[string]string {
+	params := r.PaginatedRequest.ToQueryParams()
+	if r.Ceremony != nil {
+		params["ceremony"] = *r.Ceremony
+	}
+	if r.Year != nil {
+		params["year"] = strconv.Itoa(*r.Year)
+	}
+	return params
+}
+
+type GetOrDeleteAwardRequest struct {
+	ID int `param:"id" validate:"nonzero"`
+}
+
+type GetAwardsBySubjectRequest struct {
+	ID int `param:"id" validate:"nonzero"`
+}
+
+type CreateAwardRequest struct {
+	Ceremony    string            `json:"ceremony" validate:"min=1,max=255"`
+	Year        int               `json:"year" validate:"min=1900,max=2100"`
+	Category    string            `json:"category" validate:"min=1,max=255"`
+	Nominations []*NominationInfo `json:"nominations"`
+}
+
+type UpdateAwardRequest struct {
+	ID          int               `json:"-" param:"id" validate:"nonzero"`
+	Ceremony    string            `json:"ceremony" validate:"min=1,max=255"`
+	Year        int               `json:"year" validate:"min=1900,max=2100"`
+	Category    string            `json:"category" validate:"min=1,max=255"`
+	Nominations []*NominationInfo `json:"nominations"`
+}
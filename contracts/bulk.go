@@ -0,0 +1,27 @@
+package contracts
+
+const (
+	BulkStatusCreated  = "created"
+	BulkStatusExisting = "existing"
+	BulkStatusFailed   = "failed"
+)
+
+// BulkStar is a star to be imported. Stars with an already known external id aren't imported again.
+type BulkStar struct {
+	CreateStarRequest
+	ExternalIDs map[string]string `json:"external_ids,omitempty"`
+}
+
+type BulkResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkReport struct {
+	Created  int           `json:"created"`
+	Existing int           `json:"existing"`
+	Failed   int           `json:"failed"`
+	Results  []*BulkResult `json:"results"`
+}
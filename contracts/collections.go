@@ -0,0 +1,53 @@
+package contracts
+
+import "time"
+
+type Collection struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	MovieCount  int        `json:"movie_count"`
+	AvgRating   *float64   `json:"avg_rating,omitempty"`
+	RatingCount int        `json:"rating_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+type CollectionDetails struct {
+	Collection
+	Movies []*CollectionMovie `json:"movies"`
+}
+
+type CollectionMovie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+type MovieCollection struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	OrderNo int    `json:"order_no"`
+}
+
+type GetCollectionsRequest struct {
+	PaginatedRequest
+}
+
+type GetOrDeleteCollectionRequest struct {
+	ID int `param:"id" validate:"nonzero"`
+}
+
+type CreateCollectionRequest struct {
+	Name        string `json:"name" validate:"min=1,max=255"`
+	Description string `json:"description"`
+	Movies      []int  `json:"movies"`
+}
+
+type UpdateCollectionRequest struct {
+	ID          int    `json:"-" param:"id" validate:"nonzero"`
+	Name        string `json:"name" validate:"min=1,max=255"`
+	Description string `json:"description"`
+	Movies      []int  `json:"movies"`
+}
@@ -0,0 +1,35 @@
+package contracts
+
+import "time"
+
+const (
+	ExportKindMovies = "movies"
+	ExportKindStars  = "stars"
+	ExportKindGenres = "genres"
+
+	ExportFormatJSON   = "json"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// Export is the result of a finished export job.
+type Export struct {
+	FileName    string    `json:"file_name"`
+	DownloadURL string    `json:"download_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type ExportCatalogRequest struct {
+	Kind   string `json:"kind" validate:"nonzero"`
+	Format string `json:"format" validate:"nonzero"`
+}
+
+type ExportUserDataRequest struct {
+	UserID int `param:"userId" validate:"nonzero"`
+}
+
+type DownloadExportRequest struct {
+	Key       string `param:"key" validate:"nonzero"`
+	Expires   int64  `query:"expires" validate:"nonzero"`
+	Signature string `query:"signature" validate:"nonzero"`
+}
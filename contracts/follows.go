@@ -0,0 +1,47 @@
+package contracts
+
+import "time"
+
+type UserSummary struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+type Follow struct {
+	User       UserSummary `json:"user"`
+	FollowedAt time.Time   `json:"followed_at"`
+}
+
+type Activity struct {
+	Type      string         `json:"type"`
+	SubjectID int            `json:"subject_id"`
+	User      UserSummary    `json:"user"`
+	Movie     *ActivityMovie `json:"movie,omitempty"`
+	Rating    *int           `json:"rating,omitempty"`
+	Title     string         `json:"title"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type ActivityMovie struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type FollowRequest struct {
+	UserID     int `json:"-" param:"userId" validate:"nonzero"`
+	FolloweeID int `json:"user_id" validate:"nonzero"`
+}
+
+type UnfollowRequest struct {
+	UserID     int `param:"userId" validate:"nonzero"`
+	FolloweeID int `param:"followeeId" validate:"nonzero"`
+}
+
+type GetFollowsRequest struct {
+	PaginatedRequest
+	UserID int `json:"-" param:"userId" validate:"nonzero"`
+}
+
+type GetFeedRequest struct {
+	PaginatedRequest
+}
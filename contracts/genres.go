@@ -1,8 +1,28 @@
 package contracts
 
+import "time"
+
 type Genre struct {
-	ID   int    `json:"ID"`
-	Name string `json:"Name"`
+	ID          int     `json:"ID"`
+	Name        string  `json:"Name"`
+	ParentID    *int    `json:"ParentID,omitempty"`
+	Slug        string  `json:"Slug"`
+	Description *string `json:"Description,omitempty"`
+}
+
+type GenreStats struct {
+	GenreID    int           `json:"GenreID"`
+	MovieCount int           `json:"MovieCount"`
+	AvgRating  *float64      `json:"AvgRating,omitempty"`
+	TopRated   []*RatedMovie `json:"TopRated"`
+}
+
+type RatedMovie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+	RatingCount int       `json:"rating_count"`
 }
 
 type GetGenreRequest struct {
@@ -10,12 +30,18 @@ type GetGenreRequest struct {
 }
 
 type CreateGenreRequest struct {
-	Name string `json:"Name" validate:"min=3,max=32" `
+	Name        string  `json:"Name" validate:"min=3,max=32" `
+	ParentID    *int    `json:"ParentID,omitempty"`
+	Slug        string  `json:"Slug,omitempty" validate:"max=64"`
+	Description *string `json:"Description,omitempty"`
 }
 
 type UpdateGenreRequest struct {
-	GenreId int    `param:"genreId" validate:"nonzero"`
-	Name    string `json:"Name" validate:"min=3,max=32" `
+	GenreId     int     `param:"genreId" validate:"nonzero"`
+	Name        string  `json:"Name" validate:"min=3,max=32" `
+	ParentID    *int    `json:"ParentID,omitempty"`
+	Slug        string  `json:"Slug,omitempty" validate:"max=64"`
+	Description *string `json:"Description,omitempty"`
 }
 
 type DeleteGenreRequest struct {
@@ -0,0 +1,21 @@
+package contracts
+
+import "encoding/json"
+
+type GraphQLRequest struct {
+	Query         string         `json:"query" validate:"nonzero"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// GraphQLResponse holds the data of a query as raw JSON, to be decoded into the shape of the query.
+// Data is missing if the query is invalid, otherwise the fields that failed are null and reported in Errors.
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []*GraphQLError `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
@@ -0,0 +1,15 @@
+package contracts
+
+// ImageFormField is the name of the multipart form field images are uploaded in.
+const ImageFormField = "image"
+
+type Image struct {
+	ID   string            `json:"id"`
+	URLs map[string]string `json:"urls"`
+}
+
+type UploadImageRequest struct {
+	ID       int    `json:"-" param:"id" validate:"nonzero"`
+	FileName string `json:"-" form:"-"`
+	Content  []byte `json:"-" form:"-"`
+}
@@ -0,0 +1,40 @@
+package contracts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+type Job struct {
+	ID          int             `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Progress    int             `json:"progress"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       *string         `json:"error,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	Periodic    bool            `json:"periodic"`
+	RunAt       time.Time       `json:"run_at"`
+	CreatedBy   *int            `json:"created_by,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
+
+type GetJobRequest struct {
+	ID int `param:"id" validate:"nonzero"`
+}
+
+type EnqueueJobRequest struct {
+	Type    string `json:"type" validate:"min=1,max=64"`
+	Payload any    `json:"payload,omitempty"`
+}
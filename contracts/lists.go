@@ -0,0 +1,91 @@
+package contracts
+
+import (
+	"strconv"
+	"time"
+)
+
+type List struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsPublic    bool      `json:"is_public"`
+	LikesCount  int       `json:"likes_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ListDetails struct {
+	List
+	Items []*ListItem `json:"items"`
+}
+
+type ListMovie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+type ListItem struct {
+	Movie ListMovie `json:"movie"`
+	Notes string    `json:"notes,omitempty"`
+}
+
+type ListItemInfo struct {
+	MovieID int    `json:"movie_id" validate:"nonzero"`
+	Notes   string `json:"notes" validate:"max=1000"`
+}
+
+type GetListsRequest struct {
+	PaginatedRequest
+	MovieID     *int    `query:"movieId"`
+	SortByLikes *string `query:"sortByLikes" validate:"sort"`
+}
+
+func (r *GetListsRequest) ToQueryParams() map[string]string {
+	params := r.PaginatedRequest.ToQueryParams()
+	if r.MovieID != nil {
+		params["movieId"] = strconv.Itoa(*r.MovieID)
+	}
+	if r.SortByLikes != nil {
+		params["sortByLikes"] = *r.SortByLikes
+	}
+	return params
+}
+
+type GetUserListsRequest struct {
+	PaginatedRequest
+	UserID int `json:"-" param:"userId" validate:"nonzero"`
+}
+
+type GetListRequest struct {
+	ListID int `param:"listId" validate:"nonzero"`
+}
+
+type CreateListRequest struct {
+	UserID      int             `json:"-" param:"userId" validate:"nonzero"`
+	Name        string          `json:"name" validate:"min=1,max=100"`
+	Description string          `json:"description"`
+	IsPublic    *bool           `json:"is_public,omitempty"`
+	Items       []*ListItemInfo `json:"items"`
+}
+
+type UpdateListRequest struct {
+	UserID      int             `json:"-" param:"userId" validate:"nonzero"`
+	ListID      int             `json:"-" param:"listId" validate:"nonzero"`
+	Name        string          `json:"name" validate:"min=1,max=100"`
+	Description string          `json:"description"`
+	IsPublic    bool            `json:"is_public"`
+	Items       []*ListItemInfo `json:"items"`
+}
+
+type DeleteListRequest struct {
+	UserID int `param:"userId" validate:"nonzero"`
+	ListID int `param:"listId" validate:"nonzero"`
+}
+
+type LikeListRequest struct {
+	ListID int `param:"listId" validate:"nonzero"`
+}
@@ -0,0 +1,34 @@
+package contracts
+
+import "encoding/json"
+
+// LiveEventRatingChanged follows every review event of the live streams with the rating of the movie after it.
+// The other events of the streams are the movie and review events of webhooks.
+const LiveEventRatingChanged = "movie.rating_changed"
+
+// StreamMovieEventsRequest opens the live stream of the movie. The stream resumes after the event
+// of the Last-Event-ID header, or of LastEventID if the header is missing. Events that were committed late
+// are replayed as well, so a resumed stream can repeat events, which clients skip by their ids.
+type StreamMovieEventsRequest struct {
+	MovieID     int    `param:"id" validate:"nonzero"`
+	LastEventID string `query:"last_event_id"`
+}
+
+// StreamEventsRequest opens the live stream of all movies.
+type StreamEventsRequest struct {
+	LastEventID string `query:"last_event_id"`
+}
+
+// LiveEvent is an event received from a live stream.
+type LiveEvent struct {
+	ID   string
+	Type string
+	Data json.RawMessage
+}
+
+// MovieRating is the data of LiveEventRatingChanged.
+type MovieRating struct {
+	MovieID     int      `json:"movie_id"`
+	AvgRating   *float64 `json:"avg_rating"`
+	RatingCount int      `json:"rating_count"`
+}
@@ -16,11 +16,38 @@ type Movie struct {
 
 type MovieDetails struct {
 	Movie
-	Description string         `json:"description"`
-	Version     int            `json:"version"`
-	Genres      []*Genre       `json:"genres"`
-	Cast        []*MovieCredit `json:"cast"`
+	Description         string             `json:"description"`
+	RuntimeMinutes      *int               `json:"runtime_minutes,omitempty"`
+	OriginalLanguage    *string            `json:"original_language,omitempty"`
+	ProductionCountries []string           `json:"production_countries,omitempty"`
+	Certifications      map[string]string  `json:"certifications,omitempty"`
+	ExternalIDs         map[string]string  `json:"external_ids,omitempty"`
+	Releases            []*Release         `json:"releases,omitempty"`
+	Poster              *Image             `json:"poster,omitempty"`
+	Version             int                `json:"version"`
+	Genres              []*Genre           `json:"genres"`
+	Cast                []*MovieCredit     `json:"cast"`
+	Collections         []*MovieCollection `json:"collections,omitempty"`
+	Viewer              *MovieViewerFlags  `json:"viewer,omitempty"`
 }
+
+// Release types supported by the movie releases.
+const (
+	ReleaseTypePremiere          = "premiere"
+	ReleaseTypeTheatricalLimited = "theatrical_limited"
+	ReleaseTypeTheatrical        = "theatrical"
+	ReleaseTypeDigital           = "digital"
+	ReleaseTypePhysical          = "physical"
+	ReleaseTypeTV                = "tv"
+)
+
+type Release struct {
+	Country string    `json:"country"`
+	Type    string    `json:"type"`
+	Date    time.Time `json:"date"`
+	Note    string    `json:"note,omitempty"`
+}
+
 type MovieCredit struct {
 	Star    Star    `json:"star"`
 	Role    string  `json:"role"`
@@ -34,11 +61,41 @@ type MovieCreditInfo struct {
 type GetOrDeleteMovieByIDRequest struct {
 	ID int `param:"id" validate:"nonzero"`
 }
+type GetMovieByExternalIDRequest struct {
+	Source     string `param:"source" validate:"nonzero"`
+	ExternalID string `param:"externalId" validate:"nonzero"`
+}
+
+type SimilarMovie struct {
+	Movie
+	Score float64 `json:"score"`
+}
+
+type GetSimilarMoviesRequest struct {
+	ID    int  `json:"-" param:"id" validate:"nonzero"`
+	Limit *int `json:"-" query:"limit" validate:"min=1,max=50"`
+}
+
+func (r *GetSimilarMoviesRequest) ToQueryParams() map[string]string {
+	params := make(map[string]string)
+	if r.Limit != nil {
+		params["limit"] = strconv.Itoa(*r.Limit)
+	}
+	return params
+}
+
 type GetMoviesRequest struct {
 	PaginatedRequest
 	StarID       *int    `query:"starId"`
+	GenreID      *int    `query:"genreId"`
 	SearchTerm   *string `query:"q"`
 	SortByRating *string `query:"sortByRating" validate:"sort"`
+	// ReleaseCountry, ReleasedFrom and ReleasedTo filter movies released in the country between the dates
+	ReleaseCountry *string    `query:"releaseCountry"`
+	ReleasedFrom   *time.Time `query:"releasedFrom"`
+	ReleasedTo     *time.Time `query:"releasedTo"`
+	// AwardWon filters movies that won an award of the ceremony, e.g. "Academy Awards"
+	AwardWon *string `query:"awardWon"`
 }
 
 func (r *GetMoviesRequest) ToQueryParams() map[string]string {
@@ -46,29 +103,56 @@ func (r *GetMoviesRequest) ToQueryParams() map[string]string {
 	if r.StarID != nil {
 		params["starId"] = strconv.Itoa(*r.StarID)
 	}
+	if r.GenreID != nil {
+		params["genreId"] = strconv.Itoa(*r.GenreID)
+	}
 	if r.SearchTerm != nil {
 		params["q"] = *r.SearchTerm
 	}
 	if r.SortByRating != nil {
 		params["sort"] = *r.SortByRating
 	}
+	if r.ReleaseCountry != nil {
+		params["releaseCountry"] = *r.ReleaseCountry
+	}
+	if r.ReleasedFrom != nil {
+		params["releasedFrom"] = r.ReleasedFrom.Format(time.RFC3339)
+	}
+	if r.ReleasedTo != nil {
+		params["releasedTo"] = r.ReleasedTo.Format(time.RFC3339)
+	}
+	if r.AwardWon != nil {
+		params["awardWon"] = *r.AwardWon
+	}
 	return params
 }
 
 type CreateMovieRequest struct {
-	Title       string             `json:"title" validate:"min=1,max=255"`
-	ReleaseDate time.Time          `json:"release_date" validate:"nonzero"`
-	Description string             `json:"description"`
-	Genres      []int              `json:"genres"`
-	Cast        []*MovieCreditInfo `json:"cast"`
+	Title               string             `json:"title" validate:"min=1,max=255"`
+	ReleaseDate         time.Time          `json:"release_date"`
+	Description         string             `json:"description"`
+	RuntimeMinutes      *int               `json:"runtime_minutes,omitempty" validate:"min=1"`
+	OriginalLanguage    *string            `json:"original_language,omitempty"`
+	ProductionCountries []string           `json:"production_countries,omitempty"`
+	Certifications      map[string]string  `json:"certifications,omitempty"`
+	ExternalIDs         map[string]string  `json:"external_ids,omitempty"`
+	Releases            []*Release         `json:"releases,omitempty"`
+	Genres              []int              `json:"genres"`
+	Cast                []*MovieCreditInfo `json:"cast"`
 }
 
 type UpdateMovieRequest struct {
-	ID          int                `param:"id" validate:"nonzero"`
-	Title       string             `json:"title"`
-	ReleaseDate time.Time          `json:"release_date"`
-	Description string             `json:"description"`
-	Version     int                `json:"version" validate:"min=0"`
-	Genres      []int              `json:"genres"`
-	Cast        []*MovieCreditInfo `json:"cast"`
+	ID                  int                `param:"id" validate:"nonzero"`
+	Title               string             `json:"title"`
+	ReleaseDate         time.Time          `json:"release_date"`
+	Description         string             `json:"description"`
+	RuntimeMinutes      *int               `json:"runtime_minutes,omitempty" validate:"min=1"`
+	OriginalLanguage    *string            `json:"original_language,omitempty"`
+	ProductionCountries []string           `json:"production_countries,omitempty"`
+	Certifications      map[string]string  `json:"certifications,omitempty"`
+	ExternalIDs         map[string]string  `json:"external_ids,omitempty"`
+	Releases            []*Release         `json:"releases,omitempty"`
+	Version             int                `json:"version" validate:"min=0"`
+	Genres              []int              `json:"genres"`
+	Cast                []*MovieCreditInfo `json:"cast"`
 }
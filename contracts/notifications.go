@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	NotificationTypeFolloweeReviewed      = "followee_reviewed"
+	NotificationTypeWatchlistMovieUpdated = "watchlist_movie_updated"
+
+	NotificationChannelInApp   = "in_app"
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+)
+
+type Notification struct {
+	ID        int             `json:"id"`
+	UserID    int             `json:"user_id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+}
+
+type UnreadCount struct {
+	Count int `json:"count"`
+}
+
+type NotificationPreference struct {
+	Type     string   `json:"type" validate:"nonzero"`
+	Channels []string `json:"channels"`
+}
+
+type NotificationWebhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+type GetNotificationsRequest struct {
+	PaginatedRequest
+	UnreadOnly bool `json:"-" query:"unreadOnly"`
+}
+
+type MarkNotificationReadRequest struct {
+	NotificationID int `param:"notificationId" validate:"nonzero"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []*NotificationPreference `json:"preferences" validate:"nonzero"`
+}
+
+type SetNotificationWebhookRequest struct {
+	URL string `json:"url" validate:"nonzero"`
+}
@@ -0,0 +1,22 @@
+package contracts
+
+import "strconv"
+
+type Recommendation struct {
+	Movie  ListMovie `json:"movie"`
+	Score  float64   `json:"score"`
+	Source string    `json:"source"`
+}
+
+type GetRecommendationsRequest struct {
+	UserID int  `json:"-" param:"userId" validate:"nonzero"`
+	Limit  *int `json:"-" query:"limit" validate:"min=1,max=100"`
+}
+
+func (r *GetRecommendationsRequest) ToQueryParams() map[string]string {
+	params := make(map[string]string)
+	if r.Limit != nil {
+		params["limit"] = strconv.Itoa(*r.Limit)
+	}
+	return params
+}
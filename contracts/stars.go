@@ -19,6 +19,7 @@ type StarDetails struct {
 	MiddleName *string `json:"middle_name,omitempty"`
 	BirthPlace *string `json:"birth_place,omitempty"`
 	Bio        *string `json:"bio,omitempty"`
+	Headshot   *Image  `json:"headshot,omitempty"`
 }
 
 type CreateStarRequest struct {
@@ -60,3 +61,72 @@ type UpdateStarRequest struct {
 	DeathDate  *time.Time `json:"death_date,omitempty"`
 	Bio        *string    `json:"bio,omitempty"`
 }
+
+type GetFilmographyRequest struct {
+	ID            int     `json:"-" param:"id" validate:"nonzero"`
+	SortByRelease *string `json:"-" query:"sortByRelease" validate:"sort"`
+}
+
+func (r *GetFilmographyRequest) ToQueryParams() map[string]string {
+	params := make(map[string]string)
+	if r.SortByRelease != nil {
+		params["sortByRelease"] = *r.SortByRelease
+	}
+	return params
+}
+
+type FilmographyCredit struct {
+	Movie   ListMovie `json:"movie"`
+	Details *string   `json:"details,omitempty"`
+}
+
+type RoleFilmography struct {
+	Role    string               `json:"role"`
+	Credits []*FilmographyCredit `json:"credits"`
+}
+
+type Collaborator struct {
+	Star         Star `json:"star"`
+	SharedMovies int  `json:"shared_movies"`
+}
+
+type CareerStats struct {
+	MovieCount    int             `json:"movie_count"`
+	RoleCounts    map[string]int  `json:"role_counts"`
+	AvgRating     *float64        `json:"avg_rating,omitempty"`
+	FirstRelease  *time.Time      `json:"first_release,omitempty"`
+	LastRelease   *time.Time      `json:"last_release,omitempty"`
+	Collaborators []*Collaborator `json:"collaborators"`
+}
+
+type GetCollaboratorsRequest struct {
+	PaginatedRequest
+	ID int `json:"-" param:"id" validate:"nonzero"`
+}
+
+type GetStarPathRequest struct {
+	From       int  `query:"from" validate:"nonzero"`
+	To         int  `query:"to" validate:"nonzero"`
+	MaxDegrees *int `query:"maxDegrees" validate:"min=1,max=6"`
+}
+
+func (r *GetStarPathRequest) ToQueryParams() map[string]string {
+	params := map[string]string{
+		"from": strconv.Itoa(r.From),
+		"to":   strconv.Itoa(r.To),
+	}
+	if r.MaxDegrees != nil {
+		params["maxDegrees"] = strconv.Itoa(*r.MaxDegrees)
+	}
+	return params
+}
+
+type PathStep struct {
+	Star  Star       `json:"star"`
+	Movie *ListMovie `json:"movie,omitempty"`
+}
+
+type StarPath struct {
+	Degrees int         `json:"degrees"`
+	Steps   []*PathStep `json:"steps"`
+}
@@ -0,0 +1,82 @@
+package contracts
+
+import "time"
+
+const (
+	TriviaKindTrivia = "trivia"
+	TriviaKindQuote  = "quote"
+	TriviaKindGoof   = "goof"
+
+	TriviaStatusPending  = "pending"
+	TriviaStatusApproved = "approved"
+	TriviaStatusRejected = "rejected"
+)
+
+type TriviaItem struct {
+	ID         int           `json:"id"`
+	Kind       string        `json:"kind"`
+	MovieID    *int          `json:"movie_id,omitempty"`
+	StarID     *int          `json:"star_id,omitempty"`
+	UserID     int           `json:"user_id"`
+	Content    string        `json:"content"`
+	Credit     *TriviaCredit `json:"credit,omitempty"`
+	Status     string        `json:"status"`
+	Score      int           `json:"score"`
+	CreatedAt  time.Time     `json:"created_at"`
+	ReviewedAt *time.Time    `json:"reviewed_at,omitempty"`
+}
+
+type TriviaCredit struct {
+	StarID    int    `json:"star_id"`
+	Role      string `json:"role"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+type GetTriviaRequest struct {
+	PaginatedRequest
+	ID          int     `json:"-" param:"id" validate:"nonzero"`
+	Kind        *string `json:"-" query:"kind"`
+	Status      *string `json:"-" query:"status"`
+	SortByScore *string `json:"-" query:"sortByScore" validate:"sort"`
+}
+
+func (r *GetTriviaRequest) ToQueryParams() map[string]string {
+	params := r.PaginatedRequest.ToQueryParams()
+	if r.Kind != nil {
+		params["kind"] = *r.Kind
+	}
+	if r.Status != nil {
+		params["status"] = *r.Status
+	}
+	if r.SortByScore != nil {
+		params["sortByScore"] = *r.SortByScore
+	}
+	return params
+}
+
+type SubmitTriviaRequest struct {
+	ID      int               `json:"-" param:"id" validate:"nonzero"`
+	Kind    string            `json:"kind"`
+	Content string            `json:"content" validate:"min=1,max=2000"`
+	Credit  *TriviaCreditInfo `json:"credit,omitempty"`
+}
+
+type TriviaCreditInfo struct {
+	StarID int    `json:"star_id" validate:"nonzero"`
+	Role   string `json:"role" validate:"nonzero"`
+}
+
+type ReviewTriviaRequest struct {
+	ItemID int    `json:"-" param:"itemId" validate:"nonzero"`
+	Status string `json:"status" validate:"nonzero"`
+}
+
+type VoteTriviaRequest struct {
+	ItemID int `json:"-" param:"itemId" validate:"nonzero"`
+	Value  int `json:"value" validate:"min=-1,max=1"`
+}
+
+type TriviaItemRequest struct {
+	ItemID int `param:"itemId" validate:"nonzero"`
+}
@@ -2,23 +2,73 @@ package contracts
 
 import "time"
 
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+	VisibilityPrivate   = "private"
+)
+
 type User struct {
-	ID        int        `json:"id"`
-	Username  string     `json:"username"`
-	Email     string     `json:"email"`
-	Role      string     `json:"role"`
-	Bio       *string    `json:"bio,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID             int              `json:"id"`
+	Username       string           `json:"username"`
+	DisplayName    *string          `json:"display_name,omitempty"`
+	Email          string           `json:"email,omitempty"`
+	Role           string           `json:"role"`
+	Bio            *string          `json:"bio,omitempty"`
+	Location       *string          `json:"location,omitempty"`
+	Avatar         *Image           `json:"avatar,omitempty"`
+	FavoriteGenres []*FavoriteGenre `json:"favorite_genres,omitempty"`
+	Stats          *UserStats       `json:"stats,omitempty"`
+	Privacy        *Privacy         `json:"privacy,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	DeletedAt      *time.Time       `json:"deleted_at,omitempty"`
+}
+
+type FavoriteGenre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserStats struct {
+	ReviewCount    int      `json:"review_count"`
+	AvgRatingGiven *float64 `json:"avg_rating_given,omitempty"`
+}
+
+// Privacy holds the visibility of the parts of the profile: public, followers or private.
+type Privacy struct {
+	Email   string `json:"email,omitempty" validate:"visibility"`
+	Reviews string `json:"reviews,omitempty" validate:"visibility"`
+	Lists   string `json:"lists,omitempty" validate:"visibility"`
 }
 
 type DeleteOrGetRequest struct {
 	UserId int `param:"userId"`
 }
 
+type DeleteUserRequest struct {
+	UserId int `json:"-" param:"userId"`
+	// KeepReviews keeps the reviews of the user attributed to the anonymized account, it's true by default.
+	KeepReviews *bool `json:"keep_reviews"`
+}
+
+type AccountDeletion struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+	KeepReviews bool      `json:"keep_reviews"`
+}
+
 type UpdateRequest struct {
-	UserId int     `param:"userId"`
-	Bio    *string `json:"bio"`
+	UserId           int      `json:"-" param:"userId"`
+	Bio              *string  `json:"bio,omitempty"`
+	DisplayName      *string  `json:"display_name,omitempty" validate:"max=64"`
+	Location         *string  `json:"location,omitempty" validate:"max=100"`
+	FavoriteGenreIDs []int    `json:"favorite_genre_ids" validate:"max=5"`
+	Privacy          *Privacy `json:"privacy,omitempty"`
+}
+
+type UploadAvatarRequest struct {
+	UserID   int    `json:"-" param:"userId" validate:"nonzero"`
+	FileName string `json:"-" form:"-"`
+	Content  []byte `json:"-" form:"-"`
 }
 
 type UpdateRoleRequest struct {
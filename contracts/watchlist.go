@@ -0,0 +1,88 @@
+package contracts
+
+import (
+	"strconv"
+	"time"
+)
+
+type WatchlistMovie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+type WatchlistItem struct {
+	UserID   int            `json:"user_id"`
+	Movie    WatchlistMovie `json:"movie"`
+	Notes    *string        `json:"notes,omitempty"`
+	Priority *int           `json:"priority,omitempty"`
+	AddedAt  time.Time      `json:"added_at"`
+}
+
+type WatchedEntry struct {
+	ID        int            `json:"id"`
+	UserID    int            `json:"user_id"`
+	Movie     WatchlistMovie `json:"movie"`
+	WatchedOn time.Time      `json:"watched_on"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type MovieViewerFlags struct {
+	Watchlisted bool `json:"watchlisted"`
+	Watched     bool `json:"watched"`
+}
+
+type GetWatchlistRequest struct {
+	PaginatedRequest
+	UserID int `json:"-" param:"userId" validate:"nonzero"`
+}
+
+type AddToWatchlistRequest struct {
+	UserID   int     `json:"-" param:"userId" validate:"nonzero"`
+	MovieID  int     `json:"movie_id" validate:"nonzero"`
+	Notes    *string `json:"notes,omitempty" validate:"max=1000"`
+	Priority *int    `json:"priority,omitempty" validate:"min=1,max=5"`
+}
+
+type UpdateWatchlistItemRequest struct {
+	UserID   int     `json:"-" param:"userId" validate:"nonzero"`
+	MovieID  int     `json:"-" param:"movieId" validate:"nonzero"`
+	Notes    *string `json:"notes,omitempty" validate:"max=1000"`
+	Priority *int    `json:"priority,omitempty" validate:"min=1,max=5"`
+}
+
+type DeleteWatchlistItemRequest struct {
+	UserID  int `param:"userId" validate:"nonzero"`
+	MovieID int `param:"movieId" validate:"nonzero"`
+}
+
+type ReorderWatchlistRequest struct {
+	UserID   int   `json:"-" param:"userId" validate:"nonzero"`
+	MovieIDs []int `json:"movie_ids"`
+}
+
+type GetWatchedRequest struct {
+	PaginatedRequest
+	UserID  int  `json:"-" param:"userId" validate:"nonzero"`
+	MovieID *int `json:"-" query:"movieId"`
+}
+
+func (r *GetWatchedRequest) ToQueryParams() map[string]string {
+	params := r.PaginatedRequest.ToQueryParams()
+	if r.MovieID != nil {
+		params["movieId"] = strconv.Itoa(*r.MovieID)
+	}
+	return params
+}
+
+type AddWatchedRequest struct {
+	UserID    int       `json:"-" param:"userId" validate:"nonzero"`
+	MovieID   int       `json:"movie_id" validate:"nonzero"`
+	WatchedOn time.Time `json:"watched_on"`
+}
+
+type DeleteWatchedRequest struct {
+	UserID  int `param:"userId" validate:"nonzero"`
+	EntryID int `param:"entryId" validate:"nonzero"`
+}
@@ -0,0 +1,104 @@
+package contracts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookEventMovieCreated  = "movie.created"
+	WebhookEventMovieUpdated  = "movie.updated"
+	WebhookEventMovieDeleted  = "movie.deleted"
+	WebhookEventStarCreated   = "star.created"
+	WebhookEventStarUpdated   = "star.updated"
+	WebhookEventStarDeleted   = "star.deleted"
+	WebhookEventGenreCreated  = "genre.created"
+	WebhookEventGenreUpdated  = "genre.updated"
+	WebhookEventGenreDeleted  = "genre.deleted"
+	WebhookEventReviewCreated = "review.created"
+	WebhookEventReviewUpdated = "review.updated"
+	WebhookEventReviewDeleted = "review.deleted"
+	WebhookEventPing          = "ping"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret,omitempty"`
+	EventTypes  []string  `json:"event_types"`
+	Description *string   `json:"description,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedBy   *int      `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookEvent is the body of the requests sent to webhooks.
+type WebhookEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type WebhookDelivery struct {
+	ID             int                       `json:"id"`
+	SubscriptionID int                       `json:"subscription_id"`
+	EventID        string                    `json:"event_id"`
+	EventType      string                    `json:"event_type"`
+	Payload        json.RawMessage           `json:"payload"`
+	Status         string                    `json:"status"`
+	Attempts       int                       `json:"attempts"`
+	MaxAttempts    int                       `json:"max_attempts"`
+	NextAttemptAt  time.Time                 `json:"next_attempt_at"`
+	LastStatusCode *int                      `json:"last_status_code,omitempty"`
+	LastError      *string                   `json:"last_error,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	FinishedAt     *time.Time                `json:"finished_at,omitempty"`
+	Log            []*WebhookDeliveryAttempt `json:"log,omitempty"`
+}
+
+type WebhookDeliveryAttempt struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      *string   `json:"error,omitempty"`
+	DurationMs int       `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"nonzero"`
+	EventTypes  []string `json:"event_types"`
+	Description *string  `json:"description,omitempty" validate:"max=255"`
+}
+
+type GetWebhookRequest struct {
+	ID int `param:"webhookId" validate:"nonzero"`
+}
+
+// UpdateWebhookRequest changes the set fields of the webhook. Empty EventTypes subscribe to all events.
+type UpdateWebhookRequest struct {
+	ID          int      `json:"-" param:"webhookId" validate:"nonzero"`
+	URL         *string  `json:"url,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Description *string  `json:"description,omitempty" validate:"max=255"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+type GetWebhookDeliveriesRequest struct {
+	PaginatedRequest
+	ID     int     `json:"-" param:"webhookId" validate:"nonzero"`
+	Status *string `json:"-" query:"status"`
+}
+
+type GetFailedWebhookDeliveriesRequest struct {
+	PaginatedRequest
+}
+
+type GetWebhookDeliveryRequest struct {
+	ID int `param:"deliveryId" validate:"nonzero"`
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func accountDeletionAPIChecks(t *testing.T, c *client.Client) {
+	user := RegisterRandomUser(t, c)
+	userToken := login(t, c, user.Email, standardPassword)
+
+	t.Run("users.DeleteUser: revokes tokens", func(t *testing.T) {
+		req := &contracts.DeleteUserRequest{UserId: user.ID}
+		deletion, err := c.DeleteUser(contracts.NewAuthenticated(req, userToken))
+		require.NoError(t, err)
+		require.True(t, deletion.KeepReviews)
+		require.False(t, deletion.ScheduledAt.IsZero())
+
+		_, err = c.DeleteUser(contracts.NewAuthenticated(req, userToken))
+		requireUnauthorizedError(t, err, "invalid or missing token")
+
+		_, err = c.LoginUser(&contracts.LoginUserRequest{Email: user.Email, Password: standardPassword})
+		requireNotFoundError(t, err, "user", "email", user.Email)
+	})
+	t.Run("auth.Restore: invalid password", func(t *testing.T) {
+		_, err := c.RestoreUser(&contracts.LoginUserRequest{Email: user.Email, Password: standardPassword + "1"})
+		requireUnauthorizedError(t, err, "invalid password")
+	})
+	t.Run("auth.Restore: success", func(t *testing.T) {
+		res, err := c.RestoreUser(&contracts.LoginUserRequest{Email: user.Email, Password: standardPassword})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.AccessToken)
+
+		require.NotNil(t, getUser(t, c, user.ID))
+		login(t, c, user.Email, standardPassword)
+	})
+	t.Run("auth.Restore: not deleted", func(t *testing.T) {
+		_, err := c.RestoreUser(&contracts.LoginUserRequest{Email: user.Email, Password: standardPassword})
+		requireNotFoundError(t, err, "deleted user", "email", user.Email)
+	})
+
+	t.Run("users.DeleteUser: keep reviews", func(t *testing.T) {
+		reviewer := RegisterRandomUser(t, c)
+		review := createReview(t, c, reviewer, lordOfTheRing.ID)
+
+		req := &contracts.DeleteUserRequest{UserId: reviewer.ID}
+		_, err := c.DeleteUser(contracts.NewAuthenticated(req, adminToken))
+		require.NoError(t, err)
+
+		purge(t, c, reviewer)
+		kept, err := c.GetReviewByID(review.ID)
+		require.NoError(t, err)
+		require.Equal(t, reviewer.ID, kept.UserID)
+	})
+	t.Run("users.DeleteUser: remove reviews", func(t *testing.T) {
+		reviewer := RegisterRandomUser(t, c)
+		review := createReview(t, c, reviewer, lordOfTheRing.ID)
+
+		keepReviews := false
+		req := &contracts.DeleteUserRequest{UserId: reviewer.ID, KeepReviews: &keepReviews}
+		deletion, err := c.DeleteUser(contracts.NewAuthenticated(req, adminToken))
+		require.NoError(t, err)
+		require.False(t, deletion.KeepReviews)
+
+		purge(t, c, reviewer)
+		_, err = c.GetReviewByID(review.ID)
+		requireNotFoundError(t, err, "review", "id", review.ID)
+	})
+}
+
+func createReview(t *testing.T, c *client.Client, user *contracts.User, movieID int) *contracts.Review {
+	req := &contracts.CreateReviewRequest{
+		MovieID: movieID,
+		UserID:  user.ID,
+		Rating:  7,
+		Title:   "Worth watching",
+		Content: "A solid movie that holds up well after all these years.",
+	}
+	review, err := c.CreateReview(contracts.NewAuthenticated(req, login(t, c, user.Email, standardPassword)))
+	require.NoError(t, err)
+	return review
+}
+
+// purge waits for the deleted user to be purged once the grace period is over, which frees the username and email.
+// It registers a new user with them.
+func purge(t *testing.T, c *client.Client, user *contracts.User) {
+	timer := &retry.Timer{Timeout: deletionGracePeriod + 10*time.Second, Wait: 200 * time.Millisecond}
+	retry.RunWith(timer, t, func(r *retry.R) {
+		_, err := c.RegisterUser(&contracts.RegisterUserRequest{
+			Username: user.Username,
+			Email:    user.Email,
+			Password: standardPassword,
+		})
+		require.NoError(r, err)
+	})
+}
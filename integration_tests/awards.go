@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func awardsAPIChecks(t *testing.T, c *client.Client) {
+	var bestScore, bestActor, goldenGlobe *contracts.AwardDetails
+	t.Run("awards.CreateAward: success", func(t *testing.T) {
+		req := &contracts.CreateAwardRequest{
+			Ceremony: "Academy Awards",
+			Year:     1978,
+			Category: "Best Original Score",
+			Nominations: []*contracts.NominationInfo{
+				{MovieID: starWars.ID, Won: true},
+			},
+		}
+		var err error
+		bestScore, err = c.CreateAward(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, bestScore.ID)
+		require.Len(t, bestScore.Nominations, 1)
+		require.Equal(t, starWars.ID, bestScore.Nominations[0].Movie.ID)
+		require.True(t, bestScore.Nominations[0].Won)
+
+		req = &contracts.CreateAwardRequest{
+			Ceremony: "Academy Awards",
+			Year:     1978,
+			Category: "Best Supporting Actor",
+			Nominations: []*contracts.NominationInfo{
+				{MovieID: starWars.ID, StarID: &hamill.ID},
+			},
+		}
+		bestActor, err = c.CreateAward(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, hamill.ID, bestActor.Nominations[0].Star.ID)
+		require.Equal(t, hamill.LastName, bestActor.Nominations[0].Star.LastName)
+
+		req = &contracts.CreateAwardRequest{
+			Ceremony: "Golden Globes",
+			Year:     2002,
+			Category: "Best Motion Picture – Drama",
+			Nominations: []*contracts.NominationInfo{
+				{MovieID: lordOfTheRing.ID, Won: true},
+			},
+		}
+		goldenGlobe, err = c.CreateAward(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+	})
+	t.Run("awards.CreateAward: already exists", func(t *testing.T) {
+		req := &contracts.CreateAwardRequest{
+			Ceremony: bestScore.Ceremony,
+			Year:     bestScore.Year,
+			Category: bestScore.Category,
+		}
+		_, err := c.CreateAward(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistError(t, err, "award", "(ceremony,year,category)", "(Academy Awards,1978,Best Original Score)")
+	})
+	t.Run("awards.CreateAward: movie not found", func(t *testing.T) {
+		notExistingID := 1000
+		req := &contracts.CreateAwardRequest{
+			Ceremony:    "Academy Awards",
+			Year:        1978,
+			Category:    "Best Visual Effects",
+			Nominations: []*contracts.NominationInfo{{MovieID: notExistingID}},
+		}
+		_, err := c.CreateAward(contracts.NewAuthenticated(req, johnDoeToken))
+		requireNotFoundError(t, err, "movie", "id", notExistingID)
+	})
+	t.Run("awards.GetAwards: by ceremony", func(t *testing.T) {
+		res, err := c.GetAwards(&contracts.GetAwardsRequest{Ceremony: contracts.Ptr("academy awards")})
+		require.NoError(t, err)
+		require.Equal(t, 2, res.Total)
+	})
+	t.Run("awards.GetMovieAwards: success", func(t *testing.T) {
+		awards, err := c.GetMovieAwards(starWars.ID)
+		require.NoError(t, err)
+		require.Len(t, awards, 2)
+		require.Equal(t, bestScore.ID, awards[0].Award.ID)
+		require.True(t, awards[0].Won)
+		require.Equal(t, bestActor.ID, awards[1].Award.ID)
+		require.False(t, awards[1].Won)
+		require.Equal(t, hamill.ID, awards[1].Star.ID)
+	})
+	t.Run("awards.GetMovieAwards: not found", func(t *testing.T) {
+		notExistingID := 1000
+		_, err := c.GetMovieAwards(notExistingID)
+		requireNotFoundError(t, err, "movie", "id", notExistingID)
+	})
+	t.Run("awards.GetStarAwards: success", func(t *testing.T) {
+		awards, err := c.GetStarAwards(hamill.ID)
+		require.NoError(t, err)
+		require.Len(t, awards, 1)
+		require.Equal(t, bestActor.ID, awards[0].Award.ID)
+		require.Equal(t, starWars.ID, awards[0].Movie.ID)
+	})
+	t.Run("movies.GetAllMovies: by awardWon", func(t *testing.T) {
+		res, err := c.GetMovies(&contracts.GetMoviesRequest{AwardWon: contracts.Ptr("Academy Awards")})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, starWars.ID, res.Items[0].ID)
+	})
+	t.Run("awards.UpdateAward: success", func(t *testing.T) {
+		req := &contracts.UpdateAwardRequest{
+			ID:       goldenGlobe.ID,
+			Ceremony: "Academy Awards",
+			Year:     2002,
+			Category: "Best Picture",
+			Nominations: []*contracts.NominationInfo{
+				{MovieID: lordOfTheRing.ID},
+			},
+		}
+		award, err := c.UpdateAward(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, "Best Picture", award.Category)
+		require.False(t, award.Nominations[0].Won)
+
+		res, err := c.GetMovies(&contracts.GetMoviesRequest{AwardWon: contracts.Ptr("Academy Awards")})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+	})
+	t.Run("awards.DeleteAward: success", func(t *testing.T) {
+		err := c.DeleteAward(contracts.NewAuthenticated(&contracts.GetOrDeleteAwardRequest{ID: bestScore.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		_, err = c.GetAwardByID(bestScore.ID)
+		requireNotFoundError(t, err, "award", "id", bestScore.ID)
+
+		res, err := c.GetMovies(&contracts.GetMoviesRequest{AwardWon: contracts.Ptr("Academy Awards")})
+		require.NoError(t, err)
+		require.Equal(t, 0, res.Total)
+	})
+}
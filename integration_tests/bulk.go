@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func bulkAPIChecks(t *testing.T, c *client.Client) {
+	user := RegisterRandomUser(t, c)
+	userToken := login(t, c, user.Email, standardPassword)
+
+	genresReq := []*contracts.CreateGenreRequest{
+		{Name: "Film Noir"},
+		{Name: "Space Western"},
+		{Name: "x"},
+	}
+	t.Run("bulk.BulkImportGenres: success", func(t *testing.T) {
+		report, err := c.BulkImportGenres(contracts.NewAuthenticated(genresReq, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Created)
+		require.Equal(t, 1, report.Failed)
+		require.Len(t, report.Results, 3)
+		require.Equal(t, contracts.BulkStatusCreated, report.Results[0].Status)
+		require.NotEmpty(t, report.Results[0].ID)
+		require.Equal(t, contracts.BulkStatusFailed, report.Results[2].Status)
+		require.Equal(t, 2, report.Results[2].Index)
+		require.NotEmpty(t, report.Results[2].Error)
+
+		genre, err := c.GetGenreById(report.Results[1].ID)
+		require.NoError(t, err)
+		require.Equal(t, "space-western", genre.Slug)
+	})
+	t.Run("bulk.BulkImportGenres: idempotent", func(t *testing.T) {
+		report, err := c.BulkImportGenres(contracts.NewAuthenticated(genresReq[:2], johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 0, report.Created)
+		require.Equal(t, 2, report.Existing)
+	})
+
+	starsReq := []*contracts.BulkStar{
+		{
+			CreateStarRequest: contracts.CreateStarRequest{
+				FirstName: "Harrison",
+				LastName:  "Ford",
+				BirthDate: time.Date(1942, time.July, 13, 0, 0, 0, 0, time.UTC),
+			},
+			ExternalIDs: map[string]string{"imdb": "nm0000148"},
+		},
+		{
+			CreateStarRequest: contracts.CreateStarRequest{
+				FirstName: "Carrie",
+				LastName:  "Fisher",
+				BirthDate: time.Date(1956, time.October, 21, 0, 0, 0, 0, time.UTC),
+			},
+			ExternalIDs: map[string]string{"IMDb": "nm0000402"},
+		},
+		{
+			CreateStarRequest: contracts.CreateStarRequest{
+				FirstName: "Peter",
+				LastName:  "Mayhew",
+			},
+		},
+	}
+	var ford *contracts.BulkResult
+	t.Run("bulk.BulkImportStars: success", func(t *testing.T) {
+		report, err := c.BulkImportStars(contracts.NewAuthenticated(starsReq, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Created)
+		require.Equal(t, 1, report.Failed)
+		ford = report.Results[0]
+
+		star, err := c.GetStarByID(ford.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Ford", star.LastName)
+	})
+	t.Run("bulk.BulkImportStars: idempotent", func(t *testing.T) {
+		report, err := c.BulkImportStars(contracts.NewAuthenticated(starsReq[:2], johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Existing)
+		require.Equal(t, ford.ID, report.Results[0].ID)
+	})
+
+	moviesReq := []*contracts.CreateMovieRequest{
+		{
+			Title:       "Raiders of the Lost Ark",
+			ReleaseDate: time.Date(1981, time.June, 12, 0, 0, 0, 0, time.UTC),
+			ExternalIDs: map[string]string{"imdb": "tt0082971"},
+			Cast:        []*contracts.MovieCreditInfo{{StarID: ford.ID, Role: "actor"}},
+		},
+		{
+			Title:       "Blade Runner",
+			ReleaseDate: time.Date(1982, time.June, 25, 0, 0, 0, 0, time.UTC),
+			ExternalIDs: map[string]string{"imdb": "tt0083658"},
+			Genres:      []int{1000},
+		},
+		{
+			Title:       "Star Wars",
+			ReleaseDate: starWars.ReleaseDate,
+			ExternalIDs: map[string]string{"imdb": "tt0076759"},
+		},
+	}
+	t.Run("bulk.BulkImportMovies: success", func(t *testing.T) {
+		report, err := c.BulkImportMovies(contracts.NewAuthenticated(moviesReq, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Created)
+		require.Equal(t, 1, report.Existing)
+		require.Equal(t, 1, report.Failed)
+		require.Equal(t, contracts.BulkStatusFailed, report.Results[1].Status)
+		require.Equal(t, starWars.ID, report.Results[2].ID)
+
+		movie, err := c.GetMovieByID(report.Results[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, "Raiders of the Lost Ark", movie.Title)
+		require.Len(t, movie.Cast, 1)
+	})
+	t.Run("bulk.BulkImportMovies: idempotent", func(t *testing.T) {
+		report, err := c.BulkImportMovies(contracts.NewAuthenticated(moviesReq, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 0, report.Created)
+		require.Equal(t, 2, report.Existing)
+		require.Equal(t, 1, report.Failed)
+	})
+	t.Run("bulk.BulkImportMovies: too many items", func(t *testing.T) {
+		req := make([]*contracts.CreateMovieRequest, 11)
+		for i := range req {
+			req[i] = moviesReq[0]
+		}
+		_, err := c.BulkImportMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "too many items")
+	})
+	t.Run("bulk.BulkImportGenres: insufficient permissions", func(t *testing.T) {
+		_, err := c.BulkImportGenres(contracts.NewAuthenticated(genresReq, userToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func collectionsAPIChecks(t *testing.T, c *client.Client) {
+	user := RegisterRandomUser(t, c)
+	userToken := login(t, c, user.Email, standardPassword)
+
+	var collection *contracts.CollectionDetails
+
+	t.Run("collections.CreateCollection: success", func(t *testing.T) {
+		req := &contracts.CreateCollectionRequest{
+			Name:        "Epic sagas",
+			Description: "Long running epics",
+			Movies:      []int{starWars.ID, lordOfTheRing.ID},
+		}
+		res, err := c.CreateCollection(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, res.ID)
+		require.Equal(t, req.Name, res.Name)
+		require.Equal(t, 2, res.MovieCount)
+		require.Len(t, res.Movies, 2)
+		require.Equal(t, starWars.ID, res.Movies[0].ID)
+		require.Equal(t, lordOfTheRing.ID, res.Movies[1].ID)
+		require.NotNil(t, res.AvgRating)
+		require.NotZero(t, res.RatingCount)
+		collection = res
+	})
+	t.Run("collections.CreateCollection: insufficient permissions", func(t *testing.T) {
+		req := &contracts.CreateCollectionRequest{Name: "Mine"}
+		_, err := c.CreateCollection(contracts.NewAuthenticated(req, userToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+	t.Run("collections.CreateCollection: existing name", func(t *testing.T) {
+		req := &contracts.CreateCollectionRequest{Name: collection.Name}
+		_, err := c.CreateCollection(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistError(t, err, "collection", "name", req.Name)
+	})
+	t.Run("collections.CreateCollection: movie not found", func(t *testing.T) {
+		notExistingId := 1000
+		req := &contracts.CreateCollectionRequest{Name: "Unknown", Movies: []int{notExistingId}}
+		_, err := c.CreateCollection(contracts.NewAuthenticated(req, johnDoeToken))
+		requireNotFoundError(t, err, "movie", "id", notExistingId)
+	})
+	t.Run("collections.GetCollectionByID: success", func(t *testing.T) {
+		res, err := c.GetCollectionByID(collection.ID)
+		require.NoError(t, err)
+		require.Equal(t, collection, res)
+	})
+	t.Run("movies.GetMovieByID: collection info", func(t *testing.T) {
+		movie, err := c.GetMovieByID(lordOfTheRing.ID)
+		require.NoError(t, err)
+		require.Equal(t, []*contracts.MovieCollection{{ID: collection.ID, Name: collection.Name, OrderNo: 1}}, movie.Collections)
+	})
+	t.Run("collections.UpdateCollection: reorder", func(t *testing.T) {
+		req := &contracts.UpdateCollectionRequest{
+			ID:     collection.ID,
+			Name:   collection.Name,
+			Movies: []int{lordOfTheRing.ID},
+		}
+		res, err := c.UpdateCollection(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 1, res.MovieCount)
+		require.Len(t, res.Movies, 1)
+		require.Equal(t, lordOfTheRing.ID, res.Movies[0].ID)
+
+		movie, err := c.GetMovieByID(starWars.ID)
+		require.NoError(t, err)
+		require.Empty(t, movie.Collections)
+	})
+	t.Run("collections.GetCollections: success", func(t *testing.T) {
+		res, err := c.GetCollections(&contracts.GetCollectionsRequest{})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, collection.ID, res.Items[0].ID)
+	})
+	t.Run("collections.DeleteCollection: success", func(t *testing.T) {
+		req := &contracts.GetOrDeleteCollectionRequest{ID: collection.ID}
+		err := c.DeleteCollection(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		_, err = c.GetCollectionByID(collection.ID)
+		requireNotFoundError(t, err, "collection", "id", collection.ID)
+	})
+}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/config"
@@ -8,6 +10,10 @@ import (
 
 const testPaginationSize = 2
 
+// deletionGracePeriod is short enough for the purge of deleted accounts to be awaited,
+// and long enough for the restore checks run right after the deletion.
+const deletionGracePeriod = 3 * time.Second
+
 func getConfig(pgConnString string) *config.Config {
 	return &config.Config{
 		DbUrl: pgConnString,
@@ -25,6 +31,83 @@ func getConfig(pgConnString string) *config.Config {
 			DefaultSize: testPaginationSize,
 			MaxSize:     50,
 		},
+		Recommendations: config.RecommendationsConfig{
+			MinSupport:     2,
+			Neighbors:      50,
+			MinLikedRating: 7,
+			GenreWeight:    1,
+			StarWeight:     2,
+		},
+		SimilarMovies: config.SimilarMoviesConfig{
+			GenreWeight: 1,
+			CastWeight:  1,
+			RoleWeights: map[string]float64{"director": 3, "writer": 2, "actor": 1.5},
+			EraWeight:   1,
+			EraYears:    10,
+			TextWeight:  2,
+		},
+		Images: config.ImagesConfig{
+			Dir:     filepath.Join(os.TempDir(), "movie-reviews-images"),
+			BaseURL: "/images",
+			MaxSize: 1 << 20,
+		},
+		Bulk: config.BulkConfig{
+			ChunkSize: 2,
+			MaxItems:  10,
+		},
+		Exports: config.ExportsConfig{
+			Dir:            filepath.Join(os.TempDir(), "movie-reviews-exports"),
+			Secret:         "exports-secret",
+			LinkExpiration: time.Hour,
+		},
+		Jobs: config.JobsConfig{
+			Workers:      2,
+			PollInterval: 100 * time.Millisecond,
+			LockTimeout:  time.Minute,
+			MaxAttempts:  2,
+			RetryBackoff: 100 * time.Millisecond,
+		},
+		Users: config.UsersConfig{
+			DeletionGracePeriod: deletionGracePeriod,
+		},
+		Notifications: config.NotificationsConfig{
+			WebhookTimeout:       time.Second,
+			AllowPrivateWebhooks: true,
+		},
+		Webhooks: config.WebhooksConfig{
+			PollInterval:   100 * time.Millisecond,
+			BatchSize:      10,
+			RequestTimeout: time.Second,
+			MaxAttempts:    2,
+			RetryBackoff:   100 * time.Millisecond,
+			LockTimeout:    time.Minute,
+		},
+		Outbox: config.OutboxConfig{
+			PollInterval:    50 * time.Millisecond,
+			BatchSize:       10,
+			LockTimeout:     time.Minute,
+			RetryBackoff:    100 * time.Millisecond,
+			MaxBackoff:      time.Second,
+			MaxAttempts:     20,
+			Retention:       time.Hour,
+			CleanupInterval: time.Hour,
+		},
+		Live: config.LiveConfig{
+			HeartbeatInterval: 200 * time.Millisecond,
+			ClientRetry:       time.Second,
+			BufferSize:        16,
+			ReplayLimit:       100,
+			ReplayWindow:      time.Second,
+			Retention:         time.Hour,
+			CleanupInterval:   time.Hour,
+			ListenRetry:       100 * time.Millisecond,
+		},
+		GraphQL: config.GraphQLConfig{
+			MaxDepth:      5,
+			MaxComplexity: 200,
+			MaxListSize:   20,
+			MaxBodySize:   "64K",
+		},
 		Local:    true,
 		LogLevel: "error",
 	}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func exportsAPIChecks(t *testing.T, c *client.Client) {
+	user := RegisterRandomUser(t, c)
+	userToken := login(t, c, user.Email, standardPassword)
+
+	var genresExport *contracts.Export
+	t.Run("exports.ExportCatalog: csv", func(t *testing.T) {
+		req := &contracts.ExportCatalogRequest{Kind: contracts.ExportKindGenres, Format: contracts.ExportFormatCSV}
+		job, err := c.ExportCatalog(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, contracts.JobStatusQueued, job.Status)
+
+		genresExport = waitForExport(t, c, job.ID, johnDoeToken)
+		content, err := c.DownloadExport(genresExport.DownloadURL)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		require.Equal(t, "id,name,slug,parent_id,description", lines[0])
+		require.Greater(t, len(lines), 1)
+	})
+	t.Run("exports.ExportCatalog: ndjson", func(t *testing.T) {
+		req := &contracts.ExportCatalogRequest{Kind: contracts.ExportKindMovies, Format: contracts.ExportFormatNDJSON}
+		job, err := c.ExportCatalog(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		export := waitForExport(t, c, job.ID, johnDoeToken)
+		content, err := c.DownloadExport(export.DownloadURL)
+		require.NoError(t, err)
+
+		titles := make(map[string]bool)
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			var movie struct {
+				Title string `json:"title"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &movie))
+			titles[movie.Title] = true
+		}
+		require.True(t, titles[starWars.Title])
+	})
+	t.Run("exports.ExportCatalog: unsupported format", func(t *testing.T) {
+		req := &contracts.ExportCatalogRequest{Kind: contracts.ExportKindMovies, Format: "xml"}
+		_, err := c.ExportCatalog(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, `unsupported export format "xml"`)
+	})
+	t.Run("exports.ExportCatalog: insufficient permissions", func(t *testing.T) {
+		req := &contracts.ExportCatalogRequest{Kind: contracts.ExportKindMovies, Format: contracts.ExportFormatJSON}
+		_, err := c.ExportCatalog(contracts.NewAuthenticated(req, userToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+	t.Run("exports.DownloadExport: invalid signature", func(t *testing.T) {
+		downloadURL := strings.Replace(genresExport.DownloadURL, "signature=", "signature=0", 1)
+		_, err := c.DownloadExport(downloadURL)
+		requireForbiddenError(t, err, "invalid download link")
+	})
+	t.Run("exports.ExportUserData: success", func(t *testing.T) {
+		req := &contracts.ExportUserDataRequest{UserID: user.ID}
+		job, err := c.ExportUserData(contracts.NewAuthenticated(req, userToken))
+		require.NoError(t, err)
+
+		export := waitForExport(t, c, job.ID, userToken)
+		content, err := c.DownloadExport(export.DownloadURL)
+		require.NoError(t, err)
+
+		archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		files := make(map[string]*zip.File)
+		for _, file := range archive.File {
+			files[file.Name] = file
+		}
+		require.Contains(t, files, "reviews.json")
+		require.Contains(t, files, "lists.json")
+
+		r, err := files["profile.json"].Open()
+		require.NoError(t, err)
+		defer r.Close()
+		var profile struct {
+			Email string `json:"email"`
+		}
+		require.NoError(t, json.NewDecoder(r).Decode(&profile))
+		require.Equal(t, user.Email, profile.Email)
+	})
+	t.Run("exports.ExportUserData: another user", func(t *testing.T) {
+		req := &contracts.ExportUserDataRequest{UserID: johnDoe.ID}
+		_, err := c.ExportUserData(contracts.NewAuthenticated(req, userToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+}
+
+func waitForExport(t *testing.T, c *client.Client, jobID int, token string) *contracts.Export {
+	var job *contracts.Job
+	retry.Run(t, func(r *retry.R) {
+		var err error
+		job, err = c.GetJobByID(contracts.NewAuthenticated(&contracts.GetJobRequest{ID: jobID}, token))
+		require.NoError(r, err)
+		require.Equal(r, contracts.JobStatusSucceeded, job.Status)
+	})
+
+	var export contracts.Export
+	require.NoError(t, json.Unmarshal(job.Result, &export))
+	require.NotEmpty(t, export.DownloadURL)
+	return &export
+}
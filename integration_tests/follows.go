@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func followsAPIChecks(t *testing.T, c *client.Client) {
+	follower := RegisterRandomUser(t, c)
+	followerToken := login(t, c, follower.Email, standardPassword)
+	author := RegisterRandomUser(t, c)
+	authorToken := login(t, c, author.Email, standardPassword)
+
+	t.Run("follows.Follow: success", func(t *testing.T) {
+		req := &contracts.FollowRequest{UserID: follower.ID, FolloweeID: author.ID}
+		follow, err := c.Follow(contracts.NewAuthenticated(req, followerToken))
+		require.NoError(t, err)
+		require.Equal(t, author.ID, follow.User.ID)
+		require.Equal(t, author.Username, follow.User.Username)
+		require.NotEmpty(t, follow.FollowedAt)
+	})
+	t.Run("follows.Follow: already exists", func(t *testing.T) {
+		req := &contracts.FollowRequest{UserID: follower.ID, FolloweeID: author.ID}
+		_, err := c.Follow(contracts.NewAuthenticated(req, followerToken))
+		requireAlreadyExistError(t, err, "follow", "user_id", author.ID)
+	})
+	t.Run("follows.Follow: self", func(t *testing.T) {
+		req := &contracts.FollowRequest{UserID: follower.ID, FolloweeID: follower.ID}
+		_, err := c.Follow(contracts.NewAuthenticated(req, followerToken))
+		requireBadRequestError(t, err, "users cannot follow themselves")
+	})
+	t.Run("follows.Follow: user not found", func(t *testing.T) {
+		notExistingID := 1000
+		req := &contracts.FollowRequest{UserID: follower.ID, FolloweeID: notExistingID}
+		_, err := c.Follow(contracts.NewAuthenticated(req, followerToken))
+		requireNotFoundError(t, err, "user", "id", notExistingID)
+	})
+	t.Run("follows.GetFollowers: success", func(t *testing.T) {
+		res, err := c.GetFollowers(&contracts.GetFollowsRequest{UserID: author.ID})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, follower.ID, res.Items[0].User.ID)
+
+		res, err = c.GetFollowing(&contracts.GetFollowsRequest{UserID: follower.ID})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, author.ID, res.Items[0].User.ID)
+	})
+	t.Run("feed.GetFeed: success", func(t *testing.T) {
+		listReq := &contracts.CreateListRequest{UserID: author.ID, Name: "Weekend picks"}
+		list, err := c.CreateList(contracts.NewAuthenticated(listReq, authorToken))
+		require.NoError(t, err)
+
+		reviewReq := &contracts.CreateReviewRequest{
+			MovieID: lordOfTheRing.ID,
+			UserID:  author.ID,
+			Rating:  9,
+			Title:   "Still great",
+			Content: "Holds up remarkably well after all these years",
+		}
+		review, err := c.CreateReview(contracts.NewAuthenticated(reviewReq, authorToken))
+		require.NoError(t, err)
+
+		res, err := c.GetFeed(contracts.NewAuthenticated(&contracts.GetFeedRequest{}, followerToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, res.Total)
+
+		require.Equal(t, "review", res.Items[0].Type)
+		require.Equal(t, review.ID, res.Items[0].SubjectID)
+		require.Equal(t, author.ID, res.Items[0].User.ID)
+		require.Equal(t, lordOfTheRing.ID, res.Items[0].Movie.ID)
+		require.Equal(t, 9, *res.Items[0].Rating)
+
+		require.Equal(t, "list", res.Items[1].Type)
+		require.Equal(t, list.ID, res.Items[1].SubjectID)
+		require.Nil(t, res.Items[1].Movie)
+	})
+	t.Run("feed.GetFeed: unauthenticated", func(t *testing.T) {
+		_, err := c.GetFeed(contracts.NewAuthenticated(&contracts.GetFeedRequest{}, ""))
+		requireUnauthorizedError(t, err, "invalid or missing token")
+	})
+	t.Run("follows.Unfollow: success", func(t *testing.T) {
+		req := &contracts.UnfollowRequest{UserID: follower.ID, FolloweeID: author.ID}
+		err := c.Unfollow(contracts.NewAuthenticated(req, followerToken))
+		require.NoError(t, err)
+
+		res, err := c.GetFeed(contracts.NewAuthenticated(&contracts.GetFeedRequest{}, followerToken))
+		require.NoError(t, err)
+		require.Equal(t, 0, res.Total)
+	})
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/RadkevichAnn/movie-reviews/client"
@@ -43,6 +44,7 @@ func GenresApiChecks(t *testing.T, c *client.Client) {
 			*cc.addr = g
 			require.NotEmpty(t, g.ID)
 			require.Equal(t, req.Name, g.Name)
+			require.Equal(t, strings.ToLower(req.Name), g.Slug)
 		}
 	})
 	t.Run("genres.CreateGenre: short name", func(t *testing.T) {
@@ -104,6 +106,50 @@ func GenresApiChecks(t *testing.T, c *client.Client) {
 		Spooky = getGenre(t, c, Spooky.ID)
 		require.Nil(t, Spooky)
 	})
+	t.Run("genres.CreateGenre: hierarchy", func(t *testing.T) {
+		req := &contracts.CreateGenreRequest{
+			Name:        "Speculative",
+			Description: contracts.Ptr("Fiction about worlds unlike our own"),
+		}
+		speculative, err := c.CreateGenre(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Nil(t, speculative.ParentID)
+
+		req = &contracts.CreateGenreRequest{
+			Name:     "Sci-Fi",
+			ParentID: &speculative.ID,
+		}
+		sciFi, err := c.CreateGenre(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, &speculative.ID, sciFi.ParentID)
+		require.Equal(t, "sci-fi", sciFi.Slug)
+		require.Equal(t, sciFi, getGenre(t, c, sciFi.ID))
+
+		updateReq := &contracts.UpdateGenreRequest{
+			GenreId:  speculative.ID,
+			Name:     speculative.Name,
+			ParentID: &sciFi.ID,
+		}
+		err = c.UpdateGenre(contracts.NewAuthenticated(updateReq, johnDoeToken))
+		requireBadRequestError(t, err, "cannot be a parent")
+	})
+	t.Run("genres.CreateGenre: invalid slug", func(t *testing.T) {
+		req := &contracts.CreateGenreRequest{
+			Name: "Western",
+			Slug: "Wild West",
+		}
+		_, err := c.CreateGenre(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "slug")
+	})
+	t.Run("genres.CreateGenre: parent not found", func(t *testing.T) {
+		nonExistingId := 1000
+		req := &contracts.CreateGenreRequest{
+			Name:     "Western",
+			ParentID: &nonExistingId,
+		}
+		_, err := c.CreateGenre(contracts.NewAuthenticated(req, johnDoeToken))
+		requireNotFoundError(t, err, "genre", "id", nonExistingId)
+	})
 }
 
 func getGenre(t *testing.T, c *client.Client, id int) *contracts.Genre {
@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func graphqlAPIChecks(t *testing.T, c *client.Client) {
+	user := RegisterRandomUser(t, c)
+	userToken := login(t, c, user.Email, standardPassword)
+	review := createReview(t, c, user, starWars.ID)
+
+	t.Run("graphql: movie with genres, cast and reviews", func(t *testing.T) {
+		expected, err := c.GetMovieByID(starWars.ID)
+		require.NoError(t, err)
+
+		var data struct {
+			Movie struct {
+				ID     int    `json:"id"`
+				Title  string `json:"title"`
+				Genres []struct {
+					Name string `json:"name"`
+				} `json:"genres"`
+				Cast []struct {
+					Role string `json:"role"`
+					Star struct {
+						ID int `json:"id"`
+					} `json:"star"`
+				} `json:"cast"`
+				Reviews []struct {
+					ID   int `json:"id"`
+					User struct {
+						Username string  `json:"username"`
+						Email    *string `json:"email"`
+					} `json:"user"`
+				} `json:"reviews"`
+			} `json:"movie"`
+		}
+		runGraphQL(t, c, "", `query Movie($id: Int!) {
+			movie(id: $id) {
+				id title
+				genres { name }
+				cast { role star { id } }
+				reviews(first: 1) { id user { username email } }
+			}
+		}`, map[string]any{"id": starWars.ID}, &data)
+
+		require.Equal(t, expected.ID, data.Movie.ID)
+		require.Equal(t, expected.Title, data.Movie.Title)
+		require.Len(t, data.Movie.Genres, len(expected.Genres))
+		for i, genre := range expected.Genres {
+			require.Equal(t, genre.Name, data.Movie.Genres[i].Name)
+		}
+		require.Len(t, data.Movie.Cast, len(expected.Cast))
+		for i, credit := range expected.Cast {
+			require.Equal(t, credit.Role, data.Movie.Cast[i].Role)
+			require.Equal(t, credit.Star.ID, data.Movie.Cast[i].Star.ID)
+		}
+		require.Len(t, data.Movie.Reviews, 1)
+		require.Equal(t, review.ID, data.Movie.Reviews[0].ID)
+		require.Equal(t, user.Username, data.Movie.Reviews[0].User.Username)
+		require.Nil(t, data.Movie.Reviews[0].User.Email)
+	})
+	t.Run("graphql: page of movies", func(t *testing.T) {
+		var data struct {
+			Movies []struct {
+				ID     int `json:"id"`
+				Genres []struct {
+					ID int `json:"id"`
+				} `json:"genres"`
+			} `json:"movies"`
+		}
+		runGraphQL(t, c, "", `{ movies(size: 2) { id genres { id } } }`, nil, &data)
+		require.Len(t, data.Movies, 2)
+	})
+	t.Run("graphql: missing movie", func(t *testing.T) {
+		var data struct {
+			Movie *struct {
+				ID int `json:"id"`
+			} `json:"movie"`
+		}
+		runGraphQL(t, c, "", `{ movie(id: 1000000) { id } }`, nil, &data)
+		require.Nil(t, data.Movie)
+	})
+	t.Run("graphql: me", func(t *testing.T) {
+		var data struct {
+			Me *struct {
+				ID    int     `json:"id"`
+				Email *string `json:"email"`
+			} `json:"me"`
+		}
+		runGraphQL(t, c, "", `{ me { id email } }`, nil, &data)
+		require.Nil(t, data.Me)
+
+		runGraphQL(t, c, userToken, `{ me { id email } }`, nil, &data)
+		require.NotNil(t, data.Me)
+		require.Equal(t, user.ID, data.Me.ID)
+		require.Equal(t, user.Email, *data.Me.Email)
+	})
+	t.Run("graphql: invalid list size", func(t *testing.T) {
+		req := &contracts.GraphQLRequest{Query: `{ movies(size: 30) { id } genres { id } }`}
+		res, err := c.GraphQL(contracts.NewAuthenticated(req, ""))
+		require.NoError(t, err)
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, "size must be between 1 and 20", res.Errors[0].Message)
+		require.Equal(t, []any{"movies"}, res.Errors[0].Path)
+
+		var data struct {
+			Movies []any `json:"movies"`
+			Genres []any `json:"genres"`
+		}
+		require.NoError(t, json.Unmarshal(res.Data, &data))
+		require.Nil(t, data.Movies)
+		require.NotEmpty(t, data.Genres)
+	})
+	t.Run("graphql: depth limit", func(t *testing.T) {
+		req := &contracts.GraphQLRequest{Query: `{ movie(id: 1) { reviews { movie { reviews { movie { id } } } } } }`}
+		_, err := c.GraphQL(contracts.NewAuthenticated(req, ""))
+		requireBadRequestError(t, err, "query depth exceeds the limit of 5")
+	})
+	t.Run("graphql: nesting limit", func(t *testing.T) {
+		query := strings.Repeat("{ ... ", 100) + "{ genres { id } }" + strings.Repeat(" }", 100)
+		req := &contracts.GraphQLRequest{Query: query}
+		_, err := c.GraphQL(contracts.NewAuthenticated(req, ""))
+		requireBadRequestError(t, err, "query depth exceeds the limit of 5")
+	})
+	t.Run("graphql: complexity limit", func(t *testing.T) {
+		req := &contracts.GraphQLRequest{Query: `{ movies(size: 20) { reviews(first: 20) { id } } }`}
+		_, err := c.GraphQL(contracts.NewAuthenticated(req, ""))
+		requireBadRequestError(t, err, "query complexity 421 exceeds the limit of 200")
+	})
+	t.Run("graphql: fragment bomb", func(t *testing.T) {
+		// Every fragment spreads the next one under 50 aliases, expanding to 50^4 fields
+		query := `{ movie(id: 1) { ...F0 } }`
+		for i, typeCondition := range []string{"Movie", "Review", "Movie", "Review"} {
+			var fields []string
+			for j := 0; j < 50; j++ {
+				switch {
+				case i == 3:
+					fields = append(fields, fmt.Sprintf("a%d: id", j))
+				case typeCondition == "Movie":
+					fields = append(fields, fmt.Sprintf("a%d: reviews { ...F%d }", j, i+1))
+				default:
+					fields = append(fields, fmt.Sprintf("a%d: movie { ...F%d }", j, i+1))
+				}
+			}
+			query += fmt.Sprintf(" fragment F%d on %s { %s }", i, typeCondition, strings.Join(fields, " "))
+		}
+
+		start := time.Now()
+		req := &contracts.GraphQLRequest{Query: query}
+		_, err := c.GraphQL(contracts.NewAuthenticated(req, ""))
+		requireBadRequestError(t, err, "query complexity exceeds the limit of 200")
+		require.Less(t, time.Since(start), time.Second)
+	})
+	t.Run("graphql: invalid query", func(t *testing.T) {
+		for query, msg := range map[string]string{
+			`{ movie(id: 1) { id `:             "syntax error",
+			`{ movie(id: 1) { budget } }`:      `cannot query field "budget" on type Movie`,
+			`{ movie { id } }`:                 `argument "id" of field Query.movie is required`,
+			`mutation { movie(id: 1) { id } }`: "mutation operations are not supported",
+		} {
+			req := &contracts.GraphQLRequest{Query: query}
+			_, err := c.GraphQL(contracts.NewAuthenticated(req, ""))
+			requireBadRequestError(t, err, msg)
+		}
+	})
+}
+
+// runGraphQL executes the query, requiring it to succeed, and decodes its data.
+func runGraphQL(t *testing.T, c *client.Client, accessToken, query string, variables map[string]any, data any) {
+	req := &contracts.GraphQLRequest{Query: query, Variables: variables}
+	res, err := c.GraphQL(contracts.NewAuthenticated(req, accessToken))
+	require.NoError(t, err)
+	require.Empty(t, res.Errors, fmt.Sprintf("%+v", res.Errors))
+	require.NoError(t, json.Unmarshal(res.Data, data))
+}
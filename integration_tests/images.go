@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func imagesAPIChecks(t *testing.T, c *client.Client) {
+	content := newPNG(t, 800, 1200)
+
+	t.Run("movies.UploadPoster: success", func(t *testing.T) {
+		req := &contracts.UploadImageRequest{ID: starWars.ID, FileName: "poster.png", Content: content}
+		img, err := c.UploadMoviePoster(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, img.ID)
+		require.Len(t, img.URLs, 4)
+		require.Contains(t, img.URLs, "original")
+
+		movie, err := c.GetMovieByID(starWars.ID)
+		require.NoError(t, err)
+		require.Equal(t, img, movie.Poster)
+	})
+	t.Run("movies.UploadPoster: unsupported type", func(t *testing.T) {
+		req := &contracts.UploadImageRequest{ID: starWars.ID, FileName: "poster.txt", Content: []byte("not an image")}
+		_, err := c.UploadMoviePoster(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "unsupported image type")
+	})
+	t.Run("movies.UploadPoster: not found", func(t *testing.T) {
+		notExistingId := 1000
+		req := &contracts.UploadImageRequest{ID: notExistingId, FileName: "poster.png", Content: content}
+		_, err := c.UploadMoviePoster(contracts.NewAuthenticated(req, johnDoeToken))
+		requireNotFoundError(t, err, "movie", "id", notExistingId)
+	})
+	t.Run("stars.UploadHeadshot: success", func(t *testing.T) {
+		req := &contracts.UploadImageRequest{ID: hamill.ID, FileName: "headshot.png", Content: content}
+		img, err := c.UploadStarHeadshot(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		star, err := c.GetStarByID(hamill.ID)
+		require.NoError(t, err)
+		require.Equal(t, img, star.Headshot)
+	})
+	t.Run("stars.UploadHeadshot: insufficient permissions", func(t *testing.T) {
+		user := RegisterRandomUser(t, c)
+		userToken := login(t, c, user.Email, standardPassword)
+
+		req := &contracts.UploadImageRequest{ID: hamill.ID, FileName: "headshot.png", Content: content}
+		_, err := c.UploadStarHeadshot(contracts.NewAuthenticated(req, userToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+}
+
+func newPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
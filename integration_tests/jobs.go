@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func jobsAPIChecks(t *testing.T, c *client.Client) {
+	user := RegisterRandomUser(t, c)
+	userToken := login(t, c, user.Email, standardPassword)
+
+	var job *contracts.Job
+	t.Run("jobs.EnqueueJob: success", func(t *testing.T) {
+		req := &contracts.EnqueueJobRequest{
+			Type:    "ratings.reconcile",
+			Payload: map[string]any{"dry_run": true},
+		}
+		var err error
+		job, err = c.EnqueueJob(contracts.NewAuthenticated(req, adminToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, job.ID)
+		require.Equal(t, contracts.JobStatusQueued, job.Status)
+		require.JSONEq(t, `{"dry_run": true}`, string(job.Payload))
+	})
+	t.Run("jobs.GetJobByID: succeeded", func(t *testing.T) {
+		req := &contracts.GetJobRequest{ID: job.ID}
+		retry.Run(t, func(r *retry.R) {
+			res, err := c.GetJobByID(contracts.NewAuthenticated(req, adminToken))
+			require.NoError(r, err)
+			require.Equal(r, contracts.JobStatusSucceeded, res.Status)
+			job = res
+		})
+		require.Equal(t, 100, job.Progress)
+		require.Equal(t, 1, job.Attempts)
+		require.NotNil(t, job.FinishedAt)
+
+		var result struct {
+			Drifts []any `json:"drifts"`
+		}
+		require.NoError(t, json.Unmarshal(job.Result, &result))
+		require.Empty(t, result.Drifts)
+	})
+	t.Run("jobs.GetJobByID: not the owner", func(t *testing.T) {
+		req := &contracts.GetJobRequest{ID: job.ID}
+		_, err := c.GetJobByID(contracts.NewAuthenticated(req, userToken))
+		requireNotFoundError(t, err, "job", "id", job.ID)
+	})
+	t.Run("jobs.GetJobByID: not found", func(t *testing.T) {
+		req := &contracts.GetJobRequest{ID: 1000}
+		_, err := c.GetJobByID(contracts.NewAuthenticated(req, adminToken))
+		requireNotFoundError(t, err, "job", "id", 1000)
+	})
+	t.Run("jobs.EnqueueJob: unknown type", func(t *testing.T) {
+		req := &contracts.EnqueueJobRequest{Type: "unknown"}
+		_, err := c.EnqueueJob(contracts.NewAuthenticated(req, adminToken))
+		requireBadRequestError(t, err, `unknown job type "unknown"`)
+	})
+	t.Run("jobs.EnqueueJob: system type", func(t *testing.T) {
+		req := &contracts.EnqueueJobRequest{Type: "users.purge", Payload: map[string]any{"user_id": 1}}
+		_, err := c.EnqueueJob(contracts.NewAuthenticated(req, adminToken))
+		requireBadRequestError(t, err, `unknown job type "users.purge"`)
+	})
+	t.Run("jobs.EnqueueJob: insufficient permissions", func(t *testing.T) {
+		req := &contracts.EnqueueJobRequest{Type: "ratings.reconcile"}
+		_, err := c.EnqueueJob(contracts.NewAuthenticated(req, userToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func listsAPIChecks(t *testing.T, c *client.Client) {
+	owner := RegisterRandomUser(t, c)
+	ownerToken := login(t, c, owner.Email, standardPassword)
+	other := RegisterRandomUser(t, c)
+	otherToken := login(t, c, other.Email, standardPassword)
+
+	var publicList, privateList *contracts.ListDetails
+	t.Run("lists.CreateList: success", func(t *testing.T) {
+		req := &contracts.CreateListRequest{
+			UserID:      owner.ID,
+			Name:        "Best fantasy",
+			Description: "Movies to rewatch every winter",
+			Items: []*contracts.ListItemInfo{
+				{MovieID: lordOfTheRing.ID, Notes: "extended edition"},
+				{MovieID: starWars.ID},
+			},
+		}
+		list, err := c.CreateList(contracts.NewAuthenticated(req, ownerToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, list.ID)
+		require.True(t, list.IsPublic)
+		require.Len(t, list.Items, 2)
+		require.Equal(t, lordOfTheRing.ID, list.Items[0].Movie.ID)
+		require.Equal(t, "extended edition", list.Items[0].Notes)
+		require.Equal(t, starWars.ID, list.Items[1].Movie.ID)
+		publicList = list
+
+		req = &contracts.CreateListRequest{
+			UserID:   owner.ID,
+			Name:     "Guilty pleasures",
+			IsPublic: contracts.Ptr(false),
+			Items:    []*contracts.ListItemInfo{{MovieID: lordOfTheRing.ID}},
+		}
+		privateList, err = c.CreateList(contracts.NewAuthenticated(req, ownerToken))
+		require.NoError(t, err)
+		require.False(t, privateList.IsPublic)
+	})
+	t.Run("lists.CreateList: movie not found", func(t *testing.T) {
+		notExistingID := 1000
+		req := &contracts.CreateListRequest{
+			UserID: owner.ID,
+			Name:   "Broken",
+			Items:  []*contracts.ListItemInfo{{MovieID: notExistingID}},
+		}
+		_, err := c.CreateList(contracts.NewAuthenticated(req, ownerToken))
+		requireNotFoundError(t, err, "movie", "id", notExistingID)
+	})
+	t.Run("lists.CreateList: another user", func(t *testing.T) {
+		req := &contracts.CreateListRequest{UserID: owner.ID, Name: "Not mine"}
+		_, err := c.CreateList(contracts.NewAuthenticated(req, otherToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+	t.Run("lists.GetListByID: visibility", func(t *testing.T) {
+		list, err := c.GetListByID(publicList.ID)
+		require.NoError(t, err)
+		require.Equal(t, publicList, list)
+
+		_, err = c.GetListByID(privateList.ID)
+		requireNotFoundError(t, err, "list", "id", privateList.ID)
+
+		req := &contracts.GetListRequest{ListID: privateList.ID}
+		_, err = c.GetListByIDAuthenticated(contracts.NewAuthenticated(req, otherToken))
+		requireNotFoundError(t, err, "list", "id", privateList.ID)
+
+		list, err = c.GetListByIDAuthenticated(contracts.NewAuthenticated(req, ownerToken))
+		require.NoError(t, err)
+		require.Equal(t, privateList, list)
+	})
+	t.Run("lists.GetUserLists: visibility", func(t *testing.T) {
+		req := &contracts.GetUserListsRequest{UserID: owner.ID}
+		res, err := c.GetUserLists(req)
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, publicList.ID, res.Items[0].ID)
+
+		res, err = c.GetUserListsAuthenticated(contracts.NewAuthenticated(req, ownerToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, res.Total)
+	})
+	t.Run("lists.UpdateList: success", func(t *testing.T) {
+		req := &contracts.UpdateListRequest{
+			UserID:      owner.ID,
+			ListID:      publicList.ID,
+			Name:        "Best fantasy ever",
+			Description: publicList.Description,
+			IsPublic:    true,
+			Items: []*contracts.ListItemInfo{
+				{MovieID: starWars.ID, Notes: "original cut only"},
+				{MovieID: lordOfTheRing.ID, Notes: "extended edition"},
+			},
+		}
+		list, err := c.UpdateList(contracts.NewAuthenticated(req, ownerToken))
+		require.NoError(t, err)
+		require.Equal(t, req.Name, list.Name)
+		require.Len(t, list.Items, 2)
+		require.Equal(t, starWars.ID, list.Items[0].Movie.ID)
+		require.Equal(t, "original cut only", list.Items[0].Notes)
+		require.Equal(t, lordOfTheRing.ID, list.Items[1].Movie.ID)
+		publicList = list
+	})
+	t.Run("lists.LikeList: success", func(t *testing.T) {
+		req := &contracts.LikeListRequest{ListID: publicList.ID}
+		err := c.LikeList(contracts.NewAuthenticated(req, otherToken))
+		require.NoError(t, err)
+
+		err = c.LikeList(contracts.NewAuthenticated(req, otherToken))
+		requireAlreadyExistError(t, err, "list like", "(list_id,user_id)", fmt.Sprintf("(%d,%d)", publicList.ID, other.ID))
+
+		list, err := c.GetListByID(publicList.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, list.LikesCount)
+	})
+	t.Run("lists.LikeList: private list", func(t *testing.T) {
+		req := &contracts.LikeListRequest{ListID: privateList.ID}
+		err := c.LikeList(contracts.NewAuthenticated(req, otherToken))
+		requireNotFoundError(t, err, "list", "id", privateList.ID)
+	})
+	t.Run("lists.GetLists: filter by movie", func(t *testing.T) {
+		req := &contracts.GetListsRequest{MovieID: &starWars.ID, SortByLikes: contracts.Ptr("desc")}
+		res, err := c.GetLists(req)
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, publicList.ID, res.Items[0].ID)
+		require.Equal(t, 1, res.Items[0].LikesCount)
+	})
+	t.Run("lists.UnlikeList: success", func(t *testing.T) {
+		req := &contracts.LikeListRequest{ListID: publicList.ID}
+		err := c.UnlikeList(contracts.NewAuthenticated(req, otherToken))
+		require.NoError(t, err)
+
+		list, err := c.GetListByID(publicList.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, list.LikesCount)
+	})
+	t.Run("lists.DeleteList: success", func(t *testing.T) {
+		req := &contracts.DeleteListRequest{UserID: owner.ID, ListID: privateList.ID}
+		err := c.DeleteList(contracts.NewAuthenticated(req, ownerToken))
+		require.NoError(t, err)
+
+		_, err = c.GetListByIDAuthenticated(contracts.NewAuthenticated(&contracts.GetListRequest{ListID: privateList.ID}, ownerToken))
+		requireNotFoundError(t, err, "list", "id", privateList.ID)
+	})
+}
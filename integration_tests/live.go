@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func liveAPIChecks(t *testing.T, c *client.Client) {
+	movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+		Title:       "Live",
+		ReleaseDate: time.Date(2005, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}, johnDoeToken))
+	require.NoError(t, err)
+	user := RegisterRandomUser(t, c)
+
+	t.Run("live.StreamMovieEvents: not found", func(t *testing.T) {
+		notExistingID := 1000000
+		_, err := c.StreamMovieEvents(&contracts.StreamMovieEventsRequest{MovieID: notExistingID})
+		requireNotFoundError(t, err, "movie", "id", notExistingID)
+	})
+	t.Run("live.StreamEvents: insufficient permissions", func(t *testing.T) {
+		req := &contracts.StreamEventsRequest{}
+		_, err := c.StreamEvents(contracts.NewAuthenticated(req, login(t, c, user.Email, standardPassword)))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+
+	var lastEventID string
+	t.Run("live.StreamMovieEvents: new review and rating", func(t *testing.T) {
+		stream, err := c.StreamMovieEvents(&contracts.StreamMovieEventsRequest{MovieID: movie.ID})
+		require.NoError(t, err)
+		defer stream.Close()
+
+		review := createReview(t, c, user, movie.ID)
+
+		event := nextLiveEvent(t, stream)
+		require.Equal(t, contracts.WebhookEventReviewCreated, event.Type)
+		var data contracts.Review
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		require.Equal(t, review.ID, data.ID)
+
+		event = nextLiveEvent(t, stream)
+		require.Equal(t, contracts.LiveEventRatingChanged, event.Type)
+		var rating contracts.MovieRating
+		require.NoError(t, json.Unmarshal(event.Data, &rating))
+		require.Equal(t, movie.ID, rating.MovieID)
+		require.Equal(t, 1, rating.RatingCount)
+		require.Equal(t, float64(review.Rating), *rating.AvgRating)
+		lastEventID = event.ID
+	})
+	t.Run("live.StreamMovieEvents: resume after last event id", func(t *testing.T) {
+		req := &contracts.UpdateMovieRequest{
+			ID:          movie.ID,
+			Title:       "Live Again",
+			ReleaseDate: movie.ReleaseDate,
+		}
+		require.NoError(t, c.UpdateMovie(contracts.NewAuthenticated(req, johnDoeToken)))
+
+		// The update is relayed asynchronously, so it's either missed and replayed or received live.
+		// The review events preceding the last event id can be replayed as well.
+		stream, err := c.StreamMovieEvents(&contracts.StreamMovieEventsRequest{MovieID: movie.ID, LastEventID: lastEventID})
+		require.NoError(t, err)
+		defer stream.Close()
+
+		event := nextLiveEvent(t, stream)
+		for event.Type != contracts.WebhookEventMovieUpdated {
+			require.NotEqual(t, lastEventID, event.ID)
+			event = nextLiveEvent(t, stream)
+		}
+		var data contracts.Movie
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		require.Equal(t, req.Title, data.Title)
+	})
+	t.Run("live.StreamMovieEvents: review hidden by privacy", func(t *testing.T) {
+		author := RegisterRandomUser(t, c)
+		authorToken := login(t, c, author.Email, standardPassword)
+		req := &contracts.UpdateRequest{
+			UserId: author.ID,
+			Privacy: &contracts.Privacy{
+				Email:   contracts.VisibilityPublic,
+				Reviews: contracts.VisibilityFollowers,
+				Lists:   contracts.VisibilityPublic,
+			},
+		}
+		require.NoError(t, c.UpdateUser(contracts.NewAuthenticated(req, authorToken)))
+
+		stream, err := c.StreamMovieEvents(&contracts.StreamMovieEventsRequest{MovieID: movie.ID})
+		require.NoError(t, err)
+		defer stream.Close()
+
+		createReview(t, c, author, movie.ID)
+
+		// The review is hidden from the anonymous viewer, the rating it changed isn't
+		event := nextLiveEvent(t, stream)
+		require.Equal(t, contracts.LiveEventRatingChanged, event.Type)
+		var rating contracts.MovieRating
+		require.NoError(t, json.Unmarshal(event.Data, &rating))
+		require.Equal(t, 2, rating.RatingCount)
+	})
+	t.Run("live.StreamEvents: success", func(t *testing.T) {
+		stream, err := c.StreamEvents(contracts.NewAuthenticated(&contracts.StreamEventsRequest{}, johnDoeToken))
+		require.NoError(t, err)
+		defer stream.Close()
+
+		review := createReview(t, c, RegisterRandomUser(t, c), starWars.ID)
+		for {
+			event := nextLiveEvent(t, stream)
+			if event.Type != contracts.WebhookEventReviewCreated {
+				continue
+			}
+			var data contracts.Review
+			require.NoError(t, json.Unmarshal(event.Data, &data))
+			if data.ID == review.ID {
+				require.Equal(t, starWars.ID, data.MovieID)
+				break
+			}
+		}
+	})
+}
+
+// nextLiveEvent waits for the next event of the stream.
+func nextLiveEvent(t *testing.T, stream *client.EventStream) *contracts.LiveEvent {
+	type result struct {
+		event *contracts.LiveEvent
+		err   error
+	}
+	results := make(chan result, 1)
+	go func() {
+		event, err := stream.Next()
+		results <- result{event, err}
+	}()
+	select {
+	case res := <-results:
+		require.NoError(t, res.err)
+		return res.event
+	case <-time.After(10 * time.Second):
+		t.Fatal("live event wasn't received")
+		return nil
+	}
+}
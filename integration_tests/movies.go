@@ -19,10 +19,15 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 		}{
 			{
 				req: &contracts.CreateMovieRequest{
-					Title:       "Star Wars",
-					Description: "Star Wars is an American epic space opera",
-					ReleaseDate: time.Date(1977, time.May, 25, 0, 0, 0, 0, time.UTC),
-					Genres:      []int{Action.ID, Drama.ID},
+					Title:               "Star Wars",
+					Description:         "Star Wars is an American epic space opera",
+					ReleaseDate:         time.Date(1977, time.May, 25, 0, 0, 0, 0, time.UTC),
+					RuntimeMinutes:      contracts.Ptr(121),
+					OriginalLanguage:    contracts.Ptr("en"),
+					ProductionCountries: []string{"us"},
+					Certifications:      map[string]string{"us": "PG"},
+					ExternalIDs:         map[string]string{"imdb": "tt0076759"},
+					Genres:              []int{Action.ID, Drama.ID},
 					Cast: []*contracts.MovieCreditInfo{
 						{
 							StarID: mcgregor.ID,
@@ -60,8 +65,25 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 					Title: "The Lord of the Rings. The Fellowship of the Ring",
 					Description: "The Lord of the Rings is a series of three epic fantasy adventure films directed by Peter Jackson," +
 						" based on the novel The Lord of the Rings by J. R. R. Tolkien",
-					ReleaseDate: time.Date(2001, time.December, 10, 0, 0, 0, 0, time.UTC),
-					Genres:      []int{Drama.ID},
+					Releases: []*contracts.Release{
+						{
+							Country: "us",
+							Type:    contracts.ReleaseTypeTheatrical,
+							Date:    time.Date(2001, time.December, 19, 0, 0, 0, 0, time.UTC),
+						},
+						{
+							Country: "GB",
+							Type:    contracts.ReleaseTypePremiere,
+							Date:    time.Date(2001, time.December, 10, 0, 0, 0, 0, time.UTC),
+							Note:    "Odeon Leicester Square",
+						},
+						{
+							Country: "US",
+							Type:    contracts.ReleaseTypeDigital,
+							Date:    time.Date(2001, time.November, 6, 0, 0, 0, 0, time.UTC),
+						},
+					},
+					Genres: []int{Drama.ID},
 					Cast: []*contracts.MovieCreditInfo{
 						{
 							StarID:  hamill.ID,
@@ -102,6 +124,66 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 			require.Equal(t, *cast, *movie.Cast[i])
 		}
 	})
+	t.Run("movies.GetMovieByExternalID: success", func(t *testing.T) {
+		movie, err := c.GetMovieByExternalID("imdb", "tt0076759")
+		require.NoError(t, err)
+		require.Equal(t, starWars.ID, movie.ID)
+		require.Equal(t, contracts.Ptr(121), movie.RuntimeMinutes)
+		require.Equal(t, contracts.Ptr("en"), movie.OriginalLanguage)
+		require.Equal(t, []string{"US"}, movie.ProductionCountries)
+		require.Equal(t, map[string]string{"US": "PG"}, movie.Certifications)
+		require.Equal(t, map[string]string{"imdb": "tt0076759"}, movie.ExternalIDs)
+	})
+	t.Run("movies.GetMovieByExternalID: not found", func(t *testing.T) {
+		_, err := c.GetMovieByExternalID("imdb", "tt0000000")
+		requireNotFoundError(t, err, "movie", "imdb id", "tt0000000")
+	})
+	t.Run("movies.CreateMovie: existing external id", func(t *testing.T) {
+		req := &contracts.CreateMovieRequest{
+			Title:       "Star Wars: Special Edition",
+			ReleaseDate: time.Date(1997, time.January, 31, 0, 0, 0, 0, time.UTC),
+			ExternalIDs: map[string]string{"imdb": "tt0076759"},
+		}
+		_, err := c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistError(t, err, "movie", "imdb id", "tt0076759")
+	})
+	t.Run("movies.CreateMovie: invalid country", func(t *testing.T) {
+		req := &contracts.CreateMovieRequest{
+			Title:               "Nowhere",
+			ReleaseDate:         time.Date(1997, time.January, 31, 0, 0, 0, 0, time.UTC),
+			ProductionCountries: []string{"USA"},
+		}
+		_, err := c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "invalid country code")
+	})
+	t.Run("movies.GetMovieByID: releases", func(t *testing.T) {
+		movie, err := c.GetMovieByID(lordOfTheRing.ID)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2001, time.December, 10, 0, 0, 0, 0, time.UTC), movie.ReleaseDate)
+		require.Equal(t, lordOfTheRing.Releases, movie.Releases)
+		require.Equal(t, []*contracts.Release{
+			{Country: "US", Type: contracts.ReleaseTypeDigital, Date: time.Date(2001, time.November, 6, 0, 0, 0, 0, time.UTC)},
+			{Country: "GB", Type: contracts.ReleaseTypePremiere, Date: time.Date(2001, time.December, 10, 0, 0, 0, 0, time.UTC), Note: "Odeon Leicester Square"},
+			{Country: "US", Type: contracts.ReleaseTypeTheatrical, Date: time.Date(2001, time.December, 19, 0, 0, 0, 0, time.UTC)},
+		}, movie.Releases)
+	})
+	t.Run("movies.CreateMovie: no release date", func(t *testing.T) {
+		req := &contracts.CreateMovieRequest{
+			Title: "Undated",
+		}
+		_, err := c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "either release date or releases must be provided")
+	})
+	t.Run("movies.CreateMovie: invalid release type", func(t *testing.T) {
+		req := &contracts.CreateMovieRequest{
+			Title: "Bootleg",
+			Releases: []*contracts.Release{
+				{Country: "US", Type: "bootleg", Date: time.Date(2001, time.December, 19, 0, 0, 0, 0, time.UTC)},
+			},
+		}
+		_, err := c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "invalid release type")
+	})
 	t.Run("movies.GetMovieByID: not found", func(t *testing.T) {
 		notExistingId := 10
 		_, err := c.GetMovieByID(notExistingId)
@@ -127,6 +209,111 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 		require.Equal(t, testPaginationSize, res.Size)
 		require.Equal(t, []*contracts.Movie{&lordOfTheRing.Movie}, res.Items)
 	})
+	t.Run("movies.GetAllMovies: by genreId", func(t *testing.T) {
+		req := &contracts.GetMoviesRequest{GenreID: &Drama.ID}
+		res, err := c.GetMovies(req)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, res.Total)
+		require.Equal(t, []*contracts.Movie{&starWars.Movie, &lordOfTheRing.Movie}, res.Items)
+	})
+	t.Run("movies.GetAllMovies: released in country between dates", func(t *testing.T) {
+		req := &contracts.GetMoviesRequest{
+			ReleaseCountry: contracts.Ptr("US"),
+			ReleasedFrom:   contracts.Ptr(time.Date(2001, time.December, 1, 0, 0, 0, 0, time.UTC)),
+			ReleasedTo:     contracts.Ptr(time.Date(2001, time.December, 31, 0, 0, 0, 0, time.UTC)),
+		}
+		res, err := c.GetMovies(req)
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, []*contracts.Movie{&lordOfTheRing.Movie}, res.Items)
+
+		req.ReleaseCountry = contracts.Ptr("GB")
+		req.ReleasedFrom = contracts.Ptr(time.Date(2001, time.December, 11, 0, 0, 0, 0, time.UTC))
+		res, err = c.GetMovies(req)
+		require.NoError(t, err)
+		require.Equal(t, 0, res.Total)
+	})
+	t.Run("genres.GetGenreStats: success", func(t *testing.T) {
+		stats, err := c.GetGenreStats(Action.ID)
+		require.NoError(t, err)
+
+		require.Equal(t, Action.ID, stats.GenreID)
+		require.Equal(t, 2, stats.MovieCount)
+		require.Nil(t, stats.AvgRating)
+		require.Empty(t, stats.TopRated)
+	})
+	t.Run("genres.GetGenreStats: not found", func(t *testing.T) {
+		notExistingId := 1000
+		_, err := c.GetGenreStats(notExistingId)
+		requireNotFoundError(t, err, "genre", "id", notExistingId)
+	})
+	t.Run("movies.GetSimilarMovies: success", func(t *testing.T) {
+		movies, err := c.GetSimilarMovies(&contracts.GetSimilarMoviesRequest{ID: starWars.ID})
+		require.NoError(t, err)
+		require.Len(t, movies, 2)
+
+		ids := []int{movies[0].ID, movies[1].ID}
+		require.ElementsMatch(t, []int{harryPotter.ID, lordOfTheRing.ID}, ids)
+		require.GreaterOrEqual(t, movies[0].Score, movies[1].Score)
+
+		movies, err = c.GetSimilarMovies(&contracts.GetSimilarMoviesRequest{ID: starWars.ID, Limit: contracts.Ptr(1)})
+		require.NoError(t, err)
+		require.Len(t, movies, 1)
+	})
+	t.Run("movies.GetSimilarMovies: not found", func(t *testing.T) {
+		notExistingId := 10
+		_, err := c.GetSimilarMovies(&contracts.GetSimilarMoviesRequest{ID: notExistingId})
+		requireNotFoundError(t, err, "movie", "id", notExistingId)
+	})
+	t.Run("stars.GetFilmography: success", func(t *testing.T) {
+		filmography, err := c.GetFilmography(&contracts.GetFilmographyRequest{ID: hamill.ID})
+		require.NoError(t, err)
+		require.Len(t, filmography, 1)
+		require.Equal(t, "actor", filmography[0].Role)
+		require.Len(t, filmography[0].Credits, 3)
+		require.Equal(t, starWars.ID, filmography[0].Credits[0].Movie.ID)
+		require.Equal(t, "char1, char2", *filmography[0].Credits[0].Details)
+		require.Equal(t, harryPotter.ID, filmography[0].Credits[1].Movie.ID)
+		require.Equal(t, lordOfTheRing.ID, filmography[0].Credits[2].Movie.ID)
+
+		filmography, err = c.GetFilmography(&contracts.GetFilmographyRequest{ID: hamill.ID, SortByRelease: contracts.Ptr("desc")})
+		require.NoError(t, err)
+		require.Equal(t, lordOfTheRing.ID, filmography[0].Credits[0].Movie.ID)
+	})
+	t.Run("stars.GetCareerStats: success", func(t *testing.T) {
+		stats, err := c.GetCareerStats(hamill.ID)
+		require.NoError(t, err)
+		require.Equal(t, 3, stats.MovieCount)
+		require.Equal(t, map[string]int{"actor": 3}, stats.RoleCounts)
+		require.Equal(t, starWars.ReleaseDate, *stats.FirstRelease)
+		require.Equal(t, lordOfTheRing.ReleaseDate, *stats.LastRelease)
+		require.Len(t, stats.Collaborators, 1)
+		require.Equal(t, mcgregor.ID, stats.Collaborators[0].Star.ID)
+		require.Equal(t, 1, stats.Collaborators[0].SharedMovies)
+	})
+	t.Run("stars.GetCollaborators: success", func(t *testing.T) {
+		res, err := c.GetCollaborators(&contracts.GetCollaboratorsRequest{ID: hamill.ID})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, mcgregor.ID, res.Items[0].Star.ID)
+		require.Equal(t, 1, res.Items[0].SharedMovies)
+	})
+	t.Run("stars.GetStarPath: success", func(t *testing.T) {
+		path, err := c.GetStarPath(&contracts.GetStarPathRequest{From: mcgregor.ID, To: hamill.ID})
+		require.NoError(t, err)
+		require.Equal(t, 1, path.Degrees)
+		require.Len(t, path.Steps, 2)
+		require.Equal(t, mcgregor.ID, path.Steps[0].Star.ID)
+		require.Nil(t, path.Steps[0].Movie)
+		require.Equal(t, hamill.ID, path.Steps[1].Star.ID)
+		require.Equal(t, starWars.ID, path.Steps[1].Movie.ID)
+	})
+	t.Run("stars.GetCareerStats: not found", func(t *testing.T) {
+		notExistingId := 100
+		_, err := c.GetCareerStats(notExistingId)
+		requireNotFoundError(t, err, "star", "id", notExistingId)
+	})
 	t.Run("stars.GetAllStars: by movieId success", func(t *testing.T) {
 		req := contracts.GetStarsRequest{
 			MovieID: contracts.Ptr(lordOfTheRing.ID),
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/notifications"
+	"github.com/RadkevichAnn/movie-reviews/internal/signing"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func notificationsAPIChecks(t *testing.T, c *client.Client) {
+	reader := RegisterRandomUser(t, c)
+	readerToken := login(t, c, reader.Email, standardPassword)
+	author := RegisterRandomUser(t, c)
+
+	_, err := c.Follow(contracts.NewAuthenticated(&contracts.FollowRequest{UserID: reader.ID, FolloweeID: author.ID}, readerToken))
+	require.NoError(t, err)
+
+	var notification contracts.Notification
+	t.Run("notifications.GetNotifications: followee reviewed", func(t *testing.T) {
+		review := createReview(t, c, author, starWars.ID)
+
+		retry.Run(t, func(r *retry.R) {
+			req := &contracts.GetNotificationsRequest{UnreadOnly: true}
+			res, err := c.GetNotifications(contracts.NewAuthenticated(req, readerToken))
+			require.NoError(r, err)
+			require.Len(r, res.Items, 1)
+			notification = *res.Items[0]
+		})
+		require.Equal(t, contracts.NotificationTypeFolloweeReviewed, notification.Type)
+		require.Equal(t, reader.ID, notification.UserID)
+		require.Contains(t, notification.Title, review.Title)
+		require.Nil(t, notification.ReadAt)
+
+		count, err := c.GetUnreadNotificationsCount(readerToken)
+		require.NoError(t, err)
+		require.Equal(t, 1, count.Count)
+	})
+	t.Run("notifications.MarkNotificationRead: success", func(t *testing.T) {
+		req := &contracts.MarkNotificationReadRequest{NotificationID: notification.ID}
+		err := c.MarkNotificationRead(contracts.NewAuthenticated(req, readerToken))
+		require.NoError(t, err)
+
+		count, err := c.GetUnreadNotificationsCount(readerToken)
+		require.NoError(t, err)
+		require.Equal(t, 0, count.Count)
+
+		res, err := c.GetNotifications(contracts.NewAuthenticated(&contracts.GetNotificationsRequest{}, readerToken))
+		require.NoError(t, err)
+		require.Len(t, res.Items, 1)
+		require.NotNil(t, res.Items[0].ReadAt)
+	})
+	t.Run("notifications.MarkNotificationRead: not found", func(t *testing.T) {
+		req := &contracts.MarkNotificationReadRequest{NotificationID: notification.ID}
+		err := c.MarkNotificationRead(contracts.NewAuthenticated(req, login(t, c, author.Email, standardPassword)))
+		requireNotFoundError(t, err, "notification", "id", notification.ID)
+	})
+	t.Run("notifications.MarkAllNotificationsRead: success", func(t *testing.T) {
+		marked, err := c.MarkAllNotificationsRead(readerToken)
+		require.NoError(t, err)
+		require.Equal(t, 0, marked.Count)
+	})
+
+	t.Run("notifications.GetNotificationPreferences: defaults", func(t *testing.T) {
+		preferences, err := c.GetNotificationPreferences(readerToken)
+		require.NoError(t, err)
+		require.Len(t, preferences, 2)
+		for _, preference := range preferences {
+			require.Equal(t, []string{contracts.NotificationChannelInApp}, preference.Channels)
+		}
+	})
+	t.Run("notifications.UpdateNotificationPreferences: unknown type", func(t *testing.T) {
+		req := &contracts.UpdateNotificationPreferencesRequest{
+			Preferences: []*contracts.NotificationPreference{{Type: "unknown"}},
+		}
+		_, err := c.UpdateNotificationPreferences(contracts.NewAuthenticated(req, readerToken))
+		requireBadRequestError(t, err, `unknown notification type "unknown"`)
+	})
+	t.Run("notifications.UpdateNotificationPreferences: unknown channel", func(t *testing.T) {
+		req := &contracts.UpdateNotificationPreferencesRequest{
+			Preferences: []*contracts.NotificationPreference{
+				{Type: contracts.NotificationTypeFolloweeReviewed, Channels: []string{"sms"}},
+			},
+		}
+		_, err := c.UpdateNotificationPreferences(contracts.NewAuthenticated(req, readerToken))
+		requireBadRequestError(t, err, `unknown channel "sms"`)
+	})
+	t.Run("notifications.SetNotificationWebhook: invalid url", func(t *testing.T) {
+		req := &contracts.SetNotificationWebhookRequest{URL: "ftp://example.com"}
+		_, err := c.SetNotificationWebhook(contracts.NewAuthenticated(req, readerToken))
+		requireBadRequestError(t, err, "invalid webhook url")
+	})
+
+	t.Run("notifications: webhook delivery", func(t *testing.T) {
+		type delivery struct {
+			body      []byte
+			signature string
+		}
+		deliveries := make(chan delivery, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			deliveries <- delivery{body: body, signature: r.Header.Get(notifications.SignatureHeader)}
+		}))
+		defer srv.Close()
+
+		webhook, err := c.SetNotificationWebhook(contracts.NewAuthenticated(&contracts.SetNotificationWebhookRequest{URL: srv.URL}, readerToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, webhook.Secret)
+
+		req := &contracts.UpdateNotificationPreferencesRequest{
+			Preferences: []*contracts.NotificationPreference{
+				{Type: contracts.NotificationTypeFolloweeReviewed, Channels: []string{contracts.NotificationChannelWebhook}},
+			},
+		}
+		preferences, err := c.UpdateNotificationPreferences(contracts.NewAuthenticated(req, readerToken))
+		require.NoError(t, err)
+		require.Equal(t, []string{contracts.NotificationChannelWebhook}, preferences[0].Channels)
+
+		createReview(t, c, author, lordOfTheRing.ID)
+		var d delivery
+		select {
+		case d = <-deliveries:
+		case <-time.After(10 * time.Second):
+			t.Fatal("webhook wasn't called")
+		}
+		require.Equal(t, "sha256="+signing.Sign(webhook.Secret, d.body), d.signature)
+		require.Contains(t, string(d.body), contracts.NotificationTypeFolloweeReviewed)
+
+		res, err := c.GetNotifications(contracts.NewAuthenticated(&contracts.GetNotificationsRequest{UnreadOnly: true}, readerToken))
+		require.NoError(t, err)
+		require.Empty(t, res.Items)
+
+		err = c.DeleteNotificationWebhook(readerToken)
+		require.NoError(t, err)
+		err = c.DeleteNotificationWebhook(readerToken)
+		requireNotFoundError(t, err, "webhook", "user id", reader.ID)
+	})
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/webhooks"
+	"github.com/stretchr/testify/require"
+)
+
+func outboxAPIChecks(t *testing.T, c *client.Client) {
+	receiver := newWebhookReceiver(http.StatusOK)
+	defer receiver.Close()
+
+	req := &contracts.CreateWebhookRequest{
+		URL:        receiver.URL,
+		EventTypes: []string{contracts.WebhookEventMovieUpdated, contracts.WebhookEventMovieDeleted},
+	}
+	webhook, err := c.CreateWebhook(contracts.NewAuthenticated(req, adminToken))
+	require.NoError(t, err)
+	defer func() {
+		req := &contracts.GetWebhookRequest{ID: webhook.ID}
+		require.NoError(t, c.DeleteWebhook(contracts.NewAuthenticated(req, adminToken)))
+	}()
+
+	t.Run("outbox: events of a movie are relayed in order", func(t *testing.T) {
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Outbox",
+			ReleaseDate: time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		titles := []string{"Outbox 2", "Outbox 3"}
+		for i, title := range titles {
+			req := &contracts.UpdateMovieRequest{
+				ID:          movie.ID,
+				Title:       title,
+				ReleaseDate: movie.ReleaseDate,
+				Version:     i,
+			}
+			require.NoError(t, c.UpdateMovie(contracts.NewAuthenticated(req, johnDoeToken)))
+		}
+		deleteReq := &contracts.GetOrDeleteMovieByIDRequest{ID: movie.ID}
+		require.NoError(t, c.DeleteMovie(contracts.NewAuthenticated(deleteReq, johnDoeToken)))
+
+		for _, title := range titles {
+			event := receiver.next(t, webhook.Secret)
+			require.Equal(t, contracts.WebhookEventMovieUpdated, event.Type)
+			var data contracts.Movie
+			require.NoError(t, json.Unmarshal(event.Data, &data))
+			require.Equal(t, movie.ID, data.ID)
+			require.Equal(t, title, data.Title)
+		}
+		event := receiver.next(t, webhook.Secret)
+		require.Equal(t, contracts.WebhookEventMovieDeleted, event.Type)
+		var data contracts.Movie
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		require.Equal(t, movie.ID, data.ID)
+	})
+	t.Run("outbox: failed change records no event", func(t *testing.T) {
+		req := &contracts.UpdateMovieRequest{
+			ID:          starWars.ID,
+			Title:       starWars.Title,
+			ReleaseDate: starWars.ReleaseDate,
+			Version:     1000,
+		}
+		err := c.UpdateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireVersionMismatchError(t, err, "movie", "id", req.ID, req.Version)
+
+		select {
+		case r := <-receiver.requests:
+			t.Fatalf("unexpected webhook call: %s", r.Header.Get(webhooks.EventHeader))
+		case <-time.After(time.Second):
+		}
+	})
+}
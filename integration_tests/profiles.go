@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func profilesAPIChecks(t *testing.T, c *client.Client) {
+	user := RegisterRandomUser(t, c)
+	userToken := login(t, c, user.Email, standardPassword)
+	follower := RegisterRandomUser(t, c)
+	followerToken := login(t, c, follower.Email, standardPassword)
+	stranger := RegisterRandomUser(t, c)
+	strangerToken := login(t, c, stranger.Email, standardPassword)
+
+	_, err := c.Follow(contracts.NewAuthenticated(&contracts.FollowRequest{UserID: follower.ID, FolloweeID: user.ID}, followerToken))
+	require.NoError(t, err)
+	review := createReview(t, c, user, starWars.ID)
+	listReq := &contracts.CreateListRequest{
+		UserID: user.ID,
+		Name:   "Space operas",
+		Items:  []*contracts.ListItemInfo{{MovieID: starWars.ID}},
+	}
+	list, err := c.CreateList(contracts.NewAuthenticated(listReq, userToken))
+	require.NoError(t, err)
+
+	t.Run("users.UpdateUser: profile", func(t *testing.T) {
+		displayName := "Jane"
+		location := "Vilnius"
+		req := &contracts.UpdateRequest{
+			UserId:           user.ID,
+			DisplayName:      &displayName,
+			Location:         &location,
+			FavoriteGenreIDs: []int{Drama.ID, Action.ID},
+			Privacy: &contracts.Privacy{
+				Email:   contracts.VisibilityFollowers,
+				Reviews: contracts.VisibilityFollowers,
+				Lists:   contracts.VisibilityPrivate,
+			},
+		}
+		require.NoError(t, c.UpdateUser(contracts.NewAuthenticated(req, userToken)))
+
+		u, err := c.GetUserAuthenticated(contracts.NewAuthenticated(&contracts.DeleteOrGetRequest{UserId: user.ID}, userToken))
+		require.NoError(t, err)
+		require.Equal(t, displayName, *u.DisplayName)
+		require.Equal(t, location, *u.Location)
+		require.Equal(t, user.Email, u.Email)
+		require.Equal(t, []*contracts.FavoriteGenre{{ID: Drama.ID, Name: Drama.Name}, {ID: Action.ID, Name: Action.Name}}, u.FavoriteGenres)
+		require.Equal(t, req.Privacy, u.Privacy)
+		require.Equal(t, 1, u.Stats.ReviewCount)
+		require.Equal(t, 7.0, *u.Stats.AvgRatingGiven)
+	})
+	t.Run("users.UpdateUser: invalid visibility", func(t *testing.T) {
+		req := &contracts.UpdateRequest{
+			UserId:  user.ID,
+			Privacy: &contracts.Privacy{Email: "friends"},
+		}
+		err := c.UpdateUser(contracts.NewAuthenticated(req, userToken))
+		requireBadRequestError(t, err, "visibility must be one of public, followers or private")
+	})
+	t.Run("users.UpdateUser: unknown favorite genre", func(t *testing.T) {
+		nonExistingID := 1000
+		req := &contracts.UpdateRequest{
+			UserId:           user.ID,
+			FavoriteGenreIDs: []int{nonExistingID},
+		}
+		err := c.UpdateUser(contracts.NewAuthenticated(req, userToken))
+		requireNotFoundError(t, err, "genre", "id", nonExistingID)
+	})
+	t.Run("users.GetUser: public profile", func(t *testing.T) {
+		u := getUser(t, c, user.ID)
+		require.Equal(t, "Jane", *u.DisplayName)
+		require.Len(t, u.FavoriteGenres, 2)
+		require.Empty(t, u.Email)
+		require.Nil(t, u.Stats)
+		require.Nil(t, u.Privacy)
+	})
+	t.Run("users.GetUser: follower", func(t *testing.T) {
+		u, err := c.GetUserAuthenticated(contracts.NewAuthenticated(&contracts.DeleteOrGetRequest{UserId: user.ID}, followerToken))
+		require.NoError(t, err)
+		require.Equal(t, user.Email, u.Email)
+		require.Equal(t, 1, u.Stats.ReviewCount)
+		require.Nil(t, u.Privacy)
+	})
+	t.Run("reviews.GetAllReviewsPaginated: hidden from strangers", func(t *testing.T) {
+		_, err := c.GetAllReviewsPaginated(&contracts.GetReviewsRequest{UserID: &user.ID})
+		requireForbiddenError(t, err, "reviews of the user are not visible")
+	})
+	t.Run("reviews.GetAllReviewsPaginated: movie reviews hidden from strangers", func(t *testing.T) {
+		req := &contracts.GetReviewsRequest{MovieID: &starWars.ID, PaginatedRequest: contracts.PaginatedRequest{Size: 50}}
+		res, err := c.GetAllReviewsPaginated(req)
+		require.NoError(t, err)
+		for _, r := range res.Items {
+			require.NotEqual(t, user.ID, r.UserID)
+		}
+	})
+	t.Run("reviews.GetReviewByID: followers only", func(t *testing.T) {
+		_, err := c.GetReviewByID(review.ID)
+		requireNotFoundError(t, err, "review", "id", review.ID)
+
+		req := &contracts.GetReviewRequest{ReviewID: review.ID}
+		_, err = c.GetReviewByIDAuthenticated(contracts.NewAuthenticated(req, strangerToken))
+		requireNotFoundError(t, err, "review", "id", review.ID)
+
+		r, err := c.GetReviewByIDAuthenticated(contracts.NewAuthenticated(req, followerToken))
+		require.NoError(t, err)
+		require.Equal(t, review.ID, r.ID)
+	})
+	t.Run("lists.GetLists: private owner", func(t *testing.T) {
+		res, err := c.GetLists(&contracts.GetListsRequest{MovieID: &starWars.ID, PaginatedRequest: contracts.PaginatedRequest{Size: 50}})
+		require.NoError(t, err)
+		for _, l := range res.Items {
+			require.NotEqual(t, list.ID, l.ID)
+		}
+	})
+	t.Run("lists.GetListByID: private owner", func(t *testing.T) {
+		req := &contracts.GetListRequest{ListID: list.ID}
+		_, err := c.GetListByIDAuthenticated(contracts.NewAuthenticated(req, followerToken))
+		requireNotFoundError(t, err, "list", "id", list.ID)
+
+		_, err = c.GetListByIDAuthenticated(contracts.NewAuthenticated(req, userToken))
+		require.NoError(t, err)
+	})
+	t.Run("lists.GetUserLists: private", func(t *testing.T) {
+		req := &contracts.GetUserListsRequest{UserID: user.ID}
+		_, err := c.GetUserListsAuthenticated(contracts.NewAuthenticated(req, followerToken))
+		requireForbiddenError(t, err, "lists of the user are not visible")
+
+		_, err = c.GetUserListsAuthenticated(contracts.NewAuthenticated(req, strangerToken))
+		requireForbiddenError(t, err, "lists of the user are not visible")
+
+		_, err = c.GetUserListsAuthenticated(contracts.NewAuthenticated(req, userToken))
+		require.NoError(t, err)
+	})
+	t.Run("users.UploadAvatar: success", func(t *testing.T) {
+		req := &contracts.UploadAvatarRequest{UserID: user.ID, FileName: "avatar.png", Content: newPNG(t, 200, 200)}
+		img, err := c.UploadUserAvatar(contracts.NewAuthenticated(req, userToken))
+		require.NoError(t, err)
+
+		u := getUser(t, c, user.ID)
+		require.Equal(t, img, u.Avatar)
+	})
+	t.Run("users.UploadAvatar: another user", func(t *testing.T) {
+		req := &contracts.UploadAvatarRequest{UserID: user.ID, FileName: "avatar.png", Content: newPNG(t, 200, 200)}
+		_, err := c.UploadUserAvatar(contracts.NewAuthenticated(req, strangerToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+}
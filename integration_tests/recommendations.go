@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func recommendationsAPIChecks(t *testing.T, c *client.Client) {
+	user := RegisterRandomUser(t, c)
+	userToken := login(t, c, user.Email, standardPassword)
+	other := RegisterRandomUser(t, c)
+	otherToken := login(t, c, other.Email, standardPassword)
+
+	t.Run("recommendations.GetRecommendations: cold start", func(t *testing.T) {
+		req := &contracts.GetRecommendationsRequest{UserID: user.ID, Limit: contracts.Ptr(2)}
+		recommendations, err := c.GetRecommendations(contracts.NewAuthenticated(req, userToken))
+		require.NoError(t, err)
+		require.Len(t, recommendations, 2)
+		for _, r := range recommendations {
+			require.Equal(t, "popular", r.Source)
+		}
+	})
+	t.Run("recommendations.GetRecommendations: excludes reviewed movies", func(t *testing.T) {
+		reviewReq := &contracts.CreateReviewRequest{
+			MovieID: starWars.ID,
+			UserID:  user.ID,
+			Rating:  8,
+			Title:   "A classic",
+			Content: "The space opera that started it all",
+		}
+		_, err := c.CreateReview(contracts.NewAuthenticated(reviewReq, userToken))
+		require.NoError(t, err)
+
+		req := &contracts.GetRecommendationsRequest{UserID: user.ID}
+		recommendations, err := c.GetRecommendations(contracts.NewAuthenticated(req, userToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, recommendations)
+		for _, r := range recommendations {
+			require.NotEqual(t, starWars.ID, r.Movie.ID)
+			require.Contains(t, []string{"collaborative", "content", "popular"}, r.Source)
+		}
+	})
+	t.Run("recommendations.GetRecommendations: another user", func(t *testing.T) {
+		req := &contracts.GetRecommendationsRequest{UserID: user.ID}
+		_, err := c.GetRecommendations(contracts.NewAuthenticated(req, otherToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+}
@@ -1,15 +1,20 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/RadkevichAnn/movie-reviews/client"
 	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/require"
 )
 
-func reviewsAPIChecks(t *testing.T, c *client.Client) {
+func reviewsAPIChecks(t *testing.T, c *client.Client, pgConnString string) {
 	reviewer1 := RegisterRandomUser(t, c)
 	reviewer2 := RegisterRandomUser(t, c)
 	reviewer1Token := login(t, c, reviewer1.Email, standardPassword)
@@ -201,6 +206,131 @@ func reviewsAPIChecks(t *testing.T, c *client.Client) {
 		review1 = getReview(t, c, review1.ID)
 		require.Nil(t, review1)
 	})
+
+	// The histogram of the rating aggregates isn't exposed by the API, so the aggregates are read from the database
+	conn, err := pgx.Connect(context.Background(), pgConnString)
+	require.NoError(t, err)
+	defer cleanUp(t, conn.Close)
+
+	movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+		Title:       "Rating Aggregates",
+		ReleaseDate: time.Date(2010, time.May, 1, 0, 0, 0, 0, time.UTC),
+	}, johnDoeToken))
+	require.NoError(t, err)
+
+	t.Run("reviews: rating aggregates follow review changes", func(t *testing.T) {
+		req := &contracts.CreateReviewRequest{
+			MovieID: movie.ID,
+			UserID:  reviewer1.ID,
+			Rating:  4,
+			Title:   "Not my cup of tea",
+			Content: "The story drags on and the characters never come to life, despite a few good scenes.",
+		}
+		review, err := c.CreateReview(contracts.NewAuthenticated(req, reviewer1Token))
+		require.NoError(t, err)
+		requireRatingAggregates(t, conn, movie.ID, 4, 1, histogram(map[int]int{4: 1}))
+
+		updateReq := &contracts.UpdateReviewRequest{
+			ReviewID: review.ID,
+			UserID:   reviewer1.ID,
+			Rating:   6,
+			Title:    review.Title,
+			Content:  review.Content,
+		}
+		require.NoError(t, c.UpdateReview(contracts.NewAuthenticated(updateReq, reviewer1Token)))
+		requireRatingAggregates(t, conn, movie.ID, 6, 1, histogram(map[int]int{6: 1}))
+
+		req.UserID = reviewer2.ID
+		req.Rating = 9
+		_, err = c.CreateReview(contracts.NewAuthenticated(req, reviewer2Token))
+		require.NoError(t, err)
+		requireRatingAggregates(t, conn, movie.ID, 15, 2, histogram(map[int]int{6: 1, 9: 1}))
+
+		deleteReq := &contracts.DeleteReviewRequest{ReviewID: review.ID, UserID: reviewer1.ID}
+		require.NoError(t, c.DeleteReview(contracts.NewAuthenticated(deleteReq, reviewer1Token)))
+		requireRatingAggregates(t, conn, movie.ID, 9, 1, histogram(map[int]int{9: 1}))
+	})
+	t.Run("reviews: reconcile ratings reports and fixes drift", func(t *testing.T) {
+		_, err := conn.Exec(context.Background(), `UPDATE movies
+			SET rating_sum = rating_sum + 5, rating_count = rating_count + 5, rating_histogram[1] = rating_histogram[1] + 5
+			WHERE id = $1`, movie.ID)
+		require.NoError(t, err)
+
+		drift := findRatingDrift(t, reconcileRatings(t, c, true), movie.ID)
+		require.NotNil(t, drift)
+		require.Equal(t, 6, drift.Stored.Count)
+		require.Equal(t, 1, drift.Actual.Count)
+		require.Equal(t, histogram(map[int]int{9: 1}), drift.Actual.Histogram)
+		requireRatingAggregates(t, conn, movie.ID, 14, 6, histogram(map[int]int{1: 5, 9: 1}))
+
+		drift = findRatingDrift(t, reconcileRatings(t, c, false), movie.ID)
+		require.NotNil(t, drift)
+		requireRatingAggregates(t, conn, movie.ID, 9, 1, histogram(map[int]int{9: 1}))
+
+		require.Nil(t, findRatingDrift(t, reconcileRatings(t, c, true), movie.ID))
+	})
+
+	req := &contracts.GetOrDeleteMovieByIDRequest{ID: movie.ID}
+	require.NoError(t, c.DeleteMovie(contracts.NewAuthenticated(req, johnDoeToken)))
+}
+
+type ratingAggregate struct {
+	Sum       int   `json:"sum"`
+	Count     int   `json:"count"`
+	Histogram []int `json:"histogram"`
+}
+
+type ratingDrift struct {
+	MovieID int             `json:"movie_id"`
+	Stored  ratingAggregate `json:"stored"`
+	Actual  ratingAggregate `json:"actual"`
+}
+
+// histogram returns the rating histogram having the counts of the ratings, from 1 to 10.
+func histogram(counts map[int]int) []int {
+	h := make([]int, 10)
+	for rating, n := range counts {
+		h[rating-1] = n
+	}
+	return h
+}
+
+func requireRatingAggregates(t *testing.T, conn *pgx.Conn, movieID, sum, count int, histogram []int) {
+	var actual ratingAggregate
+	err := conn.QueryRow(context.Background(), `SELECT rating_sum, rating_count, rating_histogram FROM movies WHERE id = $1`,
+		movieID).
+		Scan(&actual.Sum, &actual.Count, &actual.Histogram)
+	require.NoError(t, err)
+	require.Equal(t, ratingAggregate{Sum: sum, Count: count, Histogram: histogram}, actual)
+}
+
+// reconcileRatings runs the job reconciling the rating aggregates and returns the drift it found.
+func reconcileRatings(t *testing.T, c *client.Client, dryRun bool) []*ratingDrift {
+	req := &contracts.EnqueueJobRequest{Type: "ratings.reconcile", Payload: map[string]any{"dry_run": dryRun}}
+	job, err := c.EnqueueJob(contracts.NewAuthenticated(req, adminToken))
+	require.NoError(t, err)
+	retry.Run(t, func(r *retry.R) {
+		job, err = c.GetJobByID(contracts.NewAuthenticated(&contracts.GetJobRequest{ID: job.ID}, adminToken))
+		require.NoError(r, err)
+		require.Equal(r, contracts.JobStatusSucceeded, job.Status)
+	})
+
+	var result struct {
+		Drifts []*ratingDrift `json:"drifts"`
+	}
+	require.NoError(t, json.Unmarshal(job.Result, &result))
+	return result.Drifts
+}
+
+func findRatingDrift(t *testing.T, drifts []*ratingDrift, movieID int) *ratingDrift {
+	var found *ratingDrift
+	for _, drift := range drifts {
+		if drift.MovieID == movieID {
+			require.Nil(t, found)
+			found = drift
+		}
+	}
+	return found
 }
 
 func getReview(t *testing.T, c *client.Client, reviewID int) *contracts.Review {
@@ -38,13 +38,13 @@ func runServer(t *testing.T, pgConnString string) {
 		}
 	})
 
-	tests(t, port, cfg)
+	tests(t, port, cfg, pgConnString)
 
 	err = srv.Shutdown(context.Background())
 	require.NoError(t, err)
 }
 
-func tests(t *testing.T, port int, cfg *config.Config) {
+func tests(t *testing.T, port int, cfg *config.Config, pgConnString string) {
 	addr := fmt.Sprintf("http://localhost:%d", port)
 	c := client.New(addr)
 
@@ -53,5 +53,23 @@ func tests(t *testing.T, port int, cfg *config.Config) {
 	GenresApiChecks(t, c)
 	StarsApiChecks(t, c)
 	moviesAPIChecks(t, c)
-	reviewsAPIChecks(t, c)
+	reviewsAPIChecks(t, c, pgConnString)
+	watchlistAPIChecks(t, c)
+	listsAPIChecks(t, c)
+	followsAPIChecks(t, c)
+	recommendationsAPIChecks(t, c)
+	collectionsAPIChecks(t, c)
+	imagesAPIChecks(t, c)
+	triviaAPIChecks(t, c)
+	awardsAPIChecks(t, c)
+	bulkAPIChecks(t, c)
+	jobsAPIChecks(t, c)
+	exportsAPIChecks(t, c)
+	profilesAPIChecks(t, c)
+	accountDeletionAPIChecks(t, c)
+	notificationsAPIChecks(t, c)
+	webhooksAPIChecks(t, c)
+	outboxAPIChecks(t, c)
+	liveAPIChecks(t, c)
+	graphqlAPIChecks(t, c)
 }
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func triviaAPIChecks(t *testing.T, c *client.Client) {
+	author := RegisterRandomUser(t, c)
+	authorToken := login(t, c, author.Email, standardPassword)
+	voter := RegisterRandomUser(t, c)
+	voterToken := login(t, c, voter.Email, standardPassword)
+
+	var quote, goof, starTrivia *contracts.TriviaItem
+	t.Run("trivia.SubmitMovieTrivia: success", func(t *testing.T) {
+		req := &contracts.SubmitTriviaRequest{
+			ID:      starWars.ID,
+			Kind:    contracts.TriviaKindQuote,
+			Content: "May the Force be with you.",
+			Credit:  &contracts.TriviaCreditInfo{StarID: hamill.ID, Role: "actor"},
+		}
+		var err error
+		quote, err = c.SubmitMovieTrivia(contracts.NewAuthenticated(req, authorToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, quote.ID)
+		require.Equal(t, contracts.TriviaStatusPending, quote.Status)
+		require.Equal(t, author.ID, quote.UserID)
+		require.Equal(t, hamill.ID, quote.Credit.StarID)
+
+		req = &contracts.SubmitTriviaRequest{
+			ID:      starWars.ID,
+			Kind:    contracts.TriviaKindGoof,
+			Content: "A stormtrooper hits his head on a door frame.",
+		}
+		goof, err = c.SubmitMovieTrivia(contracts.NewAuthenticated(req, authorToken))
+		require.NoError(t, err)
+	})
+	t.Run("trivia.SubmitMovieTrivia: unauthorized", func(t *testing.T) {
+		req := &contracts.SubmitTriviaRequest{ID: starWars.ID, Kind: contracts.TriviaKindTrivia, Content: "Anonymous"}
+		_, err := c.SubmitMovieTrivia(contracts.NewAuthenticated(req, ""))
+		requireUnauthorizedError(t, err, "invalid or missing token")
+	})
+	t.Run("trivia.SubmitMovieTrivia: not credited", func(t *testing.T) {
+		req := &contracts.SubmitTriviaRequest{
+			ID:      starWars.ID,
+			Kind:    contracts.TriviaKindQuote,
+			Content: "I am your father.",
+			Credit:  &contracts.TriviaCreditInfo{StarID: hamill.ID, Role: "director"},
+		}
+		_, err := c.SubmitMovieTrivia(contracts.NewAuthenticated(req, authorToken))
+		requireBadRequestError(t, err, "is not credited as director")
+	})
+	t.Run("trivia.SubmitMovieTrivia: movie not found", func(t *testing.T) {
+		notExistingID := 1000
+		req := &contracts.SubmitTriviaRequest{ID: notExistingID, Kind: contracts.TriviaKindTrivia, Content: "Nothing"}
+		_, err := c.SubmitMovieTrivia(contracts.NewAuthenticated(req, authorToken))
+		requireNotFoundError(t, err, "movie", "id", notExistingID)
+	})
+	t.Run("trivia.SubmitStarTrivia: success", func(t *testing.T) {
+		req := &contracts.SubmitTriviaRequest{
+			ID:      hamill.ID,
+			Kind:    contracts.TriviaKindTrivia,
+			Content: "Voiced the Joker in animated series.",
+		}
+		var err error
+		starTrivia, err = c.SubmitStarTrivia(contracts.NewAuthenticated(req, authorToken))
+		require.NoError(t, err)
+		require.Equal(t, &hamill.ID, starTrivia.StarID)
+	})
+	t.Run("trivia.SubmitStarTrivia: invalid kind", func(t *testing.T) {
+		req := &contracts.SubmitTriviaRequest{ID: hamill.ID, Kind: contracts.TriviaKindGoof, Content: "Goof"}
+		_, err := c.SubmitStarTrivia(contracts.NewAuthenticated(req, authorToken))
+		requireBadRequestError(t, err, "stars can only have trivia items")
+	})
+	t.Run("trivia.GetMovieTrivia: pending items are hidden", func(t *testing.T) {
+		res, err := c.GetMovieTrivia(&contracts.GetTriviaRequest{ID: starWars.ID})
+		require.NoError(t, err)
+		require.Equal(t, 0, res.Total)
+
+		req := &contracts.GetTriviaRequest{ID: starWars.ID, Status: contracts.Ptr(contracts.TriviaStatusPending)}
+		_, err = c.GetMovieTriviaAuthenticated(contracts.NewAuthenticated(req, authorToken))
+		requireForbiddenError(t, err, "only editors can list trivia items that are not approved")
+
+		res, err = c.GetMovieTriviaAuthenticated(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, res.Total)
+	})
+	t.Run("trivia.ReviewTrivia: success", func(t *testing.T) {
+		for _, item := range []*contracts.TriviaItem{quote, goof, starTrivia} {
+			req := &contracts.ReviewTriviaRequest{ItemID: item.ID, Status: contracts.TriviaStatusApproved}
+			reviewed, err := c.ReviewTrivia(contracts.NewAuthenticated(req, johnDoeToken))
+			require.NoError(t, err)
+			require.Equal(t, contracts.TriviaStatusApproved, reviewed.Status)
+			require.NotNil(t, reviewed.ReviewedAt)
+		}
+	})
+	t.Run("trivia.ReviewTrivia: forbidden", func(t *testing.T) {
+		req := &contracts.ReviewTriviaRequest{ItemID: quote.ID, Status: contracts.TriviaStatusRejected}
+		_, err := c.ReviewTrivia(contracts.NewAuthenticated(req, authorToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+	t.Run("trivia.VoteTrivia: success", func(t *testing.T) {
+		item, err := c.VoteTrivia(contracts.NewAuthenticated(&contracts.VoteTriviaRequest{ItemID: goof.ID, Value: 1}, voterToken))
+		require.NoError(t, err)
+		require.Equal(t, 1, item.Score)
+
+		item, err = c.VoteTrivia(contracts.NewAuthenticated(&contracts.VoteTriviaRequest{ItemID: goof.ID, Value: 1}, authorToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, item.Score)
+
+		item, err = c.VoteTrivia(contracts.NewAuthenticated(&contracts.VoteTriviaRequest{ItemID: quote.ID, Value: -1}, voterToken))
+		require.NoError(t, err)
+		require.Equal(t, -1, item.Score)
+
+		item, err = c.UnvoteTrivia(contracts.NewAuthenticated(&contracts.TriviaItemRequest{ItemID: quote.ID}, voterToken))
+		require.NoError(t, err)
+		require.Equal(t, 0, item.Score)
+	})
+	t.Run("trivia.VoteTrivia: invalid value", func(t *testing.T) {
+		req := &contracts.VoteTriviaRequest{ItemID: goof.ID, Value: 0}
+		_, err := c.VoteTrivia(contracts.NewAuthenticated(req, voterToken))
+		requireBadRequestError(t, err, "vote must be 1 or -1")
+	})
+	t.Run("trivia.GetMovieTrivia: sorted by score", func(t *testing.T) {
+		req := &contracts.GetTriviaRequest{ID: starWars.ID, SortByScore: contracts.Ptr("desc")}
+		res, err := c.GetMovieTrivia(req)
+		require.NoError(t, err)
+		require.Equal(t, 2, res.Total)
+		require.Equal(t, goof.ID, res.Items[0].ID)
+		require.Equal(t, 2, res.Items[0].Score)
+		require.Equal(t, quote.ID, res.Items[1].ID)
+		require.Equal(t, "Hamill", res.Items[1].Credit.LastName)
+
+		req = &contracts.GetTriviaRequest{ID: starWars.ID, Kind: contracts.Ptr(contracts.TriviaKindQuote)}
+		res, err = c.GetMovieTrivia(req)
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, quote.ID, res.Items[0].ID)
+	})
+	t.Run("trivia.GetStarTrivia: success", func(t *testing.T) {
+		res, err := c.GetStarTrivia(&contracts.GetTriviaRequest{ID: hamill.ID})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, starTrivia.Content, res.Items[0].Content)
+	})
+	t.Run("trivia.DeleteTrivia: success", func(t *testing.T) {
+		err := c.DeleteTrivia(contracts.NewAuthenticated(&contracts.TriviaItemRequest{ItemID: starTrivia.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		res, err := c.GetStarTrivia(&contracts.GetTriviaRequest{ID: hamill.ID})
+		require.NoError(t, err)
+		require.Equal(t, 0, res.Total)
+
+		_, err = c.VoteTrivia(contracts.NewAuthenticated(&contracts.VoteTriviaRequest{ItemID: starTrivia.ID, Value: 1}, voterToken))
+		requireNotFoundError(t, err, "trivia item", "id", starTrivia.ID)
+	})
+}
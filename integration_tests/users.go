@@ -17,7 +17,7 @@ func UsersApiChecks(t *testing.T, c *client.Client, cfg *config.Config) {
 		require.NoError(t, err)
 
 		require.Equal(t, cfg.Admin.Username, u.Username)
-		require.Equal(t, cfg.Admin.Email, u.Email)
+		require.Empty(t, u.Email, "email is private by default")
 		require.Equal(t, users.AdminRole, u.Role)
 	})
 
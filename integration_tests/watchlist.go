@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func watchlistAPIChecks(t *testing.T, c *client.Client) {
+	viewer := RegisterRandomUser(t, c)
+	viewerToken := login(t, c, viewer.Email, standardPassword)
+	other := RegisterRandomUser(t, c)
+	otherToken := login(t, c, other.Email, standardPassword)
+
+	t.Run("watchlist.AddToWatchlist: success", func(t *testing.T) {
+		for _, movie := range []*contracts.MovieDetails{starWars, lordOfTheRing} {
+			req := &contracts.AddToWatchlistRequest{
+				UserID:   viewer.ID,
+				MovieID:  movie.ID,
+				Notes:    contracts.Ptr("watch with friends"),
+				Priority: contracts.Ptr(3),
+			}
+			item, err := c.AddToWatchlist(contracts.NewAuthenticated(req, viewerToken))
+			require.NoError(t, err)
+			require.Equal(t, movie.ID, item.Movie.ID)
+			require.Equal(t, movie.Title, item.Movie.Title)
+			require.NotEmpty(t, item.AddedAt)
+		}
+	})
+	t.Run("watchlist.AddToWatchlist: already exists", func(t *testing.T) {
+		req := &contracts.AddToWatchlistRequest{UserID: viewer.ID, MovieID: starWars.ID}
+		_, err := c.AddToWatchlist(contracts.NewAuthenticated(req, viewerToken))
+		requireAlreadyExistError(t, err, "watchlist item", "movie_id", starWars.ID)
+	})
+	t.Run("watchlist.AddToWatchlist: movie not found", func(t *testing.T) {
+		notExistingID := 1000
+		req := &contracts.AddToWatchlistRequest{UserID: viewer.ID, MovieID: notExistingID}
+		_, err := c.AddToWatchlist(contracts.NewAuthenticated(req, viewerToken))
+		requireNotFoundError(t, err, "movie", "id", notExistingID)
+	})
+	t.Run("watchlist.GetWatchlist: another user", func(t *testing.T) {
+		req := &contracts.GetWatchlistRequest{UserID: viewer.ID}
+		_, err := c.GetWatchlist(contracts.NewAuthenticated(req, otherToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+	t.Run("watchlist.ReorderWatchlist: success", func(t *testing.T) {
+		req := &contracts.ReorderWatchlistRequest{
+			UserID:   viewer.ID,
+			MovieIDs: []int{lordOfTheRing.ID, starWars.ID},
+		}
+		err := c.ReorderWatchlist(contracts.NewAuthenticated(req, viewerToken))
+		require.NoError(t, err)
+
+		res, err := c.GetWatchlist(contracts.NewAuthenticated(&contracts.GetWatchlistRequest{UserID: viewer.ID}, viewerToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, res.Total)
+		require.Equal(t, lordOfTheRing.ID, res.Items[0].Movie.ID)
+		require.Equal(t, starWars.ID, res.Items[1].Movie.ID)
+	})
+	t.Run("watchlist.ReorderWatchlist: missing movies", func(t *testing.T) {
+		req := &contracts.ReorderWatchlistRequest{
+			UserID:   viewer.ID,
+			MovieIDs: []int{lordOfTheRing.ID},
+		}
+		err := c.ReorderWatchlist(contracts.NewAuthenticated(req, viewerToken))
+		requireBadRequestError(t, err, "movie_ids must contain every watchlist movie exactly once")
+	})
+	t.Run("watchlist.UpdateWatchlistItem: success", func(t *testing.T) {
+		req := &contracts.UpdateWatchlistItemRequest{
+			UserID:   viewer.ID,
+			MovieID:  starWars.ID,
+			Priority: contracts.Ptr(1),
+		}
+		err := c.UpdateWatchlistItem(contracts.NewAuthenticated(req, viewerToken))
+		require.NoError(t, err)
+	})
+	t.Run("watchlist.AddWatched: success", func(t *testing.T) {
+		req := &contracts.AddWatchedRequest{
+			UserID:    viewer.ID,
+			MovieID:   lordOfTheRing.ID,
+			WatchedOn: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+		entry, err := c.AddWatched(contracts.NewAuthenticated(req, viewerToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, entry.ID)
+		require.Equal(t, req.WatchedOn, entry.WatchedOn)
+
+		res, err := c.GetWatched(contracts.NewAuthenticated(&contracts.GetWatchedRequest{UserID: viewer.ID}, viewerToken))
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total)
+		require.Equal(t, lordOfTheRing.ID, res.Items[0].Movie.ID)
+	})
+	t.Run("movies.GetMovieByID: viewer flags", func(t *testing.T) {
+		cases := []struct {
+			movieID int
+			exp     contracts.MovieViewerFlags
+		}{
+			{starWars.ID, contracts.MovieViewerFlags{Watchlisted: true}},
+			{lordOfTheRing.ID, contracts.MovieViewerFlags{Watchlisted: true, Watched: true}},
+		}
+		for _, cc := range cases {
+			req := &contracts.GetOrDeleteMovieByIDRequest{ID: cc.movieID}
+			movie, err := c.GetMovieByIDAuthenticated(contracts.NewAuthenticated(req, viewerToken))
+			require.NoError(t, err)
+			require.Equal(t, &cc.exp, movie.Viewer)
+		}
+
+		movie, err := c.GetMovieByID(starWars.ID)
+		require.NoError(t, err)
+		require.Nil(t, movie.Viewer)
+	})
+	t.Run("watchlist.DeleteWatchlistItem: success", func(t *testing.T) {
+		req := &contracts.DeleteWatchlistItemRequest{UserID: viewer.ID, MovieID: starWars.ID}
+		err := c.DeleteWatchlistItem(contracts.NewAuthenticated(req, viewerToken))
+		require.NoError(t, err)
+
+		err = c.DeleteWatchlistItem(contracts.NewAuthenticated(req, viewerToken))
+		requireNotFoundError(t, err, "watchlist item", "movie_id", starWars.ID)
+	})
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/client"
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/webhooks"
+	"github.com/RadkevichAnn/movie-reviews/internal/signing"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver records the requests of webhook deliveries responding with the status code.
+type webhookReceiver struct {
+	*httptest.Server
+	requests chan *http.Request
+	bodies   chan []byte
+}
+
+func newWebhookReceiver(statusCode int) *webhookReceiver {
+	wr := &webhookReceiver{
+		requests: make(chan *http.Request, 20),
+		bodies:   make(chan []byte, 20),
+	}
+	wr.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		wr.requests <- r
+		wr.bodies <- body
+		w.WriteHeader(statusCode)
+	}))
+	return wr
+}
+
+// next returns the next received event after checking its signature.
+func (wr *webhookReceiver) next(t *testing.T, secret string) *contracts.WebhookEvent {
+	select {
+	case r := <-wr.requests:
+		body := <-wr.bodies
+		require.Equal(t, "sha256="+signing.Sign(secret, body), r.Header.Get(webhooks.SignatureHeader))
+
+		var event contracts.WebhookEvent
+		require.NoError(t, json.Unmarshal(body, &event))
+		require.Equal(t, event.Type, r.Header.Get(webhooks.EventHeader))
+		require.Equal(t, event.ID, r.Header.Get(webhooks.DeliveryHeader))
+		return &event
+	case <-time.After(10 * time.Second):
+		t.Fatal("webhook wasn't called")
+		return nil
+	}
+}
+
+func webhooksAPIChecks(t *testing.T, c *client.Client) {
+	receiver := newWebhookReceiver(http.StatusOK)
+	defer receiver.Close()
+
+	var webhook *contracts.Webhook
+	t.Run("webhooks.CreateWebhook: insufficient permissions", func(t *testing.T) {
+		req := &contracts.CreateWebhookRequest{URL: receiver.URL}
+		_, err := c.CreateWebhook(contracts.NewAuthenticated(req, johnDoeToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+	t.Run("webhooks.CreateWebhook: unknown event type", func(t *testing.T) {
+		req := &contracts.CreateWebhookRequest{URL: receiver.URL, EventTypes: []string{"movie.watched"}}
+		_, err := c.CreateWebhook(contracts.NewAuthenticated(req, adminToken))
+		requireBadRequestError(t, err, `unknown event type "movie.watched"`)
+	})
+	t.Run("webhooks.CreateWebhook: success", func(t *testing.T) {
+		req := &contracts.CreateWebhookRequest{
+			URL:        receiver.URL,
+			EventTypes: []string{contracts.WebhookEventGenreCreated, contracts.WebhookEventGenreCreated},
+		}
+		var err error
+		webhook, err = c.CreateWebhook(contracts.NewAuthenticated(req, adminToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, webhook.Secret)
+		require.Equal(t, []string{contracts.WebhookEventGenreCreated}, webhook.EventTypes)
+		require.True(t, webhook.IsActive)
+
+		got, err := c.GetWebhookByID(contracts.NewAuthenticated(&contracts.GetWebhookRequest{ID: webhook.ID}, adminToken))
+		require.NoError(t, err)
+		require.Empty(t, got.Secret)
+	})
+	t.Run("webhooks.PingWebhook: success", func(t *testing.T) {
+		delivery, err := c.PingWebhook(contracts.NewAuthenticated(&contracts.GetWebhookRequest{ID: webhook.ID}, adminToken))
+		require.NoError(t, err)
+		require.Equal(t, contracts.WebhookDeliverySucceeded, delivery.Status)
+		require.Len(t, delivery.Log, 1)
+		require.Equal(t, http.StatusOK, *delivery.Log[0].StatusCode)
+
+		event := receiver.next(t, webhook.Secret)
+		require.Equal(t, contracts.WebhookEventPing, event.Type)
+	})
+	t.Run("webhooks: genre created", func(t *testing.T) {
+		req := &contracts.CreateStarRequest{FirstName: "Filtered", LastName: "Out", BirthDate: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)}
+		_, err := c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		genre := createRandomGenre(t, c)
+
+		event := receiver.next(t, webhook.Secret)
+		require.Equal(t, contracts.WebhookEventGenreCreated, event.Type)
+		var data contracts.Genre
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		require.Equal(t, genre.ID, data.ID)
+	})
+	t.Run("webhooks.GetWebhookDeliveries: success", func(t *testing.T) {
+		status := contracts.WebhookDeliverySucceeded
+		req := &contracts.GetWebhookDeliveriesRequest{ID: webhook.ID, Status: &status}
+		retry.Run(t, func(r *retry.R) {
+			res, err := c.GetWebhookDeliveries(contracts.NewAuthenticated(req, adminToken))
+			require.NoError(r, err)
+			require.Equal(r, 2, res.Total)
+		})
+	})
+
+	failing := newWebhookReceiver(http.StatusInternalServerError)
+	defer failing.Close()
+	var failingWebhook *contracts.Webhook
+	t.Run("webhooks: dead letter", func(t *testing.T) {
+		var err error
+		req := &contracts.CreateWebhookRequest{URL: failing.URL}
+		failingWebhook, err = c.CreateWebhook(contracts.NewAuthenticated(req, adminToken))
+		require.NoError(t, err)
+		require.Empty(t, failingWebhook.EventTypes)
+		createRandomGenre(t, c)
+
+		var delivery *contracts.WebhookDelivery
+		retry.Run(t, func(r *retry.R) {
+			req := &contracts.GetFailedWebhookDeliveriesRequest{}
+			res, err := c.GetFailedWebhookDeliveries(contracts.NewAuthenticated(req, adminToken))
+			require.NoError(r, err)
+			for _, d := range res.Items {
+				if d.SubscriptionID == failingWebhook.ID {
+					delivery = d
+				}
+			}
+			require.NotNil(r, delivery)
+		})
+		require.Equal(t, contracts.WebhookEventGenreCreated, delivery.EventType)
+		require.Equal(t, 2, delivery.Attempts)
+		require.Equal(t, http.StatusInternalServerError, *delivery.LastStatusCode)
+
+		delivery, err = c.GetWebhookDelivery(contracts.NewAuthenticated(&contracts.GetWebhookDeliveryRequest{ID: delivery.ID}, adminToken))
+		require.NoError(t, err)
+		require.Len(t, delivery.Log, 2)
+
+		delivery, err = c.RedeliverWebhook(contracts.NewAuthenticated(&contracts.GetWebhookDeliveryRequest{ID: delivery.ID}, adminToken))
+		require.NoError(t, err)
+		require.Equal(t, contracts.WebhookDeliveryPending, delivery.Status)
+		require.Equal(t, 4, delivery.MaxAttempts)
+	})
+	t.Run("webhooks.UpdateWebhook: deactivate", func(t *testing.T) {
+		isActive := false
+		req := &contracts.UpdateWebhookRequest{ID: failingWebhook.ID, IsActive: &isActive}
+		updated, err := c.UpdateWebhook(contracts.NewAuthenticated(req, adminToken))
+		require.NoError(t, err)
+		require.False(t, updated.IsActive)
+		require.Empty(t, updated.EventTypes)
+	})
+	t.Run("webhooks.DeleteWebhook: success", func(t *testing.T) {
+		for _, id := range []int{webhook.ID, failingWebhook.ID} {
+			req := &contracts.GetWebhookRequest{ID: id}
+			require.NoError(t, c.DeleteWebhook(contracts.NewAuthenticated(req, adminToken)))
+			_, err := c.GetWebhookByID(contracts.NewAuthenticated(req, adminToken))
+			requireNotFoundError(t, err, "webhook", "id", id)
+		}
+	})
+}
+
+func createRandomGenre(t *testing.T, c *client.Client) *contracts.Genre {
+	req := &contracts.CreateGenreRequest{Name: fmt.Sprintf("Genre %d", rand.Intn(1000000))}
+	genre, err := c.CreateGenre(contracts.NewAuthenticated(req, johnDoeToken))
+	require.NoError(t, err)
+	return genre
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/caarlos0/env/v8"
+	"gopkg.in/validator.v2"
 )
 
 type AdminConfig struct {
@@ -13,13 +14,26 @@ type AdminConfig struct {
 	Password string `env:"PASSWORD" validate:"password"`
 }
 type Config struct {
-	DbUrl      string           `env:"DB_URL"`
-	Port       int              `env:"PORT" envDefault:"8080"`
-	JWT        JwtConfig        `envPrefix:"JWT_"`
-	Admin      AdminConfig      `envPrefix:"ADMIN_"`
-	Local      bool             `env:"LOCAL" envDefault:"false"`
-	LogLevel   string           `env:"LOG_LEVEL" envDefault:"info"`
-	Pagination PaginationConfig `envPrefix:"PAGINATION_"`
+	DbUrl           string                `env:"DB_URL"`
+	Port            int                   `env:"PORT" envDefault:"8080"`
+	JWT             JwtConfig             `envPrefix:"JWT_"`
+	Admin           AdminConfig           `envPrefix:"ADMIN_"`
+	Local           bool                  `env:"LOCAL" envDefault:"false"`
+	LogLevel        string                `env:"LOG_LEVEL" envDefault:"info"`
+	Pagination      PaginationConfig      `envPrefix:"PAGINATION_"`
+	Feed            FeedConfig            `envPrefix:"FEED_"`
+	Recommendations RecommendationsConfig `envPrefix:"RECOMMENDATIONS_"`
+	SimilarMovies   SimilarMoviesConfig   `envPrefix:"SIMILAR_MOVIES_"`
+	Images          ImagesConfig          `envPrefix:"IMAGES_"`
+	Bulk            BulkConfig            `envPrefix:"BULK_"`
+	Jobs            JobsConfig            `envPrefix:"JOBS_"`
+	Exports         ExportsConfig         `envPrefix:"EXPORTS_"`
+	Users           UsersConfig           `envPrefix:"USERS_"`
+	Notifications   NotificationsConfig   `envPrefix:"NOTIFICATIONS_"`
+	Webhooks        WebhooksConfig        `envPrefix:"WEBHOOKS_"`
+	Outbox          OutboxConfig          `envPrefix:"OUTBOX_"`
+	Live            LiveConfig            `envPrefix:"LIVE_"`
+	GraphQL         GraphQLConfig         `envPrefix:"GRAPHQL_"`
 }
 
 type JwtConfig struct {
@@ -31,15 +45,173 @@ type PaginationConfig struct {
 	MaxSize     int `env:"MAX_SIZE" envDefault:"100"`
 }
 
+// FeedConfig controls how activity feeds are built. Feeds are built on read from live data
+// unless SnapshotRefreshInterval is positive, in which case they are read from a snapshot
+// that is refreshed at that interval.
+type FeedConfig struct {
+	SnapshotRefreshInterval time.Duration `env:"SNAPSHOT_REFRESH_INTERVAL" envDefault:"0"`
+}
+
+func (fc *FeedConfig) Precomputed() bool {
+	return fc.SnapshotRefreshInterval > 0
+}
+
+// RecommendationsConfig tunes movie recommendations. Item-item similarities are recomputed by a periodic job
+// every RefreshInterval; a zero interval disables the background refresh.
+type RecommendationsConfig struct {
+	RefreshInterval time.Duration `env:"REFRESH_INTERVAL" envDefault:"1h"`
+	MinSupport      int           `env:"MIN_SUPPORT" envDefault:"2"`
+	Neighbors       int           `env:"NEIGHBORS" envDefault:"50"`
+	MinLikedRating  int           `env:"MIN_LIKED_RATING" envDefault:"7"`
+	GenreWeight     float64       `env:"GENRE_WEIGHT" envDefault:"1"`
+	StarWeight      float64       `env:"STAR_WEIGHT" envDefault:"2"`
+}
+
+// SimilarMoviesConfig holds the weights of the signals that make two movies similar.
+// Shared credits are weighted by the role in the source movie and decay with the billing order.
+// Release era proximity decreases linearly to zero at EraYears years apart.
+type SimilarMoviesConfig struct {
+	GenreWeight float64            `env:"GENRE_WEIGHT" envDefault:"1"`
+	CastWeight  float64            `env:"CAST_WEIGHT" envDefault:"1"`
+	RoleWeights map[string]float64 `env:"ROLE_WEIGHTS" envDefault:"director:3,writer:2,actor:1.5,voice actor:1,producer:1,composer:0.5"`
+	EraWeight   float64            `env:"ERA_WEIGHT" envDefault:"1"`
+	EraYears    int                `env:"ERA_YEARS" envDefault:"10" validate:"min=1"`
+	TextWeight  float64            `env:"TEXT_WEIGHT" envDefault:"2"`
+}
+
+// ImagesConfig controls the storage of uploaded images. Images are kept in Dir
+// and served as static files under BaseURL.
+type ImagesConfig struct {
+	Dir     string `env:"DIR" envDefault:"./data/images"`
+	BaseURL string `env:"BASE_URL" envDefault:"/images"`
+	MaxSize int64  `env:"MAX_SIZE" envDefault:"10485760"`
+}
+
+// BulkConfig limits bulk imports. Items are imported in transactions of ChunkSize items,
+// requests with more than MaxItems items are rejected.
+type BulkConfig struct {
+	ChunkSize int `env:"CHUNK_SIZE" envDefault:"200" validate:"min=1"`
+	MaxItems  int `env:"MAX_ITEMS" envDefault:"10000" validate:"min=1"`
+}
+
+// JobsConfig tunes the background job workers. A running job whose lock expired is considered abandoned
+// by a crashed worker and is picked up again. Failed jobs are retried with exponential backoff.
+type JobsConfig struct {
+	Workers      int           `env:"WORKERS" envDefault:"4"`
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"1s"`
+	LockTimeout  time.Duration `env:"LOCK_TIMEOUT" envDefault:"15m"`
+	MaxAttempts  int           `env:"MAX_ATTEMPTS" envDefault:"3"`
+	RetryBackoff time.Duration `env:"RETRY_BACKOFF" envDefault:"30s"`
+}
+
+// ExportsConfig controls data exports. Exports are kept in Dir, which must not be served publicly,
+// and are downloaded by links signed with Secret that expire after LinkExpiration, when the files are deleted.
+type ExportsConfig struct {
+	Dir            string        `env:"DIR" envDefault:"exports"`
+	Secret         string        `env:"SECRET" validate:"nonzero"`
+	LinkExpiration time.Duration `env:"LINK_EXPIRATION" envDefault:"24h"`
+}
+
+// UsersConfig controls user accounts. A deleted account can be restored during DeletionGracePeriod,
+// after which it is anonymized.
+type UsersConfig struct {
+	DeletionGracePeriod time.Duration `env:"DELETION_GRACE_PERIOD" envDefault:"720h"`
+}
+
+// NotificationsConfig controls the delivery of notifications. Webhooks of the users
+// must respond within WebhookTimeout. They must be on public addresses unless AllowPrivateWebhooks is set,
+// which is meant for local setups.
+type NotificationsConfig struct {
+	WebhookTimeout       time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"5s"`
+	AllowPrivateWebhooks bool          `env:"ALLOW_PRIVATE_WEBHOOKS" envDefault:"false"`
+}
+
+// WebhooksConfig tunes the delivery of catalog events to webhook subscriptions. Due deliveries are polled
+// every PollInterval in batches of BatchSize, whose deliveries are made concurrently and time out after
+// RequestTimeout. A failed delivery is retried with a backoff starting at RetryBackoff and doubling with every
+// attempt, until MaxAttempts is reached. A delivery whose outcome wasn't stored within LockTimeout, which must
+// exceed RequestTimeout, is attempted again.
+type WebhooksConfig struct {
+	PollInterval   time.Duration `env:"POLL_INTERVAL" envDefault:"1s"`
+	BatchSize      int           `env:"BATCH_SIZE" envDefault:"20" validate:"min=1"`
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" envDefault:"10s"`
+	MaxAttempts    int           `env:"MAX_ATTEMPTS" envDefault:"8" validate:"min=1"`
+	RetryBackoff   time.Duration `env:"RETRY_BACKOFF" envDefault:"30s"`
+	LockTimeout    time.Duration `env:"LOCK_TIMEOUT" envDefault:"1m"`
+}
+
+// OutboxConfig tunes the relay of domain events. Due events are polled every PollInterval in batches
+// of BatchSize. A failed event is retried with a backoff starting at RetryBackoff and doubling with every
+// attempt up to MaxBackoff, and dead-lettered after MaxAttempts. An event whose outcome wasn't stored
+// within LockTimeout is relayed again. Processed events are deleted after Retention, checked every CleanupInterval,
+// dead-lettered events are kept.
+type OutboxConfig struct {
+	PollInterval    time.Duration `env:"POLL_INTERVAL" envDefault:"500ms"`
+	BatchSize       int           `env:"BATCH_SIZE" envDefault:"100" validate:"min=1"`
+	LockTimeout     time.Duration `env:"LOCK_TIMEOUT" envDefault:"1m"`
+	RetryBackoff    time.Duration `env:"RETRY_BACKOFF" envDefault:"1s"`
+	MaxBackoff      time.Duration `env:"MAX_BACKOFF" envDefault:"10m"`
+	MaxAttempts     int           `env:"MAX_ATTEMPTS" envDefault:"20" validate:"min=1"`
+	Retention       time.Duration `env:"RETENTION" envDefault:"168h"`
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+// LiveConfig tunes the streams of live events. Idle streams get a heartbeat every HeartbeatInterval
+// and clients are told to reconnect after ClientRetry. A stream falling BufferSize events behind is closed,
+// and a reconnected client is sent at most ReplayLimit of the events it missed. Since the events aren't always
+// committed in the order of their ids, the ones stored within ReplayWindow before the last event a client got
+// are sent again. Events are kept for Retention, checked every CleanupInterval, and the
+// listener of the events is restarted after ListenRetry when its connection fails.
+type LiveConfig struct {
+	HeartbeatInterval time.Duration `env:"HEARTBEAT_INTERVAL" envDefault:"15s"`
+	ClientRetry       time.Duration `env:"CLIENT_RETRY" envDefault:"3s"`
+	BufferSize        int           `env:"BUFFER_SIZE" envDefault:"64"`
+	ReplayLimit       int           `env:"REPLAY_LIMIT" envDefault:"500" validate:"min=1"`
+	ReplayWindow      time.Duration `env:"REPLAY_WINDOW" envDefault:"5s"`
+	Retention         time.Duration `env:"RETENTION" envDefault:"24h"`
+	CleanupInterval   time.Duration `env:"CLEANUP_INTERVAL" envDefault:"1h"`
+	ListenRetry       time.Duration `env:"LISTEN_RETRY" envDefault:"1s"`
+}
+
+// GraphQLConfig limits the queries of the GraphQL endpoint: fields can't be nested deeper than MaxDepth,
+// and the sum of their complexity, where lists count as many times as the items they request, can't
+// exceed MaxComplexity. Lists of movies and reviews have at most MaxListSize items. Request bodies
+// larger than MaxBodySize, such as 64K or 1M, are rejected.
+type GraphQLConfig struct {
+	MaxDepth      int    `env:"MAX_DEPTH" envDefault:"8"`
+	MaxComplexity int    `env:"MAX_COMPLEXITY" envDefault:"1000"`
+	MaxListSize   int    `env:"MAX_LIST_SIZE" envDefault:"50"`
+	MaxBodySize   string `env:"MAX_BODY_SIZE" envDefault:"64K"`
+}
+
 func NewConfig() (*Config, error) {
 	var c Config
 	err := env.Parse(&c)
 	if err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
+	if err = c.validate(); err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
 	return &c, nil
 }
 
+// validate checks the settings that would make the server fail at runtime. The admin settings
+// are validated when the initial admin is created, since they are optional, and the exports settings
+// when the exports module is created, since the command line tools don't need them.
+func (c *Config) validate() error {
+	for _, v := range []any{c.SimilarMovies, c.Bulk, c.Webhooks, c.Outbox, c.Live} {
+		if err := validator.Validate(v); err != nil {
+			return err
+		}
+	}
+	if c.Webhooks.LockTimeout <= c.Webhooks.RequestTimeout {
+		return fmt.Errorf("webhooks lock timeout %s must exceed the request timeout %s",
+			c.Webhooks.LockTimeout, c.Webhooks.RequestTimeout)
+	}
+	return nil
+}
+
 func (ac *AdminConfig) IsSet() bool {
 	return ac.Username != "" && ac.Email != "" && ac.Password != ""
 }
@@ -20,3 +20,11 @@ func IsUniqueViolation(err error, name string) bool {
 func IsNoRows(err error) bool {
 	return errors.Is(err, pgx.ErrNoRows)
 }
+
+func IsForeignKeyViolation(err error, name string) bool {
+	var perr *pgconn.PgError
+	if errors.As(err, &perr) {
+		return perr.Code == pgerrcode.ForeignKeyViolation && strings.Contains(perr.ConstraintName, name)
+	}
+	return false
+}
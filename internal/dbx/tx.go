@@ -23,8 +23,16 @@ func FromContext(ctx context.Context, def Queryable) Queryable {
 	return def
 }
 
-func InTransaction(ctx context.Context, db *pgxpool.Pool, fn func(context.Context, pgx.Tx) error) error {
-	tx, err := db.Begin(ctx)
+// InTransaction runs fn in a transaction that is committed if fn succeeds and rolled back otherwise.
+// When ctx already carries a transaction, fn runs in a savepoint of it, so that a failure of fn
+// rolls back only its own changes.
+func InTransaction(ctx context.Context, db *pgxpool.Pool, fn func(context.Context, pgx.Tx) error) (err error) {
+	var tx pgx.Tx
+	if outer, ok := ctx.Value(contextKey).(pgx.Tx); ok {
+		tx, err = outer.Begin(ctx)
+	} else {
+		tx, err = db.Begin(ctx)
+	}
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
@@ -33,15 +41,32 @@ func InTransaction(ctx context.Context, db *pgxpool.Pool, fn func(context.Contex
 	defer func() {
 		if err != nil {
 			if txErr := tx.Rollback(ctx); txErr != nil {
-				err = errors.Join(err, fmt.Errorf("rollback transaction: %w", err))
+				err = errors.Join(err, fmt.Errorf("rollback transaction: %w", txErr))
 			}
 		} else {
-			cerr := tx.Commit(ctx)
-			if cerr != nil {
-				err = fmt.Errorf("commit transaction: %w", err)
+			if cerr := tx.Commit(ctx); cerr != nil {
+				err = fmt.Errorf("commit transaction: %w", cerr)
 			}
 		}
 	}()
 
 	return fn(ctx, tx)
 }
+
+// Transactor runs functions in transactions of the pool. It's meant to be embedded in the repositories
+// whose services group several changes in a transaction.
+type Transactor struct {
+	db *pgxpool.Pool
+}
+
+func NewTransactor(db *pgxpool.Pool) Transactor {
+	return Transactor{db: db}
+}
+
+// InTransaction runs fn in a transaction, or in a savepoint when ctx already carries a transaction.
+// The transaction is passed to the queries through ctx.
+func (t Transactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return InTransaction(ctx, t.db, func(ctx context.Context, _ pgx.Tx) error {
+		return fn(ctx)
+	})
+}
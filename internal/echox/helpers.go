@@ -1,6 +1,9 @@
 package echox
 
 import (
+	"fmt"
+	"mime/multipart"
+
 	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
 	"github.com/labstack/echo/v4"
 	"gopkg.in/validator.v2"
@@ -16,3 +19,16 @@ func BindAndValidate[T any](c echo.Context) (*T, error) {
 	}
 	return req, nil
 }
+
+// OpenFormFile opens the file uploaded in the multipart form field with the name.
+func OpenFormFile(c echo.Context, name string) (multipart.File, error) {
+	header, err := c.FormFile(name)
+	if err != nil {
+		return nil, apperrors.BadRequestHidden(err, fmt.Sprintf("missing %s file", name))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return file, nil
+}
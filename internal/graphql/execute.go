@@ -0,0 +1,692 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Response is the result of a request. Data is nil if the request is invalid, otherwise it holds
+// the fields resolved without error, the others being null.
+type Response struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+	// Path is the response keys of the field that failed. Since fields are resolved for all
+	// their parents at once, it doesn't contain list indexes.
+	Path []any `json:"path,omitempty"`
+	// Err is the error returned by the resolver, if any.
+	Err error `json:"-"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Limits bound the cost of a query, which is checked before it's executed. Zero means no limit.
+type Limits struct {
+	// MaxDepth is the maximum nesting of fields, the root fields being at depth 1. It also bounds the nesting
+	// of the document, which is checked while it's parsed.
+	MaxDepth int
+	// MaxComplexity is the maximum sum of the complexity of the fields.
+	MaxComplexity int
+}
+
+// Execute validates and runs the query operation of the request. Fields of the same level are
+// resolved for all their parents at once, so a query costs one resolver call per field of the
+// document, whatever the number of values returned.
+//
+// A null value of a non-null field is reported as an error, but isn't propagated to its parent.
+func (s *Schema) Execute(ctx context.Context, req *Request, limits Limits) *Response {
+	e, err := s.prepare(req, limits)
+	if err != nil {
+		var gqlErr *Error
+		if !errors.As(err, &gqlErr) {
+			gqlErr = &Error{Message: err.Error()}
+		}
+		return &Response{Errors: []*Error{gqlErr}}
+	}
+	data := e.executeSelections(ctx, s.Query, []any{nil}, e.op.selections, nil)[0]
+	return &Response{Data: data, Errors: e.errors}
+}
+
+type execution struct {
+	schema *Schema
+	doc    *document
+	op     *operation
+	limits Limits
+	// vars are the coerced values of the variables provided or having a default value.
+	vars    map[string]any
+	defined map[string]bool
+	// args are the coerced arguments of the fields of the document.
+	args map[*field]map[string]any
+	// fields is the number of fields validated so far. Since every field costs at least 1, it's a lower bound
+	// of the complexity, which stops the validation of a query expanding to too many fields, e.g. through
+	// fragments spread under many aliases, before all of them are expanded.
+	fields int
+	errors []*Error
+}
+
+func (s *Schema) prepare(req *Request, limits Limits) (*execution, error) {
+	doc, err := parse(req.Query, limits.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != "query" {
+		return nil, fmt.Errorf("%s operations are not supported", op.kind)
+	}
+	if len(op.directives) > 0 {
+		return nil, fmt.Errorf("directive @%s is not supported on operations", op.directives[0].name)
+	}
+	if err = checkFragments(doc); err != nil {
+		return nil, err
+	}
+	e := &execution{
+		schema:  s,
+		doc:     doc,
+		op:      op,
+		limits:  limits,
+		vars:    make(map[string]any),
+		defined: make(map[string]bool),
+		args:    make(map[*field]map[string]any),
+	}
+	if err = e.coerceVariables(req.Variables); err != nil {
+		return nil, err
+	}
+	complexity, err := e.validateSelections(s.Query, op.selections, 1)
+	if err != nil {
+		return nil, err
+	}
+	if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+		return nil, fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, limits.MaxComplexity)
+	}
+	return e, nil
+}
+
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) != 1 {
+			return nil, errors.New("the document must contain exactly one operation, or the operation name must be given")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// checkFragments checks that the spread fragments exist and don't spread themselves.
+func checkFragments(doc *document) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		states[name] = visiting
+		for _, spread := range fragmentSpreads(doc.fragments[name].selections, nil) {
+			if _, ok := doc.fragments[spread]; !ok {
+				return fmt.Errorf("unknown fragment %q", spread)
+			}
+			switch states[spread] {
+			case visiting:
+				return fmt.Errorf("fragment %q spreads itself", spread)
+			case 0:
+				if err := visit(spread); err != nil {
+					return err
+				}
+			}
+		}
+		states[name] = visited
+		return nil
+	}
+
+	names := make([]string, 0, len(doc.fragments))
+	for name := range doc.fragments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if states[name] == 0 {
+			if err := visit(name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, op := range doc.operations {
+		for _, spread := range fragmentSpreads(op.selections, nil) {
+			if _, ok := doc.fragments[spread]; !ok {
+				return fmt.Errorf("unknown fragment %q", spread)
+			}
+		}
+	}
+	return nil
+}
+
+// fragmentSpreads appends the names of the fragments spread in the selections, at any level.
+func fragmentSpreads(selections []selection, names []string) []string {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			names = fragmentSpreads(sel.selections, names)
+		case *fragmentSpread:
+			names = append(names, sel.name)
+		case *inlineFragment:
+			names = fragmentSpreads(sel.selections, names)
+		}
+	}
+	return names
+}
+
+func (e *execution) coerceVariables(values map[string]any) error {
+	for _, def := range e.op.variables {
+		if e.defined[def.name] {
+			return fmt.Errorf("variable $%s is defined more than once", def.name)
+		}
+		e.defined[def.name] = true
+		t, err := e.schema.inputType(def.typ)
+		if err != nil {
+			return fmt.Errorf("variable $%s: %w", def.name, err)
+		}
+		raw, ok := values[def.name]
+		switch {
+		case ok:
+			e.vars[def.name], err = coerceInput(t, raw)
+		case def.defaultValue != nil:
+			e.vars[def.name], err = e.coerceLiteral(t, def.defaultValue)
+		default:
+			if _, nonNull := t.(*NonNull); nonNull {
+				err = fmt.Errorf("value of type %s is required", t)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("variable $%s: %w", def.name, err)
+		}
+	}
+	return nil
+}
+
+// inputType returns the type of a variable, which can only be made of scalars.
+func (s *Schema) inputType(ref *typeRef) (Type, error) {
+	var t Type
+	if ref.elem != nil {
+		elem, err := s.inputType(ref.elem)
+		if err != nil {
+			return nil, err
+		}
+		t = NewList(elem)
+	} else {
+		scalar, ok := s.types[ref.name].(*Scalar)
+		if !ok {
+			return nil, fmt.Errorf("unknown input type %s", ref.name)
+		}
+		t = scalar
+	}
+	if ref.nonNull {
+		t = NewNonNull(t)
+	}
+	return t, nil
+}
+
+// coerceInput coerces a value decoded from JSON, or an already coerced variable.
+func coerceInput(t Type, v any) (any, error) {
+	if nn, ok := t.(*NonNull); ok {
+		if v == nil {
+			return nil, fmt.Errorf("expected %s, found null", t)
+		}
+		return coerceInput(nn.OfType, v)
+	}
+	if v == nil {
+		return nil, nil
+	}
+	switch t := t.(type) {
+	case *List:
+		items, ok := v.([]any)
+		if !ok {
+			item, err := coerceInput(t.OfType, v)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+		list := make([]any, len(items))
+		for i, item := range items {
+			var err error
+			if list[i], err = coerceInput(t.OfType, item); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case *Scalar:
+		if coerced, ok := t.coerce(v); ok {
+			return coerced, nil
+		}
+		return nil, fmt.Errorf("expected %s, found %v", t, v)
+	default:
+		return nil, fmt.Errorf("type %s can't be an input", t)
+	}
+}
+
+// coerceLiteral coerces a value of the document, which may contain variables.
+func (e *execution) coerceLiteral(t Type, v value) (any, error) {
+	if name, ok := v.(variable); ok {
+		if !e.defined[string(name)] {
+			return nil, fmt.Errorf("variable $%s is not defined", name)
+		}
+		return coerceInput(t, e.vars[string(name)])
+	}
+	if nn, ok := t.(*NonNull); ok {
+		if v == nil {
+			return nil, fmt.Errorf("expected %s, found null", t)
+		}
+		return e.coerceLiteral(nn.OfType, v)
+	}
+	if v == nil {
+		return nil, nil
+	}
+	switch t := t.(type) {
+	case *List:
+		items, ok := v.([]value)
+		if !ok {
+			item, err := e.coerceLiteral(t.OfType, v)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+		list := make([]any, len(items))
+		for i, item := range items {
+			var err error
+			if list[i], err = e.coerceLiteral(t.OfType, item); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case *Scalar:
+		if coerced, ok := t.coerce(v); ok {
+			return coerced, nil
+		}
+		return nil, fmt.Errorf("expected %s, found %v", t, v)
+	default:
+		return nil, fmt.Errorf("type %s can't be an input", t)
+	}
+}
+
+// fieldGroup is the fields of a selection set sharing a response key, which are merged.
+type fieldGroup struct {
+	key    string
+	fields []*field
+}
+
+// collectFields groups the fields selected on the object, expanding the fragments and applying
+// the @skip and @include directives.
+func (e *execution) collectFields(obj *Object, selections []selection) ([]*fieldGroup, error) {
+	var groups []*fieldGroup
+	index := make(map[string]*fieldGroup)
+	spread := make(map[string]bool)
+
+	var collect func(selections []selection) error
+	collect = func(selections []selection) error {
+		for _, sel := range selections {
+			switch sel := sel.(type) {
+			case *field:
+				if ok, err := e.included(sel.directives); err != nil {
+					return err
+				} else if !ok {
+					continue
+				}
+				key := sel.responseKey()
+				g, ok := index[key]
+				if !ok {
+					g = &fieldGroup{key: key}
+					index[key] = g
+					groups = append(groups, g)
+				}
+				g.fields = append(g.fields, sel)
+			case *fragmentSpread:
+				if ok, err := e.included(sel.directives); err != nil {
+					return err
+				} else if !ok {
+					continue
+				}
+				if spread[sel.name] {
+					continue
+				}
+				spread[sel.name] = true
+				frag := e.doc.fragments[sel.name]
+				if frag.typeCondition != obj.Name {
+					return fmt.Errorf("fragment %q on %s can't be spread on %s", frag.name, frag.typeCondition, obj.Name)
+				}
+				if err := collect(frag.selections); err != nil {
+					return err
+				}
+			case *inlineFragment:
+				if ok, err := e.included(sel.directives); err != nil {
+					return err
+				} else if !ok {
+					continue
+				}
+				if sel.typeCondition != "" && sel.typeCondition != obj.Name {
+					return fmt.Errorf("fragment on %s can't be spread on %s", sel.typeCondition, obj.Name)
+				}
+				if err := collect(sel.selections); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := collect(selections); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// included applies the @skip and @include directives, the only ones supported.
+func (e *execution) included(directives []*directive) (bool, error) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			return false, fmt.Errorf("unknown directive @%s", d.name)
+		}
+		if len(d.arguments) != 1 || d.arguments[0].name != "if" {
+			return false, fmt.Errorf("directive @%s requires a single argument \"if\"", d.name)
+		}
+		v, err := e.coerceLiteral(NewNonNull(Boolean), d.arguments[0].value)
+		if err != nil {
+			return false, fmt.Errorf("directive @%s: %w", d.name, err)
+		}
+		if v.(bool) == (d.name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// validateSelections checks the selections of the object, coerces the arguments of their fields and
+// returns their complexity.
+func (e *execution) validateSelections(obj *Object, selections []selection, depth int) (int, error) {
+	groups, err := e.collectFields(obj, selections)
+	if err != nil {
+		return 0, err
+	}
+	complexity := 0
+	for _, g := range groups {
+		f := g.fields[0]
+		for _, other := range g.fields[1:] {
+			if other.name != f.name {
+				return 0, fmt.Errorf("fields %q and %q conflict because both are returned as %q", f.name, other.name, g.key)
+			}
+		}
+		if f.name == "__typename" {
+			for _, node := range g.fields {
+				if len(node.arguments) > 0 || len(node.selections) > 0 {
+					return 0, errors.New("field \"__typename\" can't have arguments or a selection of subfields")
+				}
+			}
+			continue
+		}
+		def, ok := obj.Fields[f.name]
+		if !ok {
+			return 0, fmt.Errorf("cannot query field %q on type %s", f.name, obj.Name)
+		}
+		if e.limits.MaxDepth > 0 && depth > e.limits.MaxDepth {
+			return 0, fmt.Errorf("query depth exceeds the limit of %d", e.limits.MaxDepth)
+		}
+		e.fields++
+		if e.limits.MaxComplexity > 0 && e.fields > e.limits.MaxComplexity {
+			return 0, fmt.Errorf("query complexity exceeds the limit of %d", e.limits.MaxComplexity)
+		}
+
+		var children []selection
+		for i, node := range g.fields {
+			var args map[string]any
+			if args, err = e.coerceArguments(obj, node, def); err != nil {
+				return 0, err
+			}
+			if i > 0 && !reflect.DeepEqual(args, e.args[f]) {
+				return 0, fmt.Errorf("field %q is queried with different arguments as %q", f.name, g.key)
+			}
+			e.args[node] = args
+			children = append(children, node.selections...)
+		}
+
+		childComplexity := 0
+		if child, ok := namedType(def.Type).(*Object); ok {
+			if len(children) == 0 {
+				return 0, fmt.Errorf("field %q of type %s must have a selection of subfields", f.name, def.Type)
+			}
+			if childComplexity, err = e.validateSelections(child, children, depth+1); err != nil {
+				return 0, err
+			}
+		} else if len(children) > 0 {
+			return 0, fmt.Errorf("field %q of type %s can't have a selection of subfields", f.name, def.Type)
+		}
+		if def.Complexity != nil {
+			complexity += def.Complexity(e.args[f], childComplexity)
+		} else {
+			complexity += 1 + childComplexity
+		}
+	}
+	return complexity, nil
+}
+
+func (e *execution) coerceArguments(obj *Object, f *field, def *Field) (map[string]any, error) {
+	literals := make(map[string]value, len(f.arguments))
+	for _, arg := range f.arguments {
+		if _, ok := def.Args[arg.name]; !ok {
+			return nil, fmt.Errorf("unknown argument %q on field %s.%s", arg.name, obj.Name, f.name)
+		}
+		if _, ok := literals[arg.name]; ok {
+			return nil, fmt.Errorf("argument %q of field %s.%s is given more than once", arg.name, obj.Name, f.name)
+		}
+		literals[arg.name] = arg.value
+	}
+
+	names := make([]string, 0, len(def.Args))
+	for name := range def.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make(map[string]any, len(def.Args))
+	for _, name := range names {
+		argDef := def.Args[name]
+		literal, ok := literals[name]
+		// An argument set to a variable which isn't provided is omitted
+		if v, isVar := literal.(variable); ok && isVar && e.defined[string(v)] {
+			_, ok = e.vars[string(v)]
+		}
+		if !ok {
+			if argDef.Default != nil {
+				args[name] = argDef.Default
+			} else if _, nonNull := argDef.Type.(*NonNull); nonNull {
+				return nil, fmt.Errorf("argument %q of field %s.%s is required", name, obj.Name, f.name)
+			}
+			continue
+		}
+		v, err := e.coerceLiteral(argDef.Type, literal)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q of field %s.%s: %w", name, obj.Name, f.name, err)
+		}
+		args[name] = v
+	}
+	return args, nil
+}
+
+// executeSelections resolves the selections of the object for all the sources at once.
+func (e *execution) executeSelections(ctx context.Context, obj *Object, sources []any, selections []selection, path []any) []*orderedMap {
+	results := make([]*orderedMap, len(sources))
+	for i := range results {
+		results[i] = &orderedMap{values: make(map[string]any)}
+	}
+	// The selections were validated, they can be collected without error
+	groups, _ := e.collectFields(obj, selections)
+	for _, g := range groups {
+		f := g.fields[0]
+		fieldPath := append(append([]any{}, path...), g.key)
+		if f.name == "__typename" {
+			for _, res := range results {
+				res.set(g.key, obj.Name)
+			}
+			continue
+		}
+
+		def := obj.Fields[f.name]
+		values, err := def.Resolve(ctx, ResolveParams{Sources: sources, Args: e.args[f]})
+		if err == nil && len(values) != len(sources) {
+			err = fmt.Errorf("field %s.%s resolved %d values for %d sources", obj.Name, f.name, len(values), len(sources))
+		}
+		var completed []any
+		if err != nil {
+			e.errors = append(e.errors, &Error{Message: err.Error(), Path: fieldPath, Err: err})
+			completed = make([]any, len(sources))
+		} else {
+			var children []selection
+			for _, node := range g.fields {
+				children = append(children, node.selections...)
+			}
+			completed = e.completeValues(ctx, def.Type, values, children, fieldPath)
+		}
+		for i, res := range results {
+			res.set(g.key, completed[i])
+		}
+	}
+	return results
+}
+
+// completeValues converts the resolved values of a field to the values of the response, resolving
+// the selections of objects.
+func (e *execution) completeValues(ctx context.Context, t Type, values []any, selections []selection, path []any) []any {
+	results := make([]any, len(values))
+	switch t := t.(type) {
+	case *NonNull:
+		results = e.completeValues(ctx, t.OfType, values, selections, path)
+		for _, res := range results {
+			if res == nil {
+				e.errors = append(e.errors, &Error{Message: fmt.Sprintf("cannot return null for non-null type %s", t), Path: path})
+				break
+			}
+		}
+	case *List:
+		var items []any
+		lengths := make([]int, len(values))
+		for i, v := range values {
+			if isNil(v) {
+				lengths[i] = -1
+				continue
+			}
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Slice {
+				e.errors = append(e.errors, &Error{Message: fmt.Sprintf("expected a list, found %T", v), Path: path})
+				lengths[i] = -1
+				continue
+			}
+			lengths[i] = rv.Len()
+			for j := 0; j < rv.Len(); j++ {
+				items = append(items, rv.Index(j).Interface())
+			}
+		}
+		completed := e.completeValues(ctx, t.OfType, items, selections, path)
+		offset := 0
+		for i, n := range lengths {
+			if n < 0 {
+				continue
+			}
+			results[i] = completed[offset : offset+n : offset+n]
+			offset += n
+		}
+	case *Scalar:
+		for i, v := range values {
+			results[i] = scalarValue(v)
+		}
+	case *Object:
+		var (
+			sources []any
+			indexes []int
+		)
+		for i, v := range values {
+			if !isNil(v) {
+				sources = append(sources, v)
+				indexes = append(indexes, i)
+			}
+		}
+		if len(sources) > 0 {
+			for i, res := range e.executeSelections(ctx, t, sources, selections, path) {
+				results[indexes[i]] = res
+			}
+		}
+	}
+	return results
+}
+
+// scalarValue dereferences the pointers of a scalar value.
+func scalarValue(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	return rv.Interface()
+}
+
+// orderedMap is an object of the response, its keys are kept in the order of the selections.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func (m *orderedMap) set(key string, v any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
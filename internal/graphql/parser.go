@@ -0,0 +1,556 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// document is a parsed GraphQL document of executable definitions.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	kind       string
+	name       string
+	variables  []*variableDefinition
+	directives []*directive
+	selections []selection
+}
+
+type variableDefinition struct {
+	name         string
+	typ          *typeRef
+	defaultValue value
+	pos          int
+}
+
+// typeRef is a type of a variable definition: a named type, or a list of elem, optionally non-null.
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+}
+
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// selection is a *field, a *fragmentSpread or an *inlineFragment.
+type selection interface{}
+
+type field struct {
+	alias      string
+	name       string
+	arguments  []*argument
+	directives []*directive
+	selections []selection
+	pos        int
+}
+
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type argument struct {
+	name  string
+	value value
+}
+
+type directive struct {
+	name      string
+	arguments []*argument
+	pos       int
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+	pos        int
+}
+
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	selections    []selection
+	pos           int
+}
+
+// value is a literal or a variable: variable, int, float64, string, bool, nil, enumValue, []value or objectValue.
+type value interface{}
+
+type variable string
+
+type enumValue string
+
+type objectValue map[string]value
+
+type parser struct {
+	lex *lexer
+	tok token
+	// depth is the current nesting of selection sets, list and object values and list types,
+	// which can't exceed maxDepth, so that a deeply nested document doesn't exhaust the stack.
+	depth    int
+	maxDepth int
+}
+
+// parse parses the document, failing if it's nested deeper than maxDepth. Zero means no limit.
+func parse(src string, maxDepth int) (*document, error) {
+	p := &parser{lex: &lexer{src: src}, maxDepth: maxDepth}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selections: selections})
+		case p.peek(tokenName, "fragment"):
+			f, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[f.name]; ok {
+				return nil, p.errorf(f.pos, "fragment %q is defined more than once", f.name)
+			}
+			doc.fragments[f.name] = f
+		case p.peek(tokenName, "query"), p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, &Error{Message: "document contains no operation"}
+	}
+	return doc, nil
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if p.peek(tokenPunct, "(") {
+		if op.variables, err = p.parseVariableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+	if op.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if op.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]*variableDefinition, error) {
+	if err := p.expect(tokenPunct, "("); err != nil {
+		return nil, err
+	}
+	var defs []*variableDefinition
+	for !p.peek(tokenPunct, ")") {
+		def := &variableDefinition{pos: p.tok.pos}
+		if err := p.expect(tokenPunct, "$"); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		def.name = name
+		if err = p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		if def.typ, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if p.peek(tokenPunct, "=") {
+			if err = p.advance(); err != nil {
+				return nil, err
+			}
+			if def.defaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+		defs = append(defs, def)
+	}
+	return defs, p.advance()
+}
+
+func (p *parser) parseType() (*typeRef, error) {
+	t := &typeRef{}
+	if p.peek(tokenPunct, "[") {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		t.elem = elem
+		if err = p.expect(tokenPunct, "]"); err != nil {
+			return nil, err
+		}
+	} else {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		t.name = name
+	}
+	if p.peek(tokenPunct, "!") {
+		t.nonNull = true
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	if err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+	var selections []selection
+	for !p.peek(tokenPunct, "}") {
+		s, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, s)
+	}
+	if len(selections) == 0 {
+		return nil, p.errorf(p.tok.pos, "selection set is empty")
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	if !p.peek(tokenPunct, "...") {
+		return p.parseField()
+	}
+	pos := p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		spread := &fragmentSpread{name: p.tok.value, pos: pos}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		spread.directives, err = p.parseDirectives()
+		return spread, err
+	}
+	inline := &inlineFragment{}
+	if p.peek(tokenName, "on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		inline.typeCondition = name
+	}
+	var err error
+	if inline.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if inline.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return inline, nil
+}
+
+func (p *parser) parseField() (*field, error) {
+	f := &field{pos: p.tok.pos}
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunct, ":") {
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		f.alias = name
+		if name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+	f.name = name
+	if p.peek(tokenPunct, "(") {
+		if f.arguments, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+	}
+	if f.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunct, "{") {
+		if f.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) parseArguments() ([]*argument, error) {
+	if err := p.expect(tokenPunct, "("); err != nil {
+		return nil, err
+	}
+	var args []*argument
+	for !p.peek(tokenPunct, ")") {
+		pos := p.tok.pos
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		for _, arg := range args {
+			if arg.name == name {
+				return nil, p.errorf(pos, "argument %q is set more than once", name)
+			}
+		}
+		if err = p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &argument{name: name, value: v})
+	}
+	if len(args) == 0 {
+		return nil, p.errorf(p.tok.pos, "argument list is empty")
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseDirectives() ([]*directive, error) {
+	var directives []*directive
+	for p.peek(tokenPunct, "@") {
+		d := &directive{pos: p.tok.pos}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		d.name = name
+		if p.peek(tokenPunct, "(") {
+			if d.arguments, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	f := &fragment{pos: p.tok.pos}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenName, "on") {
+		return nil, p.unexpected()
+	}
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	f.name = name
+	if err = p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+	if f.typeCondition, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunct, "@") {
+		return nil, p.errorf(p.tok.pos, "directives on fragment definitions are not supported")
+	}
+	if f.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseValue parses a value, which can't contain variables if it's constant.
+func (p *parser) parseValue(constant bool) (value, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokenPunct && tok.value == "$":
+		if constant {
+			return nil, p.errorf(tok.pos, "variables are not allowed here")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		return variable(name), err
+	case tok.kind == tokenPunct && tok.value == "[":
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []value{}
+		for !p.peek(tokenPunct, "]") {
+			v, err := p.parseValue(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, p.advance()
+	case tok.kind == tokenPunct && tok.value == "{":
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		obj := objectValue{}
+		for !p.peek(tokenPunct, "}") {
+			pos := p.tok.pos
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := obj[name]; ok {
+				return nil, p.errorf(pos, "field %q is set more than once", name)
+			}
+			if err = p.expect(tokenPunct, ":"); err != nil {
+				return nil, err
+			}
+			if obj[name], err = p.parseValue(constant); err != nil {
+				return nil, err
+			}
+		}
+		return obj, p.advance()
+	case tok.kind == tokenInt:
+		n, err := strconv.ParseInt(tok.value, 10, 32)
+		if err != nil {
+			return nil, p.errorf(tok.pos, "int %s is out of range", tok.value)
+		}
+		return int(n), p.advance()
+	case tok.kind == tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf(tok.pos, "float %s is out of range", tok.value)
+		}
+		return f, p.advance()
+	case tok.kind == tokenString:
+		return tok.value, p.advance()
+	case tok.kind == tokenName:
+		var v value
+		switch tok.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = enumValue(tok.value)
+		}
+		return v, p.advance()
+	default:
+		return nil, p.unexpected()
+	}
+}
+
+func (p *parser) parseName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) peek(kind tokenKind, v string) bool {
+	return p.tok.kind == kind && p.tok.value == v
+}
+
+func (p *parser) expect(kind tokenKind, v string) error {
+	if !p.peek(kind, v) {
+		return p.errorf(p.tok.pos, "expected %q, found %s", v, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// enter starts a nested element, which must be ended with leave.
+func (p *parser) enter() error {
+	if p.maxDepth > 0 && p.depth >= p.maxDepth {
+		return &Error{Message: fmt.Sprintf("query depth exceeds the limit of %d", p.maxDepth)}
+	}
+	p.depth++
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) unexpected() error {
+	return p.errorf(p.tok.pos, "unexpected %s", p.tok)
+}
+
+func (p *parser) errorf(pos int, format string, args ...any) error {
+	return p.lex.errorf(pos, format, args...)
+}
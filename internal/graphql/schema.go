@@ -0,0 +1,271 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Type is a *Scalar, an *Object, a *List or a *NonNull.
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Its output values are serialized as is, pointers are dereferenced.
+type Scalar struct {
+	Name string
+	// coerce converts an input value, literal or decoded from JSON, to the Go value passed to the resolvers.
+	coerce func(v any) (any, bool)
+}
+
+func (t *Scalar) String() string { return t.Name }
+
+// Built-in scalars. Int input values are passed to the resolvers as int, Float as float64,
+// and String, Boolean and ID as string and bool.
+var (
+	Int     = &Scalar{Name: "Int", coerce: coerceInt}
+	Float   = &Scalar{Name: "Float", coerce: coerceFloat}
+	String  = &Scalar{Name: "String", coerce: coerceString}
+	Boolean = &Scalar{Name: "Boolean", coerce: coerceBoolean}
+	ID      = &Scalar{Name: "ID", coerce: coerceID}
+)
+
+// Object is a type made of fields. Fields can be set after the object is created, so that objects
+// can reference each other.
+type Object struct {
+	Name   string
+	Fields Fields
+}
+
+func (t *Object) String() string { return t.Name }
+
+type Fields map[string]*Field
+
+// List is a list of OfType. A nil slice returned by a resolver is an empty list.
+type List struct {
+	OfType Type
+}
+
+func NewList(t Type) *List { return &List{OfType: t} }
+
+func (t *List) String() string { return "[" + t.OfType.String() + "]" }
+
+// NonNull is a type that can't be null.
+type NonNull struct {
+	OfType Type
+}
+
+func NewNonNull(t Type) *NonNull { return &NonNull{OfType: t} }
+
+func (t *NonNull) String() string { return t.OfType.String() + "!" }
+
+type Field struct {
+	Type Type
+	Args Args
+	// Resolve resolves the field of all the parent values at once.
+	Resolve ResolveFunc
+	// Complexity returns the cost of the field given its arguments and the cost of its selections.
+	// By default, it's 1 plus the cost of the selections, which it must not be lower than.
+	Complexity func(args map[string]any, childComplexity int) int
+}
+
+type Args map[string]*Argument
+
+type Argument struct {
+	Type    Type
+	Default any
+}
+
+// ResolveParams are the parent values of a field, in the order the results are expected, and its
+// arguments. Sources of the root fields contain a single nil value.
+type ResolveParams struct {
+	Sources []any
+	Args    map[string]any
+}
+
+// ResolveFunc returns the value of the field for each of the sources. A value can be nil, a pointer
+// or, for list types, a slice.
+type ResolveFunc func(ctx context.Context, p ResolveParams) ([]any, error)
+
+// Each builds a ResolveFunc from a function resolving the field of a single source.
+// It's meant for fields that don't need a query, as it doesn't batch anything.
+func Each[S any](fn func(ctx context.Context, source S, args map[string]any) (any, error)) ResolveFunc {
+	return func(ctx context.Context, p ResolveParams) ([]any, error) {
+		values := make([]any, len(p.Sources))
+		for i, source := range p.Sources {
+			s, _ := source.(S)
+			v, err := fn(ctx, s, argsOrEmpty(p.Args))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	}
+}
+
+// Prop builds a ResolveFunc from a function returning a property of the source.
+func Prop[S any](fn func(source S) any) ResolveFunc {
+	return Each(func(_ context.Context, source S, _ map[string]any) (any, error) {
+		return fn(source), nil
+	})
+}
+
+func argsOrEmpty(m map[string]any) map[string]any {
+	if m == nil {
+		return map[string]any{}
+	}
+	return m
+}
+
+// Schema is a query-only schema. Mutations and subscriptions go through the REST API.
+type Schema struct {
+	Query *Object
+	types map[string]Type
+}
+
+// NewSchema checks the types reachable from the query type.
+func NewSchema(query *Object) (*Schema, error) {
+	s := &Schema{Query: query, types: make(map[string]Type)}
+	for _, t := range []*Scalar{Int, Float, String, Boolean, ID} {
+		s.types[t.Name] = t
+	}
+	if err := s.addType(query); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Schema) addType(t Type) error {
+	switch t := t.(type) {
+	case *List:
+		return s.addType(t.OfType)
+	case *NonNull:
+		if _, ok := t.OfType.(*NonNull); ok {
+			return fmt.Errorf("type %s is non-null twice", t)
+		}
+		return s.addType(t.OfType)
+	case *Scalar:
+		if existing, ok := s.types[t.Name]; ok && existing != t {
+			return fmt.Errorf("type %s is defined more than once", t.Name)
+		}
+		s.types[t.Name] = t
+		return nil
+	case *Object:
+		if existing, ok := s.types[t.Name]; ok {
+			if existing != t {
+				return fmt.Errorf("type %s is defined more than once", t.Name)
+			}
+			return nil
+		}
+		s.types[t.Name] = t
+		if len(t.Fields) == 0 {
+			return fmt.Errorf("type %s has no fields", t.Name)
+		}
+		names := make([]string, 0, len(t.Fields))
+		for name := range t.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			f := t.Fields[name]
+			if f.Type == nil || f.Resolve == nil {
+				return fmt.Errorf("field %s.%s has no type or resolver", t.Name, name)
+			}
+			for argName, arg := range f.Args {
+				if _, ok := namedType(arg.Type).(*Scalar); !ok {
+					return fmt.Errorf("argument %s of %s.%s isn't a scalar", argName, t.Name, name)
+				}
+				if err := s.addType(arg.Type); err != nil {
+					return err
+				}
+			}
+			if err := s.addType(f.Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %T", t)
+	}
+}
+
+// namedType unwraps the list and non-null types.
+func namedType(t Type) Type {
+	for {
+		switch tt := t.(type) {
+		case *List:
+			t = tt.OfType
+		case *NonNull:
+			t = tt.OfType
+		default:
+			return t
+		}
+	}
+}
+
+func coerceInt(v any) (any, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, n >= math.MinInt32 && n <= math.MaxInt32
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, false
+		}
+		return int(n), true
+	default:
+		return nil, false
+	}
+}
+
+func coerceFloat(v any) (any, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return nil, false
+	}
+}
+
+func coerceString(v any) (any, bool) {
+	s, ok := v.(string)
+	return s, ok
+}
+
+func coerceBoolean(v any) (any, bool) {
+	b, ok := v.(bool)
+	return b, ok
+}
+
+func coerceID(v any) (any, bool) {
+	switch id := v.(type) {
+	case string:
+		return id, true
+	case int:
+		return fmt.Sprint(id), true
+	case float64:
+		if id != math.Trunc(id) {
+			return nil, false
+		}
+		return fmt.Sprint(int64(id)), true
+	default:
+		return nil, false
+	}
+}
+
+// isNil reports whether a resolved value is null.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
@@ -61,3 +61,15 @@ func (h *Handler) Login(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, response)
 }
+
+func (h *Handler) Restore(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.LoginUserRequest](c)
+	if err != nil {
+		return err
+	}
+	accessToken, err := h.authService.Restore(c.Request().Context(), req.Email, req.Password)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, contracts.LoginUserResponse{AccessToken: accessToken})
+}
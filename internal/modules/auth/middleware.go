@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"time"
+
 	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/users"
@@ -51,3 +53,37 @@ func Admin(next echo.HandlerFunc) echo.HandlerFunc {
 		return errForbidden
 	}
 }
+
+func User(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if claims := jwt.GetClaims(c); claims == nil {
+			return errUnauthorized
+		}
+		return next(c)
+	}
+}
+
+// RevokedTokens makes the requests with the token of a deleted user, or a token issued before the user's tokens
+// were revoked, unauthenticated. It must follow the JWT middleware.
+func RevokedTokens(userService *users.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := jwt.GetClaims(c)
+			if claims == nil {
+				return next(c)
+			}
+			var issuedAt time.Time
+			if claims.IssuedAt != nil {
+				issuedAt = claims.IssuedAt.Time
+			}
+			valid, err := userService.IsTokenValid(c.Request().Context(), claims.UserID, issuedAt)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				jwt.ClearClaims(c)
+			}
+			return next(c)
+		}
+	}
+}
@@ -41,16 +41,33 @@ func (s *Service) Login(ctx context.Context, email, password string) (string, er
 	if err != nil {
 		return "", err
 	}
-
-	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		if err == bcrypt.ErrMismatchedHashAndPassword {
-			return "", apperrors.Unauthorized("invalid password")
-		}
-		return "", apperrors.Internal(err)
+	if err = checkPassword(user, password); err != nil {
+		return "", err
 	}
-	accessToken, err := s.jwtService.GenerateToken(user.ID, user.Role)
+	return s.jwtService.GenerateToken(user.ID, user.Role)
+}
+
+// Restore cancels the deletion of the account that is still in its grace period and logs the user in.
+func (s *Service) Restore(ctx context.Context, email, password string) (string, error) {
+	user, err := s.userService.GetUserPendingDeletion(ctx, email)
 	if err != nil {
 		return "", err
 	}
-	return accessToken, nil
+	if err = checkPassword(user, password); err != nil {
+		return "", err
+	}
+	if err = s.userService.Restore(ctx, user.ID); err != nil {
+		return "", err
+	}
+	return s.jwtService.GenerateToken(user.ID, user.Role)
+}
+
+func checkPassword(user *users.UserWithPassword, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return apperrors.Unauthorized("invalid password")
+		}
+		return apperrors.Internal(err)
+	}
+	return nil
 }
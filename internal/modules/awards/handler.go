@@ -0,0 +1,150 @@
+package awards
+
+import (
+	"net/http"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/RadkevichAnn/movie-reviews/internal/slices"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+	reqGroup         singleflight.Group
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) GetAwards(c echo.Context) error {
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+		req, err := echox.BindAndValidate[contracts.GetAwardsRequest](c)
+		if err != nil {
+			return nil, err
+		}
+		pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+		offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+		filter := &Filter{
+			Ceremony: req.Ceremony,
+			Year:     req.Year,
+		}
+		awards, total, err := h.service.GetAwardsPaginated(c.Request().Context(), filter, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		return pagination.Response(&req.PaginatedRequest, total, awards), nil
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) GetAwardByID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetOrDeleteAwardRequest](c)
+	if err != nil {
+		return err
+	}
+	award, err := h.service.GetAwardByID(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, award)
+}
+
+func (h *Handler) GetMovieAwards(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetAwardsBySubjectRequest](c)
+	if err != nil {
+		return err
+	}
+	awards, err := h.service.GetAwardsByMovieID(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, awards)
+}
+
+func (h *Handler) GetStarAwards(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetAwardsBySubjectRequest](c)
+	if err != nil {
+		return err
+	}
+	awards, err := h.service.GetAwardsByStarID(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, awards)
+}
+
+func (h *Handler) CreateAward(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.CreateAwardRequest](c)
+	if err != nil {
+		return err
+	}
+	award := &AwardDetails{
+		Award: Award{
+			Ceremony: req.Ceremony,
+			Year:     req.Year,
+			Category: req.Category,
+		},
+		Nominations: toNominations(req.Nominations),
+	}
+	if err = h.service.CreateAward(c.Request().Context(), award); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, award)
+}
+
+func (h *Handler) UpdateAward(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UpdateAwardRequest](c)
+	if err != nil {
+		return err
+	}
+	award := &AwardDetails{
+		Award: Award{
+			ID:       req.ID,
+			Ceremony: req.Ceremony,
+			Year:     req.Year,
+			Category: req.Category,
+		},
+		Nominations: toNominations(req.Nominations),
+	}
+	if err = h.service.UpdateAward(c.Request().Context(), award); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, award)
+}
+
+func (h *Handler) DeleteAward(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetOrDeleteAwardRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.DeleteAward(c.Request().Context(), req.ID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func toNominations(infos []*contracts.NominationInfo) []*Nomination {
+	return slices.MapIndex(infos, func(_ int, info *contracts.NominationInfo) *Nomination {
+		nomination := &Nomination{
+			Movie: Movie{ID: info.MovieID},
+			Won:   info.Won,
+		}
+		if info.StarID != nil {
+			nomination.Star = &Star{ID: *info.StarID}
+		}
+		return nomination
+	})
+}
@@ -0,0 +1,78 @@
+package awards
+
+import (
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+)
+
+// Award is a category of a ceremony held in a given year, e.g. Academy Awards 1978 Best Original Score.
+type Award struct {
+	ID        int        `json:"id"`
+	Ceremony  string     `json:"ceremony"`
+	Year      int        `json:"year"`
+	Category  string     `json:"category"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type AwardDetails struct {
+	Award
+	Nominations []*Nomination `json:"nominations"`
+}
+
+// Nomination is a nomination of a movie for the award. Awards given to people also refer to the nominated star.
+type Nomination struct {
+	Movie Movie `json:"movie"`
+	Star  *Star `json:"star,omitempty"`
+	Won   bool  `json:"won"`
+}
+
+type Movie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+type Star struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// MovieAward is a nomination of a movie along with the award it was nominated for.
+type MovieAward struct {
+	Award Award `json:"award"`
+	Star  *Star `json:"star,omitempty"`
+	Won   bool  `json:"won"`
+}
+
+// StarAward is a nomination of a star along with the award and the movie it was nominated for.
+type StarAward struct {
+	Award Award `json:"award"`
+	Movie Movie `json:"movie"`
+	Won   bool  `json:"won"`
+}
+
+var _ dbx.Keyer = NominationRelation{}
+
+// NominationRelation is a nomination as stored in the database. StarID is zero when no star is nominated.
+type NominationRelation struct {
+	AwardID int
+	MovieID int
+	StarID  int
+	Won     bool
+	OrderNo int
+}
+
+func (n NominationRelation) Key() any {
+	type NominationRelationKey struct {
+		AwardID, MovieID, StarID int
+	}
+	return NominationRelationKey{
+		AwardID: n.AwardID,
+		MovieID: n.MovieID,
+		StarID:  n.StarID,
+	}
+}
@@ -0,0 +1,391 @@
+package awards
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/RadkevichAnn/movie-reviews/internal/slices"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// Filter narrows down the awards returned by GetAwardsPaginated.
+type Filter struct {
+	Ceremony *string
+	Year     *int
+}
+
+func (r *Repository) CreateAward(ctx context.Context, award *AwardDetails) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `INSERT INTO awards (ceremony, year, category)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at`,
+			award.Ceremony, award.Year, award.Category).
+			Scan(&award.ID, &award.CreatedAt)
+		switch {
+		case dbx.IsUniqueViolation(err, "ceremony_year_category"):
+			return alreadyExists(&award.Award)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+
+		return r.UpdateNominations(ctx, nil, toRelations(award))
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetAwardByID(ctx context.Context, id int) (*Award, error) {
+	var award Award
+	err := r.db.QueryRow(ctx, `SELECT id, ceremony, year, category, created_at
+		FROM awards
+		WHERE deleted_at IS NULL AND id = $1`, id).
+		Scan(&award.ID, &award.Ceremony, &award.Year, &award.Category, &award.CreatedAt)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("award", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return &award, nil
+}
+
+func (r *Repository) GetNominationsByAwardID(ctx context.Context, awardID int) ([]*Nomination, error) {
+	rows, err := r.db.Query(ctx, `SELECT m.id, m.title, m.release_date, m.avg_rating, s.id, s.first_name, s.last_name, an.won
+		FROM award_nominations an
+		INNER JOIN movies m ON m.id = an.movie_id
+		LEFT JOIN stars s ON s.id = an.star_id
+		WHERE an.award_id = $1 AND m.deleted_at IS NULL
+		ORDER BY an.order_no`, awardID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var nominations []*Nomination
+	for rows.Next() {
+		var (
+			nomination Nomination
+			star       nullableStar
+		)
+		if err = rows.Scan(
+			&nomination.Movie.ID,
+			&nomination.Movie.Title,
+			&nomination.Movie.ReleaseDate,
+			&nomination.Movie.AvgRating,
+			&star.ID,
+			&star.FirstName,
+			&star.LastName,
+			&nomination.Won); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		nomination.Star = star.toStar()
+		nominations = append(nominations, &nomination)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return nominations, nil
+}
+
+func (r *Repository) GetAwardsByMovieID(ctx context.Context, movieID int) ([]*MovieAward, error) {
+	if err := r.ensureExists(ctx, "movie", "movies", movieID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT a.id, a.ceremony, a.year, a.category, a.created_at, s.id, s.first_name, s.last_name, an.won
+		FROM award_nominations an
+		INNER JOIN awards a ON a.id = an.award_id
+		LEFT JOIN stars s ON s.id = an.star_id
+		WHERE an.movie_id = $1 AND a.deleted_at IS NULL
+		ORDER BY a.year, a.ceremony, a.category`, movieID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var awards []*MovieAward
+	for rows.Next() {
+		var (
+			award MovieAward
+			star  nullableStar
+		)
+		if err = rows.Scan(
+			&award.Award.ID,
+			&award.Award.Ceremony,
+			&award.Award.Year,
+			&award.Award.Category,
+			&award.Award.CreatedAt,
+			&star.ID,
+			&star.FirstName,
+			&star.LastName,
+			&award.Won); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		award.Star = star.toStar()
+		awards = append(awards, &award)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return awards, nil
+}
+
+func (r *Repository) GetAwardsByStarID(ctx context.Context, starID int) ([]*StarAward, error) {
+	if err := r.ensureExists(ctx, "star", "stars", starID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT a.id, a.ceremony, a.year, a.category, a.created_at,
+       m.id, m.title, m.release_date, m.avg_rating, an.won
+		FROM award_nominations an
+		INNER JOIN awards a ON a.id = an.award_id
+		INNER JOIN movies m ON m.id = an.movie_id
+		WHERE an.star_id = $1 AND a.deleted_at IS NULL AND m.deleted_at IS NULL
+		ORDER BY a.year, a.ceremony, a.category`, starID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var awards []*StarAward
+	for rows.Next() {
+		var award StarAward
+		if err = rows.Scan(
+			&award.Award.ID,
+			&award.Award.Ceremony,
+			&award.Award.Year,
+			&award.Award.Category,
+			&award.Award.CreatedAt,
+			&award.Movie.ID,
+			&award.Movie.Title,
+			&award.Movie.ReleaseDate,
+			&award.Movie.AvgRating,
+			&award.Won); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		awards = append(awards, &award)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return awards, nil
+}
+
+func (r *Repository) GetAwardsPaginated(ctx context.Context, filter *Filter, offset int, limit int) ([]*Award, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select("id, ceremony, year, category, created_at").
+		From("awards").
+		Where("deleted_at IS NULL").
+		OrderBy("year DESC", "ceremony", "category").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("awards").
+		Where("deleted_at IS NULL")
+
+	if filter.Ceremony != nil {
+		selectQuery = selectQuery.Where("LOWER(ceremony) = LOWER(?)", *filter.Ceremony)
+		queryTotal = queryTotal.Where("LOWER(ceremony) = LOWER(?)", *filter.Ceremony)
+	}
+	if filter.Year != nil {
+		selectQuery = selectQuery.Where("year = ?", *filter.Year)
+		queryTotal = queryTotal.Where("year = ?", *filter.Year)
+	}
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var awards []*Award
+	for rows.Next() {
+		var award Award
+		if err = rows.Scan(
+			&award.ID,
+			&award.Ceremony,
+			&award.Year,
+			&award.Category,
+			&award.CreatedAt); err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		awards = append(awards, &award)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return awards, total, nil
+}
+
+func (r *Repository) UpdateAward(ctx context.Context, award *AwardDetails) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := tx.Exec(ctx, `UPDATE awards
+			SET ceremony = $1, year = $2, category = $3
+			WHERE deleted_at IS NULL AND id = $4`,
+			award.Ceremony, award.Year, award.Category, award.ID)
+		switch {
+		case dbx.IsUniqueViolation(err, "ceremony_year_category"):
+			return alreadyExists(&award.Award)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			return apperrors.NotFound("award", "id", award.ID)
+		}
+
+		current, err := r.GetRelationsByAwardID(ctx, award.ID)
+		if err != nil {
+			return err
+		}
+		return r.UpdateNominations(ctx, current, toRelations(award))
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteAward(ctx context.Context, id int) error {
+	n, err := r.db.Exec(ctx, `UPDATE awards SET deleted_at = NOW() WHERE deleted_at IS NULL AND id = $1`, id)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("award", "id", id)
+	}
+	return nil
+}
+
+func (r *Repository) GetRelationsByAwardID(ctx context.Context, awardID int) ([]NominationRelation, error) {
+	q := dbx.FromContext(ctx, r.db)
+	rows, err := q.Query(ctx, `SELECT award_id, movie_id, COALESCE(star_id, 0), won, order_no
+		FROM award_nominations
+		WHERE award_id = $1`, awardID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var relations []NominationRelation
+	for rows.Next() {
+		var relation NominationRelation
+		if err = rows.Scan(
+			&relation.AwardID,
+			&relation.MovieID,
+			&relation.StarID,
+			&relation.Won,
+			&relation.OrderNo); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		relations = append(relations, relation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return relations, nil
+}
+
+func (r *Repository) UpdateNominations(ctx context.Context, current, next []NominationRelation) error {
+	q := dbx.FromContext(ctx, r.db)
+	addFunc := func(rel NominationRelation) error {
+		_, err := q.Exec(ctx, `INSERT INTO award_nominations (award_id, movie_id, star_id, won, order_no)
+			VALUES ($1, $2, NULLIF($3, 0), $4, $5)`,
+			rel.AwardID, rel.MovieID, rel.StarID, rel.Won, rel.OrderNo)
+		switch {
+		case dbx.IsForeignKeyViolation(err, "movie_id"):
+			return apperrors.NotFound("movie", "id", rel.MovieID)
+		case dbx.IsForeignKeyViolation(err, "star_id"):
+			return apperrors.NotFound("star", "id", rel.StarID)
+		case dbx.IsUniqueViolation(err, "nominee"):
+			return apperrors.AlreadyExists("nomination", "(movie_id,star_id)", fmt.Sprintf("(%d,%d)", rel.MovieID, rel.StarID))
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	removeFunc := func(rel NominationRelation) error {
+		_, err := q.Exec(ctx, `DELETE FROM award_nominations
+			WHERE award_id = $1 AND movie_id = $2 AND COALESCE(star_id, 0) = $3`,
+			rel.AwardID, rel.MovieID, rel.StarID)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	return dbx.AdjustRelations(current, next, addFunc, removeFunc)
+}
+
+func (r *Repository) ensureExists(ctx context.Context, subject, table string, id int) error {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)`, id).
+		Scan(&exists)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if !exists {
+		return apperrors.NotFound(subject, "id", id)
+	}
+	return nil
+}
+
+// nullableStar scans a star of a nomination that may have no star.
+type nullableStar struct {
+	ID                  *int
+	FirstName, LastName *string
+}
+
+func (s nullableStar) toStar() *Star {
+	if s.ID == nil {
+		return nil
+	}
+	return &Star{ID: *s.ID, FirstName: *s.FirstName, LastName: *s.LastName}
+}
+
+func alreadyExists(award *Award) error {
+	return apperrors.AlreadyExists("award", "(ceremony,year,category)",
+		fmt.Sprintf("(%s,%d,%s)", award.Ceremony, award.Year, award.Category))
+}
+
+func toRelations(award *AwardDetails) []NominationRelation {
+	return slices.MapIndex(award.Nominations, func(i int, nomination *Nomination) NominationRelation {
+		relation := NominationRelation{
+			AwardID: award.ID,
+			MovieID: nomination.Movie.ID,
+			Won:     nomination.Won,
+			OrderNo: i,
+		}
+		if nomination.Star != nil {
+			relation.StarID = nomination.Star.ID
+		}
+		return relation
+	})
+}
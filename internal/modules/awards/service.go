@@ -0,0 +1,77 @@
+package awards
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) CreateAward(ctx context.Context, award *AwardDetails) error {
+	if err := s.repo.CreateAward(ctx, award); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("award created",
+		"awardId", award.ID,
+		"ceremony", award.Ceremony,
+		"year", award.Year,
+		"category", award.Category)
+	return s.assemble(ctx, award)
+}
+
+func (s *Service) GetAwardByID(ctx context.Context, id int) (*AwardDetails, error) {
+	award := &AwardDetails{Award: Award{ID: id}}
+	if err := s.assemble(ctx, award); err != nil {
+		return nil, err
+	}
+	return award, nil
+}
+
+func (s *Service) GetAwardsPaginated(ctx context.Context, filter *Filter, offset int, limit int) ([]*Award, int, error) {
+	return s.repo.GetAwardsPaginated(ctx, filter, offset, limit)
+}
+
+func (s *Service) GetAwardsByMovieID(ctx context.Context, movieID int) ([]*MovieAward, error) {
+	return s.repo.GetAwardsByMovieID(ctx, movieID)
+}
+
+func (s *Service) GetAwardsByStarID(ctx context.Context, starID int) ([]*StarAward, error) {
+	return s.repo.GetAwardsByStarID(ctx, starID)
+}
+
+func (s *Service) UpdateAward(ctx context.Context, award *AwardDetails) error {
+	if err := s.repo.UpdateAward(ctx, award); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("award updated",
+		"awardId", award.ID)
+	return s.assemble(ctx, award)
+}
+
+func (s *Service) DeleteAward(ctx context.Context, id int) error {
+	if err := s.repo.DeleteAward(ctx, id); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("award deleted",
+		"awardId", id)
+	return nil
+}
+
+// assemble reloads the award with its ordered nominations.
+func (s *Service) assemble(ctx context.Context, award *AwardDetails) error {
+	loaded, err := s.repo.GetAwardByID(ctx, award.ID)
+	if err != nil {
+		return err
+	}
+	award.Award = *loaded
+
+	award.Nominations, err = s.repo.GetNominationsByAwardID(ctx, award.ID)
+	return err
+}
@@ -0,0 +1,160 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+	"github.com/labstack/echo/v4"
+	"gopkg.in/validator.v2"
+)
+
+type Handler struct {
+	service *Service
+	cfg     config.BulkConfig
+}
+
+func NewHandler(service *Service, cfg config.BulkConfig) *Handler {
+	return &Handler{
+		service: service,
+		cfg:     cfg,
+	}
+}
+
+func (h *Handler) ImportGenres(c echo.Context) error {
+	items, err := decodeItems(c, h.cfg.MaxItems, func(req *contracts.CreateGenreRequest) *genres.Genre {
+		return &genres.Genre{
+			Name:        req.Name,
+			ParentID:    req.ParentID,
+			Slug:        req.Slug,
+			Description: req.Description,
+		}
+	})
+	if err != nil {
+		return err
+	}
+	report, err := h.service.ImportGenres(c.Request().Context(), items)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) ImportStars(c echo.Context) error {
+	items, err := decodeItems(c, h.cfg.MaxItems, func(req *contracts.BulkStar) *Star {
+		return &Star{
+			StarDetails: stars.StarDetails{
+				Star: stars.Star{
+					FirstName: req.FirstName,
+					LastName:  req.LastName,
+					BirthDate: req.BirthDate,
+					DeathDate: req.DeathDate,
+				},
+				MiddleName: req.MiddleName,
+				BirthPlace: req.BirthPlace,
+				Bio:        req.Bio,
+			},
+			ExternalIDs: req.ExternalIDs,
+		}
+	})
+	if err != nil {
+		return err
+	}
+	report, err := h.service.ImportStars(c.Request().Context(), items)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) ImportMovies(c echo.Context) error {
+	items, err := decodeItems(c, h.cfg.MaxItems, movies.MovieFromCreateRequest)
+	if err != nil {
+		return err
+	}
+	report, err := h.service.ImportMovies(c.Request().Context(), items)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// decodeItems decodes the request body holding either a JSON array or newline delimited JSON values.
+// Values that can't be decoded into the request or don't pass the validation become failed items,
+// while a malformed body fails the whole request.
+func decodeItems[Req any, T any](c echo.Context, maxItems int, convert func(req *Req) T) ([]*Item[T], error) {
+	body := bufio.NewReader(c.Request().Body)
+	dec := json.NewDecoder(body)
+
+	isArray, err := startsWithArray(body)
+	if err != nil {
+		return nil, err
+	}
+	if isArray {
+		if _, err = dec.Token(); err != nil {
+			return nil, apperrors.BadRequestHidden(err, "invalid or malformed request")
+		}
+	}
+
+	var items []*Item[T]
+	for {
+		if isArray && !dec.More() {
+			break
+		}
+		var raw json.RawMessage
+		err = dec.Decode(&raw)
+		if !isArray && errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, apperrors.BadRequestHidden(err, "invalid or malformed request")
+		}
+		if len(items) == maxItems {
+			return nil, apperrors.BadRequest(fmt.Errorf("too many items, at most %d items can be imported at once", maxItems))
+		}
+		items = append(items, decodeItem(raw, convert))
+	}
+	if isArray {
+		if _, err = dec.Token(); err != nil {
+			return nil, apperrors.BadRequestHidden(err, "invalid or malformed request")
+		}
+	}
+	return items, nil
+}
+
+func decodeItem[Req any, T any](raw json.RawMessage, convert func(req *Req) T) *Item[T] {
+	req := new(Req)
+	if err := json.Unmarshal(raw, req); err != nil {
+		return &Item[T]{Err: apperrors.BadRequestHidden(err, "invalid item")}
+	}
+	if err := validator.Validate(req); err != nil {
+		return &Item[T]{Err: apperrors.BadRequest(err)}
+	}
+	return &Item[T]{Value: convert(req)}
+}
+
+// startsWithArray reports whether the first non-space character of the body opens a JSON array.
+func startsWithArray(body *bufio.Reader) (bool, error) {
+	for {
+		r, _, err := body.ReadRune()
+		switch {
+		case errors.Is(err, io.EOF):
+			return false, nil
+		case err != nil:
+			return false, apperrors.BadRequestHidden(err, "invalid or malformed request")
+		case unicode.IsSpace(r):
+			continue
+		}
+		return r == '[', body.UnreadRune()
+	}
+}
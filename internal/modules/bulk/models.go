@@ -0,0 +1,42 @@
+package bulk
+
+import "github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+
+const (
+	StatusCreated  = "created"
+	StatusExisting = "existing"
+	StatusFailed   = "failed"
+)
+
+// Item is an item of a bulk import. Items that failed to be decoded or validated carry the error and are skipped.
+type Item[T any] struct {
+	Value T
+	Err   error
+}
+
+// Result is the outcome of the import of the item with the index in the request.
+type Result struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Created  int       `json:"created"`
+	Existing int       `json:"existing"`
+	Failed   int       `json:"failed"`
+	Results  []*Result `json:"results"`
+}
+
+// Star is a star to be imported. Stars having an external id that is already known aren't imported again.
+type Star struct {
+	stars.StarDetails
+	ExternalIDs map[string]string
+}
+
+// ExternalID identifies a movie or a star in an external source such as IMDb.
+type ExternalID struct {
+	Source string
+	ID     string
+}
@@ -0,0 +1,27 @@
+package bulk
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	Handler    *Handler
+	Service    *Service
+	Repository *Repository
+}
+
+func NewModule(db *pgxpool.Pool, cfg config.BulkConfig, genresModule *genres.Module, starsModule *stars.Module, moviesModule *movies.Module, outboxModule *outbox.Module) *Module {
+	repo := NewRepository(db, genresModule.Repository, starsModule.Repository, moviesModule.Repository)
+	service := NewService(repo, cfg, outboxModule.Service)
+	handler := NewHandler(service, cfg)
+	return &Module{
+		Handler:    handler,
+		Service:    service,
+		Repository: repo,
+	}
+}
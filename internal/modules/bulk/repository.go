@@ -0,0 +1,190 @@
+package bulk
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	dbx.Transactor
+	db               *pgxpool.Pool
+	genresRepository *genres.Repository
+	starsRepository  *stars.Repository
+	moviesRepository *movies.Repository
+}
+
+func NewRepository(db *pgxpool.Pool, genresRepository *genres.Repository, starsRepository *stars.Repository, moviesRepository *movies.Repository) *Repository {
+	return &Repository{
+		Transactor:       dbx.NewTransactor(db),
+		db:               db,
+		genresRepository: genresRepository,
+		starsRepository:  starsRepository,
+		moviesRepository: moviesRepository,
+	}
+}
+
+// GetGenreIDsBySlugs returns the ids of the existing genres by their slugs.
+func (r *Repository) GetGenreIDsBySlugs(ctx context.Context, slugs []string) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT slug, id FROM genres WHERE slug = ANY($1)`, slugs)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int, len(slugs))
+	for rows.Next() {
+		var (
+			slug string
+			id   int
+		)
+		if err = rows.Scan(&slug, &id); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		ids[slug] = id
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return ids, nil
+}
+
+// InsertGenres inserts the genres with a single batch.
+func (r *Repository) InsertGenres(ctx context.Context, items []*genres.Genre) error {
+	return dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		b := &pgx.Batch{}
+		for _, genre := range items {
+			b.Queue(`INSERT INTO genres (name, parent_id, slug, description) VALUES ($1, $2, $3, $4) RETURNING id`,
+				genre.Name, genre.ParentID, genre.Slug, genre.Description)
+		}
+		br := tx.SendBatch(ctx, b)
+		for _, genre := range items {
+			if err := br.QueryRow().Scan(&genre.ID); err != nil {
+				br.Close()
+				return apperrors.Internal(err)
+			}
+		}
+		if err := br.Close(); err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	})
+}
+
+// InsertGenre inserts the genre reporting the exact reason of a failure.
+func (r *Repository) InsertGenre(ctx context.Context, genre *genres.Genre) error {
+	return r.genresRepository.CreateGenre(ctx, genre)
+}
+
+// GetStarIDsByExternalIDs returns the ids of the stars known by the external ids.
+func (r *Repository) GetStarIDsByExternalIDs(ctx context.Context, externalIDs []ExternalID) (map[ExternalID]int, error) {
+	return r.getIDsByExternalIDs(ctx, "star_external_ids", "star_id", externalIDs)
+}
+
+// InsertStars inserts the stars with a single batch and copies their external ids.
+func (r *Repository) InsertStars(ctx context.Context, items []*Star) error {
+	return dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		b := &pgx.Batch{}
+		for _, star := range items {
+			b.Queue(`INSERT INTO stars (first_name, middle_name, last_name, birth_date, birth_place, death_date, bio)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id, created_at`,
+				star.FirstName, star.MiddleName, star.LastName, star.BirthDate, star.BirthPlace, star.DeathDate, star.Bio)
+		}
+		br := tx.SendBatch(ctx, b)
+		for _, star := range items {
+			if err := br.QueryRow().Scan(&star.ID, &star.CreatedAt); err != nil {
+				br.Close()
+				return apperrors.Internal(err)
+			}
+		}
+		if err := br.Close(); err != nil {
+			return apperrors.Internal(err)
+		}
+
+		var rows [][]any
+		for _, star := range items {
+			for source, externalID := range star.ExternalIDs {
+				rows = append(rows, []any{star.ID, source, externalID})
+			}
+		}
+		_, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"star_external_ids"},
+			[]string{"star_id", "source", "external_id"},
+			pgx.CopyFromRows(rows))
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	})
+}
+
+// InsertStar inserts the star reporting the exact reason of a failure.
+func (r *Repository) InsertStar(ctx context.Context, star *Star) error {
+	return dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		if err := r.starsRepository.CreateStar(ctx, &star.StarDetails); err != nil {
+			return err
+		}
+		for source, externalID := range star.ExternalIDs {
+			_, err := tx.Exec(ctx, `INSERT INTO star_external_ids (star_id, source, external_id) VALUES ($1, $2, $3)`,
+				star.ID, source, externalID)
+			switch {
+			case dbx.IsUniqueViolation(err, "external_id"):
+				return apperrors.AlreadyExists("star", source+" id", externalID)
+			case err != nil:
+				return apperrors.Internal(err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetMovieIDsByExternalIDs returns the ids of the movies known by the external ids.
+func (r *Repository) GetMovieIDsByExternalIDs(ctx context.Context, externalIDs []ExternalID) (map[ExternalID]int, error) {
+	return r.getIDsByExternalIDs(ctx, "movie_external_ids", "movie_id", externalIDs)
+}
+
+// InsertMovie inserts the movie along with its relations.
+func (r *Repository) InsertMovie(ctx context.Context, movie *movies.MovieDetails) error {
+	return r.moviesRepository.CreateMovie(ctx, movie)
+}
+
+func (r *Repository) getIDsByExternalIDs(ctx context.Context, table, idColumn string, externalIDs []ExternalID) (map[ExternalID]int, error) {
+	sources := make([]string, 0, len(externalIDs))
+	values := make([]string, 0, len(externalIDs))
+	for _, externalID := range externalIDs {
+		sources = append(sources, externalID.Source)
+		values = append(values, externalID.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT e.source, e.external_id, e.`+idColumn+`
+		FROM `+table+` e
+		INNER JOIN UNNEST($1::TEXT[], $2::TEXT[]) AS k(source, external_id)
+			ON k.source = e.source AND k.external_id = e.external_id`, sources, values)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	ids := make(map[ExternalID]int, len(externalIDs))
+	for rows.Next() {
+		var (
+			externalID ExternalID
+			id         int
+		)
+		if err = rows.Scan(&externalID.Source, &externalID.ID, &id); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		ids[externalID] = id
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,345 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+)
+
+type Service struct {
+	repo          *Repository
+	cfg           config.BulkConfig
+	outboxService *outbox.Service
+}
+
+func NewService(repo *Repository, cfg config.BulkConfig, outboxService *outbox.Service) *Service {
+	return &Service{
+		repo:          repo,
+		cfg:           cfg,
+		outboxService: outboxService,
+	}
+}
+
+// importer describes how the values of a kind are imported.
+type importer[T any] struct {
+	subject string
+	// normalize validates the value and converts it to the canonical form.
+	normalize func(value T) error
+	// findExisting returns the ids of the values that are already imported, 0 for the new ones.
+	findExisting func(ctx context.Context, values []T) ([]int, error)
+	// insertAll inserts all the values at once. It's optional, values are inserted one by one when it fails or isn't set.
+	insertAll func(ctx context.Context, values []T) error
+	insert    func(ctx context.Context, value T) error
+	id        func(value T) int
+	// keys returns the keys findExisting looks the value up by. Values of a chunk sharing a key are the same value.
+	keys func(value T) []string
+	// aggregateType, eventType and payload describe the event recorded in the outbox for every created value.
+	aggregateType string
+	eventType     string
+	payload       func(value T) any
+}
+
+func (s *Service) ImportGenres(ctx context.Context, items []*Item[*genres.Genre]) (*Report, error) {
+	return runImport(ctx, s, items, &importer[*genres.Genre]{
+		subject:   "genres",
+		normalize: genres.NormalizeSlug,
+		findExisting: func(ctx context.Context, values []*genres.Genre) ([]int, error) {
+			slugs := make([]string, 0, len(values))
+			for _, genre := range values {
+				slugs = append(slugs, genre.Slug)
+			}
+			ids, err := s.repo.GetGenreIDsBySlugs(ctx, slugs)
+			if err != nil {
+				return nil, err
+			}
+			res := make([]int, len(values))
+			for i, genre := range values {
+				res[i] = ids[genre.Slug]
+			}
+			return res, nil
+		},
+		insertAll: s.repo.InsertGenres,
+		insert:    s.repo.InsertGenre,
+		id:        func(genre *genres.Genre) int { return genre.ID },
+		keys:      func(genre *genres.Genre) []string { return []string{genre.Slug} },
+
+		aggregateType: genres.AggregateType,
+		eventType:     genres.EventCreated,
+		payload:       func(genre *genres.Genre) any { return genre },
+	})
+}
+
+func (s *Service) ImportStars(ctx context.Context, items []*Item[*Star]) (*Report, error) {
+	return runImport(ctx, s, items, &importer[*Star]{
+		subject: "stars",
+		normalize: func(star *Star) (err error) {
+			star.ExternalIDs, err = movies.NormalizeExternalIDs(star.ExternalIDs)
+			return err
+		},
+		findExisting: func(ctx context.Context, values []*Star) ([]int, error) {
+			return findByExternalIDs(ctx, values, s.repo.GetStarIDsByExternalIDs, func(star *Star) map[string]string {
+				return star.ExternalIDs
+			})
+		},
+		insertAll: s.repo.InsertStars,
+		insert:    s.repo.InsertStar,
+		id:        func(star *Star) int { return star.ID },
+		keys:      func(star *Star) []string { return externalIDKeys(star.ExternalIDs) },
+
+		aggregateType: stars.AggregateType,
+		eventType:     stars.EventCreated,
+		payload:       func(star *Star) any { return &star.StarDetails },
+	})
+}
+
+// ImportMovies imports the movies one by one, since every movie carries relations to several tables.
+func (s *Service) ImportMovies(ctx context.Context, items []*Item[*movies.MovieDetails]) (*Report, error) {
+	return runImport(ctx, s, items, &importer[*movies.MovieDetails]{
+		subject:   "movies",
+		normalize: movies.Normalize,
+		findExisting: func(ctx context.Context, values []*movies.MovieDetails) ([]int, error) {
+			return findByExternalIDs(ctx, values, s.repo.GetMovieIDsByExternalIDs, func(movie *movies.MovieDetails) map[string]string {
+				return movie.ExternalIDs
+			})
+		},
+		insert: s.repo.InsertMovie,
+		id:     func(movie *movies.MovieDetails) int { return movie.ID },
+		keys:   func(movie *movies.MovieDetails) []string { return externalIDKeys(movie.ExternalIDs) },
+
+		aggregateType: movies.AggregateType,
+		eventType:     movies.EventCreated,
+		payload:       func(movie *movies.MovieDetails) any { return movie },
+	})
+}
+
+// runImport imports the items in chunks. Each chunk is imported in a single transaction: values are inserted
+// all at once when possible, otherwise every value is inserted in its own savepoint, so that a failed value
+// doesn't affect the rest of the chunk. The created events are recorded in the outbox in the same transaction.
+func runImport[T any](ctx context.Context, s *Service, items []*Item[T], imp *importer[T]) (*Report, error) {
+	report := &Report{Results: make([]*Result, len(items))}
+	for start := 0; start < len(items); start += s.cfg.ChunkSize {
+		end := start + s.cfg.ChunkSize
+		if end > len(items) {
+			end = len(items)
+		}
+		if err := importChunk(ctx, s, items[start:end], report.Results[start:end], start, imp); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, result := range report.Results {
+		switch result.Status {
+		case StatusCreated:
+			report.Created++
+		case StatusExisting:
+			report.Existing++
+		case StatusFailed:
+			report.Failed++
+		}
+	}
+	log.FromContext(ctx).Info(imp.subject+" imported",
+		"created", report.Created,
+		"existing", report.Existing,
+		"failed", report.Failed)
+	return report, nil
+}
+
+func importChunk[T any](ctx context.Context, s *Service, items []*Item[T], results []*Result, offset int, imp *importer[T]) error {
+	var (
+		values  []T
+		indices []int
+	)
+	for i, item := range items {
+		results[i] = &Result{Index: offset + i}
+		err := item.Err
+		if err == nil {
+			err = imp.normalize(item.Value)
+		}
+		if err != nil {
+			fail(results[i], err)
+			continue
+		}
+		values = append(values, item.Value)
+		indices = append(indices, i)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	ids, err := imp.findExisting(ctx, values)
+	if err != nil {
+		return err
+	}
+	var (
+		newValues  []T
+		newIndices []int
+		// duplicates maps the indices of the new values sharing a key with a previous one to the index of the latter
+		duplicates = make(map[int]int)
+		keyIndices = make(map[string]int)
+	)
+	for i, id := range ids {
+		if id != 0 {
+			results[indices[i]].Status = StatusExisting
+			results[indices[i]].ID = id
+			continue
+		}
+		if original, ok := findDuplicate(keyIndices, imp.keys(values[i])); ok {
+			duplicates[indices[i]] = original
+			continue
+		}
+		for _, key := range imp.keys(values[i]) {
+			keyIndices[key] = indices[i]
+		}
+		newValues = append(newValues, values[i])
+		newIndices = append(newIndices, indices[i])
+	}
+
+	if err = insertChunk(ctx, s, newValues, results, newIndices, imp); err != nil {
+		return err
+	}
+	for i, original := range duplicates {
+		if results[original].Status == StatusFailed {
+			results[i].Status = StatusFailed
+			results[i].Error = results[original].Error
+			continue
+		}
+		results[i].Status = StatusExisting
+		results[i].ID = results[original].ID
+	}
+	return nil
+}
+
+// insertChunk inserts the new values of the chunk and records their outcome in the results with the indices.
+func insertChunk[T any](ctx context.Context, s *Service, values []T, results []*Result, indices []int, imp *importer[T]) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	if imp.insertAll != nil {
+		err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+			if err := imp.insertAll(ctx, values); err != nil {
+				return err
+			}
+			for _, value := range values {
+				if err := recordCreated(ctx, s, imp, value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			for i, value := range values {
+				results[indices[i]].Status = StatusCreated
+				results[indices[i]].ID = imp.id(value)
+			}
+			return nil
+		}
+		log.FromContext(ctx).Warn(imp.subject+" batch insert failed, inserting one by one", "error", err)
+	}
+
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		for i, value := range values {
+			result := results[indices[i]]
+			err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+				if err := imp.insert(ctx, value); err != nil {
+					return err
+				}
+				return recordCreated(ctx, s, imp, value)
+			})
+			if apperrors.Is(err, apperrors.AlreadyExistsCode) {
+				// The value may have been imported concurrently since it was looked up
+				ids, findErr := imp.findExisting(ctx, []T{value})
+				if findErr != nil {
+					return findErr
+				}
+				if ids[0] != 0 {
+					result.Status = StatusExisting
+					result.ID = ids[0]
+					continue
+				}
+			}
+			if err != nil {
+				if apperrors.Is(err, apperrors.InternalCode) {
+					log.FromContext(ctx).Error(imp.subject+" insert failed", "index", result.Index, "error", err)
+				}
+				fail(result, err)
+				continue
+			}
+			result.Status = StatusCreated
+			result.ID = imp.id(value)
+		}
+		return nil
+	})
+}
+
+// findDuplicate returns the index of the value having any of the keys.
+func findDuplicate(keyIndices map[string]int, keys []string) (int, bool) {
+	for _, key := range keys {
+		if index, ok := keyIndices[key]; ok {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+func externalIDKeys(externalIDs map[string]string) []string {
+	keys := make([]string, 0, len(externalIDs))
+	for source, id := range externalIDs {
+		keys = append(keys, source+":"+id)
+	}
+	return keys
+}
+
+func recordCreated[T any](ctx context.Context, s *Service, imp *importer[T], value T) error {
+	return s.outboxService.Record(ctx, imp.aggregateType, imp.id(value), imp.eventType, imp.payload(value))
+}
+
+// findByExternalIDs returns the ids of the values having any of their external ids already known, 0 for the new ones.
+func findByExternalIDs[T any](
+	ctx context.Context,
+	values []T,
+	getIDs func(ctx context.Context, externalIDs []ExternalID) (map[ExternalID]int, error),
+	externalIDsOf func(value T) map[string]string,
+) ([]int, error) {
+	var externalIDs []ExternalID
+	for _, value := range values {
+		for source, id := range externalIDsOf(value) {
+			externalIDs = append(externalIDs, ExternalID{Source: source, ID: id})
+		}
+	}
+	res := make([]int, len(values))
+	if len(externalIDs) == 0 {
+		return res, nil
+	}
+
+	ids, err := getIDs(ctx, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		for source, id := range externalIDsOf(value) {
+			if existingID, ok := ids[ExternalID{Source: source, ID: id}]; ok {
+				res[i] = existingID
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+func fail(result *Result, err error) {
+	result.Status = StatusFailed
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		result.Error = appErr.SafeError()
+	} else {
+		result.Error = err.Error()
+	}
+}
@@ -0,0 +1,113 @@
+package collections
+
+import (
+	"net/http"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/RadkevichAnn/movie-reviews/internal/slices"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+	reqGroup         singleflight.Group
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) GetCollections(c echo.Context) error {
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+		req, err := echox.BindAndValidate[contracts.GetCollectionsRequest](c)
+		if err != nil {
+			return nil, err
+		}
+		pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+		offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+		collections, total, err := h.service.GetCollectionsPaginated(c.Request().Context(), offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		return pagination.Response(&req.PaginatedRequest, total, collections), nil
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) GetCollectionByID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetOrDeleteCollectionRequest](c)
+	if err != nil {
+		return err
+	}
+	collection, err := h.service.GetCollectionByID(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, collection)
+}
+
+func (h *Handler) CreateCollection(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.CreateCollectionRequest](c)
+	if err != nil {
+		return err
+	}
+	collection := &CollectionDetails{
+		Collection: Collection{
+			Name:        req.Name,
+			Description: req.Description,
+		},
+		Movies: toMovies(req.Movies),
+	}
+	if err = h.service.CreateCollection(c.Request().Context(), collection); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, collection)
+}
+
+func (h *Handler) UpdateCollection(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UpdateCollectionRequest](c)
+	if err != nil {
+		return err
+	}
+	collection := &CollectionDetails{
+		Collection: Collection{
+			ID:          req.ID,
+			Name:        req.Name,
+			Description: req.Description,
+		},
+		Movies: toMovies(req.Movies),
+	}
+	if err = h.service.UpdateCollection(c.Request().Context(), collection); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, collection)
+}
+
+func (h *Handler) DeleteCollection(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetOrDeleteCollectionRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.DeleteCollection(c.Request().Context(), req.ID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func toMovies(ids []int) []*Movie {
+	return slices.MapIndex(ids, func(_ int, id int) *Movie {
+		return &Movie{ID: id}
+	})
+}
@@ -0,0 +1,57 @@
+package collections
+
+import (
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+)
+
+// Collection is a franchise or a series of movies. The rating aggregates are computed
+// over all reviews of the movies that belong to the collection.
+type Collection struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	MovieCount  int        `json:"movie_count"`
+	AvgRating   *float64   `json:"avg_rating,omitempty"`
+	RatingCount int        `json:"rating_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+type CollectionDetails struct {
+	Collection
+	Movies []*Movie `json:"movies"`
+}
+
+type Movie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+// MovieCollection is the collection a movie belongs to along with the position of the movie in it.
+type MovieCollection struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	OrderNo int    `json:"order_no"`
+}
+
+var _ dbx.Keyer = CollectionMovieRelation{}
+
+type CollectionMovieRelation struct {
+	CollectionID int
+	MovieID      int
+	OrderNo      int
+}
+
+func (c CollectionMovieRelation) Key() any {
+	type CollectionMovieRelationKey struct {
+		CollectionID, MovieID int
+	}
+	return CollectionMovieRelationKey{
+		CollectionID: c.CollectionID,
+		MovieID:      c.MovieID,
+	}
+}
@@ -0,0 +1,23 @@
+package collections
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	Handler    *Handler
+	Service    *Service
+	Repository *Repository
+}
+
+func NewModule(db *pgxpool.Pool, paginationConfig config.PaginationConfig) *Module {
+	repo := NewRepository(db)
+	service := NewService(repo)
+	handler := NewHandler(service, paginationConfig)
+	return &Module{
+		Handler:    handler,
+		Service:    service,
+		Repository: repo,
+	}
+}
@@ -0,0 +1,281 @@
+package collections
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/RadkevichAnn/movie-reviews/internal/slices"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// aggregatesJoin computes the movie count and the rating aggregates of the collection c.
+const aggregatesJoin = `LEFT JOIN LATERAL (
+		SELECT COUNT(*) AS movie_count,
+		       SUM(m.rating_sum)::FLOAT8 / NULLIF(SUM(m.rating_count), 0) AS avg_rating,
+		       COALESCE(SUM(m.rating_count), 0) AS rating_count
+		FROM collection_movies cm
+		INNER JOIN movies m ON m.id = cm.movie_id
+		WHERE cm.collection_id = c.id AND m.deleted_at IS NULL
+	) a ON TRUE`
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateCollection(ctx context.Context, collection *CollectionDetails) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `INSERT INTO collections (name, description)
+			VALUES ($1, $2)
+			RETURNING id, created_at`,
+			collection.Name, collection.Description).
+			Scan(&collection.ID, &collection.CreatedAt)
+		switch {
+		case dbx.IsUniqueViolation(err, "name"):
+			return apperrors.AlreadyExists("collection", "name", collection.Name)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+
+		return r.UpdateMovies(ctx, nil, toRelations(collection))
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetCollectionByID(ctx context.Context, id int) (*Collection, error) {
+	var collection Collection
+	err := r.db.QueryRow(ctx, `SELECT c.id, c.name, c.description, a.movie_count, a.avg_rating, a.rating_count, c.created_at
+		FROM collections c
+		`+aggregatesJoin+`
+		WHERE c.deleted_at IS NULL AND c.id = $1`, id).
+		Scan(&collection.ID, &collection.Name, &collection.Description, &collection.MovieCount,
+			&collection.AvgRating, &collection.RatingCount, &collection.CreatedAt)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("collection", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return &collection, nil
+}
+
+func (r *Repository) GetMoviesByCollectionID(ctx context.Context, collectionID int) ([]*Movie, error) {
+	rows, err := r.db.Query(ctx, `SELECT m.id, m.title, m.release_date, m.avg_rating
+		FROM collection_movies cm
+		INNER JOIN movies m ON m.id = cm.movie_id
+		WHERE cm.collection_id = $1 AND m.deleted_at IS NULL
+		ORDER BY cm.order_no`, collectionID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var movies []*Movie
+	for rows.Next() {
+		var movie Movie
+		if err = rows.Scan(
+			&movie.ID,
+			&movie.Title,
+			&movie.ReleaseDate,
+			&movie.AvgRating); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		movies = append(movies, &movie)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return movies, nil
+}
+
+func (r *Repository) GetCollectionsByMovieID(ctx context.Context, movieID int) ([]*MovieCollection, error) {
+	rows, err := r.db.Query(ctx, `SELECT c.id, c.name, cm.order_no
+		FROM collection_movies cm
+		INNER JOIN collections c ON c.id = cm.collection_id
+		WHERE cm.movie_id = $1 AND c.deleted_at IS NULL
+		ORDER BY c.id`, movieID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var collections []*MovieCollection
+	for rows.Next() {
+		var collection MovieCollection
+		if err = rows.Scan(
+			&collection.ID,
+			&collection.Name,
+			&collection.OrderNo); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		collections = append(collections, &collection)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return collections, nil
+}
+
+func (r *Repository) GetCollectionsPaginated(ctx context.Context, offset int, limit int) ([]*Collection, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select("c.id, c.name, c.description, a.movie_count, a.avg_rating, a.rating_count, c.created_at").
+		From("collections c").
+		JoinClause(aggregatesJoin).
+		Where("c.deleted_at IS NULL").
+		OrderBy("c.id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("collections c").
+		Where("c.deleted_at IS NULL")
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var collections []*Collection
+	for rows.Next() {
+		var collection Collection
+		if err = rows.Scan(
+			&collection.ID,
+			&collection.Name,
+			&collection.Description,
+			&collection.MovieCount,
+			&collection.AvgRating,
+			&collection.RatingCount,
+			&collection.CreatedAt); err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		collections = append(collections, &collection)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return collections, total, nil
+}
+
+func (r *Repository) UpdateCollection(ctx context.Context, collection *CollectionDetails) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := tx.Exec(ctx, `UPDATE collections
+			SET name = $1, description = $2
+			WHERE deleted_at IS NULL AND id = $3`,
+			collection.Name, collection.Description, collection.ID)
+		switch {
+		case dbx.IsUniqueViolation(err, "name"):
+			return apperrors.AlreadyExists("collection", "name", collection.Name)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			return apperrors.NotFound("collection", "id", collection.ID)
+		}
+
+		current, err := r.GetRelationsByCollectionID(ctx, collection.ID)
+		if err != nil {
+			return err
+		}
+		return r.UpdateMovies(ctx, current, toRelations(collection))
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteCollection(ctx context.Context, id int) error {
+	n, err := r.db.Exec(ctx, `UPDATE collections SET deleted_at = NOW() WHERE deleted_at IS NULL AND id = $1`, id)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("collection", "id", id)
+	}
+	return nil
+}
+
+func (r *Repository) GetRelationsByCollectionID(ctx context.Context, collectionID int) ([]CollectionMovieRelation, error) {
+	q := dbx.FromContext(ctx, r.db)
+	rows, err := q.Query(ctx, `SELECT collection_id, movie_id, order_no FROM collection_movies WHERE collection_id = $1`, collectionID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var relations []CollectionMovieRelation
+	for rows.Next() {
+		var relation CollectionMovieRelation
+		if err = rows.Scan(
+			&relation.CollectionID,
+			&relation.MovieID,
+			&relation.OrderNo); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		relations = append(relations, relation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return relations, nil
+}
+
+func (r *Repository) UpdateMovies(ctx context.Context, current, next []CollectionMovieRelation) error {
+	q := dbx.FromContext(ctx, r.db)
+	addFunc := func(rel CollectionMovieRelation) error {
+		_, err := q.Exec(ctx, `INSERT INTO collection_movies (collection_id, movie_id, order_no) VALUES ($1, $2, $3)`,
+			rel.CollectionID, rel.MovieID, rel.OrderNo)
+		switch {
+		case dbx.IsForeignKeyViolation(err, "movie_id"):
+			return apperrors.NotFound("movie", "id", rel.MovieID)
+		case dbx.IsUniqueViolation(err, "pkey"):
+			return apperrors.AlreadyExists("collection movie", "movie_id", rel.MovieID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	removeFunc := func(rel CollectionMovieRelation) error {
+		_, err := q.Exec(ctx, `DELETE FROM collection_movies WHERE collection_id = $1 AND movie_id = $2`,
+			rel.CollectionID, rel.MovieID)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	return dbx.AdjustRelations(current, next, addFunc, removeFunc)
+}
+
+func toRelations(collection *CollectionDetails) []CollectionMovieRelation {
+	return slices.MapIndex(collection.Movies, func(i int, movie *Movie) CollectionMovieRelation {
+		return CollectionMovieRelation{
+			CollectionID: collection.ID,
+			MovieID:      movie.ID,
+			OrderNo:      i,
+		}
+	})
+}
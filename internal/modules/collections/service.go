@@ -0,0 +1,71 @@
+package collections
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) CreateCollection(ctx context.Context, collection *CollectionDetails) error {
+	if err := s.repo.CreateCollection(ctx, collection); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("collection created",
+		"collectionId", collection.ID,
+		"name", collection.Name)
+	return s.assemble(ctx, collection)
+}
+
+func (s *Service) GetCollectionByID(ctx context.Context, id int) (*CollectionDetails, error) {
+	collection := &CollectionDetails{Collection: Collection{ID: id}}
+	if err := s.assemble(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (s *Service) GetCollectionsPaginated(ctx context.Context, offset int, limit int) ([]*Collection, int, error) {
+	return s.repo.GetCollectionsPaginated(ctx, offset, limit)
+}
+
+func (s *Service) GetCollectionsByMovieID(ctx context.Context, movieID int) ([]*MovieCollection, error) {
+	return s.repo.GetCollectionsByMovieID(ctx, movieID)
+}
+
+func (s *Service) UpdateCollection(ctx context.Context, collection *CollectionDetails) error {
+	if err := s.repo.UpdateCollection(ctx, collection); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("collection updated",
+		"collectionId", collection.ID)
+	return s.assemble(ctx, collection)
+}
+
+func (s *Service) DeleteCollection(ctx context.Context, id int) error {
+	if err := s.repo.DeleteCollection(ctx, id); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("collection deleted",
+		"collectionId", id)
+	return nil
+}
+
+// assemble reloads the collection with its rating aggregates and ordered movies.
+func (s *Service) assemble(ctx context.Context, collection *CollectionDetails) error {
+	loaded, err := s.repo.GetCollectionByID(ctx, collection.ID)
+	if err != nil {
+		return err
+	}
+	collection.Collection = *loaded
+
+	collection.Movies, err = s.repo.GetMoviesByCollectionID(ctx, collection.ID)
+	return err
+}
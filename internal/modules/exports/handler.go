@@ -0,0 +1,60 @@
+package exports
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ExportCatalog queues the catalog export. The download link is the result of the returned job.
+func (h *Handler) ExportCatalog(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.ExportCatalogRequest](c)
+	if err != nil {
+		return err
+	}
+	job, err := h.service.ExportCatalog(c.Request().Context(), req.Kind, req.Format, jwt.GetClaims(c).UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusAccepted, job)
+}
+
+// ExportUserData queues the export of the user data. The download link is the result of the returned job.
+func (h *Handler) ExportUserData(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.ExportUserDataRequest](c)
+	if err != nil {
+		return err
+	}
+	job, err := h.service.ExportUserData(c.Request().Context(), req.UserID, jwt.GetClaims(c).UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusAccepted, job)
+}
+
+// DownloadExport streams the export. The request is authorized by the signature of the link rather than by a token.
+func (h *Handler) DownloadExport(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DownloadExportRequest](c)
+	if err != nil {
+		return err
+	}
+	r, contentType, err := h.service.OpenExport(c.Request().Context(), req.Key, req.Expires, req.Signature)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+req.Key+`"`)
+	return c.Stream(http.StatusOK, contentType, r)
+}
@@ -0,0 +1,176 @@
+package exports
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CatalogExportJob is the type of the job exporting a part of the catalog.
+	CatalogExportJob = "exports.catalog"
+	// UserDataExportJob is the type of the job exporting all the data of a user as a zip archive.
+	UserDataExportJob = "exports.user_data"
+	// DeleteExportJob is the type of the job deleting an export file once its download link has expired.
+	DeleteExportJob = "exports.delete"
+
+	KindMovies = "movies"
+	KindStars  = "stars"
+	KindGenres = "genres"
+
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+type CatalogExportPayload struct {
+	Kind   string `json:"kind"`
+	Format string `json:"format"`
+}
+
+type UserDataExportPayload struct {
+	UserID int `json:"user_id"`
+}
+
+type DeleteExportPayload struct {
+	Key string `json:"key"`
+}
+
+// Export is the result of an export job. The download URL is valid until ExpiresAt.
+type Export struct {
+	FileName    string    `json:"file_name"`
+	DownloadURL string    `json:"download_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Record is a row of a catalog export.
+type Record interface {
+	// CSVRecord returns the fields of the record in the order of the CSV header of its kind.
+	CSVRecord() []string
+}
+
+var movieHeader = []string{"id", "title", "release_date", "description", "runtime_minutes", "original_language",
+	"avg_rating", "genres", "cast"}
+
+type MovieRecord struct {
+	ID               int             `json:"id"`
+	Title            string          `json:"title"`
+	ReleaseDate      time.Time       `json:"release_date"`
+	Description      string          `json:"description"`
+	RuntimeMinutes   *int            `json:"runtime_minutes,omitempty"`
+	OriginalLanguage *string         `json:"original_language,omitempty"`
+	AvgRating        *float64        `json:"avg_rating,omitempty"`
+	Genres           []string        `json:"genres"`
+	Cast             []*CreditRecord `json:"cast"`
+}
+
+type CreditRecord struct {
+	StarID    int     `json:"star_id"`
+	FirstName string  `json:"first_name"`
+	LastName  string  `json:"last_name"`
+	Role      string  `json:"role"`
+	Details   *string `json:"details,omitempty"`
+}
+
+// CSVRecord joins genres and cast with "|", a credit is written as "First Last (role)".
+func (m *MovieRecord) CSVRecord() []string {
+	cast := make([]string, 0, len(m.Cast))
+	for _, credit := range m.Cast {
+		cast = append(cast, strings.TrimSpace(credit.FirstName+" "+credit.LastName)+" ("+credit.Role+")")
+	}
+	var avgRating string
+	if m.AvgRating != nil {
+		avgRating = strconv.FormatFloat(*m.AvgRating, 'f', 2, 64)
+	}
+	return []string{
+		strconv.Itoa(m.ID),
+		m.Title,
+		formatDate(&m.ReleaseDate),
+		m.Description,
+		formatInt(m.RuntimeMinutes),
+		deref(m.OriginalLanguage),
+		avgRating,
+		strings.Join(m.Genres, "|"),
+		strings.Join(cast, "|"),
+	}
+}
+
+var starHeader = []string{"id", "first_name", "middle_name", "last_name", "birth_date", "birth_place", "death_date", "bio"}
+
+type StarRecord struct {
+	ID         int        `json:"id"`
+	FirstName  string     `json:"first_name"`
+	MiddleName *string    `json:"middle_name,omitempty"`
+	LastName   string     `json:"last_name"`
+	BirthDate  time.Time  `json:"birth_date"`
+	BirthPlace *string    `json:"birth_place,omitempty"`
+	DeathDate  *time.Time `json:"death_date,omitempty"`
+	Bio        *string    `json:"bio,omitempty"`
+}
+
+func (s *StarRecord) CSVRecord() []string {
+	return []string{
+		strconv.Itoa(s.ID),
+		s.FirstName,
+		deref(s.MiddleName),
+		s.LastName,
+		formatDate(&s.BirthDate),
+		deref(s.BirthPlace),
+		formatDate(s.DeathDate),
+		deref(s.Bio),
+	}
+}
+
+var genreHeader = []string{"id", "name", "slug", "parent_id", "description"}
+
+type GenreRecord struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Slug        string  `json:"slug"`
+	ParentID    *int    `json:"parent_id,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+func (g *GenreRecord) CSVRecord() []string {
+	return []string{
+		strconv.Itoa(g.ID),
+		g.Name,
+		g.Slug,
+		formatInt(g.ParentID),
+		deref(g.Description),
+	}
+}
+
+// RawRecord is a row of a user data export, which is built as JSON by the database.
+type RawRecord json.RawMessage
+
+func (r RawRecord) MarshalJSON() ([]byte, error) {
+	return r, nil
+}
+
+// CSVRecord isn't supported by raw records, user data is only exported as JSON.
+func (r RawRecord) CSVRecord() []string {
+	return []string{string(r)}
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+func formatInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,37 @@
+package exports
+
+import (
+	"fmt"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/blob"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jobs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/validator.v2"
+)
+
+type Module struct {
+	Handler    *Handler
+	Service    *Service
+	Repository *Repository
+}
+
+// NewModule creates the module and registers its job handlers. It fails if the config is invalid.
+func NewModule(db *pgxpool.Pool, store blob.BlobStore, jobsModule *jobs.Module, cfg config.ExportsConfig) (*Module, error) {
+	if err := validator.Validate(cfg); err != nil {
+		return nil, fmt.Errorf("validate exports config: %w", err)
+	}
+
+	repo := NewRepository(db)
+	service := NewService(repo, store, jobsModule.Service, cfg)
+	handler := NewHandler(service)
+
+	jobs.Register(jobsModule.Service, CatalogExportJob, service.RunCatalogExport)
+	jobs.Register(jobsModule.Service, UserDataExportJob, service.RunUserDataExport)
+	jobs.Register(jobsModule.Service, DeleteExportJob, service.RunDeleteExport)
+	return &Module{
+		Handler:    handler,
+		Service:    service,
+		Repository: repo,
+	}, nil
+}
@@ -0,0 +1,244 @@
+package exports
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// userDataSections are the queries building the user data export, one JSON object per row.
+// Every section becomes a file of the archive.
+var userDataSections = []struct {
+	name  string
+	query string
+}{
+	{
+		name: "reviews",
+		query: `SELECT json_build_object('id', r.id, 'movie_id', r.movie_id, 'movie_title', m.title, 'rating', r.rating,
+				'title', r.title, 'content', r.content, 'created_at', r.created_at)
+			FROM reviews r
+			INNER JOIN movies m ON m.id = r.movie_id
+			WHERE r.user_id = $1 AND r.deleted_at IS NULL
+			ORDER BY r.id`,
+	},
+	{
+		name: "watchlist",
+		query: `SELECT json_build_object('movie_id', w.movie_id, 'movie_title', m.title, 'notes', w.notes,
+				'priority', w.priority, 'added_at', w.added_at)
+			FROM watchlist_items w
+			INNER JOIN movies m ON m.id = w.movie_id
+			WHERE w.user_id = $1
+			ORDER BY w.order_no`,
+	},
+	{
+		name: "watched",
+		query: `SELECT json_build_object('movie_id', w.movie_id, 'movie_title', m.title, 'watched_on', w.watched_on)
+			FROM watched_movies w
+			INNER JOIN movies m ON m.id = w.movie_id
+			WHERE w.user_id = $1
+			ORDER BY w.watched_on, w.id`,
+	},
+	{
+		name: "lists",
+		query: `SELECT json_build_object('id', l.id, 'name', l.name, 'description', l.description, 'is_public', l.is_public,
+				'created_at', l.created_at, 'updated_at', l.updated_at,
+				'items', COALESCE((
+					SELECT json_agg(json_build_object('movie_id', li.movie_id, 'movie_title', m.title, 'notes', li.notes)
+						ORDER BY li.order_no)
+					FROM list_items li
+					INNER JOIN movies m ON m.id = li.movie_id
+					WHERE li.list_id = l.id), '[]'))
+			FROM lists l
+			WHERE l.user_id = $1 AND l.deleted_at IS NULL
+			ORDER BY l.id`,
+	},
+	{
+		name: "list_likes",
+		query: `SELECT json_build_object('list_id', ll.list_id, 'list_name', l.name, 'created_at', ll.created_at)
+			FROM list_likes ll
+			INNER JOIN lists l ON l.id = ll.list_id
+			WHERE ll.user_id = $1
+			ORDER BY ll.created_at`,
+	},
+	{
+		name: "following",
+		query: `SELECT json_build_object('user_id', u.id, 'username', u.username, 'created_at', f.created_at)
+			FROM follows f
+			INNER JOIN users u ON u.id = f.followee_id
+			WHERE f.follower_id = $1
+			ORDER BY f.created_at`,
+	},
+	{
+		name: "followers",
+		query: `SELECT json_build_object('user_id', u.id, 'username', u.username, 'created_at', f.created_at)
+			FROM follows f
+			INNER JOIN users u ON u.id = f.follower_id
+			WHERE f.followee_id = $1
+			ORDER BY f.created_at`,
+	},
+	{
+		name: "trivia",
+		query: `SELECT json_build_object('id', t.id, 'kind', t.kind, 'movie_id', t.movie_id, 'star_id', t.star_id,
+				'content', t.content, 'status', t.status, 'score', t.score, 'created_at', t.created_at)
+			FROM trivia_items t
+			WHERE t.user_id = $1 AND t.deleted_at IS NULL
+			ORDER BY t.id`,
+	},
+	{
+		name: "trivia_votes",
+		query: `SELECT json_build_object('item_id', v.item_id, 'value', v.value)
+			FROM trivia_votes v
+			WHERE v.user_id = $1
+			ORDER BY v.item_id`,
+	},
+}
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// StreamMovies calls fn for every movie in the order of ids. The rows are read from the database as fn consumes them.
+func (r *Repository) StreamMovies(ctx context.Context, fn func(movie *MovieRecord) error) error {
+	rows, err := r.db.Query(ctx, `SELECT m.id, m.title, m.release_date, m.description, m.runtime_minutes,
+			m.original_language, m.avg_rating,
+			ARRAY(SELECT g.name FROM movie_genres mg INNER JOIN genres g ON g.id = mg.genre_id
+				WHERE mg.movie_id = m.id ORDER BY g.name),
+			COALESCE((
+				SELECT json_agg(json_build_object('star_id', s.id, 'first_name', s.first_name, 'last_name', s.last_name,
+					'role', ms.role, 'details', ms.details) ORDER BY ms.order_no)
+				FROM movie_stars ms
+				INNER JOIN stars s ON s.id = ms.star_id
+				WHERE ms.movie_id = m.id), '[]')
+		FROM movies m
+		WHERE m.deleted_at IS NULL
+		ORDER BY m.id`)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	var movie MovieRecord
+	_, err = pgx.ForEachRow(rows, []any{&movie.ID, &movie.Title, &movie.ReleaseDate, &movie.Description,
+		&movie.RuntimeMinutes, &movie.OriginalLanguage, &movie.AvgRating, &movie.Genres, &movie.Cast},
+		func() error {
+			record := movie
+			return fn(&record)
+		})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) StreamStars(ctx context.Context, fn func(star *StarRecord) error) error {
+	rows, err := r.db.Query(ctx, `SELECT id, first_name, middle_name, last_name, birth_date, birth_place, death_date, bio
+		FROM stars
+		WHERE deleted_at IS NULL
+		ORDER BY id`)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	var star StarRecord
+	_, err = pgx.ForEachRow(rows, []any{&star.ID, &star.FirstName, &star.MiddleName, &star.LastName, &star.BirthDate,
+		&star.BirthPlace, &star.DeathDate, &star.Bio},
+		func() error {
+			record := star
+			return fn(&record)
+		})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) StreamGenres(ctx context.Context, fn func(genre *GenreRecord) error) error {
+	rows, err := r.db.Query(ctx, `SELECT id, name, slug, parent_id, description FROM genres ORDER BY id`)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	var genre GenreRecord
+	_, err = pgx.ForEachRow(rows, []any{&genre.ID, &genre.Name, &genre.Slug, &genre.ParentID, &genre.Description},
+		func() error {
+			record := genre
+			return fn(&record)
+		})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+// GetUserProfile returns the profile of the user as JSON.
+func (r *Repository) GetUserProfile(ctx context.Context, userID int) (RawRecord, error) {
+	var profile RawRecord
+	err := r.db.QueryRow(ctx, `SELECT json_build_object('id', id, 'username', username, 'email', email, 'role', role,
+			'bio', bio, 'display_name', display_name, 'location', location, 'created_at', created_at)
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL`, userID).
+		Scan((*[]byte)(&profile))
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("user", "id", userID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return profile, nil
+}
+
+// StreamUserSection calls fn for every row of the section of the user data.
+func (r *Repository) StreamUserSection(ctx context.Context, query string, userID int, fn func(record RawRecord) error) error {
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("query user data: %w", err))
+	}
+
+	var record []byte
+	_, err = pgx.ForEachRow(rows, []any{&record}, func() error {
+		return fn(RawRecord(record))
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+// CreateFile records the export file. userID is the user whose data is exported, if any.
+func (r *Repository) CreateFile(ctx context.Context, key string, userID *int, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx, `INSERT INTO export_files (key, user_id, expires_at) VALUES ($1, $2, $3)`,
+		key, userID, expiresAt)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteFile(ctx context.Context, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM export_files WHERE key = $1`, key)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+// DeleteUserFiles deletes the records of the data exports of the user and returns the keys of the files.
+func (r *Repository) DeleteUserFiles(ctx context.Context, userID int) ([]string, error) {
+	rows, err := r.db.Query(ctx, `DELETE FROM export_files WHERE user_id = $1 RETURNING key`, userID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,315 @@
+package exports
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/blob"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jobs"
+	"github.com/RadkevichAnn/movie-reviews/internal/signing"
+)
+
+const (
+	// downloadPath is the path of the export download endpoint
+	downloadPath = "/api/exports/"
+	// formatZip is the format of user data exports
+	formatZip = "zip"
+)
+
+var (
+	errInvalidLink = apperrors.Forbidden("invalid download link")
+	errExpiredLink = apperrors.Forbidden("download link has expired")
+)
+
+var contentTypes = map[string]string{
+	FormatJSON:   "application/json",
+	FormatNDJSON: "application/x-ndjson",
+	FormatCSV:    "text/csv",
+	formatZip:    "application/zip",
+}
+
+type Service struct {
+	repo        *Repository
+	store       blob.BlobStore
+	jobsService *jobs.Service
+	cfg         config.ExportsConfig
+}
+
+func NewService(repo *Repository, store blob.BlobStore, jobsService *jobs.Service, cfg config.ExportsConfig) *Service {
+	return &Service{
+		repo:        repo,
+		store:       store,
+		jobsService: jobsService,
+		cfg:         cfg,
+	}
+}
+
+// ExportCatalog queues the export of the movies, stars or genres in the format.
+func (s *Service) ExportCatalog(ctx context.Context, kind, format string, userID int) (*jobs.Job, error) {
+	payload := &CatalogExportPayload{Kind: kind, Format: format}
+	if _, err := catalogHeader(payload); err != nil {
+		return nil, err
+	}
+	return s.jobsService.Enqueue(ctx, CatalogExportJob, payload, &userID)
+}
+
+// ExportUserData queues the export of all the data of the user on behalf of the requester.
+func (s *Service) ExportUserData(ctx context.Context, userID, requesterID int) (*jobs.Job, error) {
+	if _, err := s.repo.GetUserProfile(ctx, userID); err != nil {
+		return nil, err
+	}
+	payload := &UserDataExportPayload{UserID: userID}
+	return s.jobsService.Enqueue(ctx, UserDataExportJob, payload, &requesterID)
+}
+
+// RunCatalogExport writes the catalog export. It is the handler of CatalogExportJob.
+func (s *Service) RunCatalogExport(ctx context.Context, payload CatalogExportPayload) (any, error) {
+	header, err := catalogHeader(&payload)
+	if err != nil {
+		return nil, err
+	}
+	var stream func(ctx context.Context, w recordWriter) error
+	switch payload.Kind {
+	case KindMovies:
+		stream = func(ctx context.Context, w recordWriter) error {
+			return s.repo.StreamMovies(ctx, func(movie *MovieRecord) error { return w.Write(movie) })
+		}
+	case KindStars:
+		stream = func(ctx context.Context, w recordWriter) error {
+			return s.repo.StreamStars(ctx, func(star *StarRecord) error { return w.Write(star) })
+		}
+	case KindGenres:
+		stream = func(ctx context.Context, w recordWriter) error {
+			return s.repo.StreamGenres(ctx, func(genre *GenreRecord) error { return w.Write(genre) })
+		}
+	}
+
+	key, err := newKey(payload.Kind + "." + payload.Format)
+	if err != nil {
+		return nil, err
+	}
+	err = s.write(ctx, key, func(w io.Writer) error {
+		rw, err := newRecordWriter(w, payload.Format, header)
+		if err != nil {
+			return err
+		}
+		if err = stream(ctx, rw); err != nil {
+			return err
+		}
+		return rw.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	export, err := s.newExport(ctx, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("catalog exported",
+		"kind", payload.Kind,
+		"format", payload.Format,
+		"key", key)
+	return export, nil
+}
+
+// RunUserDataExport writes the zip archive with the profile of the user and a JSON file per section of their data.
+// It is the handler of UserDataExportJob.
+func (s *Service) RunUserDataExport(ctx context.Context, payload UserDataExportPayload) (any, error) {
+	profile, err := s.repo.GetUserProfile(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newKey("user-" + strconv.Itoa(payload.UserID) + ".zip")
+	if err != nil {
+		return nil, err
+	}
+	err = s.write(ctx, key, func(w io.Writer) error {
+		archive := zip.NewWriter(w)
+		file, err := archive.Create("profile.json")
+		if err != nil {
+			return err
+		}
+		if _, err = file.Write(profile); err != nil {
+			return err
+		}
+
+		for _, section := range userDataSections {
+			if file, err = archive.Create(section.name + ".json"); err != nil {
+				return err
+			}
+			rw, err := newRecordWriter(file, FormatJSON, nil)
+			if err != nil {
+				return err
+			}
+			err = s.repo.StreamUserSection(ctx, section.query, payload.UserID, func(record RawRecord) error {
+				return rw.Write(record)
+			})
+			if err != nil {
+				return err
+			}
+			if err = rw.Close(); err != nil {
+				return err
+			}
+		}
+		return archive.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	export, err := s.newExport(ctx, key, &payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("user data exported",
+		"userId", payload.UserID,
+		"key", key)
+	return export, nil
+}
+
+// RunDeleteExport deletes the export file. It is the handler of DeleteExportJob.
+func (s *Service) RunDeleteExport(ctx context.Context, payload DeleteExportPayload) (any, error) {
+	if err := s.store.Delete(ctx, payload.Key); err != nil && !errors.Is(err, blob.ErrNotFound) {
+		return nil, apperrors.Internal(err)
+	}
+	if err := s.repo.DeleteFile(ctx, payload.Key); err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("export deleted", "key", payload.Key)
+	return nil, nil
+}
+
+// DeleteUserExports deletes the data exports of the user. The files that can't be deleted now are
+// still deleted when their download links expire.
+func (s *Service) DeleteUserExports(ctx context.Context, userID int) {
+	keys, err := s.repo.DeleteUserFiles(ctx, userID)
+	if err != nil {
+		log.FromContext(ctx).Error("failed to delete user exports",
+			"userId", userID,
+			"err", err)
+		return
+	}
+	for _, key := range keys {
+		if err = s.store.Delete(ctx, key); err != nil && !errors.Is(err, blob.ErrNotFound) {
+			log.FromContext(ctx).Error("failed to delete export",
+				"key", key,
+				"err", err)
+		}
+	}
+}
+
+// OpenExport returns the content of the export if the download link is valid.
+func (s *Service) OpenExport(ctx context.Context, key string, expires int64, signature string) (io.ReadCloser, string, error) {
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return nil, "", errInvalidLink
+	}
+	if time.Now().Unix() > expires {
+		return nil, "", errExpiredLink
+	}
+
+	r, err := s.store.Open(ctx, key)
+	switch {
+	case errors.Is(err, blob.ErrNotFound):
+		return nil, "", apperrors.NotFound("export", "key", key)
+	case err != nil:
+		return nil, "", apperrors.Internal(err)
+	}
+	return r, contentType(key), nil
+}
+
+// write streams the content written by fn to the blob store without buffering it.
+func (s *Service) write(ctx context.Context, key string, fn func(w io.Writer) error) error {
+	pr, pw := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := fn(pw)
+		pw.CloseWithError(err)
+		written <- err
+	}()
+
+	err := s.store.Put(ctx, key, pr, contentType(key))
+	// Unblocks the writer if the store stopped reading
+	pr.CloseWithError(err)
+	if writeErr := <-written; writeErr != nil {
+		return apperrors.EnsureInternal(writeErr)
+	}
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+// newExport records the written export file of the user, if any, and schedules its deletion
+// when the download link expires.
+func (s *Service) newExport(ctx context.Context, key string, userID *int) (*Export, error) {
+	expiresAt := time.Now().Add(s.cfg.LinkExpiration).Truncate(time.Second)
+	expires := expiresAt.Unix()
+
+	// The deletion is scheduled first, so that the file doesn't outlive its link if it can't be recorded
+	if err := s.jobsService.Schedule(ctx, DeleteExportJob, &DeleteExportPayload{Key: key}, expiresAt); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateFile(ctx, key, userID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(key, expires))
+	return &Export{
+		FileName:    key,
+		DownloadURL: downloadPath + key + "?" + query.Encode(),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func (s *Service) sign(key string, expires int64) string {
+	return signing.Sign(s.cfg.Secret, []byte(key+"\n"+strconv.FormatInt(expires, 10)))
+}
+
+// catalogHeader validates the kind and the format of the export and returns the CSV header of the kind.
+func catalogHeader(payload *CatalogExportPayload) ([]string, error) {
+	if _, ok := contentTypes[payload.Format]; !ok || payload.Format == formatZip {
+		return nil, apperrors.BadRequest(fmt.Errorf("unsupported export format %q", payload.Format))
+	}
+	switch payload.Kind {
+	case KindMovies:
+		return movieHeader, nil
+	case KindStars:
+		return starHeader, nil
+	case KindGenres:
+		return genreHeader, nil
+	default:
+		return nil, apperrors.BadRequest(fmt.Errorf("unsupported export kind %q", payload.Kind))
+	}
+}
+
+func contentType(key string) string {
+	if ct, ok := contentTypes[strings.TrimPrefix(path.Ext(key), ".")]; ok {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// newKey returns an unguessable key of the export file with the name.
+func newKey(name string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", apperrors.Internal(err)
+	}
+	return hex.EncodeToString(b) + "-" + name, nil
+}
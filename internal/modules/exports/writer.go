@@ -0,0 +1,107 @@
+package exports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// recordWriter writes the records one by one, so that exports never hold all of them in memory.
+type recordWriter interface {
+	Write(record Record) error
+	// Close completes the output. It doesn't close the underlying writer.
+	Close() error
+}
+
+func newRecordWriter(w io.Writer, format string, header []string) (recordWriter, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonWriter{w: bufio.NewWriter(w)}, nil
+	case FormatNDJSON:
+		buf := bufio.NewWriter(w)
+		return &ndjsonWriter{buf: buf, enc: json.NewEncoder(buf)}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w), header: header}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// jsonWriter writes the records as a JSON array.
+type jsonWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (w *jsonWriter) Write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if w.count == 0 {
+		sep = "[\n"
+	}
+	w.count++
+	if _, err = w.w.WriteString(sep); err != nil {
+		return err
+	}
+	_, err = w.w.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	end := "\n]\n"
+	if w.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := w.w.WriteString(end); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+type ndjsonWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(record Record) error {
+	return w.enc.Encode(record)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.buf.Flush()
+}
+
+// csvWriter writes the header before the first record, so that empty exports still have it.
+type csvWriter struct {
+	w             *csv.Writer
+	header        []string
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(record Record) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.w.Write(record.CSVRecord())
+}
+
+func (w *csvWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.w.Write(w.header)
+}
@@ -0,0 +1,39 @@
+package feed
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) GetFeed(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetFeedRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	claims := jwt.GetClaims(c)
+	activities, total, err := h.service.GetFeedPaginated(c.Request().Context(), claims.UserID, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, activities))
+}
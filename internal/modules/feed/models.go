@@ -0,0 +1,30 @@
+package feed
+
+import "time"
+
+const (
+	ReviewActivity = "review"
+	ListActivity   = "list"
+)
+
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+type Movie struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+// Activity is an entry of the feed. SubjectID refers to a review or a list depending on Type.
+// Reviews carry the movie and the rating, lists are reported whenever they are created or updated.
+type Activity struct {
+	Type      string    `json:"type"`
+	SubjectID int       `json:"subject_id"`
+	User      User      `json:"user"`
+	Movie     *Movie    `json:"movie,omitempty"`
+	Rating    *int      `json:"rating,omitempty"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,118 @@
+package feed
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	liveSource        = "user_activities"
+	precomputedSource = "user_activities_snapshot"
+)
+
+// visibleActivity excludes the activities that their authors made private. Feeds consist of the activities
+// of the followed users, so the activities visible to followers are kept.
+const visibleActivity = `((a.type <> 'review' OR u.reviews_visibility <> 'private') AND
+	(a.type <> 'list' OR u.lists_visibility <> 'private'))`
+
+type Repository struct {
+	db     *pgxpool.Pool
+	source string
+}
+
+// NewRepository creates a repository that builds feeds on read from the live activities view,
+// or from its periodically refreshed snapshot if precomputed is set.
+func NewRepository(db *pgxpool.Pool, precomputed bool) *Repository {
+	source := liveSource
+	if precomputed {
+		source = precomputedSource
+	}
+	return &Repository{db: db, source: source}
+}
+
+func (r *Repository) GetFeedPaginated(ctx context.Context, userID int, offset int, limit int) ([]*Activity, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select("a.type, a.subject_id, u.id, u.username, m.id, m.title, a.rating, a.title, a.created_at").
+		From("follows f").
+		Join(r.source+" a ON a.user_id = f.followee_id").
+		Join("users u ON u.id = a.user_id").
+		LeftJoin("movies m ON m.id = a.movie_id").
+		Where("f.follower_id = ?", userID).
+		Where("u.deleted_at IS NULL").
+		Where(visibleActivity).
+		Where("(a.movie_id IS NULL OR m.deleted_at IS NULL)").
+		OrderBy("a.created_at DESC", "a.subject_id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("follows f").
+		Join(r.source+" a ON a.user_id = f.followee_id").
+		Join("users u ON u.id = a.user_id").
+		LeftJoin("movies m ON m.id = a.movie_id").
+		Where("f.follower_id = ?", userID).
+		Where("u.deleted_at IS NULL").
+		Where(visibleActivity).
+		Where("(a.movie_id IS NULL OR m.deleted_at IS NULL)")
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var activities []*Activity
+	for rows.Next() {
+		var (
+			activity   Activity
+			movieID    *int
+			movieTitle *string
+		)
+		if err = rows.Scan(
+			&activity.Type,
+			&activity.SubjectID,
+			&activity.User.ID,
+			&activity.User.Username,
+			&movieID,
+			&movieTitle,
+			&activity.Rating,
+			&activity.Title,
+			&activity.CreatedAt); err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		if movieID != nil && movieTitle != nil {
+			activity.Movie = &Movie{ID: *movieID, Title: *movieTitle}
+		}
+		activities = append(activities, &activity)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return activities, total, nil
+}
+
+func (r *Repository) RefreshSnapshot(ctx context.Context) error {
+	if _, err := r.db.Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY user_activities_snapshot`); err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
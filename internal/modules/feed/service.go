@@ -0,0 +1,37 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) GetFeedPaginated(ctx context.Context, userID int, offset int, limit int) ([]*Activity, int, error) {
+	return s.repo.GetFeedPaginated(ctx, userID, offset, limit)
+}
+
+// RunSnapshotRefresher refreshes the precomputed activities snapshot every interval until ctx is done.
+func (s *Service) RunSnapshotRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.repo.RefreshSnapshot(ctx); err != nil && ctx.Err() == nil {
+				log.FromContext(ctx).Error("refresh feed snapshot", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package follows
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) Follow(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.FollowRequest](c)
+	if err != nil {
+		return err
+	}
+	follow, err := h.service.Follow(c.Request().Context(), req.UserID, req.FolloweeID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, follow)
+}
+
+func (h *Handler) Unfollow(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UnfollowRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.Unfollow(c.Request().Context(), req.UserID, req.FolloweeID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) GetFollowers(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetFollowsRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	follows, total, err := h.service.GetFollowersPaginated(c.Request().Context(), req.UserID, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, follows))
+}
+
+func (h *Handler) GetFollowing(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetFollowsRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	follows, total, err := h.service.GetFollowingPaginated(c.Request().Context(), req.UserID, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, follows))
+}
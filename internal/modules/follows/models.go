@@ -0,0 +1,14 @@
+package follows
+
+import "time"
+
+type UserSummary struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// Follow is an entry of a follower or following list. User is the other side of the relation.
+type Follow struct {
+	User       UserSummary `json:"user"`
+	FollowedAt time.Time   `json:"followed_at"`
+}
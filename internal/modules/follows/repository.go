@@ -0,0 +1,117 @@
+package follows
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Follow(ctx context.Context, followerID, followeeID int) (*Follow, error) {
+	follow := Follow{User: UserSummary{ID: followeeID}}
+	err := r.db.QueryRow(ctx, `WITH inserted AS (
+			INSERT INTO follows (follower_id, followee_id)
+			SELECT $1, u.id
+			FROM users u
+			WHERE u.id = $2 AND u.deleted_at IS NULL
+			RETURNING followee_id, created_at
+		)
+		SELECT u.username, i.created_at
+		FROM inserted i
+		INNER JOIN users u ON u.id = i.followee_id`,
+		followerID, followeeID).
+		Scan(&follow.User.Username, &follow.FollowedAt)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("user", "id", followeeID)
+	case dbx.IsUniqueViolation(err, "pkey"):
+		return nil, apperrors.AlreadyExists("follow", "user_id", followeeID)
+	case dbx.IsForeignKeyViolation(err, "follower_id"):
+		return nil, apperrors.NotFound("user", "id", followerID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return &follow, nil
+}
+
+func (r *Repository) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	n, err := r.db.Exec(ctx, `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("follow", "user_id", followeeID)
+	}
+	return nil
+}
+
+func (r *Repository) GetFollowersPaginated(ctx context.Context, userID int, offset int, limit int) ([]*Follow, int, error) {
+	return r.getPaginated(ctx, "f.follower_id", "f.followee_id", userID, offset, limit)
+}
+
+func (r *Repository) GetFollowingPaginated(ctx context.Context, userID int, offset int, limit int) ([]*Follow, int, error) {
+	return r.getPaginated(ctx, "f.followee_id", "f.follower_id", userID, offset, limit)
+}
+
+// getPaginated returns users referenced by otherColumn of the follows where ownColumn is userID.
+func (r *Repository) getPaginated(ctx context.Context, otherColumn, ownColumn string, userID int, offset int, limit int) ([]*Follow, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select("u.id, u.username, f.created_at").
+		From("follows f").
+		Join("users u ON u.id = "+otherColumn).
+		Where(ownColumn+" = ?", userID).
+		Where("u.deleted_at IS NULL").
+		OrderBy("f.created_at DESC", "u.id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("follows f").
+		Join("users u ON u.id = "+otherColumn).
+		Where(ownColumn+" = ?", userID).
+		Where("u.deleted_at IS NULL")
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var follows []*Follow
+	for rows.Next() {
+		var follow Follow
+		if err = rows.Scan(&follow.User.ID, &follow.User.Username, &follow.FollowedAt); err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		follows = append(follows, &follow)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return follows, total, nil
+}
@@ -0,0 +1,49 @@
+package follows
+
+import (
+	"context"
+	"errors"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) Follow(ctx context.Context, followerID, followeeID int) (*Follow, error) {
+	if followerID == followeeID {
+		return nil, apperrors.BadRequest(errors.New("users cannot follow themselves"))
+	}
+	follow, err := s.repo.Follow(ctx, followerID, followeeID)
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("user followed",
+		"followerId", followerID,
+		"followeeId", followeeID)
+	return follow, nil
+}
+
+func (s *Service) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	if err := s.repo.Unfollow(ctx, followerID, followeeID); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("user unfollowed",
+		"followerId", followerID,
+		"followeeId", followeeID)
+	return nil
+}
+
+func (s *Service) GetFollowersPaginated(ctx context.Context, userID int, offset int, limit int) ([]*Follow, int, error) {
+	return s.repo.GetFollowersPaginated(ctx, userID, offset, limit)
+}
+
+func (s *Service) GetFollowingPaginated(ctx context.Context, userID int, offset int, limit int) ([]*Follow, int, error) {
+	return s.repo.GetFollowingPaginated(ctx, userID, offset, limit)
+}
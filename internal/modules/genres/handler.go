@@ -46,13 +46,32 @@ func (h *Handler) GetGenreByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, genre)
 }
 
+func (h *Handler) GetGenreStats(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetGenreRequest](c)
+	if err != nil {
+		return err
+	}
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+		return h.service.GetGenreStats(c.Request().Context(), req.GenreId)
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
 func (h *Handler) CreateGenre(c echo.Context) error {
 	req, err := echox.BindAndValidate[contracts.CreateGenreRequest](c)
 	if err != nil {
 		return err
 	}
-	genre, err := h.service.CreateGenre(c.Request().Context(), req.Name)
-	if err != nil {
+	genre := &Genre{
+		Name:        req.Name,
+		ParentID:    req.ParentID,
+		Slug:        req.Slug,
+		Description: req.Description,
+	}
+	if err = h.service.CreateGenre(c.Request().Context(), genre); err != nil {
 		return err
 	}
 	return c.JSON(http.StatusCreated, genre)
@@ -64,7 +83,13 @@ func (h *Handler) UpdateGenre(c echo.Context) error {
 		return err
 	}
 
-	return h.service.UpdateGenre(c.Request().Context(), req.GenreId, req.Name)
+	return h.service.UpdateGenre(c.Request().Context(), &Genre{
+		ID:          req.GenreId,
+		Name:        req.Name,
+		ParentID:    req.ParentID,
+		Slug:        req.Slug,
+		Description: req.Description,
+	})
 }
 
 func (h *Handler) DeleteGenre(c echo.Context) error {
@@ -1,10 +1,40 @@
 package genres
 
-import "github.com/RadkevichAnn/movie-reviews/internal/dbx"
+import (
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+)
+
+// Domain events of genres recorded in the outbox. Their payload is the genre, or its id when it's deleted.
+const (
+	AggregateType = "genre"
+	EventCreated  = "genre.created"
+	EventUpdated  = "genre.updated"
+	EventDeleted  = "genre.deleted"
+)
 
 type Genre struct {
-	ID   int    `json:"ID"`
-	Name string `json:"Name"`
+	ID          int     `json:"ID"`
+	Name        string  `json:"Name"`
+	ParentID    *int    `json:"ParentID,omitempty"`
+	Slug        string  `json:"Slug"`
+	Description *string `json:"Description,omitempty"`
+}
+
+type GenreStats struct {
+	GenreID    int           `json:"GenreID"`
+	MovieCount int           `json:"MovieCount"`
+	AvgRating  *float64      `json:"AvgRating,omitempty"`
+	TopRated   []*RatedMovie `json:"TopRated"`
+}
+
+type RatedMovie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+	RatingCount int       `json:"rating_count"`
 }
 
 var _ dbx.Keyer = MovieGenreRelation{}
@@ -1,6 +1,7 @@
 package genres
 
 import (
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -10,9 +11,9 @@ type Module struct {
 	Repository *Repository
 }
 
-func NewModule(db *pgxpool.Pool) *Module {
+func NewModule(db *pgxpool.Pool, outboxModule *outbox.Module) *Module {
 	repo := NewRepository(db)
-	service := NewService(repo)
+	service := NewService(repo, outboxModule.Service)
 	handler := NewHandler(service)
 	return &Module{
 		Handler:    handler,
@@ -2,6 +2,8 @@ package genres
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 
@@ -10,16 +12,32 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// SubtreeQuery selects the ids of a genre and all of its descendants.
+// It expects the id of the root genre as its only argument, with a ? placeholder for dbx.StatementBuilder.
+const SubtreeQuery = `WITH RECURSIVE subtree AS (
+		SELECT id FROM genres WHERE id = ?
+		UNION
+		SELECT g.id FROM genres g INNER JOIN subtree s ON g.parent_id = s.id
+	)
+	SELECT id FROM subtree`
+
+// subtreeQuery is SubtreeQuery for the queries of this repository, where the root genre id is $1.
+var subtreeQuery = strings.Replace(SubtreeQuery, "?", "$1", 1)
+
 type Repository struct {
+	dbx.Transactor
 	db *pgxpool.Pool
 }
 
 func NewRepository(db *pgxpool.Pool) *Repository {
-	return &Repository{db: db}
+	return &Repository{
+		Transactor: dbx.NewTransactor(db),
+		db:         db,
+	}
 }
 
 func (r *Repository) GetAllGenres(ctx context.Context) ([]*Genre, error) {
-	queryString := `SELECT id, name FROM genres `
+	queryString := `SELECT id, name, parent_id, slug, description FROM genres `
 	rows, err := r.db.Query(ctx, queryString)
 	if err != nil {
 		return nil, apperrors.Internal(err)
@@ -29,11 +47,11 @@ func (r *Repository) GetAllGenres(ctx context.Context) ([]*Genre, error) {
 }
 
 func (r *Repository) GetGenreById(ctx context.Context, id int) (*Genre, error) {
-	queryString := `SELECT id, name FROM genres WHERE id = $1`
+	queryString := `SELECT id, name, parent_id, slug, description FROM genres WHERE id = $1`
 	row := r.db.QueryRow(ctx, queryString, id)
 
 	var genre Genre
-	err := row.Scan(&genre.ID, &genre.Name)
+	err := row.Scan(&genre.ID, &genre.Name, &genre.ParentID, &genre.Slug, &genre.Description)
 	switch {
 	case dbx.IsNoRows(err):
 		return nil, apperrors.NotFound("genre", "id", id)
@@ -43,35 +61,55 @@ func (r *Repository) GetGenreById(ctx context.Context, id int) (*Genre, error) {
 	return &genre, nil
 }
 
-func (r *Repository) CreateGenre(ctx context.Context, name string) (*Genre, error) {
-	queryString := "INSERT INTO genres (name) VALUES ($1) returning id, name;"
-	row := r.db.QueryRow(ctx, queryString, name)
-
-	var genre Genre
-	err := row.Scan(&genre.ID, &genre.Name)
-	if dbx.IsUniqueViolation(err, "name") {
-		return nil, apperrors.AlreadyExists("genre", "name", name)
-	}
+func (r *Repository) CreateGenre(ctx context.Context, genre *Genre) error {
+	queryString := `INSERT INTO genres (name, parent_id, slug, description) VALUES ($1, $2, $3, $4) returning id;`
+	q := dbx.FromContext(ctx, r.db)
+	err := q.QueryRow(ctx, queryString, genre.Name, genre.ParentID, genre.Slug, genre.Description).Scan(&genre.ID)
 	if err != nil {
-		return nil, apperrors.Internal(err)
+		return specifyModificationError(err, genre)
 	}
-
-	return &genre, nil
+	return nil
 }
 
-func (r *Repository) UpdateGenre(ctx context.Context, id int, name string) error {
-	n, err := r.db.Exec(ctx, "UPDATE genres SET name = $1 WHERE id = $2", name, id)
+// UpdateGenre replaces the genre attributes. The new parent must not be the genre
+// itself or one of its descendants, otherwise the hierarchy would contain a cycle.
+// Changes of the hierarchy are serialized by a lock, so that concurrent updates can't form a cycle together.
+func (r *Repository) UpdateGenre(ctx context.Context, genre *Genre) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		if genre.ParentID != nil {
+			if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('genres.hierarchy'))`); err != nil {
+				return apperrors.Internal(err)
+			}
+			var cyclic bool
+			err := tx.QueryRow(ctx, `SELECT $2::INTEGER IN (`+subtreeQuery+`)`,
+				genre.ID, *genre.ParentID).Scan(&cyclic)
+			if err != nil {
+				return apperrors.Internal(err)
+			}
+			if cyclic {
+				return apperrors.BadRequest(fmt.Errorf("genre with id %d cannot be a parent of genre with id %d", *genre.ParentID, genre.ID))
+			}
+		}
+
+		n, err := tx.Exec(ctx, `UPDATE genres SET name = $1, parent_id = $2, slug = $3, description = $4 WHERE id = $5`,
+			genre.Name, genre.ParentID, genre.Slug, genre.Description, genre.ID)
+		if err != nil {
+			return specifyModificationError(err, genre)
+		}
+		if n.RowsAffected() == 0 {
+			return apperrors.NotFound("genre", "id", genre.ID)
+		}
+		return nil
+	})
 	if err != nil {
-		return apperrors.Internal(err)
-	}
-	if n.RowsAffected() == 0 {
-		return apperrors.NotFound("genre", "id", id)
+		return apperrors.EnsureInternal(err)
 	}
 	return nil
 }
 
 func (r *Repository) DeleteGenre(ctx context.Context, id int) error {
-	n, err := r.db.Exec(ctx, "DELETE FROM genres WHERE id = $1", id)
+	q := dbx.FromContext(ctx, r.db)
+	n, err := q.Exec(ctx, "DELETE FROM genres WHERE id = $1", id)
 	if err != nil {
 		return apperrors.Internal(err)
 	}
@@ -81,6 +119,54 @@ func (r *Repository) DeleteGenre(ctx context.Context, id int) error {
 	return nil
 }
 
+// GetGenreStats aggregates the movies of the genre and all of its descendants.
+// The average rating is weighted by the number of reviews of every movie.
+func (r *Repository) GetGenreStats(ctx context.Context, id int, topLimit int) (*GenreStats, error) {
+	stats := GenreStats{GenreID: id}
+
+	b := &pgx.Batch{}
+	b.Queue(`SELECT COUNT(*), SUM(m.rating_sum)::FLOAT8 / NULLIF(SUM(m.rating_count), 0)
+		FROM movies m
+		WHERE m.deleted_at IS NULL
+		  AND EXISTS (SELECT 1 FROM movie_genres mg WHERE mg.movie_id = m.id AND mg.genre_id IN (`+subtreeQuery+`))`, id)
+	b.Queue(`SELECT m.id, m.title, m.release_date, m.avg_rating, m.rating_count
+		FROM movies m
+		WHERE m.deleted_at IS NULL
+		  AND m.rating_count > 0
+		  AND EXISTS (SELECT 1 FROM movie_genres mg WHERE mg.movie_id = m.id AND mg.genre_id IN (`+subtreeQuery+`))
+		ORDER BY m.avg_rating DESC, m.rating_count DESC, m.id
+		LIMIT $2`, id, topLimit)
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	if err := br.QueryRow().Scan(&stats.MovieCount, &stats.AvgRating); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movie RatedMovie
+		if err = rows.Scan(
+			&movie.ID,
+			&movie.Title,
+			&movie.ReleaseDate,
+			&movie.AvgRating,
+			&movie.RatingCount); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		stats.TopRated = append(stats.TopRated, &movie)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return &stats, nil
+}
+
 func (r *Repository) GetRelationsByMovieID(ctx context.Context, id int) ([]*MovieGenreRelation, error) {
 	queryString := `SELECT movie_id,genre_id,order_no FROM movie_genres WHERE movie_id = $1`
 	rows, err := r.db.Query(ctx, queryString, id)
@@ -104,7 +190,7 @@ func (r *Repository) GetRelationsByMovieID(ctx context.Context, id int) ([]*Movi
 }
 
 func (r *Repository) GetGenresByMovieID(ctx context.Context, id int) ([]*Genre, error) {
-	queryString := `SELECT g.id, g.name
+	queryString := `SELECT g.id, g.name, g.parent_id, g.slug, g.description
 	FROM genres g
 	INNER JOIN movie_genres mg on mg.genre_id = g.id	
 	WHERE mg.movie_id = $1
@@ -115,3 +201,44 @@ func (r *Repository) GetGenresByMovieID(ctx context.Context, id int) ([]*Genre,
 	}
 	return pgx.CollectRows[*Genre](rows, pgx.RowToAddrOfStructByPos[Genre])
 }
+
+func (r *Repository) GetGenresByMovieIDs(ctx context.Context, ids []int) (map[int][]*Genre, error) {
+	rows, err := r.db.Query(ctx, `SELECT mg.movie_id, g.id, g.name, g.parent_id, g.slug, g.description
+	FROM genres g
+	INNER JOIN movie_genres mg on mg.genre_id = g.id
+	WHERE mg.movie_id = ANY($1)
+	ORDER BY mg.movie_id, mg.order_no`, ids)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	genres := make(map[int][]*Genre, len(ids))
+	for rows.Next() {
+		var (
+			movieID int
+			genre   Genre
+		)
+		if err = rows.Scan(&movieID, &genre.ID, &genre.Name, &genre.ParentID, &genre.Slug, &genre.Description); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		genres[movieID] = append(genres[movieID], &genre)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return genres, nil
+}
+
+func specifyModificationError(err error, genre *Genre) error {
+	switch {
+	case dbx.IsUniqueViolation(err, "name"):
+		return apperrors.AlreadyExists("genre", "name", genre.Name)
+	case dbx.IsUniqueViolation(err, "slug"):
+		return apperrors.AlreadyExists("genre", "slug", genre.Slug)
+	case dbx.IsForeignKeyViolation(err, "parent_id"):
+		return apperrors.NotFound("genre", "id", *genre.ParentID)
+	default:
+		return apperrors.Internal(err)
+	}
+}
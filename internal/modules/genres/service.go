@@ -1,13 +1,37 @@
 package genres
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
+)
+
+const topRatedLimit = 5
+
+var (
+	slugPattern    = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+	errInvalidSlug = apperrors.BadRequest(errors.New("slug must consist of lowercase letters and digits separated by hyphens"))
+)
 
 type Service struct {
-	repo *Repository
+	repo          *Repository
+	outboxService *outbox.Service
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, outboxService *outbox.Service) *Service {
+	return &Service{
+		repo:          repo,
+		outboxService: outboxService,
+	}
 }
 
 func (s *Service) GetAllGenres(ctx context.Context) ([]*Genre, error) {
@@ -18,18 +42,92 @@ func (s *Service) GetGenreById(ctx context.Context, id int) (*Genre, error) {
 	return s.repo.GetGenreById(ctx, id)
 }
 
-func (s *Service) CreateGenre(ctx context.Context, name string) (*Genre, error) {
-	return s.repo.CreateGenre(ctx, name)
+func (s *Service) CreateGenre(ctx context.Context, genre *Genre) error {
+	if err := NormalizeSlug(genre); err != nil {
+		return err
+	}
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateGenre(ctx, genre); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, genre.ID, EventCreated, genre)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	log.FromContext(ctx).Info("genre created",
+		"genreId", genre.ID,
+		"slug", genre.Slug)
+	return nil
 }
 
-func (s *Service) UpdateGenre(ctx context.Context, id int, name string) error {
-	return s.repo.UpdateGenre(ctx, id, name)
+func (s *Service) UpdateGenre(ctx context.Context, genre *Genre) error {
+	if err := NormalizeSlug(genre); err != nil {
+		return err
+	}
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateGenre(ctx, genre); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, genre.ID, EventUpdated, genre)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
 }
 
 func (s *Service) DeleteGenre(ctx context.Context, id int) error {
-	return s.repo.DeleteGenre(ctx, id)
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteGenre(ctx, id); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, id, EventDeleted, map[string]int{"id": id})
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
 }
 
 func (s *Service) GetGenreByMovieID(ctx context.Context, id int) ([]*Genre, error) {
 	return s.repo.GetGenresByMovieID(ctx, id)
 }
+
+// GetGenresByMovieIDs returns the genres of each of the movies in one query.
+func (s *Service) GetGenresByMovieIDs(ctx context.Context, ids []int) (map[int][]*Genre, error) {
+	return s.repo.GetGenresByMovieIDs(ctx, ids)
+}
+
+// GetGenreStats returns the statistics of the genre including all of its descendants.
+func (s *Service) GetGenreStats(ctx context.Context, id int) (*GenreStats, error) {
+	if _, err := s.repo.GetGenreById(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGenreStats(ctx, id, topRatedLimit)
+}
+
+// NormalizeSlug derives the slug from the genre name when it's not set
+// and validates it otherwise.
+func NormalizeSlug(genre *Genre) error {
+	if genre.Slug == "" {
+		genre.Slug = Slugify(genre.Name)
+	}
+	if !slugPattern.MatchString(genre.Slug) {
+		return errInvalidSlug
+	}
+	return nil
+}
+
+// Slugify converts the name into a lowercase, hyphen separated identifier, e.g. "Sci-Fi & Fantasy" becomes "sci-fi-fantasy".
+// Names without latin letters or digits get a slug derived from a hash of the name, e.g. "genre-4f9a06b2".
+func Slugify(name string) string {
+	slug := slugSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(name))
+		slug = fmt.Sprintf("genre-%08x", h.Sum32())
+	}
+	return slug
+}
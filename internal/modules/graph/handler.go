@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/graphql"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Query executes a GraphQL query over the catalog. Invalid queries and queries exceeding the limits
+// get a bad request with no data; errors of the fields are returned with the rest of the data.
+func (h *Handler) Query(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GraphQLRequest](c)
+	if err != nil {
+		return err
+	}
+	res := h.service.Execute(c.Request().Context(), &graphql.Request{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+	}, privacy.GetViewer(c))
+	status := http.StatusOK
+	if res.Data == nil {
+		status = http.StatusBadRequest
+	}
+	return c.JSON(status, res)
+}
@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/users"
+)
+
+type Module struct {
+	Handler *Handler
+	Service *Service
+}
+
+func NewModule(cfg config.GraphQLConfig, moviesModule *movies.Module, starsModule *stars.Module, genresModule *genres.Module,
+	reviewsModule *reviews.Module, usersModule *users.Module) *Module {
+	service := NewService(moviesModule.Service, starsModule.Service, genresModule.Service,
+		reviewsModule.Service, usersModule.Service, cfg)
+	handler := NewHandler(service)
+	return &Module{
+		Handler: handler,
+		Service: service,
+	}
+}
@@ -0,0 +1,305 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/graphql"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/users"
+)
+
+const (
+	defaultPageSize    = 10
+	defaultReviewCount = 10
+)
+
+var (
+	requiredID = graphql.Args{"id": {Type: graphql.NewNonNull(graphql.Int)}}
+	// nonNull shortens the definitions of the non-null types
+	nonNull = graphql.NewNonNull
+)
+
+// newSchema defines the types of the catalog. Fields referencing other entities are resolved with
+// a single call of the services for all the parent values, e.g. the cast of all the movies of a page.
+func (s *Service) newSchema() (*graphql.Schema, error) {
+	movie := &graphql.Object{Name: "Movie"}
+	credit := &graphql.Object{Name: "Credit"}
+	star := &graphql.Object{Name: "Star"}
+	genre := &graphql.Object{Name: "Genre"}
+	review := &graphql.Object{Name: "Review"}
+	user := &graphql.Object{Name: "User"}
+
+	movie.Fields = graphql.Fields{
+		"id":             {Type: nonNull(graphql.Int), Resolve: graphql.Prop(func(m *movies.MovieDetails) any { return m.ID })},
+		"title":          {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(m *movies.MovieDetails) any { return m.Title })},
+		"releaseDate":    {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(m *movies.MovieDetails) any { return formatDate(&m.ReleaseDate) })},
+		"avgRating":      {Type: graphql.Float, Resolve: graphql.Prop(func(m *movies.MovieDetails) any { return m.AvgRating })},
+		"description":    {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(m *movies.MovieDetails) any { return m.Description })},
+		"runtimeMinutes": {Type: graphql.Int, Resolve: graphql.Prop(func(m *movies.MovieDetails) any { return m.RuntimeMinutes })},
+		"version":        {Type: nonNull(graphql.Int), Resolve: graphql.Prop(func(m *movies.MovieDetails) any { return m.Version })},
+		"genres": {
+			Type: nonNull(graphql.NewList(nonNull(genre))),
+			Resolve: batch(movieID, func(ctx context.Context, ids []int, _ map[string]any) (map[int][]*genres.Genre, error) {
+				return s.genresService.GetGenresByMovieIDs(ctx, ids)
+			}),
+		},
+		"cast": {
+			Type: nonNull(graphql.NewList(nonNull(credit))),
+			Resolve: batch(movieID, func(ctx context.Context, ids []int, _ map[string]any) (map[int][]*stars.MovieCredit, error) {
+				return s.starsService.GetCastByMovieIDs(ctx, ids)
+			}),
+		},
+		"reviews": {
+			Type: nonNull(graphql.NewList(nonNull(review))),
+			Args: graphql.Args{"first": {Type: nonNull(graphql.Int), Default: defaultReviewCount}},
+			Resolve: batch(movieID, func(ctx context.Context, ids []int, args map[string]any) (map[int][]*reviews.Review, error) {
+				first, err := s.listSize(args, "first")
+				if err != nil {
+					return nil, err
+				}
+				return s.reviewsService.GetLatestReviewsByMovieIDs(ctx, ids, getViewer(ctx), first)
+			}),
+			Complexity: listComplexity("first"),
+		},
+	}
+
+	credit.Fields = graphql.Fields{
+		"role":    {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(c *stars.MovieCredit) any { return c.Role })},
+		"details": {Type: graphql.String, Resolve: graphql.Prop(func(c *stars.MovieCredit) any { return c.Details })},
+		"star":    {Type: nonNull(star), Resolve: graphql.Prop(func(c *stars.MovieCredit) any { return &c.Star })},
+	}
+
+	star.Fields = graphql.Fields{
+		"id":        {Type: nonNull(graphql.Int), Resolve: graphql.Prop(func(st *stars.Star) any { return st.ID })},
+		"firstName": {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(st *stars.Star) any { return st.FirstName })},
+		"lastName":  {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(st *stars.Star) any { return st.LastName })},
+		"birthDate": {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(st *stars.Star) any { return formatDate(&st.BirthDate) })},
+		"deathDate": {Type: graphql.String, Resolve: graphql.Prop(func(st *stars.Star) any { return formatDate(st.DeathDate) })},
+	}
+
+	genre.Fields = graphql.Fields{
+		"id":          {Type: nonNull(graphql.Int), Resolve: graphql.Prop(func(g *genres.Genre) any { return g.ID })},
+		"name":        {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(g *genres.Genre) any { return g.Name })},
+		"slug":        {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(g *genres.Genre) any { return g.Slug })},
+		"description": {Type: graphql.String, Resolve: graphql.Prop(func(g *genres.Genre) any { return g.Description })},
+		"parentId":    {Type: graphql.Int, Resolve: graphql.Prop(func(g *genres.Genre) any { return g.ParentID })},
+	}
+
+	review.Fields = graphql.Fields{
+		"id":        {Type: nonNull(graphql.Int), Resolve: graphql.Prop(func(r *reviews.Review) any { return r.ID })},
+		"rating":    {Type: nonNull(graphql.Int), Resolve: graphql.Prop(func(r *reviews.Review) any { return r.Rating })},
+		"title":     {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(r *reviews.Review) any { return r.Title })},
+		"content":   {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(r *reviews.Review) any { return r.Content })},
+		"createdAt": {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(r *reviews.Review) any { return formatTime(&r.CreatedAt) })},
+		// The movie and the user are null if they were deleted
+		"movie": {
+			Type: movie,
+			Resolve: batch(func(r *reviews.Review) int { return r.MovieID }, func(ctx context.Context, ids []int, _ map[string]any) (map[int]*movies.MovieDetails, error) {
+				return s.moviesService.GetMoviesByIDs(ctx, ids)
+			}),
+		},
+		"user": {
+			Type: user,
+			Resolve: batch(func(r *reviews.Review) int { return r.UserID }, func(ctx context.Context, ids []int, _ map[string]any) (map[int]*users.Profile, error) {
+				return s.usersService.GetProfiles(ctx, ids, getViewer(ctx))
+			}),
+		},
+	}
+
+	user.Fields = graphql.Fields{
+		"id":          {Type: nonNull(graphql.Int), Resolve: graphql.Prop(func(u *users.Profile) any { return u.ID })},
+		"username":    {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(u *users.Profile) any { return u.Username })},
+		"displayName": {Type: graphql.String, Resolve: graphql.Prop(func(u *users.Profile) any { return u.DisplayName })},
+		// The email is null unless the privacy settings of the user allow the viewer to see it
+		"email": {Type: graphql.String, Resolve: graphql.Prop(func(u *users.Profile) any {
+			if u.Email == "" {
+				return nil
+			}
+			return u.Email
+		})},
+		"role":      {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(u *users.Profile) any { return u.Role })},
+		"bio":       {Type: graphql.String, Resolve: graphql.Prop(func(u *users.Profile) any { return u.Bio })},
+		"createdAt": {Type: nonNull(graphql.String), Resolve: graphql.Prop(func(u *users.Profile) any { return formatTime(&u.CreatedAt) })},
+	}
+
+	query := &graphql.Object{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"movie": {
+				Type: movie,
+				Args: requiredID,
+				Resolve: graphql.Each(func(ctx context.Context, _ any, args map[string]any) (any, error) {
+					id := args["id"].(int)
+					found, err := s.moviesService.GetMoviesByIDs(ctx, []int{id})
+					if err != nil {
+						return nil, err
+					}
+					return found[id], nil
+				}),
+			},
+			"movies": {
+				Type: nonNull(graphql.NewList(nonNull(movie))),
+				Args: graphql.Args{
+					"page": {Type: nonNull(graphql.Int), Default: 1},
+					"size": {Type: nonNull(graphql.Int), Default: defaultPageSize},
+				},
+				Resolve: graphql.Each(func(ctx context.Context, _ any, args map[string]any) (any, error) {
+					page := args["page"].(int)
+					if page < 1 {
+						return nil, apperrors.BadRequest(errors.New("page must be positive"))
+					}
+					size, err := s.listSize(args, "size")
+					if err != nil {
+						return nil, err
+					}
+					found, _, err := s.moviesService.GetAllMoviesPaginated(ctx, &movies.Filter{}, (page-1)*size, size)
+					return found, err
+				}),
+				Complexity: listComplexity("size"),
+			},
+			"star": {
+				Type: star,
+				Args: requiredID,
+				Resolve: graphql.Each(func(ctx context.Context, _ any, args map[string]any) (any, error) {
+					found, err := s.starsService.GetStarByID(ctx, args["id"].(int))
+					if err != nil {
+						return nullIfNotFound(err)
+					}
+					return &found.Star, nil
+				}),
+			},
+			"genre": {
+				Type: genre,
+				Args: requiredID,
+				Resolve: graphql.Each(func(ctx context.Context, _ any, args map[string]any) (any, error) {
+					found, err := s.genresService.GetGenreById(ctx, args["id"].(int))
+					if err != nil {
+						return nullIfNotFound(err)
+					}
+					return found, nil
+				}),
+			},
+			"genres": {
+				Type: nonNull(graphql.NewList(nonNull(genre))),
+				Resolve: graphql.Each(func(ctx context.Context, _ any, _ map[string]any) (any, error) {
+					return s.genresService.GetAllGenres(ctx)
+				}),
+			},
+			"review": {
+				Type: review,
+				Args: requiredID,
+				Resolve: graphql.Each(func(ctx context.Context, _ any, args map[string]any) (any, error) {
+					found, err := s.reviewsService.GetReviewByID(ctx, args["id"].(int), getViewer(ctx))
+					if err != nil {
+						return nullIfNotFound(err)
+					}
+					return found, nil
+				}),
+			},
+			"user": {
+				Type: user,
+				Args: requiredID,
+				Resolve: graphql.Each(func(ctx context.Context, _ any, args map[string]any) (any, error) {
+					id := args["id"].(int)
+					found, err := s.usersService.GetProfiles(ctx, []int{id}, getViewer(ctx))
+					if err != nil {
+						return nil, err
+					}
+					return found[id], nil
+				}),
+			},
+			// me is the authenticated user, null for anonymous requests
+			"me": {
+				Type: user,
+				Resolve: graphql.Each(func(ctx context.Context, _ any, _ map[string]any) (any, error) {
+					viewer := getViewer(ctx)
+					if viewer == nil {
+						return nil, nil
+					}
+					found, err := s.usersService.GetProfiles(ctx, []int{viewer.UserID}, viewer)
+					if err != nil {
+						return nil, err
+					}
+					return found[viewer.UserID], nil
+				}),
+			},
+		},
+	}
+	return graphql.NewSchema(query)
+}
+
+// batch builds a resolver loading the values of all the sources with a single call of load, by the
+// distinct keys of the sources. Sources missing from the loaded values get the zero value.
+func batch[S any, V any](key func(S) int, load func(ctx context.Context, keys []int, args map[string]any) (map[int]V, error)) graphql.ResolveFunc {
+	return func(ctx context.Context, p graphql.ResolveParams) ([]any, error) {
+		keys := make([]int, 0, len(p.Sources))
+		seen := make(map[int]bool, len(p.Sources))
+		for _, source := range p.Sources {
+			if k := key(source.(S)); !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+		loaded, err := load(ctx, keys, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]any, len(p.Sources))
+		for i, source := range p.Sources {
+			values[i] = loaded[key(source.(S))]
+		}
+		return values, nil
+	}
+}
+
+func movieID(m *movies.MovieDetails) int {
+	return m.ID
+}
+
+// listComplexity counts the selections of a list as many times as the items requested by the argument.
+func listComplexity(arg string) func(args map[string]any, childComplexity int) int {
+	return func(args map[string]any, childComplexity int) int {
+		n, _ := args[arg].(int)
+		if n < 1 {
+			n = 1
+		}
+		return 1 + n*childComplexity
+	}
+}
+
+func (s *Service) listSize(args map[string]any, arg string) (int, error) {
+	n := args[arg].(int)
+	if n < 1 || n > s.cfg.MaxListSize {
+		return 0, apperrors.BadRequest(fmt.Errorf("%s must be between 1 and %d", arg, s.cfg.MaxListSize))
+	}
+	return n, nil
+}
+
+// nullIfNotFound makes a missing entity null rather than an error.
+func nullIfNotFound(err error) (any, error) {
+	if apperrors.Is(err, apperrors.NotFoundCode) {
+		return nil, nil
+	}
+	return nil, err
+}
+
+func formatDate(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.DateOnly)
+}
+
+func formatTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
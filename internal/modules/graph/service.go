@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/graphql"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/users"
+)
+
+type viewerKey struct{}
+
+type Service struct {
+	moviesService  *movies.Service
+	starsService   *stars.Service
+	genresService  *genres.Service
+	reviewsService *reviews.Service
+	usersService   *users.Service
+	cfg            config.GraphQLConfig
+	schema         *graphql.Schema
+}
+
+func NewService(moviesService *movies.Service, starsService *stars.Service, genresService *genres.Service,
+	reviewsService *reviews.Service, usersService *users.Service, cfg config.GraphQLConfig) *Service {
+	s := &Service{
+		moviesService:  moviesService,
+		starsService:   starsService,
+		genresService:  genresService,
+		reviewsService: reviewsService,
+		usersService:   usersService,
+		cfg:            cfg,
+	}
+	schema, err := s.newSchema()
+	if err != nil {
+		// The schema is static, it can only be invalid because of a programming error
+		panic(err)
+	}
+	s.schema = schema
+	return s
+}
+
+// Execute runs the query on behalf of the viewer, nil for anonymous requests. The errors of the
+// resolvers are reported like the errors of the REST API: internal errors are logged and hidden.
+func (s *Service) Execute(ctx context.Context, req *graphql.Request, viewer *users.Viewer) *graphql.Response {
+	ctx = context.WithValue(ctx, viewerKey{}, viewer)
+	res := s.schema.Execute(ctx, req, graphql.Limits{
+		MaxDepth:      s.cfg.MaxDepth,
+		MaxComplexity: s.cfg.MaxComplexity,
+	})
+	for _, gqlErr := range res.Errors {
+		if gqlErr.Err == nil {
+			continue
+		}
+		var appErr *apperrors.Error
+		if !errors.As(gqlErr.Err, &appErr) {
+			appErr = apperrors.InternalWithoutStackTrace(gqlErr.Err)
+		}
+		if appErr.Code == apperrors.InternalCode {
+			log.FromContext(ctx).Error("graphql resolver error", "path", gqlErr.Path, "message", gqlErr.Err.Error(),
+				"incidientId", appErr.IncidentId, "stackTrace", appErr.StackTrace)
+		}
+		gqlErr.Message = appErr.SafeError()
+	}
+	return res
+}
+
+func getViewer(ctx context.Context) *users.Viewer {
+	viewer, _ := ctx.Value(viewerKey{}).(*users.Viewer)
+	return viewer
+}
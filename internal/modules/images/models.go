@@ -0,0 +1,7 @@
+package images
+
+// Image is an uploaded image along with its thumbnails. URLs are keyed by the size name.
+type Image struct {
+	ID   string            `json:"id"`
+	URLs map[string]string `json:"urls"`
+}
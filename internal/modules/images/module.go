@@ -0,0 +1,16 @@
+package images
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/blob"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+)
+
+type Module struct {
+	Service *Service
+}
+
+func NewModule(store blob.BlobStore, cfg config.ImagesConfig) *Module {
+	return &Module{
+		Service: NewService(store, cfg.MaxSize),
+	}
+}
@@ -0,0 +1,50 @@
+package images
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// resize scales the image down to the width preserving the aspect ratio. Every pixel
+// of the result is the average of the source pixels it covers. Images narrower than
+// the width are returned as is.
+func resize(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if width >= bounds.Dx() {
+		return src
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height == 0 {
+		height = 1
+	}
+
+	dst := image.NewRGBA64(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy0 := bounds.Min.Y + y*bounds.Dy()/height
+		sy1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			sx0 := bounds.Min.X + x*bounds.Dx()/width
+			sx1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// flatten draws the image over a white background, since JPEG has no transparency.
+func flatten(src image.Image) image.Image {
+	dst := image.NewRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Over)
+	return dst
+}
@@ -0,0 +1,141 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/blob"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+const (
+	OriginalSize = "original"
+
+	thumbnailQuality = 85
+	// maxPixels protects from images that are small when compressed but huge when decoded
+	maxPixels = 50_000_000
+)
+
+// thumbnailWidths are the widths of the thumbnails generated for every uploaded image.
+var thumbnailWidths = map[string]int{
+	"small":  92,
+	"medium": 185,
+	"large":  500,
+}
+
+var allowedContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
+var errInvalidImage = apperrors.BadRequest(errors.New("file is not a valid image"))
+
+type Service struct {
+	store   blob.BlobStore
+	maxSize int64
+}
+
+func NewService(store blob.BlobStore, maxSize int64) *Service {
+	return &Service{
+		store:   store,
+		maxSize: maxSize,
+	}
+}
+
+// Upload validates the image, stores it along with its thumbnails and returns the stored image.
+func (s *Service) Upload(ctx context.Context, r io.Reader) (*Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, apperrors.BadRequest(fmt.Errorf("image exceeds the maximum size of %d bytes", s.maxSize))
+	}
+
+	contentType := http.DetectContentType(data)
+	if !allowedContentTypes[contentType] {
+		return nil, apperrors.BadRequest(fmt.Errorf("unsupported image type %s", contentType))
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errInvalidImage
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, apperrors.BadRequest(fmt.Errorf("image exceeds the maximum of %d pixels", maxPixels))
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errInvalidImage
+	}
+
+	id := uuid.NewString()
+	if err = s.store.Put(ctx, originalKey(id), bytes.NewReader(data), contentType); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	for size, width := range thumbnailWidths {
+		var buf bytes.Buffer
+		if err = jpeg.Encode(&buf, flatten(resize(src, width)), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			s.Delete(ctx, id)
+			return nil, apperrors.Internal(err)
+		}
+		if err = s.store.Put(ctx, thumbnailKey(id, size), &buf, "image/jpeg"); err != nil {
+			s.Delete(ctx, id)
+			return nil, apperrors.Internal(err)
+		}
+	}
+
+	log.FromContext(ctx).Info("image uploaded",
+		"imageId", id,
+		"contentType", contentType,
+		"size", len(data))
+	return s.GetImage(&id), nil
+}
+
+// Delete removes the image with all of its thumbnails. Failures are only logged,
+// since a leftover blob doesn't affect the consistency of the data.
+func (s *Service) Delete(ctx context.Context, id string) {
+	keys := []string{originalKey(id)}
+	for size := range thumbnailWidths {
+		keys = append(keys, thumbnailKey(id, size))
+	}
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, blob.ErrNotFound) {
+			log.FromContext(ctx).Error("failed to delete image blob",
+				"key", key,
+				"err", err)
+		}
+	}
+}
+
+// GetImage returns the image with the id, or nil if the id is nil.
+func (s *Service) GetImage(id *string) *Image {
+	if id == nil {
+		return nil
+	}
+	img := &Image{
+		ID:   *id,
+		URLs: map[string]string{OriginalSize: s.store.URL(originalKey(*id))},
+	}
+	for size := range thumbnailWidths {
+		img.URLs[size] = s.store.URL(thumbnailKey(*id, size))
+	}
+	return img
+}
+
+func originalKey(id string) string {
+	return id + "/" + OriginalSize
+}
+
+func thumbnailKey(id, size string) string {
+	return id + "/" + size + ".jpg"
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+// adminRole is users.AdminRole, which can't be used directly since the users module schedules jobs.
+const adminRole = "admin"
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetJobByID returns the job status and progress. Users can only see the jobs they've queued.
+func (h *Handler) GetJobByID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetJobRequest](c)
+	if err != nil {
+		return err
+	}
+	job, err := h.service.GetJobByID(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+
+	claims := jwt.GetClaims(c)
+	isOwner := job.CreatedBy != nil && *job.CreatedBy == claims.UserID
+	if !isOwner && claims.Role != adminRole {
+		return apperrors.NotFound("job", "id", req.ID)
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+func (h *Handler) EnqueueJob(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.EnqueueJobRequest](c)
+	if err != nil {
+		return err
+	}
+	userID := jwt.GetClaims(c).UserID
+	job, err := h.service.EnqueueManual(c.Request().Context(), req.Type, req.Payload, userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusAccepted, job)
+}
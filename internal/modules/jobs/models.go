@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Job is a unit of background work of a registered type. Payload and Result are kept as raw JSON,
+// so that the job handlers define their shape. A periodic job is queued again for its next run when it finishes,
+// keeping the outcome of the last run.
+type Job struct {
+	ID          int             `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Progress    int             `json:"progress"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       *string         `json:"error,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	Periodic    bool            `json:"periodic"`
+	RunAt       time.Time       `json:"run_at"`
+	CreatedBy   *int            `json:"created_by,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	Handler    *Handler
+	Service    *Service
+	Repository *Repository
+}
+
+func NewModule(db *pgxpool.Pool, cfg config.JobsConfig) *Module {
+	repo := NewRepository(db)
+	service := NewService(repo, cfg)
+	handler := NewHandler(service)
+	return &Module{
+		Handler:    handler,
+		Service:    service,
+		Repository: repo,
+	}
+}
@@ -0,0 +1,146 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const selectJobColumns = `id, type, payload, status, progress, result, error, attempts, max_attempts, periodic, run_at,
+	created_by, created_at, started_at, finished_at`
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// CreateJob queues the job. It uses the transaction of ctx if any, so that jobs can be queued atomically with other changes.
+func (r *Repository) CreateJob(ctx context.Context, job *Job) error {
+	q := dbx.FromContext(ctx, r.db)
+	var runAt *time.Time
+	if !job.RunAt.IsZero() {
+		runAt = &job.RunAt
+	}
+	err := q.QueryRow(ctx, `INSERT INTO jobs (type, payload, max_attempts, created_by, run_at)
+		VALUES ($1, $2, $3, $4, COALESCE($5::TIMESTAMP, NOW()))
+		RETURNING `+selectJobColumns,
+		job.Type, job.Payload, job.MaxAttempts, job.CreatedBy, runAt).
+		Scan(scanTargets(job)...)
+	switch {
+	case dbx.IsForeignKeyViolation(err, "created_by"):
+		return apperrors.NotFound("user", "id", *job.CreatedBy)
+	case err != nil:
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetJobByID(ctx context.Context, id int) (*Job, error) {
+	job := &Job{}
+	err := r.db.QueryRow(ctx, `SELECT `+selectJobColumns+` FROM jobs WHERE id = $1`, id).Scan(scanTargets(job)...)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("job", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return job, nil
+}
+
+// CreatePeriodicJob queues the periodic job of the type to be run right away, unless it's already in the queue.
+func (r *Repository) CreatePeriodicJob(ctx context.Context, jobType string, maxAttempts int) error {
+	_, err := r.db.Exec(ctx, `INSERT INTO jobs (type, max_attempts, periodic) VALUES ($1, $2, TRUE)
+		ON CONFLICT (type) WHERE periodic DO NOTHING`,
+		jobType, maxAttempts)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+// ClaimJob locks the next due job of the types for the lock timeout. Queued jobs are picked up once they're due
+// and running jobs once their lock expired. SKIP LOCKED lets the workers claim different jobs concurrently.
+// It returns nil if there is no job to run.
+func (r *Repository) ClaimJob(ctx context.Context, types []string, lockTimeout time.Duration) (*Job, error) {
+	job := &Job{}
+	err := r.db.QueryRow(ctx, `UPDATE jobs
+		SET status = 'running',
+			attempts = attempts + 1,
+			locked_until = NOW() + make_interval(secs => $2),
+			started_at = COALESCE(started_at, NOW())
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE type = ANY($1)
+				AND ((status = 'queued' AND run_at <= NOW()) OR (status = 'running' AND locked_until < NOW()))
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+selectJobColumns,
+		types, lockTimeout.Seconds()).
+		Scan(scanTargets(job)...)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, nil
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return job, nil
+}
+
+// UpdateProgress stores the progress of the running job and extends its lock.
+func (r *Repository) UpdateProgress(ctx context.Context, job *Job, progress int, lockTimeout time.Duration) error {
+	return r.updateClaimedJob(ctx, job, `progress = $3, locked_until = NOW() + make_interval(secs => $4)`,
+		progress, lockTimeout.Seconds())
+}
+
+func (r *Repository) CompleteJob(ctx context.Context, job *Job, result json.RawMessage) error {
+	return r.updateClaimedJob(ctx, job, `status = 'succeeded', progress = 100, result = $3, error = NULL,
+		locked_until = NULL, finished_at = NOW()`, result)
+}
+
+func (r *Repository) FailJob(ctx context.Context, job *Job, message string) error {
+	return r.updateClaimedJob(ctx, job, `status = 'failed', error = $3, locked_until = NULL, finished_at = NOW()`, message)
+}
+
+// RetryJob queues the job again to be run after the delay.
+func (r *Repository) RetryJob(ctx context.Context, job *Job, message string, delay time.Duration) error {
+	return r.updateClaimedJob(ctx, job, `status = 'queued', error = $3, locked_until = NULL,
+		run_at = NOW() + make_interval(secs => $4)`, message, delay.Seconds())
+}
+
+// RescheduleJob stores the outcome of the run of the periodic job and queues it again to be run after the interval.
+func (r *Repository) RescheduleJob(ctx context.Context, job *Job, result json.RawMessage, message *string, interval time.Duration) error {
+	return r.updateClaimedJob(ctx, job, `status = 'queued', attempts = 0, result = $3, error = $4, locked_until = NULL,
+		finished_at = NOW(), run_at = NOW() + make_interval(secs => $5)`, result, message, interval.Seconds())
+}
+
+// ReleaseJob queues the interrupted job again without counting the attempt.
+func (r *Repository) ReleaseJob(ctx context.Context, job *Job) error {
+	return r.updateClaimedJob(ctx, job, `status = 'queued', attempts = attempts - 1, locked_until = NULL`)
+}
+
+// updateClaimedJob updates the job unless it was claimed again by another worker after its lock expired.
+func (r *Repository) updateClaimedJob(ctx context.Context, job *Job, set string, args ...any) error {
+	args = append([]any{job.ID, job.Attempts}, args...)
+	tag, err := r.db.Exec(ctx, `UPDATE jobs SET `+set+` WHERE id = $1 AND attempts = $2 AND status = 'running'`, args...)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.NotFound("running job", "id", job.ID)
+	}
+	return nil
+}
+
+func scanTargets(job *Job) []any {
+	return []any{&job.ID, &job.Type, &job.Payload, &job.Status, &job.Progress, &job.Result, &job.Error,
+		&job.Attempts, &job.MaxAttempts, &job.Periodic, &job.RunAt, &job.CreatedBy, &job.CreatedAt, &job.StartedAt, &job.FinishedAt}
+}
@@ -0,0 +1,324 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+// finishTimeout limits the time to store the outcome of a job, which is done even when the workers are being stopped.
+const finishTimeout = 5 * time.Second
+
+// HandlerFunc runs the job and returns its result that is stored as JSON. Errors with a client error code
+// such as bad request or not found fail the job immediately, other errors are retried with backoff.
+type HandlerFunc func(ctx context.Context, job *Job) (any, error)
+
+// Scheduler schedules system jobs. It's implemented by Service and used by the modules that only schedule jobs.
+type Scheduler interface {
+	Schedule(ctx context.Context, jobType string, payload any, runAt time.Time) error
+}
+
+type Service struct {
+	repo     *Repository
+	cfg      config.JobsConfig
+	handlers map[string]HandlerFunc
+	// periodic holds the intervals between the runs of the periodic job types.
+	periodic map[string]time.Duration
+	// manual holds the job types that admins can enqueue through the API.
+	manual map[string]bool
+
+	stopOnce sync.Once
+	cancel   context.CancelFunc
+	workers  sync.WaitGroup
+}
+
+func NewService(repo *Repository, cfg config.JobsConfig) *Service {
+	return &Service{
+		repo:     repo,
+		cfg:      cfg,
+		handlers: make(map[string]HandlerFunc),
+		periodic: make(map[string]time.Duration),
+		manual:   make(map[string]bool),
+	}
+}
+
+// Register sets the handler of the job type. Handlers must be registered before the workers are started.
+func (s *Service) Register(jobType string, handler HandlerFunc) {
+	s.handlers[jobType] = handler
+}
+
+// Register sets the handler of the job type whose payload is decoded into T.
+func Register[T any](s *Service, jobType string, handler func(ctx context.Context, payload T) (any, error)) {
+	s.Register(jobType, func(ctx context.Context, job *Job) (any, error) {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return nil, apperrors.BadRequestHidden(err, "invalid job payload")
+		}
+		return handler(ctx, payload)
+	})
+}
+
+// RegisterPeriodic sets the handler of the system job of the type that is run every interval. A single job
+// of the type is kept in the queue for all the server instances, so that one worker runs it at a time.
+func (s *Service) RegisterPeriodic(jobType string, interval time.Duration, handler HandlerFunc) {
+	s.Register(jobType, handler)
+	s.periodic[jobType] = interval
+}
+
+// RegisterPeriodic sets the handler of the periodic job type whose payload is decoded into T.
+func RegisterPeriodic[T any](s *Service, jobType string, interval time.Duration, handler func(ctx context.Context, payload T) (any, error)) {
+	Register(s, jobType, handler)
+	s.periodic[jobType] = interval
+}
+
+// RegisterManual sets the handler of the job type that admins can also enqueue through the API.
+func RegisterManual[T any](s *Service, jobType string, handler func(ctx context.Context, payload T) (any, error)) {
+	Register(s, jobType, handler)
+	s.manual[jobType] = true
+}
+
+// EnqueueManual queues the job on behalf of an admin. Only the job types registered with RegisterManual
+// are accepted, the others are run by the system on its own terms.
+func (s *Service) EnqueueManual(ctx context.Context, jobType string, payload any, createdBy int) (*Job, error) {
+	if !s.manual[jobType] {
+		return nil, apperrors.BadRequest(fmt.Errorf("unknown job type %q", jobType))
+	}
+	return s.enqueue(ctx, jobType, payload, &createdBy, time.Time{})
+}
+
+// Enqueue queues the job of the registered type. It joins the transaction of ctx if any.
+func (s *Service) Enqueue(ctx context.Context, jobType string, payload any, createdBy *int) (*Job, error) {
+	return s.enqueue(ctx, jobType, payload, createdBy, time.Time{})
+}
+
+// Schedule queues the system job of the registered type to be run not earlier than runAt.
+// It joins the transaction of ctx if any.
+func (s *Service) Schedule(ctx context.Context, jobType string, payload any, runAt time.Time) error {
+	_, err := s.enqueue(ctx, jobType, payload, nil, runAt)
+	return err
+}
+
+func (s *Service) enqueue(ctx context.Context, jobType string, payload any, createdBy *int, runAt time.Time) (*Job, error) {
+	if _, ok := s.handlers[jobType]; !ok {
+		return nil, apperrors.BadRequest(fmt.Errorf("unknown job type %q", jobType))
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, apperrors.BadRequest(err)
+	}
+	if payload == nil {
+		data = []byte("{}")
+	}
+
+	job := &Job{
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: s.cfg.MaxAttempts,
+		CreatedBy:   createdBy,
+		RunAt:       runAt,
+	}
+	if err = s.repo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("job queued",
+		"jobId", job.ID,
+		"type", job.Type,
+		"runAt", job.RunAt)
+	return job, nil
+}
+
+func (s *Service) GetJobByID(ctx context.Context, id int) (*Job, error) {
+	return s.repo.GetJobByID(ctx, id)
+}
+
+// Start queues the periodic jobs that aren't queued yet and starts the workers, which run until Stop is called.
+func (s *Service) Start(ctx context.Context) error {
+	for jobType := range s.periodic {
+		if err := s.repo.CreatePeriodicJob(ctx, jobType, s.cfg.MaxAttempts); err != nil {
+			return fmt.Errorf("queue periodic job %s: %w", jobType, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	types := make([]string, 0, len(s.handlers))
+	for jobType := range s.handlers {
+		types = append(types, jobType)
+	}
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.workers.Add(1)
+		go func(worker int) {
+			defer s.workers.Done()
+			logger := slog.Default().With("worker", worker)
+			s.runWorker(log.WithLogger(ctx, logger), types)
+		}(i)
+	}
+	return nil
+}
+
+// Stop stops the workers and waits for them to finish. Running jobs are interrupted and queued again.
+func (s *Service) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("stop job workers: %w", ctx.Err())
+	}
+}
+
+func (s *Service) runWorker(ctx context.Context, types []string) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		job, err := s.repo.ClaimJob(ctx, types, s.cfg.LockTimeout)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.FromContext(ctx).Error("claim job", "err", err)
+		case job != nil:
+			s.runJob(ctx, job)
+			// There may be more jobs due, so the next one is claimed without waiting
+			timer.Reset(0)
+			continue
+		}
+		timer.Reset(s.cfg.PollInterval)
+	}
+}
+
+func (s *Service) runJob(ctx context.Context, job *Job) {
+	logger := log.FromContext(ctx).With("jobId", job.ID, "type", job.Type, "attempt", job.Attempts)
+	ctx = log.WithLogger(ctx, logger)
+
+	var (
+		result any
+		err    error
+	)
+	if job.Attempts > job.MaxAttempts {
+		err = errors.New("job was abandoned too many times")
+	} else {
+		result, err = s.handle(withJob(ctx, s, job), job)
+	}
+
+	finishCtx, cancel := context.WithTimeout(log.WithLogger(context.Background(), logger), finishTimeout)
+	defer cancel()
+	// A periodic job is queued again for its next run when it finishes, whatever its outcome
+	interval := s.periodic[job.Type]
+	periodic := job.Periodic && interval > 0
+	switch {
+	case err != nil && ctx.Err() != nil:
+		logger.Info("job interrupted")
+		err = s.repo.ReleaseJob(finishCtx, job)
+	case err != nil && periodic && (job.Attempts >= job.MaxAttempts || !isRetryable(err)):
+		logger.Error("periodic job failed", "err", err, "next", interval)
+		message := errorMessage(err)
+		err = s.repo.RescheduleJob(finishCtx, job, nil, &message, interval)
+	case err != nil && (job.Attempts >= job.MaxAttempts || !isRetryable(err)):
+		logger.Error("job failed", "err", err)
+		err = s.repo.FailJob(finishCtx, job, errorMessage(err))
+	case err != nil:
+		delay := s.backoff(job.Attempts)
+		logger.Warn("job failed, retrying", "err", err, "delay", delay)
+		err = s.repo.RetryJob(finishCtx, job, errorMessage(err), delay)
+	default:
+		var data []byte
+		if data, err = json.Marshal(result); err != nil {
+			message := fmt.Sprintf("marshal result: %s", err)
+			if periodic {
+				err = s.repo.RescheduleJob(finishCtx, job, nil, &message, interval)
+				break
+			}
+			err = s.repo.FailJob(finishCtx, job, message)
+			break
+		}
+		if periodic {
+			logger.Info("periodic job succeeded", "next", interval)
+			err = s.repo.RescheduleJob(finishCtx, job, data, nil, interval)
+			break
+		}
+		logger.Info("job succeeded")
+		err = s.repo.CompleteJob(finishCtx, job, data)
+	}
+	if err != nil {
+		logger.Error("store job outcome", "err", err)
+	}
+}
+
+// handle runs the handler of the job turning its panic into an error.
+func (s *Service) handle(ctx context.Context, job *Job) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return s.handlers[job.Type](ctx, job)
+}
+
+// backoff returns the delay before the next attempt, which doubles with every attempt.
+func (s *Service) backoff(attempt int) time.Duration {
+	return s.cfg.RetryBackoff * time.Duration(1<<(attempt-1))
+}
+
+func isRetryable(err error) bool {
+	var appErr *apperrors.Error
+	return !errors.As(err, &appErr) || appErr.Code == apperrors.InternalCode
+}
+
+func errorMessage(err error) string {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		return appErr.SafeError()
+	}
+	return err.Error()
+}
+
+type contextKey struct{}
+
+type runningJob struct {
+	service *Service
+	job     *Job
+}
+
+func withJob(ctx context.Context, service *Service, job *Job) context.Context {
+	return context.WithValue(ctx, contextKey{}, &runningJob{service: service, job: job})
+}
+
+// ReportProgress stores the progress in percents of the job running with ctx and extends its lock,
+// so long-running handlers should report their progress regularly. It does nothing outside of a job.
+func ReportProgress(ctx context.Context, progress int) error {
+	running, ok := ctx.Value(contextKey{}).(*runningJob)
+	if !ok {
+		return nil
+	}
+	if progress < 0 || progress > 100 {
+		return fmt.Errorf("invalid progress %d", progress)
+	}
+	running.job.Progress = progress
+	return running.service.repo.UpdateProgress(ctx, running.job, progress, running.service.cfg.LockTimeout)
+}
@@ -31,3 +31,8 @@ func GetClaims(c echo.Context) *AccessClaims {
 	}
 	return token.(*jwt.Token).Claims.(*AccessClaims)
 }
+
+// ClearClaims makes the request unauthenticated.
+func ClearClaims(c echo.Context) {
+	c.Set(tokenContextKey, nil)
+}
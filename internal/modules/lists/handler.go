@@ -0,0 +1,159 @@
+package lists
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
+	"github.com/RadkevichAnn/movie-reviews/internal/slices"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) GetLists(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetListsRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	filter := &Filter{
+		MovieID:     req.MovieID,
+		Viewer:      privacy.GetViewer(c),
+		SortByLikes: req.SortByLikes,
+	}
+	lists, total, err := h.service.GetListsPaginated(c.Request().Context(), filter, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, lists))
+}
+
+func (h *Handler) GetUserLists(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetUserListsRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	viewer := privacy.GetViewer(c)
+	filter := &Filter{
+		UserID:         &req.UserID,
+		Viewer:         viewer,
+		IncludePrivate: canSee(viewer, &List{UserID: req.UserID}),
+	}
+	lists, total, err := h.service.GetListsPaginated(c.Request().Context(), filter, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, lists))
+}
+
+func (h *Handler) GetListByID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetListRequest](c)
+	if err != nil {
+		return err
+	}
+	list, err := h.service.GetListByID(c.Request().Context(), req.ListID, privacy.GetViewer(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) CreateList(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.CreateListRequest](c)
+	if err != nil {
+		return err
+	}
+	list := &ListDetails{
+		List: List{
+			UserID:      req.UserID,
+			Name:        req.Name,
+			Description: req.Description,
+			IsPublic:    req.IsPublic == nil || *req.IsPublic,
+		},
+		Items: toItems(req.Items),
+	}
+	if err = h.service.CreateList(c.Request().Context(), list); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, list)
+}
+
+func (h *Handler) UpdateList(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UpdateListRequest](c)
+	if err != nil {
+		return err
+	}
+	list := &ListDetails{
+		List: List{
+			ID:          req.ListID,
+			UserID:      req.UserID,
+			Name:        req.Name,
+			Description: req.Description,
+			IsPublic:    req.IsPublic,
+		},
+		Items: toItems(req.Items),
+	}
+	if err = h.service.UpdateList(c.Request().Context(), list); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) DeleteList(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DeleteListRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.DeleteList(c.Request().Context(), req.ListID, req.UserID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) LikeList(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.LikeListRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.LikeList(c.Request().Context(), req.ListID, privacy.GetViewer(c)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) UnlikeList(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.LikeListRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.UnlikeList(c.Request().Context(), req.ListID, privacy.GetViewer(c)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func toItems(infos []*contracts.ListItemInfo) []*ListItem {
+	return slices.MapIndex(infos, func(_ int, info *contracts.ListItemInfo) *ListItem {
+		return &ListItem{
+			Movie: Movie{ID: info.MovieID},
+			Notes: info.Notes,
+		}
+	})
+}
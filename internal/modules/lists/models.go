@@ -0,0 +1,62 @@
+package lists
+
+import (
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
+)
+
+type List struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	IsPublic    bool       `json:"is_public"`
+	LikesCount  int        `json:"likes_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+type ListDetails struct {
+	List
+	Items []*ListItem `json:"items"`
+}
+
+type Movie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+type ListItem struct {
+	Movie Movie  `json:"movie"`
+	Notes string `json:"notes,omitempty"`
+}
+
+var _ dbx.Keyer = ListItemRelation{}
+
+type ListItemRelation struct {
+	ListID  int
+	MovieID int
+	Notes   string
+	OrderNo int
+}
+
+func (l ListItemRelation) Key() any {
+	type ListItemRelationKey struct {
+		ListID, MovieID int
+	}
+	return ListItemRelationKey{
+		ListID:  l.ListID,
+		MovieID: l.MovieID,
+	}
+}
+
+// canSee reports whether the list is visible to the viewer. Private lists are visible only to their owners and admins.
+// The privacy settings of the owner are applied by the repository queries.
+func canSee(viewer *privacy.Viewer, list *List) bool {
+	return list.IsPublic || (viewer != nil && (viewer.IsAdmin || viewer.UserID == list.UserID))
+}
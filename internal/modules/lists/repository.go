@@ -0,0 +1,368 @@
+package lists
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
+	"github.com/RadkevichAnn/movie-reviews/internal/slices"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// Filter narrows down the lists returned by GetListsPaginated. The lists of the users whose privacy settings
+// hide them from Viewer are left out.
+type Filter struct {
+	UserID         *int
+	MovieID        *int
+	Viewer         *privacy.Viewer
+	IncludePrivate bool
+	SortByLikes    *string
+}
+
+func (r *Repository) CreateList(ctx context.Context, list *ListDetails) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `INSERT INTO lists (user_id, name, description, is_public)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, likes_count, created_at, updated_at`,
+			list.UserID, list.Name, list.Description, list.IsPublic).
+			Scan(&list.ID, &list.LikesCount, &list.CreatedAt, &list.UpdatedAt)
+		switch {
+		case dbx.IsForeignKeyViolation(err, "user_id"):
+			return apperrors.NotFound("user", "id", list.UserID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+
+		return r.UpdateItems(ctx, nil, toRelations(list))
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetListByID(ctx context.Context, id int) (*List, error) {
+	var list List
+	err := r.db.QueryRow(ctx, `SELECT id, user_id, name, description, is_public, likes_count, created_at, updated_at
+		FROM lists
+		WHERE deleted_at IS NULL AND id = $1`, id).
+		Scan(&list.ID, &list.UserID, &list.Name, &list.Description, &list.IsPublic, &list.LikesCount,
+			&list.CreatedAt, &list.UpdatedAt)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("list", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return &list, nil
+}
+
+// GetVisibleListByID returns the list if the privacy settings of its owner let the viewer see their lists,
+// and a not found error otherwise.
+func (r *Repository) GetVisibleListByID(ctx context.Context, id int, viewer *privacy.Viewer) (*List, error) {
+	query := dbx.StatementBuilder.
+		Select("l.id, l.user_id, l.name, l.description, l.is_public, l.likes_count, l.created_at, l.updated_at").
+		From("lists l").
+		Join("users u ON u.id = l.user_id").
+		Where("l.deleted_at IS NULL").
+		Where("l.id = ?", id).
+		Where(privacy.Visible(viewer, "l.user_id", "u.lists_visibility"))
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	var list List
+	err = r.db.QueryRow(ctx, sql, args...).
+		Scan(&list.ID, &list.UserID, &list.Name, &list.Description, &list.IsPublic, &list.LikesCount,
+			&list.CreatedAt, &list.UpdatedAt)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("list", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return &list, nil
+}
+
+func (r *Repository) GetItemsByListID(ctx context.Context, listID int) ([]*ListItem, error) {
+	rows, err := r.db.Query(ctx, `SELECT m.id, m.title, m.release_date, m.avg_rating, li.notes
+		FROM list_items li
+		INNER JOIN movies m ON m.id = li.movie_id
+		WHERE li.list_id = $1 AND m.deleted_at IS NULL
+		ORDER BY li.order_no`, listID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var items []*ListItem
+	for rows.Next() {
+		var item ListItem
+		if err = rows.Scan(
+			&item.Movie.ID,
+			&item.Movie.Title,
+			&item.Movie.ReleaseDate,
+			&item.Movie.AvgRating,
+			&item.Notes); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		items = append(items, &item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return items, nil
+}
+
+func (r *Repository) GetListsPaginated(ctx context.Context, filter *Filter, offset int, limit int) ([]*List, int, error) {
+	visible := privacy.Visible(filter.Viewer, "l.user_id", "u.lists_visibility")
+	selectQuery := dbx.StatementBuilder.
+		Select("l.id, l.user_id, l.name, l.description, l.is_public, l.likes_count, l.created_at, l.updated_at").
+		From("lists l").
+		Join("users u ON u.id = l.user_id").
+		Where("l.deleted_at IS NULL").
+		Where(visible).
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("lists l").
+		Join("users u ON u.id = l.user_id").
+		Where("l.deleted_at IS NULL").
+		Where(visible)
+
+	if !filter.IncludePrivate {
+		selectQuery = selectQuery.Where("l.is_public")
+		queryTotal = queryTotal.Where("l.is_public")
+	}
+	if filter.UserID != nil {
+		selectQuery = selectQuery.Where("l.user_id = ?", *filter.UserID)
+		queryTotal = queryTotal.Where("l.user_id = ?", *filter.UserID)
+	}
+	if filter.MovieID != nil {
+		selectQuery = selectQuery.
+			Join("list_items li ON li.list_id = l.id").
+			Where("li.movie_id = ?", *filter.MovieID)
+		queryTotal = queryTotal.
+			Join("list_items li ON li.list_id = l.id").
+			Where("li.movie_id = ?", *filter.MovieID)
+	}
+	if filter.SortByLikes != nil {
+		selectQuery = selectQuery.OrderByClause("l.likes_count " + *filter.SortByLikes)
+	}
+	selectQuery = selectQuery.OrderBy("l.id DESC")
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var lists []*List
+	for rows.Next() {
+		var list List
+		if err = rows.Scan(
+			&list.ID,
+			&list.UserID,
+			&list.Name,
+			&list.Description,
+			&list.IsPublic,
+			&list.LikesCount,
+			&list.CreatedAt,
+			&list.UpdatedAt); err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		lists = append(lists, &list)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return lists, total, nil
+}
+
+func (r *Repository) UpdateList(ctx context.Context, list *ListDetails) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `UPDATE lists
+			SET name = $1, description = $2, is_public = $3, updated_at = NOW()
+			WHERE deleted_at IS NULL AND id = $4 AND user_id = $5
+			RETURNING likes_count, created_at, updated_at`,
+			list.Name, list.Description, list.IsPublic, list.ID, list.UserID).
+			Scan(&list.LikesCount, &list.CreatedAt, &list.UpdatedAt)
+		switch {
+		case dbx.IsNoRows(err):
+			return r.specifyModificationError(ctx, list.ID, list.UserID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+
+		current, err := r.GetRelationsByListID(ctx, list.ID)
+		if err != nil {
+			return err
+		}
+		return r.UpdateItems(ctx, current, toRelations(list))
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteList(ctx context.Context, id, userID int) error {
+	n, err := r.db.Exec(ctx, `UPDATE lists SET deleted_at = NOW() WHERE deleted_at IS NULL AND id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return r.specifyModificationError(ctx, id, userID)
+	}
+	return nil
+}
+
+func (r *Repository) LikeList(ctx context.Context, listID, userID int) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `INSERT INTO list_likes (list_id, user_id) VALUES ($1, $2)`, listID, userID)
+		switch {
+		case dbx.IsUniqueViolation(err, "pkey"):
+			return apperrors.AlreadyExists("list like", "(list_id,user_id)", fmt.Sprintf("(%d,%d)", listID, userID))
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+		return r.adjustLikesCount(ctx, listID, 1)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) UnlikeList(ctx context.Context, listID, userID int) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := tx.Exec(ctx, `DELETE FROM list_likes WHERE list_id = $1 AND user_id = $2`, listID, userID)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			return apperrors.NotFound("list like", "(list_id,user_id)", fmt.Sprintf("(%d,%d)", listID, userID))
+		}
+		return r.adjustLikesCount(ctx, listID, -1)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetRelationsByListID(ctx context.Context, listID int) ([]ListItemRelation, error) {
+	q := dbx.FromContext(ctx, r.db)
+	rows, err := q.Query(ctx, `SELECT list_id, movie_id, notes, order_no FROM list_items WHERE list_id = $1`, listID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var relations []ListItemRelation
+	for rows.Next() {
+		var relation ListItemRelation
+		if err = rows.Scan(
+			&relation.ListID,
+			&relation.MovieID,
+			&relation.Notes,
+			&relation.OrderNo); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		relations = append(relations, relation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return relations, nil
+}
+
+func (r *Repository) UpdateItems(ctx context.Context, current, next []ListItemRelation) error {
+	q := dbx.FromContext(ctx, r.db)
+	addFunc := func(rel ListItemRelation) error {
+		_, err := q.Exec(ctx, `INSERT INTO list_items (list_id, movie_id, notes, order_no) VALUES ($1, $2, $3, $4)`,
+			rel.ListID, rel.MovieID, rel.Notes, rel.OrderNo)
+		switch {
+		case dbx.IsForeignKeyViolation(err, "movie_id"):
+			return apperrors.NotFound("movie", "id", rel.MovieID)
+		case dbx.IsUniqueViolation(err, "pkey"):
+			return apperrors.AlreadyExists("list item", "movie_id", rel.MovieID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	removeFunc := func(rel ListItemRelation) error {
+		_, err := q.Exec(ctx, `DELETE FROM list_items WHERE list_id = $1 AND movie_id = $2`,
+			rel.ListID, rel.MovieID)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	return dbx.AdjustRelations(current, next, addFunc, removeFunc)
+}
+
+func (r *Repository) adjustLikesCount(ctx context.Context, listID, delta int) error {
+	q := dbx.FromContext(ctx, r.db)
+	n, err := q.Exec(ctx, `UPDATE lists SET likes_count = likes_count + $1 WHERE deleted_at IS NULL AND id = $2`, delta, listID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("list", "id", listID)
+	}
+	return nil
+}
+
+func (r *Repository) specifyModificationError(ctx context.Context, listID, userID int) error {
+	list, err := r.GetListByID(ctx, listID)
+	if err != nil {
+		return err
+	}
+
+	if list.UserID != userID {
+		return apperrors.Forbidden(fmt.Sprintf("list with id %d is not owned by user with id %d", listID, userID))
+	}
+
+	return apperrors.Internal(fmt.Errorf("unexpected error modifying list with id %d", listID))
+}
+
+func toRelations(list *ListDetails) []ListItemRelation {
+	return slices.MapIndex(list.Items, func(i int, item *ListItem) ListItemRelation {
+		return ListItemRelation{
+			ListID:  list.ID,
+			MovieID: item.Movie.ID,
+			Notes:   item.Notes,
+			OrderNo: i,
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) CreateList(ctx context.Context, list *ListDetails) error {
+	if err := s.repo.CreateList(ctx, list); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("list created",
+		"listId", list.ID,
+		"userId", list.UserID)
+	return s.assemble(ctx, list)
+}
+
+// GetListByID returns the list with its items. Private lists that the viewer
+// is not allowed to see are reported as not found.
+func (s *Service) GetListByID(ctx context.Context, id int, viewer *privacy.Viewer) (*ListDetails, error) {
+	list, err := s.repo.GetVisibleListByID(ctx, id, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !canSee(viewer, list) {
+		return nil, apperrors.NotFound("list", "id", id)
+	}
+
+	details := &ListDetails{List: *list}
+	if err = s.assemble(ctx, details); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+func (s *Service) GetListsPaginated(ctx context.Context, filter *Filter, offset int, limit int) ([]*List, int, error) {
+	return s.repo.GetListsPaginated(ctx, filter, offset, limit)
+}
+
+func (s *Service) UpdateList(ctx context.Context, list *ListDetails) error {
+	if err := s.repo.UpdateList(ctx, list); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("list updated",
+		"listId", list.ID,
+		"userId", list.UserID)
+	return s.assemble(ctx, list)
+}
+
+func (s *Service) DeleteList(ctx context.Context, id, userID int) error {
+	if err := s.repo.DeleteList(ctx, id, userID); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("list deleted",
+		"listId", id,
+		"userId", userID)
+	return nil
+}
+
+func (s *Service) LikeList(ctx context.Context, listID int, viewer *privacy.Viewer) error {
+	list, err := s.repo.GetVisibleListByID(ctx, listID, viewer)
+	if err != nil {
+		return err
+	}
+	if !canSee(viewer, list) {
+		return apperrors.NotFound("list", "id", listID)
+	}
+
+	if err = s.repo.LikeList(ctx, listID, viewer.UserID); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("list liked",
+		"listId", listID,
+		"userId", viewer.UserID)
+	return nil
+}
+
+func (s *Service) UnlikeList(ctx context.Context, listID int, viewer *privacy.Viewer) error {
+	if err := s.repo.UnlikeList(ctx, listID, viewer.UserID); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("list unliked",
+		"listId", listID,
+		"userId", viewer.UserID)
+	return nil
+}
+
+func (s *Service) assemble(ctx context.Context, list *ListDetails) error {
+	items, err := s.repo.GetItemsByListID(ctx, list.ID)
+	if err != nil {
+		return err
+	}
+	list.Items = items
+	return nil
+}
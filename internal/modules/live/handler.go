@@ -0,0 +1,140 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
+	"github.com/labstack/echo/v4"
+)
+
+const lastEventIDHeader = "Last-Event-ID"
+
+type Handler struct {
+	service *Service
+	cfg     config.LiveConfig
+}
+
+func NewHandler(service *Service, cfg config.LiveConfig) *Handler {
+	return &Handler{
+		service: service,
+		cfg:     cfg,
+	}
+}
+
+// StreamMovieEvents streams the events of the movie: new, updated and deleted reviews,
+// rating changes and movie edits.
+func (h *Handler) StreamMovieEvents(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.StreamMovieEventsRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.CheckMovie(c.Request().Context(), req.MovieID); err != nil {
+		return err
+	}
+	return h.stream(c, &req.MovieID, req.LastEventID)
+}
+
+// StreamEvents streams the events of all movies.
+func (h *Handler) StreamEvents(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.StreamEventsRequest](c)
+	if err != nil {
+		return err
+	}
+	return h.stream(c, nil, req.LastEventID)
+}
+
+// stream sends the messages as server-sent events until the client disconnects or the stream is closed.
+// The stream is opened before the missed messages are fetched, so that none is lost in between,
+// and the messages received both ways are sent once. Missed messages include the ones preceding the last event id
+// within the replay window, so a resumed client may get again some of the messages it got before.
+func (h *Handler) stream(c echo.Context, movieID *int, lastEventID string) error {
+	if header := c.Request().Header.Get(lastEventIDHeader); header != "" {
+		lastEventID = header
+	}
+	var lastSeq int64
+	if lastEventID != "" {
+		var err error
+		if lastSeq, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || lastSeq < 0 {
+			return apperrors.BadRequest(fmt.Errorf("invalid last event id %q", lastEventID))
+		}
+	}
+
+	ctx := c.Request().Context()
+	stream := h.service.Open(movieID, privacy.GetViewer(c))
+	defer h.service.CloseStream(stream)
+
+	var missed []*Message
+	if lastEventID != "" {
+		var err error
+		if missed, err = h.service.GetMissedMessages(ctx, stream, lastSeq); err != nil {
+			return err
+		}
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", h.cfg.ClientRetry.Milliseconds()); err != nil {
+		return nil
+	}
+
+	sent := make(map[int64]bool, len(missed))
+	for _, msg := range missed {
+		if err := h.writeMessage(ctx, res, stream, msg); err != nil {
+			return nil
+		}
+		sent[msg.Seq] = true
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(h.cfg.HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-stream.Messages:
+			if !ok {
+				return nil
+			}
+			if sent[msg.Seq] {
+				continue
+			}
+			if err := h.writeMessage(ctx, res, stream, msg); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+// writeMessage sends the message if the viewer can see it. A hidden message is skipped, the client still
+// resumes after it since the ids of the messages only increase.
+func (h *Handler) writeMessage(ctx context.Context, res *echo.Response, stream *Stream, msg *Message) error {
+	visible, err := h.service.CanSee(ctx, stream, msg)
+	if err != nil {
+		log.FromContext(ctx).Error("check live event visibility", "seq", msg.Seq, "err", err)
+		return err
+	}
+	if !visible {
+		return nil
+	}
+	_, err = fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", msg.Seq, msg.Type, msg.Data)
+	return err
+}
@@ -0,0 +1,31 @@
+package live
+
+import (
+	"encoding/json"
+)
+
+// channel is the Postgres notification channel announcing the stored events to every server instance.
+const channel = "live_events"
+
+// EventRatingChanged follows every review event with the rating of the movie after it.
+const EventRatingChanged = "movie.rating_changed"
+
+// Message is an event pushed to the streams. Seq is increasing, so that a reconnected
+// client resumes its stream after the last message it got. UserID is the author of a review message,
+// which is sent only to the viewers the privacy settings of the author let see their reviews. Visibility
+// is the visibility of the reviews of the author when the message was stored.
+type Message struct {
+	Seq        int64
+	Type       string
+	MovieID    int
+	Data       json.RawMessage
+	UserID     *int
+	Visibility *string
+}
+
+// Rating is the data of EventRatingChanged.
+type Rating struct {
+	MovieID     int      `json:"movie_id"`
+	AvgRating   *float64 `json:"avg_rating"`
+	RatingCount int      `json:"rating_count"`
+}
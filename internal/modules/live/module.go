@@ -0,0 +1,23 @@
+package live
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	Handler    *Handler
+	Service    *Service
+	Repository *Repository
+}
+
+func NewModule(db *pgxpool.Pool, cfg config.LiveConfig) *Module {
+	repo := NewRepository(db)
+	service := NewService(repo, cfg)
+	handler := NewHandler(service, cfg)
+	return &Module{
+		Handler:    handler,
+		Service:    service,
+		Repository: repo,
+	}
+}
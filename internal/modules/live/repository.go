@@ -0,0 +1,159 @@
+package live
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// CreateMessages stores the messages caused by the domain event and announces them on the channel.
+// Messages already stored for the event are skipped, so that a redelivered event is pushed once.
+// The announcements are sent on commit, when the messages can be read by the listeners.
+func (r *Repository) CreateMessages(ctx context.Context, eventID string, messages []*Message) error {
+	return dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		for _, msg := range messages {
+			err := tx.QueryRow(ctx, `INSERT INTO live_events (event_id, type, movie_id, data, user_id, visibility)
+				VALUES ($1, $2, $3, $4, $5, (SELECT reviews_visibility FROM users WHERE id = $5))
+				ON CONFLICT (event_id, type) DO NOTHING
+				RETURNING seq`,
+				eventID, msg.Type, msg.MovieID, msg.Data, msg.UserID).
+				Scan(&msg.Seq)
+			switch {
+			case dbx.IsNoRows(err):
+				continue
+			case err != nil:
+				return apperrors.Internal(err)
+			}
+			if _, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, channel, strconv.FormatInt(msg.Seq, 10)); err != nil {
+				return apperrors.Internal(err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetMovieRating returns the current rating of the movie, including a deleted one.
+func (r *Repository) GetMovieRating(ctx context.Context, movieID int) (*Rating, error) {
+	rating := &Rating{MovieID: movieID}
+	err := r.db.QueryRow(ctx, `SELECT avg_rating, rating_count FROM movies WHERE id = $1`, movieID).
+		Scan(&rating.AvgRating, &rating.RatingCount)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("movie", "id", movieID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return rating, nil
+}
+
+// GetMessageBySeq returns the stored message.
+func (r *Repository) GetMessageBySeq(ctx context.Context, seq int64) (*Message, error) {
+	var msg Message
+	err := r.db.QueryRow(ctx, `SELECT seq, type, movie_id, data, user_id, visibility::TEXT FROM live_events WHERE seq = $1`, seq).
+		Scan(&msg.Seq, &msg.Type, &msg.MovieID, &msg.Data, &msg.UserID, &msg.Visibility)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("live event", "seq", seq)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return &msg, nil
+}
+
+// GetMessagesAfter returns up to limit messages following the seq, of the movie if it's set, in order.
+// Messages preceding the seq that were stored within the window before it are returned as well, since
+// a message can be committed after the ones following it.
+func (r *Repository) GetMessagesAfter(ctx context.Context, seq int64, window time.Duration, movieID *int, limit int) ([]*Message, error) {
+	rows, err := r.db.Query(ctx, `SELECT seq, type, movie_id, data, user_id, visibility::TEXT FROM live_events
+		WHERE (seq > $1 OR seq < $1 AND created_at >= (
+				SELECT created_at - make_interval(secs => $2) FROM live_events WHERE seq = $1
+			))
+			AND ($3::INTEGER IS NULL OR movie_id = $3)
+		ORDER BY seq
+		LIMIT $4`,
+		seq, window.Seconds(), movieID, limit)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	messages, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[Message])
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return messages, nil
+}
+
+// IsFollowing reports whether the follower follows the followee.
+func (r *Repository) IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error) {
+	var following bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2)`,
+		followerID, followeeID).Scan(&following)
+	if err != nil {
+		return false, apperrors.Internal(err)
+	}
+	return following, nil
+}
+
+// DeleteMessages removes the messages stored before the retention period and returns their number.
+func (r *Repository) DeleteMessages(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := r.db.Exec(ctx, `DELETE FROM live_events WHERE created_at < NOW() - make_interval(secs => $1)`,
+		retention.Seconds())
+	if err != nil {
+		return 0, apperrors.Internal(err)
+	}
+	return int(n.RowsAffected()), nil
+}
+
+// Listen calls fn with the seq of every message announced on the channel until ctx is done or the connection fails.
+// It holds a connection of its own for the whole time, which is closed on return.
+func (r *Repository) Listen(ctx context.Context, ready func(), fn func(seq int64)) error {
+	pooled, err := r.db.Acquire(ctx)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	// The connection stays subscribed to the channel, so it's never given back to the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err = conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return apperrors.Internal(err)
+	}
+	ready()
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		seq, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			continue
+		}
+		fn(seq)
+	}
+}
+
+// CheckMovie returns an error if the movie doesn't exist.
+func (r *Repository) CheckMovie(ctx context.Context, movieID int) error {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM movies WHERE deleted_at IS NULL AND id = $1)`, movieID).
+		Scan(&exists)
+	switch {
+	case err != nil:
+		return apperrors.Internal(err)
+	case !exists:
+		return apperrors.NotFound("movie", "id", movieID)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/users"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
+)
+
+// Stream receives the messages of a movie, or of all movies if MovieID is nil, on behalf of the viewer.
+// Messages is closed when the stream falls behind or the service is closed, then the client has to reconnect.
+type Stream struct {
+	MovieID  *int
+	Messages chan *Message
+
+	viewer *privacy.Viewer
+	// following caches whether the viewer follows the authors of the review messages, for the ones whose reviews
+	// are visible to their followers. It's only used by the goroutine serving the stream.
+	following map[int]bool
+}
+
+type Service struct {
+	repo *Repository
+	cfg  config.LiveConfig
+
+	mu      sync.Mutex
+	streams map[*Stream]struct{}
+	closed  bool
+	// lastSeq is the last message received by the listener, the ones after it are fetched
+	// when the listener reconnects, since the notifications sent meanwhile are lost.
+	lastSeq int64
+	// pushed holds the last pushed messages, in the order they were pushed in recent, so that
+	// the ones fetched again when the listener reconnects are pushed once.
+	pushed map[int64]struct{}
+	recent []int64
+}
+
+func NewService(repo *Repository, cfg config.LiveConfig) *Service {
+	return &Service{
+		repo:    repo,
+		cfg:     cfg,
+		streams: make(map[*Stream]struct{}),
+		pushed:  make(map[int64]struct{}, cfg.ReplayLimit),
+	}
+}
+
+// Subscribe stores the messages pushed to the streams for the domain events relayed by the outbox:
+// new, updated and deleted reviews with the resulting rating of their movie, and movie edits.
+func (s *Service) Subscribe(subscribers *outbox.Subscribers) {
+	for _, eventType := range []string{reviews.EventCreated, reviews.EventUpdated, reviews.EventDeleted} {
+		subscribers.Subscribe(eventType, s.handleReviewEvent)
+	}
+	for _, eventType := range []string{movies.EventCreated, movies.EventUpdated, movies.EventDeleted} {
+		subscribers.Subscribe(eventType, s.handleMovieEvent)
+	}
+}
+
+func (s *Service) handleReviewEvent(ctx context.Context, e *outbox.Event) error {
+	var review struct {
+		MovieID int `json:"movie_id"`
+		UserID  int `json:"user_id"`
+	}
+	if err := json.Unmarshal(e.Payload, &review); err != nil {
+		return err
+	}
+	rating, err := s.repo.GetMovieRating(ctx, review.MovieID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(rating)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateMessages(ctx, e.ID, []*Message{
+		{Type: e.Type, MovieID: review.MovieID, Data: e.Payload, UserID: &review.UserID},
+		{Type: EventRatingChanged, MovieID: review.MovieID, Data: data},
+	})
+}
+
+func (s *Service) handleMovieEvent(ctx context.Context, e *outbox.Event) error {
+	return s.repo.CreateMessages(ctx, e.ID, []*Message{
+		{Type: e.Type, MovieID: e.AggregateID, Data: e.Payload},
+	})
+}
+
+// CheckMovie returns an error if the movie doesn't exist.
+func (s *Service) CheckMovie(ctx context.Context, movieID int) error {
+	return s.repo.CheckMovie(ctx, movieID)
+}
+
+// Open starts a stream of the messages of the movie, or of all movies if movieID is nil, for the viewer.
+// It must be closed with CloseStream.
+func (s *Service) Open(movieID *int, viewer *privacy.Viewer) *Stream {
+	stream := &Stream{
+		MovieID:   movieID,
+		Messages:  make(chan *Message, s.cfg.BufferSize),
+		viewer:    viewer,
+		following: make(map[int]bool),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(stream.Messages)
+		return stream
+	}
+	s.streams[stream] = struct{}{}
+	return stream
+}
+
+func (s *Service) CloseStream(stream *Stream) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.streams[stream]; ok {
+		delete(s.streams, stream)
+		close(stream.Messages)
+	}
+}
+
+// Close closes all the streams, so that the server can shut down without waiting for the clients.
+func (s *Service) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for stream := range s.streams {
+		delete(s.streams, stream)
+		close(stream.Messages)
+	}
+}
+
+// CanSee reports whether the message can be sent to the viewer of the stream: review messages are hidden
+// from the viewers that the privacy settings of their authors exclude. The visibility stored with the message
+// is used, so that only the reviews visible to followers need a query, once per author and stream.
+func (s *Service) CanSee(ctx context.Context, stream *Stream, msg *Message) (bool, error) {
+	viewer := stream.viewer
+	switch {
+	case msg.UserID == nil:
+		return true, nil
+	case viewer != nil && (viewer.IsAdmin || viewer.UserID == *msg.UserID):
+		return true, nil
+	case msg.Visibility == nil:
+		return false, nil
+	case *msg.Visibility == users.VisibilityPublic:
+		return true, nil
+	case viewer == nil || *msg.Visibility != users.VisibilityFollowers:
+		return false, nil
+	}
+
+	following, ok := stream.following[*msg.UserID]
+	if !ok {
+		var err error
+		if following, err = s.repo.IsFollowing(ctx, viewer.UserID, *msg.UserID); err != nil {
+			return false, err
+		}
+		stream.following[*msg.UserID] = following
+	}
+	return following, nil
+}
+
+// GetMissedMessages returns the messages following lastSeq the client of the stream has missed, and the ones
+// preceding it within the replay window, which the client may have missed as well since they were committed
+// after lastSeq. The client skips the ones it already got by their ids.
+func (s *Service) GetMissedMessages(ctx context.Context, stream *Stream, lastSeq int64) ([]*Message, error) {
+	return s.repo.GetMessagesAfter(ctx, lastSeq, s.cfg.ReplayWindow, stream.MovieID, s.cfg.ReplayLimit)
+}
+
+// Run listens to the messages stored by any server instance, pushing them to the streams, and deletes
+// the expired messages until ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	go s.runCleanup(ctx)
+
+	for {
+		err := s.repo.Listen(ctx, func() { s.catchUp(ctx) }, func(seq int64) { s.receive(ctx, seq) })
+		if ctx.Err() != nil {
+			return
+		}
+		log.FromContext(ctx).Error("listen to live events", "err", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.ListenRetry):
+		}
+	}
+}
+
+// catchUp pushes the messages stored while the listener wasn't connected.
+func (s *Service) catchUp(ctx context.Context) {
+	s.mu.Lock()
+	lastSeq := s.lastSeq
+	s.mu.Unlock()
+	if lastSeq == 0 {
+		return
+	}
+	messages, err := s.repo.GetMessagesAfter(ctx, lastSeq, s.cfg.ReplayWindow, nil, s.cfg.ReplayLimit)
+	if err != nil {
+		log.FromContext(ctx).Error("get missed live events", "err", err)
+		return
+	}
+	for _, msg := range messages {
+		s.push(msg)
+	}
+}
+
+func (s *Service) receive(ctx context.Context, seq int64) {
+	msg, err := s.repo.GetMessageBySeq(ctx, seq)
+	if err != nil {
+		log.FromContext(ctx).Error("get live event", "seq", seq, "err", err)
+		return
+	}
+	s.push(msg)
+}
+
+// push sends the message to the streams interested in it, unless it was pushed recently. A stream whose
+// buffer is full is closed rather than blocking the others, its client resumes after the last message it got.
+func (s *Service) push(msg *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pushed[msg.Seq]; ok {
+		return
+	}
+	s.remember(msg.Seq)
+	if msg.Seq > s.lastSeq {
+		s.lastSeq = msg.Seq
+	}
+	for stream := range s.streams {
+		if stream.MovieID != nil && *stream.MovieID != msg.MovieID {
+			continue
+		}
+		select {
+		case stream.Messages <- msg:
+		default:
+			delete(s.streams, stream)
+			close(stream.Messages)
+		}
+	}
+}
+
+// remember adds the seq to the pushed messages, forgetting the oldest one when ReplayLimit messages are kept,
+// since no more are fetched again.
+func (s *Service) remember(seq int64) {
+	if len(s.recent) >= s.cfg.ReplayLimit {
+		delete(s.pushed, s.recent[0])
+		s.recent = s.recent[1:]
+	}
+	s.pushed[seq] = struct{}{}
+	s.recent = append(s.recent, seq)
+}
+
+func (s *Service) runCleanup(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.repo.DeleteMessages(ctx, s.cfg.Retention)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.FromContext(ctx).Error("delete live events", "err", err)
+				}
+				continue
+			}
+			if n > 0 {
+				log.FromContext(ctx).Info("live events deleted", "count", n)
+			}
+		}
+	}
+}
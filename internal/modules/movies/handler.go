@@ -12,10 +12,13 @@ import (
 	"github.com/RadkevichAnn/movie-reviews/contracts"
 	"github.com/RadkevichAnn/movie-reviews/internal/config"
 	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
 	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
 	"github.com/labstack/echo/v4"
 )
 
+const defaultSimilarLimit = 10
+
 type Handler struct {
 	service          *Service
 	paginationConfig config.PaginationConfig
@@ -34,25 +37,7 @@ func (h *Handler) CreateMovie(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	movie := &MovieDetails{
-		Movie: Movie{
-			Title:       req.Title,
-			ReleaseDate: req.ReleaseDate,
-		},
-		Description: req.Description,
-	}
-	for _, genreID := range req.Genres {
-		movie.Genres = append(movie.Genres, &genres.Genre{ID: genreID})
-	}
-	for _, creditID := range req.Cast {
-		movie.Cast = append(movie.Cast, &stars.MovieCredit{
-			Star: stars.Star{
-				ID: creditID.StarID,
-			},
-			Role:    creditID.Role,
-			Details: creditID.Details,
-		})
-	}
+	movie := MovieFromCreateRequest(req)
 	err = h.service.CreateMovie(c.Request().Context(), movie)
 	if err != nil {
 		return err
@@ -75,6 +60,45 @@ func (h *Handler) GetMovieByID(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+
+	// The movie is shared between concurrent requests, while viewer flags are specific to the requester
+	movie := *res.(*MovieDetails)
+	if claims := jwt.GetClaims(c); claims != nil {
+		movie.Viewer, err = h.service.GetViewerFlags(c.Request().Context(), claims.UserID, movie.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusOK, movie)
+}
+
+func (h *Handler) GetMovieByExternalID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetMovieByExternalIDRequest](c)
+	if err != nil {
+		return err
+	}
+	movie, err := h.service.GetMovieByExternalID(c.Request().Context(), req.Source, req.ExternalID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, movie)
+}
+
+func (h *Handler) GetSimilarMovies(c echo.Context) error {
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+		req, err := echox.BindAndValidate[contracts.GetSimilarMoviesRequest](c)
+		if err != nil {
+			return nil, err
+		}
+		limit := defaultSimilarLimit
+		if req.Limit != nil {
+			limit = *req.Limit
+		}
+		return h.service.GetSimilarMovies(c.Request().Context(), req.ID, limit)
+	})
+	if err != nil {
+		return err
+	}
 	return c.JSON(http.StatusOK, res)
 }
 
@@ -86,7 +110,17 @@ func (h *Handler) GetAllMovies(c echo.Context) error {
 		}
 		pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
 		offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
-		movies, total, err := h.service.GetAllMoviesPaginated(c.Request().Context(), req.SearchTerm, req.SortByRating, req.StarID, offset, limit)
+		filter := &Filter{
+			SearchTerm:     req.SearchTerm,
+			SortByRating:   req.SortByRating,
+			StarID:         req.StarID,
+			GenreID:        req.GenreID,
+			ReleaseCountry: req.ReleaseCountry,
+			ReleasedFrom:   req.ReleasedFrom,
+			ReleasedTo:     req.ReleasedTo,
+			AwardWon:       req.AwardWon,
+		}
+		movies, total, err := h.service.GetAllMoviesPaginated(c.Request().Context(), filter, offset, limit)
 		if err != nil {
 			return nil, err
 		}
@@ -109,7 +143,13 @@ func (h *Handler) UpdateMovie(c echo.Context) error {
 			Title:       req.Title,
 			ReleaseDate: req.ReleaseDate,
 		},
-		Description: req.Description,
+		Description:         req.Description,
+		RuntimeMinutes:      req.RuntimeMinutes,
+		OriginalLanguage:    req.OriginalLanguage,
+		ProductionCountries: req.ProductionCountries,
+		Certifications:      req.Certifications,
+		ExternalIDs:         req.ExternalIDs,
+		Releases:            toReleases(req.Releases),
 	}
 	for _, genreID := range req.Genres {
 		movie.Genres = append(movie.Genres, &genres.Genre{ID: genreID})
@@ -129,6 +169,24 @@ func (h *Handler) UpdateMovie(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+func (h *Handler) UploadPoster(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UploadImageRequest](c)
+	if err != nil {
+		return err
+	}
+	file, err := echox.OpenFormFile(c, contracts.ImageFormField)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	img, err := h.service.UploadPoster(c.Request().Context(), req.ID, file)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, img)
+}
+
 func (h *Handler) DeleteMovie(c echo.Context) error {
 	req, err := echox.BindAndValidate[contracts.GetOrDeleteMovieByIDRequest](c)
 	if err != nil {
@@ -139,3 +197,46 @@ func (h *Handler) DeleteMovie(c echo.Context) error {
 	}
 	return c.NoContent(http.StatusOK)
 }
+
+func toReleases(releases []*contracts.Release) []*Release {
+	res := make([]*Release, 0, len(releases))
+	for _, release := range releases {
+		res = append(res, &Release{
+			Country: release.Country,
+			Type:    release.Type,
+			Date:    release.Date,
+			Note:    release.Note,
+		})
+	}
+	return res
+}
+
+// MovieFromCreateRequest converts the request to the movie to be created.
+func MovieFromCreateRequest(req *contracts.CreateMovieRequest) *MovieDetails {
+	movie := &MovieDetails{
+		Movie: Movie{
+			Title:       req.Title,
+			ReleaseDate: req.ReleaseDate,
+		},
+		Description:         req.Description,
+		RuntimeMinutes:      req.RuntimeMinutes,
+		OriginalLanguage:    req.OriginalLanguage,
+		ProductionCountries: req.ProductionCountries,
+		Certifications:      req.Certifications,
+		ExternalIDs:         req.ExternalIDs,
+		Releases:            toReleases(req.Releases),
+	}
+	for _, genreID := range req.Genres {
+		movie.Genres = append(movie.Genres, &genres.Genre{ID: genreID})
+	}
+	for _, creditID := range req.Cast {
+		movie.Cast = append(movie.Cast, &stars.MovieCredit{
+			Star: stars.Star{
+				ID: creditID.StarID,
+			},
+			Role:    creditID.Role,
+			Details: creditID.Details,
+		})
+	}
+	return movie
+}
@@ -3,9 +3,21 @@ package movies
 import (
 	"time"
 
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/collections"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/watchlist"
+)
+
+// Domain events of movies recorded in the outbox. Their payload is the movie, or its id when it's deleted.
+const (
+	AggregateType = "movie"
+	EventCreated  = "movie.created"
+	EventUpdated  = "movie.updated"
+	EventDeleted  = "movie.deleted"
 )
 
 type Movie struct {
@@ -19,8 +31,95 @@ type Movie struct {
 
 type MovieDetails struct {
 	Movie
-	Description string               `json:"description"`
-	Version     int                  `json:"version"`
-	Genres      []*genres.Genre      `json:"genres"`
-	Cast        []*stars.MovieCredit `json:"cast"`
+	Description         string                         `json:"description"`
+	RuntimeMinutes      *int                           `json:"runtime_minutes,omitempty"`
+	OriginalLanguage    *string                        `json:"original_language,omitempty"`
+	ProductionCountries []string                       `json:"production_countries,omitempty"`
+	Certifications      map[string]string              `json:"certifications,omitempty"`
+	ExternalIDs         map[string]string              `json:"external_ids,omitempty"`
+	Releases            []*Release                     `json:"releases,omitempty"`
+	PosterID            *string                        `json:"-"`
+	Poster              *images.Image                  `json:"poster,omitempty"`
+	Version             int                            `json:"version"`
+	Genres              []*genres.Genre                `json:"genres"`
+	Cast                []*stars.MovieCredit           `json:"cast"`
+	Collections         []*collections.MovieCollection `json:"collections,omitempty"`
+	Viewer              *watchlist.MovieFlags          `json:"viewer,omitempty"`
+}
+
+// Release is a release of the movie in a country. The earliest theatrical one
+// (or the earliest of any type if there is none) is the primary release date of the movie.
+type Release struct {
+	Country string    `json:"country"`
+	Type    string    `json:"type"`
+	Date    time.Time `json:"date"`
+	Note    string    `json:"note,omitempty"`
+}
+
+type SimilarMovie struct {
+	Movie
+	Score float64 `json:"score"`
+}
+
+var (
+	_ dbx.Keyer = CertificationRelation{}
+	_ dbx.Keyer = ExternalIDRelation{}
+	_ dbx.Keyer = ReleaseRelation{}
+)
+
+type CertificationRelation struct {
+	MovieID       int
+	Country       string
+	Certification string
+}
+
+func (c CertificationRelation) Key() any {
+	type CertificationRelationKey struct {
+		MovieID int
+		Country string
+	}
+	return CertificationRelationKey{
+		MovieID: c.MovieID,
+		Country: c.Country,
+	}
+}
+
+type ExternalIDRelation struct {
+	MovieID    int
+	Source     string
+	ExternalID string
+}
+
+func (e ExternalIDRelation) Key() any {
+	type ExternalIDRelationKey struct {
+		MovieID int
+		Source  string
+	}
+	return ExternalIDRelationKey{
+		MovieID: e.MovieID,
+		Source:  e.Source,
+	}
+}
+
+type ReleaseRelation struct {
+	MovieID int
+	Country string
+	Type    string
+	Date    time.Time
+	Note    string
+}
+
+func (r ReleaseRelation) Key() any {
+	type ReleaseRelationKey struct {
+		MovieID int
+		Country string
+		Type    string
+		Date    time.Time
+	}
+	return ReleaseRelationKey{
+		MovieID: r.MovieID,
+		Country: r.Country,
+		Type:    r.Type,
+		Date:    r.Date,
+	}
 }
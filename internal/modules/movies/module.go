@@ -2,8 +2,12 @@ package movies
 
 import (
 	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/collections"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/watchlist"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -13,9 +17,9 @@ type Module struct {
 	Repository *Repository
 }
 
-func NewModule(db *pgxpool.Pool, paginationConfig config.PaginationConfig, genresModule *genres.Module, starsModule *stars.Module) *Module {
+func NewModule(db *pgxpool.Pool, paginationConfig config.PaginationConfig, similarConfig config.SimilarMoviesConfig, genresModule *genres.Module, starsModule *stars.Module, watchlistModule *watchlist.Module, collectionsModule *collections.Module, imagesModule *images.Module, outboxModule *outbox.Module) *Module {
 	repo := NewRepository(db, genresModule.Repository, starsModule.Repository)
-	service := NewService(repo, genresModule.Service, starsModule.Service)
+	service := NewService(repo, similarConfig, genresModule.Service, starsModule.Service, watchlistModule.Service, collectionsModule.Service, imagesModule.Service, outboxModule.Service)
 	handler := NewHandler(service, paginationConfig)
 	return &Module{
 		Handler:    handler,
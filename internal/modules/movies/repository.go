@@ -2,6 +2,8 @@ package movies
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
 
@@ -9,12 +11,14 @@ import (
 	"github.com/RadkevichAnn/movie-reviews/internal/slices"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
 	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Repository struct {
+	dbx.Transactor
 	db               *pgxpool.Pool
 	genresRepository *genres.Repository
 	starsRepository  *stars.Repository
@@ -22,6 +26,7 @@ type Repository struct {
 
 func NewRepository(db *pgxpool.Pool, genresRepository *genres.Repository, starsRepository *stars.Repository) *Repository {
 	return &Repository{
+		Transactor:       dbx.NewTransactor(db),
 		db:               db,
 		genresRepository: genresRepository,
 		starsRepository:  starsRepository,
@@ -32,10 +37,11 @@ func (r *Repository) CreateMovie(ctx context.Context, movie *MovieDetails) error
 	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
 		// Insert movies
 		queryString := `INSERT INTO movies 
-						(title,release_date,description) 
-						VALUES ($1,$2,$3)
+						(title,release_date,description,runtime_minutes,original_language,production_countries) 
+						VALUES ($1,$2,$3,$4,$5,COALESCE($6::CHAR(2)[],'{}'))
 							RETURNING id,created_at,deleted_at`
-		err := tx.QueryRow(ctx, queryString, movie.Title, movie.ReleaseDate, movie.Description).
+		err := tx.QueryRow(ctx, queryString, movie.Title, movie.ReleaseDate, movie.Description,
+			movie.RuntimeMinutes, movie.OriginalLanguage, movie.ProductionCountries).
 			Scan(&movie.ID, &movie.CreatedAt, &movie.DeletedAt)
 		if err != nil {
 			return err
@@ -63,21 +69,34 @@ func (r *Repository) CreateMovie(ctx context.Context, movie *MovieDetails) error
 				OrderNo: i,
 			}
 		})
-		return r.UpdateCast(ctx, nil, nextCast)
+		if err = r.UpdateCast(ctx, nil, nextCast); err != nil {
+			return err
+		}
+
+		// Insert metadata
+		if err = r.UpdateCertifications(ctx, nil, toCertificationRelations(movie)); err != nil {
+			return err
+		}
+		if err = r.UpdateReleases(ctx, nil, toReleaseRelations(movie)); err != nil {
+			return err
+		}
+		return r.UpdateExternalIDs(ctx, nil, toExternalIDRelations(movie))
 	})
 	if err != nil {
-		return apperrors.Internal(err)
+		return apperrors.EnsureInternal(err)
 	}
 	return nil
 }
 
 func (r *Repository) GetMovieByID(ctx context.Context, id int) (*MovieDetails, error) {
 	var movie MovieDetails
-	queryString := `SELECT id,title,release_date,avg_rating,created_at,deleted_at,description,version
+	queryString := `SELECT id,title,release_date,avg_rating,created_at,deleted_at,description,version,
+       runtime_minutes,original_language,production_countries,poster_id
  FROM movies WHERE id=$1 AND deleted_at IS NULL;`
 	row := r.db.QueryRow(ctx, queryString, id)
 	err := row.Scan(&movie.ID, &movie.Title, &movie.ReleaseDate, &movie.AvgRating,
-		&movie.CreatedAt, &movie.DeletedAt, &movie.Description, &movie.Version)
+		&movie.CreatedAt, &movie.DeletedAt, &movie.Description, &movie.Version,
+		&movie.RuntimeMinutes, &movie.OriginalLanguage, &movie.ProductionCountries, &movie.PosterID)
 	if dbx.IsNoRows(err) {
 		return nil, apperrors.NotFound("movie", "id", id)
 	}
@@ -87,7 +106,48 @@ func (r *Repository) GetMovieByID(ctx context.Context, id int) (*MovieDetails, e
 	return &movie, nil
 }
 
-func (r *Repository) GetAllMoviesPaginated(ctx context.Context, searchTerm *string, sortByRating *string, starID *int, offset int, limit int) ([]*MovieDetails, int, error) {
+func (r *Repository) GetMoviesByIDs(ctx context.Context, ids []int) (map[int]*MovieDetails, error) {
+	rows, err := r.db.Query(ctx, `SELECT id,title,release_date,avg_rating,created_at,deleted_at,description,version,
+       runtime_minutes,original_language,production_countries,poster_id
+ FROM movies WHERE id = ANY($1) AND deleted_at IS NULL`, ids)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	movies := make(map[int]*MovieDetails, len(ids))
+	for rows.Next() {
+		var movie MovieDetails
+		err = rows.Scan(&movie.ID, &movie.Title, &movie.ReleaseDate, &movie.AvgRating,
+			&movie.CreatedAt, &movie.DeletedAt, &movie.Description, &movie.Version,
+			&movie.RuntimeMinutes, &movie.OriginalLanguage, &movie.ProductionCountries, &movie.PosterID)
+		if err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		movies[movie.ID] = &movie
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return movies, nil
+}
+
+type Filter struct {
+	SearchTerm   *string
+	SortByRating *string
+	StarID       *int
+	// GenreID matches movies of the genre or any of its descendants.
+	GenreID *int
+	// ReleaseCountry, ReleasedFrom and ReleasedTo match movies having a release
+	// in the country within the dates (both inclusive). Each of them is optional.
+	ReleaseCountry *string
+	ReleasedFrom   *time.Time
+	ReleasedTo     *time.Time
+	// AwardWon matches movies that won an award of the ceremony, compared case-insensitively.
+	AwardWon *string
+}
+
+func (r *Repository) GetAllMoviesPaginated(ctx context.Context, filter *Filter, offset int, limit int) ([]*MovieDetails, int, error) {
 	selectQuery := dbx.StatementBuilder.
 		Select("id,title,release_date,avg_rating,created_at,deleted_at").
 		From("movies").
@@ -99,26 +159,60 @@ func (r *Repository) GetAllMoviesPaginated(ctx context.Context, searchTerm *stri
 		From("movies").
 		Where("deleted_at IS NULL")
 
-	if sortByRating != nil {
+	if filter.SortByRating != nil {
 		selectQuery = selectQuery.
-			OrderByClause("avg_rating " + *sortByRating)
+			OrderByClause("avg_rating " + *filter.SortByRating)
 	}
-	if starID != nil {
+	if filter.StarID != nil {
 		selectQuery = selectQuery.
 			Join("movie_stars on movies.id = movie_stars.movie_id").
-			Where("star_id = ?", starID)
+			Where("star_id = ?", filter.StarID)
 
 		queryTotal = queryTotal.
 			Join("movie_stars on movies.id = movie_stars.movie_id").
-			Where("star_id = ?", starID)
+			Where("star_id = ?", filter.StarID)
+	}
+	if filter.GenreID != nil {
+		genreCondition := "EXISTS (SELECT 1 FROM movie_genres mg WHERE mg.movie_id = movies.id AND mg.genre_id IN (" + genres.SubtreeQuery + "))"
+		selectQuery = selectQuery.
+			Where(genreCondition, *filter.GenreID)
+
+		queryTotal = queryTotal.
+			Where(genreCondition, *filter.GenreID)
+	}
+	if filter.ReleaseCountry != nil || filter.ReleasedFrom != nil || filter.ReleasedTo != nil {
+		releaseCondition := "EXISTS (SELECT 1 FROM movie_releases mr WHERE mr.movie_id = movies.id"
+		var args []any
+		if filter.ReleaseCountry != nil {
+			releaseCondition += " AND mr.country = ?"
+			args = append(args, strings.ToUpper(*filter.ReleaseCountry))
+		}
+		if filter.ReleasedFrom != nil {
+			releaseCondition += " AND mr.release_date >= ?::DATE"
+			args = append(args, *filter.ReleasedFrom)
+		}
+		if filter.ReleasedTo != nil {
+			releaseCondition += " AND mr.release_date <= ?::DATE"
+			args = append(args, *filter.ReleasedTo)
+		}
+		releaseCondition += ")"
+		selectQuery = selectQuery.Where(releaseCondition, args...)
+		queryTotal = queryTotal.Where(releaseCondition, args...)
 	}
-	if searchTerm != nil {
+	if filter.AwardWon != nil {
+		awardCondition := `EXISTS (SELECT 1 FROM award_nominations an
+			INNER JOIN awards a ON a.id = an.award_id
+			WHERE an.movie_id = movies.id AND an.won AND a.deleted_at IS NULL AND LOWER(a.ceremony) = LOWER(?))`
+		selectQuery = selectQuery.Where(awardCondition, *filter.AwardWon)
+		queryTotal = queryTotal.Where(awardCondition, *filter.AwardWon)
+	}
+	if filter.SearchTerm != nil {
 		selectQuery = selectQuery.
-			Where("search_vector @@ to_tsquery('english', ?)", *searchTerm).
-			OrderByClause("ts_rank_cd(search_vector, to_tsquery('english', ?)) DESC", *searchTerm)
+			Where("search_vector @@ to_tsquery('english', ?)", *filter.SearchTerm).
+			OrderByClause("ts_rank_cd(search_vector, to_tsquery('english', ?)) DESC", *filter.SearchTerm)
 
 		queryTotal = queryTotal.
-			Where("search_vector @@ to_tsquery('english', ?)", *searchTerm)
+			Where("search_vector @@ to_tsquery('english', ?)", *filter.SearchTerm)
 	}
 
 	b := &pgx.Batch{}
@@ -170,12 +264,18 @@ func (r *Repository) UpdateMovie(ctx context.Context, movie *MovieDetails) error
 			SET version = version + 1, 
 			title = $1,
 			description = $2, 
-			release_date = $3 
-			WHERE id = $4 
-			AND version = $5;`,
+			release_date = $3,
+			runtime_minutes = $4,
+			original_language = $5,
+			production_countries = COALESCE($6::CHAR(2)[], '{}')
+			WHERE id = $7 
+			AND version = $8;`,
 				movie.Title,
 				movie.Description,
 				movie.ReleaseDate,
+				movie.RuntimeMinutes,
+				movie.OriginalLanguage,
+				movie.ProductionCountries,
 				movie.ID,
 				movie.Version,
 			)
@@ -225,8 +325,29 @@ func (r *Repository) UpdateMovie(ctx context.Context, movie *MovieDetails) error
 		if err != nil {
 			return err
 		}
+		if err = r.UpdateCast(ctx, currentCast, nextCast); err != nil {
+			return err
+		}
 
-		return r.UpdateCast(ctx, currentCast, nextCast)
+		currentCertifications, err := r.GetCertificationRelationsByMovieID(ctx, movie.ID)
+		if err != nil {
+			return err
+		}
+		if err = r.UpdateCertifications(ctx, currentCertifications, toCertificationRelations(movie)); err != nil {
+			return err
+		}
+		currentReleases, err := r.GetReleaseRelationsByMovieID(ctx, movie.ID)
+		if err != nil {
+			return err
+		}
+		if err = r.UpdateReleases(ctx, currentReleases, toReleaseRelations(movie)); err != nil {
+			return err
+		}
+		currentExternalIDs, err := r.GetExternalIDRelationsByMovieID(ctx, movie.ID)
+		if err != nil {
+			return err
+		}
+		return r.UpdateExternalIDs(ctx, currentExternalIDs, toExternalIDRelations(movie))
 	})
 	if err != nil {
 		return apperrors.EnsureInternal(err)
@@ -235,9 +356,29 @@ func (r *Repository) UpdateMovie(ctx context.Context, movie *MovieDetails) error
 	return nil
 }
 
+// UpdatePoster sets the poster of the movie and returns the id of the previous one.
+func (r *Repository) UpdatePoster(ctx context.Context, movieID int, imageID string) (*string, error) {
+	var prevID *string
+	err := r.db.QueryRow(ctx, `WITH prev AS (
+			SELECT id, poster_id FROM movies WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		)
+		UPDATE movies m SET poster_id = $2
+		FROM prev
+		WHERE m.id = prev.id
+		RETURNING prev.poster_id`, movieID, imageID).
+		Scan(&prevID)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("movie", "id", movieID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return prevID, nil
+}
+
 func (r *Repository) DeleteMovie(ctx context.Context, id int) error {
 	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
-		n, err := r.db.Exec(ctx, `UPDATE movies SET deleted_at = NOW() WHERE id=$1 AND deleted_at IS NULL`, id)
+		n, err := tx.Exec(ctx, `UPDATE movies SET deleted_at = NOW() WHERE id=$1 AND deleted_at IS NULL`, id)
 		if err != nil {
 			return apperrors.Internal(err)
 		}
@@ -257,7 +398,16 @@ func (r *Repository) DeleteMovie(ctx context.Context, id int) error {
 		if err != nil {
 			return err
 		}
-		return r.UpdateCast(ctx, currentCast, []*stars.MovieStarRelation{})
+		if err = r.UpdateCast(ctx, currentCast, []*stars.MovieStarRelation{}); err != nil {
+			return err
+		}
+
+		// Release external ids so that they can be assigned to another movie
+		currentExternalIDs, err := r.GetExternalIDRelationsByMovieID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return r.UpdateExternalIDs(ctx, currentExternalIDs, nil)
 	})
 	if err != nil {
 		return apperrors.EnsureInternal(err)
@@ -266,6 +416,86 @@ func (r *Repository) DeleteMovie(ctx context.Context, id int) error {
 	return nil
 }
 
+// GetSimilarMovies scores movies sharing genres, credits or description terms with the movie.
+// Release era proximity only adjusts the score of such candidates.
+func (r *Repository) GetSimilarMovies(ctx context.Context, movieID int, cfg config.SimilarMoviesConfig, limit int) ([]*SimilarMovie, error) {
+	roles := make([]string, 0, len(cfg.RoleWeights))
+	weights := make([]float64, 0, len(cfg.RoleWeights))
+	for role, weight := range cfg.RoleWeights {
+		roles = append(roles, role)
+		weights = append(weights, weight)
+	}
+
+	rows, err := r.db.Query(ctx, `WITH src AS (
+			SELECT id, release_date, search_vector,
+			       to_tsquery('simple', array_to_string(ARRAY(
+			           SELECT quote_literal(lexeme) FROM unnest(tsvector_to_array(search_vector)) AS lexeme
+			       ), ' | ')) AS terms
+			FROM movies
+			WHERE id = $1 AND deleted_at IS NULL
+		),
+		role_weights AS (
+			SELECT * FROM unnest($2::TEXT[], $3::DOUBLE PRECISION[]) AS rw(role, weight)
+		),
+		scores AS (
+			SELECT mg.movie_id, COUNT(*) * $4::DOUBLE PRECISION AS score
+			FROM movie_genres sg
+			INNER JOIN movie_genres mg ON mg.genre_id = sg.genre_id
+			WHERE sg.movie_id = $1 AND mg.movie_id <> $1
+			GROUP BY mg.movie_id
+			UNION ALL
+			SELECT ms.movie_id, SUM(rw.weight / (1 + ss.order_no) / (1 + ms.order_no)) * $5::DOUBLE PRECISION
+			FROM movie_stars ss
+			INNER JOIN role_weights rw ON rw.role = ss.role::TEXT
+			INNER JOIN movie_stars ms ON ms.star_id = ss.star_id
+			WHERE ss.movie_id = $1 AND ms.movie_id <> $1
+			GROUP BY ms.movie_id
+			UNION ALL
+			SELECT m.id, ts_rank(m.search_vector, src.terms) * $6::DOUBLE PRECISION
+			FROM movies m, src
+			WHERE m.search_vector @@ src.terms AND m.id <> $1
+		),
+		candidates AS (
+			SELECT movie_id, SUM(score) AS score
+			FROM scores
+			GROUP BY movie_id
+		)
+		SELECT m.id, m.title, m.release_date, m.avg_rating, m.created_at,
+		       c.score + $7::DOUBLE PRECISION * GREATEST(0, 1 - ABS(
+		           EXTRACT(YEAR FROM m.release_date)::INTEGER - EXTRACT(YEAR FROM src.release_date)::INTEGER
+		       ) / $8::DOUBLE PRECISION) AS score
+		FROM candidates c
+		INNER JOIN movies m ON m.id = c.movie_id
+		CROSS JOIN src
+		WHERE m.deleted_at IS NULL
+		ORDER BY score DESC, m.id
+		LIMIT $9`,
+		movieID, roles, weights, cfg.GenreWeight, cfg.CastWeight, cfg.TextWeight, cfg.EraWeight, cfg.EraYears, limit)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var movies []*SimilarMovie
+	for rows.Next() {
+		var movie SimilarMovie
+		if err = rows.Scan(
+			&movie.ID,
+			&movie.Title,
+			&movie.ReleaseDate,
+			&movie.AvgRating,
+			&movie.CreatedAt,
+			&movie.Score); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		movies = append(movies, &movie)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return movies, nil
+}
+
 func (r *Repository) Lock(ctx context.Context, tx pgx.Tx, movieID int) error {
 	n, err := r.db.Exec(ctx, `SELECT 1 FROM movies WHERE deleted_at IS NULL AND id = $1 for update`, movieID)
 	if err != nil {
@@ -318,3 +548,200 @@ func (r *Repository) UpdateCast(ctx context.Context, current, next []*stars.Movi
 	}
 	return dbx.AdjustRelations(current, next, addFunc, removeFunc)
 }
+
+func (r *Repository) GetMovieIDByExternalID(ctx context.Context, source, externalID string) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx, `SELECT e.movie_id
+		FROM movie_external_ids e
+		INNER JOIN movies m ON m.id = e.movie_id
+		WHERE e.source = $1 AND e.external_id = $2 AND m.deleted_at IS NULL`, source, externalID).
+		Scan(&id)
+	switch {
+	case dbx.IsNoRows(err):
+		return 0, apperrors.NotFound("movie", source+" id", externalID)
+	case err != nil:
+		return 0, apperrors.Internal(err)
+	}
+	return id, nil
+}
+
+func (r *Repository) GetCertificationRelationsByMovieID(ctx context.Context, movieID int) ([]CertificationRelation, error) {
+	q := dbx.FromContext(ctx, r.db)
+	rows, err := q.Query(ctx, `SELECT movie_id, country, certification FROM movie_certifications WHERE movie_id = $1`, movieID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var relations []CertificationRelation
+	for rows.Next() {
+		var relation CertificationRelation
+		if err = rows.Scan(
+			&relation.MovieID,
+			&relation.Country,
+			&relation.Certification); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		relations = append(relations, relation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return relations, nil
+}
+
+func (r *Repository) GetExternalIDRelationsByMovieID(ctx context.Context, movieID int) ([]ExternalIDRelation, error) {
+	q := dbx.FromContext(ctx, r.db)
+	rows, err := q.Query(ctx, `SELECT movie_id, source, external_id FROM movie_external_ids WHERE movie_id = $1`, movieID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var relations []ExternalIDRelation
+	for rows.Next() {
+		var relation ExternalIDRelation
+		if err = rows.Scan(
+			&relation.MovieID,
+			&relation.Source,
+			&relation.ExternalID); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		relations = append(relations, relation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return relations, nil
+}
+
+func (r *Repository) UpdateCertifications(ctx context.Context, current, next []CertificationRelation) error {
+	q := dbx.FromContext(ctx, r.db)
+	addFunc := func(rel CertificationRelation) error {
+		_, err := q.Exec(ctx, `INSERT INTO movie_certifications (movie_id, country, certification) VALUES ($1, $2, $3)`,
+			rel.MovieID, rel.Country, rel.Certification)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	removeFunc := func(rel CertificationRelation) error {
+		_, err := q.Exec(ctx, `DELETE FROM movie_certifications WHERE movie_id = $1 AND country = $2`,
+			rel.MovieID, rel.Country)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	return dbx.AdjustRelations(current, next, addFunc, removeFunc)
+}
+
+func (r *Repository) UpdateExternalIDs(ctx context.Context, current, next []ExternalIDRelation) error {
+	q := dbx.FromContext(ctx, r.db)
+	addFunc := func(rel ExternalIDRelation) error {
+		_, err := q.Exec(ctx, `INSERT INTO movie_external_ids (movie_id, source, external_id) VALUES ($1, $2, $3)`,
+			rel.MovieID, rel.Source, rel.ExternalID)
+		switch {
+		case dbx.IsUniqueViolation(err, "external_id"):
+			return apperrors.AlreadyExists("movie", rel.Source+" id", rel.ExternalID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	removeFunc := func(rel ExternalIDRelation) error {
+		_, err := q.Exec(ctx, `DELETE FROM movie_external_ids WHERE movie_id = $1 AND source = $2`,
+			rel.MovieID, rel.Source)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	return dbx.AdjustRelations(current, next, addFunc, removeFunc)
+}
+
+func toCertificationRelations(movie *MovieDetails) []CertificationRelation {
+	relations := make([]CertificationRelation, 0, len(movie.Certifications))
+	for country, certification := range movie.Certifications {
+		relations = append(relations, CertificationRelation{
+			MovieID:       movie.ID,
+			Country:       country,
+			Certification: certification,
+		})
+	}
+	return relations
+}
+
+func toExternalIDRelations(movie *MovieDetails) []ExternalIDRelation {
+	relations := make([]ExternalIDRelation, 0, len(movie.ExternalIDs))
+	for source, externalID := range movie.ExternalIDs {
+		relations = append(relations, ExternalIDRelation{
+			MovieID:    movie.ID,
+			Source:     source,
+			ExternalID: externalID,
+		})
+	}
+	return relations
+}
+
+func (r *Repository) GetReleaseRelationsByMovieID(ctx context.Context, movieID int) ([]ReleaseRelation, error) {
+	q := dbx.FromContext(ctx, r.db)
+	rows, err := q.Query(ctx, `SELECT movie_id, country, type, release_date, note FROM movie_releases 
+		WHERE movie_id = $1
+		ORDER BY release_date, country, type`, movieID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var relations []ReleaseRelation
+	for rows.Next() {
+		var relation ReleaseRelation
+		if err = rows.Scan(
+			&relation.MovieID,
+			&relation.Country,
+			&relation.Type,
+			&relation.Date,
+			&relation.Note); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		relations = append(relations, relation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return relations, nil
+}
+
+func (r *Repository) UpdateReleases(ctx context.Context, current, next []ReleaseRelation) error {
+	q := dbx.FromContext(ctx, r.db)
+	addFunc := func(rel ReleaseRelation) error {
+		_, err := q.Exec(ctx, `INSERT INTO movie_releases (movie_id, country, type, release_date, note) VALUES ($1, $2, $3, $4, $5)`,
+			rel.MovieID, rel.Country, rel.Type, rel.Date, rel.Note)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	removeFunc := func(rel ReleaseRelation) error {
+		_, err := q.Exec(ctx, `DELETE FROM movie_releases WHERE movie_id = $1 AND country = $2 AND type = $3 AND release_date = $4`,
+			rel.MovieID, rel.Country, rel.Type, rel.Date)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		return nil
+	}
+	return dbx.AdjustRelations(current, next, addFunc, removeFunc)
+}
+
+func toReleaseRelations(movie *MovieDetails) []ReleaseRelation {
+	return slices.MapIndex(movie.Releases, func(_ int, release *Release) ReleaseRelation {
+		return ReleaseRelation{
+			MovieID: movie.ID,
+			Country: release.Country,
+			Type:    release.Type,
+			Date:    release.Date,
+			Note:    release.Note,
+		}
+	})
+}
@@ -2,34 +2,77 @@ package movies
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/collections"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/watchlist"
 
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
 	"github.com/RadkevichAnn/movie-reviews/internal/log"
 )
 
+const (
+	maxCertificationLength = 16
+	maxExternalIDLength    = 64
+	maxReleaseNoteLength   = 255
+)
+
+var (
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	languagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+	sourcePattern   = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+)
+
 type Service struct {
-	repo          *Repository
-	genresService *genres.Service
-	starService   *stars.Service
+	repo              *Repository
+	similarConfig     config.SimilarMoviesConfig
+	genresService     *genres.Service
+	starService       *stars.Service
+	watchlistService  *watchlist.Service
+	collectionService *collections.Service
+	imagesService     *images.Service
+	outboxService     *outbox.Service
 }
 
-func NewService(repo *Repository, genresService *genres.Service, starService *stars.Service) *Service {
+func NewService(repo *Repository, similarConfig config.SimilarMoviesConfig, genresService *genres.Service, starService *stars.Service, watchlistService *watchlist.Service, collectionService *collections.Service, imagesService *images.Service, outboxService *outbox.Service) *Service {
 	return &Service{
-		repo:          repo,
-		genresService: genresService,
-		starService:   starService,
+		repo:              repo,
+		similarConfig:     similarConfig,
+		genresService:     genresService,
+		starService:       starService,
+		watchlistService:  watchlistService,
+		collectionService: collectionService,
+		imagesService:     imagesService,
+		outboxService:     outboxService,
 	}
 }
 
 func (s *Service) CreateMovie(ctx context.Context, movie *MovieDetails) error {
-	if err := s.repo.CreateMovie(ctx, movie); err != nil {
+	if err := Normalize(movie); err != nil {
 		return err
 	}
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateMovie(ctx, movie); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, movie.ID, EventCreated, movie)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
 	log.FromContext(ctx).Info(
 		"movie created",
 		"movie title", movie.Title,
@@ -46,14 +89,48 @@ func (s *Service) GetMovieByID(ctx context.Context, id int) (*MovieDetails, erro
 	return m, err
 }
 
-func (s *Service) GetAllMoviesPaginated(ctx context.Context, searchTerm *string, sortByRating *string, starID *int, offset int, limit int) ([]*MovieDetails, int, error) {
-	return s.repo.GetAllMoviesPaginated(ctx, searchTerm, sortByRating, starID, offset, limit)
+// GetMoviesByIDs returns the existing movies among the ids in one query. Unlike GetMovieByID,
+// their genres, cast and other related data are not loaded.
+func (s *Service) GetMoviesByIDs(ctx context.Context, ids []int) (map[int]*MovieDetails, error) {
+	return s.repo.GetMoviesByIDs(ctx, ids)
+}
+
+func (s *Service) GetMovieByExternalID(ctx context.Context, source, externalID string) (*MovieDetails, error) {
+	id, err := s.repo.GetMovieIDByExternalID(ctx, strings.ToLower(source), externalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetMovieByID(ctx, id)
+}
+
+func (s *Service) GetViewerFlags(ctx context.Context, userID, movieID int) (*watchlist.MovieFlags, error) {
+	return s.watchlistService.GetMovieFlags(ctx, userID, movieID)
+}
+
+func (s *Service) GetSimilarMovies(ctx context.Context, id int, limit int) ([]*SimilarMovie, error) {
+	if _, err := s.repo.GetMovieByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSimilarMovies(ctx, id, s.similarConfig, limit)
+}
+
+func (s *Service) GetAllMoviesPaginated(ctx context.Context, filter *Filter, offset int, limit int) ([]*MovieDetails, int, error) {
+	return s.repo.GetAllMoviesPaginated(ctx, filter, offset, limit)
 }
 
 func (s *Service) UpdateMovie(ctx context.Context, movie *MovieDetails) error {
-	if err := s.repo.UpdateMovie(ctx, movie); err != nil {
+	if err := Normalize(movie); err != nil {
 		return err
 	}
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateMovie(ctx, movie); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, movie.ID, EventUpdated, movie)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
 	log.FromContext(ctx).Info(
 		"movie updated",
 		"id", movie.ID)
@@ -61,8 +138,14 @@ func (s *Service) UpdateMovie(ctx context.Context, movie *MovieDetails) error {
 }
 
 func (s *Service) DeleteMovie(ctx context.Context, id int) error {
-	if err := s.repo.DeleteMovie(ctx, id); err != nil {
-		return err
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteMovie(ctx, id); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, id, EventDeleted, map[string]int{"id": id})
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
 	}
 	log.FromContext(ctx).Info(
 		"movie deleted",
@@ -70,7 +153,33 @@ func (s *Service) DeleteMovie(ctx context.Context, id int) error {
 	return nil
 }
 
+// UploadPoster stores the image as the poster of the movie replacing the previous one.
+func (s *Service) UploadPoster(ctx context.Context, movieID int, r io.Reader) (*images.Image, error) {
+	if _, err := s.repo.GetMovieByID(ctx, movieID); err != nil {
+		return nil, err
+	}
+	img, err := s.imagesService.Upload(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	prevID, err := s.repo.UpdatePoster(ctx, movieID, img.ID)
+	if err != nil {
+		s.imagesService.Delete(ctx, img.ID)
+		return nil, err
+	}
+	if prevID != nil {
+		s.imagesService.Delete(ctx, *prevID)
+	}
+	log.FromContext(ctx).Info(
+		"movie poster uploaded",
+		"movieId", movieID,
+		"imageId", img.ID)
+	return img, nil
+}
+
 func (s *Service) assemble(ctx context.Context, movie *MovieDetails) error {
+	movie.Poster = s.imagesService.GetImage(movie.PosterID)
+
 	group, groupCtx := errgroup.WithContext(ctx)
 
 	group.Go(func() error {
@@ -83,5 +192,160 @@ func (s *Service) assemble(ctx context.Context, movie *MovieDetails) error {
 		movie.Cast, err = s.starService.GetCastByMovieID(groupCtx, movie.ID)
 		return err
 	})
+	group.Go(func() error {
+		var err error
+		movie.Collections, err = s.collectionService.GetCollectionsByMovieID(groupCtx, movie.ID)
+		return err
+	})
+	group.Go(func() error {
+		relations, err := s.repo.GetCertificationRelationsByMovieID(groupCtx, movie.ID)
+		if err != nil {
+			return err
+		}
+		movie.Certifications = make(map[string]string, len(relations))
+		for _, relation := range relations {
+			movie.Certifications[relation.Country] = relation.Certification
+		}
+		return nil
+	})
+	group.Go(func() error {
+		relations, err := s.repo.GetExternalIDRelationsByMovieID(groupCtx, movie.ID)
+		if err != nil {
+			return err
+		}
+		movie.ExternalIDs = make(map[string]string, len(relations))
+		for _, relation := range relations {
+			movie.ExternalIDs[relation.Source] = relation.ExternalID
+		}
+		return nil
+	})
+	group.Go(func() error {
+		relations, err := s.repo.GetReleaseRelationsByMovieID(groupCtx, movie.ID)
+		if err != nil {
+			return err
+		}
+		movie.Releases = make([]*Release, 0, len(relations))
+		for _, relation := range relations {
+			movie.Releases = append(movie.Releases, &Release{
+				Country: relation.Country,
+				Type:    relation.Type,
+				Date:    relation.Date,
+				Note:    relation.Note,
+			})
+		}
+		return nil
+	})
 	return group.Wait()
 }
+
+// Normalize validates the movie and converts its metadata to the canonical form before it's stored.
+func Normalize(movie *MovieDetails) error {
+	if err := normalizeMetadata(movie); err != nil {
+		return err
+	}
+	return normalizeReleases(movie)
+}
+
+// normalizeMetadata converts country, language and source codes to their canonical case
+// and rejects codes that don't follow ISO 3166-1 alpha-2 and ISO 639 formats.
+func normalizeMetadata(movie *MovieDetails) error {
+	if movie.OriginalLanguage != nil {
+		language := strings.ToLower(*movie.OriginalLanguage)
+		if !languagePattern.MatchString(language) {
+			return apperrors.BadRequest(fmt.Errorf("invalid language code %q", *movie.OriginalLanguage))
+		}
+		movie.OriginalLanguage = &language
+	}
+
+	for i, country := range movie.ProductionCountries {
+		movie.ProductionCountries[i] = strings.ToUpper(country)
+		if !countryPattern.MatchString(movie.ProductionCountries[i]) {
+			return apperrors.BadRequest(fmt.Errorf("invalid country code %q", country))
+		}
+	}
+
+	certifications := make(map[string]string, len(movie.Certifications))
+	for country, certification := range movie.Certifications {
+		code := strings.ToUpper(country)
+		if !countryPattern.MatchString(code) {
+			return apperrors.BadRequest(fmt.Errorf("invalid country code %q", country))
+		}
+		if certification == "" || len(certification) > maxCertificationLength {
+			return apperrors.BadRequest(fmt.Errorf("invalid certification %q for country %s", certification, code))
+		}
+		certifications[code] = certification
+	}
+	movie.Certifications = certifications
+
+	externalIDs, err := NormalizeExternalIDs(movie.ExternalIDs)
+	if err != nil {
+		return err
+	}
+	movie.ExternalIDs = externalIDs
+	return nil
+}
+
+// NormalizeExternalIDs lowercases the sources of the external ids and validates the ids.
+func NormalizeExternalIDs(ids map[string]string) (map[string]string, error) {
+	externalIDs := make(map[string]string, len(ids))
+	for source, externalID := range ids {
+		source = strings.ToLower(source)
+		if !sourcePattern.MatchString(source) {
+			return nil, apperrors.BadRequest(fmt.Errorf("invalid external source %q", source))
+		}
+		if externalID == "" || len(externalID) > maxExternalIDLength {
+			return nil, apperrors.BadRequest(fmt.Errorf("invalid %s id %q", source, externalID))
+		}
+		externalIDs[source] = externalID
+	}
+	return externalIDs, nil
+}
+
+// normalizeReleases validates the releases of the movie and derives its primary release date from them:
+// the earliest theatrical release, or the earliest release of any type if there are no theatrical ones.
+// The release date of the movie is kept as is when it has no releases.
+func normalizeReleases(movie *MovieDetails) error {
+	var primary, earliest time.Time
+	for _, release := range movie.Releases {
+		release.Country = strings.ToUpper(release.Country)
+		if !countryPattern.MatchString(release.Country) {
+			return apperrors.BadRequest(fmt.Errorf("invalid country code %q", release.Country))
+		}
+		release.Type = strings.ToLower(release.Type)
+		switch release.Type {
+		case contracts.ReleaseTypePremiere, contracts.ReleaseTypeTheatricalLimited, contracts.ReleaseTypeTheatrical,
+			contracts.ReleaseTypeDigital, contracts.ReleaseTypePhysical, contracts.ReleaseTypeTV:
+		default:
+			return apperrors.BadRequest(fmt.Errorf("invalid release type %q", release.Type))
+		}
+		if release.Date.IsZero() {
+			return apperrors.BadRequest(fmt.Errorf("release date is required for %s release in %s", release.Type, release.Country))
+		}
+		if len(release.Note) > maxReleaseNoteLength {
+			return apperrors.BadRequest(fmt.Errorf("release note must be at most %d characters long", maxReleaseNoteLength))
+		}
+		// Releases are stored with day precision
+		release.Date = time.Date(release.Date.Year(), release.Date.Month(), release.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+		if earliest.IsZero() || release.Date.Before(earliest) {
+			earliest = release.Date
+		}
+		if isTheatricalRelease(release.Type) && (primary.IsZero() || release.Date.Before(primary)) {
+			primary = release.Date
+		}
+	}
+
+	switch {
+	case !primary.IsZero():
+		movie.ReleaseDate = primary
+	case !earliest.IsZero():
+		movie.ReleaseDate = earliest
+	case movie.ReleaseDate.IsZero():
+		return apperrors.BadRequest(fmt.Errorf("either release date or releases must be provided"))
+	}
+	return nil
+}
+
+func isTheatricalRelease(t string) bool {
+	return t == contracts.ReleaseTypePremiere || t == contracts.ReleaseTypeTheatricalLimited || t == contracts.ReleaseTypeTheatrical
+}
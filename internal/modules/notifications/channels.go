@@ -0,0 +1,168 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/signing"
+)
+
+// SignatureHeader holds the HMAC-SHA256 signature of the webhook request body made with the webhook secret.
+const SignatureHeader = "X-Signature"
+
+// Channel delivers notifications to the recipients that chose it.
+type Channel interface {
+	Name() string
+	Deliver(ctx context.Context, recipient *Recipient, n *Notification) error
+}
+
+// inAppChannel stores the notification in the inbox of the recipient.
+type inAppChannel struct {
+	repo *Repository
+}
+
+func (ch *inAppChannel) Name() string {
+	return ChannelInApp
+}
+
+func (ch *inAppChannel) Deliver(ctx context.Context, recipient *Recipient, n *Notification) error {
+	return ch.repo.CreateNotification(ctx, recipient.UserID, n)
+}
+
+type emailChannel struct {
+	mailer Mailer
+}
+
+func (ch *emailChannel) Name() string {
+	return ChannelEmail
+}
+
+func (ch *emailChannel) Deliver(ctx context.Context, recipient *Recipient, n *Notification) error {
+	return ch.mailer.Send(ctx, &Message{
+		To:      recipient.Email,
+		Subject: n.Title,
+		Body:    n.Title,
+	})
+}
+
+// nonPublicNetworks are the address ranges that aren't reachable on the internet and aren't covered
+// by the checks of net.IP.
+var nonPublicNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+	mustParseCIDR("240.0.0.0/4"),
+}
+
+var errNonPublicAddress = errors.New("webhook address is not public")
+
+// webhookChannel posts the notification to the webhook of the recipient, if they have one.
+type webhookChannel struct {
+	client *http.Client
+}
+
+// newWebhookClient creates the client of the webhooks. Unless private webhooks are allowed, it refuses to connect
+// to non-public addresses, which are checked once resolved, so that a host can't be changed to resolve
+// to an internal address after the webhook was set. Proxies are not used, since they would be dialed instead.
+func newWebhookClient(cfg config.NotificationsConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.AllowPrivateWebhooks {
+		dialer := &net.Dialer{
+			Timeout: cfg.WebhookTimeout,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+					return errNonPublicAddress
+				}
+				return nil
+			},
+		}
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+	}
+	return &http.Client{Timeout: cfg.WebhookTimeout, Transport: transport}
+}
+
+// checkPublicHost returns a bad request error unless the host, a name or an IP address, resolves to public
+// addresses only.
+func checkPublicHost(ctx context.Context, host string) error {
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return apperrors.BadRequest(fmt.Errorf("can't resolve webhook host %q", host))
+		}
+		ips = ips[:0]
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return apperrors.BadRequest(fmt.Errorf("webhook host %q is not a public address", host))
+		}
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+func (ch *webhookChannel) Name() string {
+	return ChannelWebhook
+}
+
+func (ch *webhookChannel) Deliver(ctx context.Context, recipient *Recipient, n *Notification) error {
+	if recipient.Webhook == nil {
+		return nil
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, recipient.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, "sha256="+signing.Sign(recipient.Webhook.Secret, body))
+
+	resp, err := ch.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
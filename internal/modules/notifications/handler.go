@@ -0,0 +1,116 @@
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) GetNotifications(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetNotificationsRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	claims := jwt.GetClaims(c)
+	notifications, total, err := h.service.GetNotificationsPaginated(c.Request().Context(), claims.UserID, req.UnreadOnly, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, notifications))
+}
+
+func (h *Handler) GetUnreadCount(c echo.Context) error {
+	claims := jwt.GetClaims(c)
+	count, err := h.service.CountUnread(c.Request().Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &contracts.UnreadCount{Count: count})
+}
+
+func (h *Handler) MarkRead(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.MarkNotificationReadRequest](c)
+	if err != nil {
+		return err
+	}
+	claims := jwt.GetClaims(c)
+	if err = h.service.MarkRead(c.Request().Context(), claims.UserID, req.NotificationID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) MarkAllRead(c echo.Context) error {
+	claims := jwt.GetClaims(c)
+	count, err := h.service.MarkAllRead(c.Request().Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &contracts.UnreadCount{Count: count})
+}
+
+func (h *Handler) GetPreferences(c echo.Context) error {
+	claims := jwt.GetClaims(c)
+	preferences, err := h.service.GetPreferences(c.Request().Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, preferences)
+}
+
+func (h *Handler) UpdatePreferences(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UpdateNotificationPreferencesRequest](c)
+	if err != nil {
+		return err
+	}
+	preferences := make([]*Preference, 0, len(req.Preferences))
+	for _, preference := range req.Preferences {
+		preferences = append(preferences, &Preference{Type: preference.Type, Channels: preference.Channels})
+	}
+	claims := jwt.GetClaims(c)
+	updated, err := h.service.UpdatePreferences(c.Request().Context(), claims.UserID, preferences)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *Handler) SetWebhook(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.SetNotificationWebhookRequest](c)
+	if err != nil {
+		return err
+	}
+	claims := jwt.GetClaims(c)
+	webhook, err := h.service.SetWebhook(c.Request().Context(), claims.UserID, req.URL)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, webhook)
+}
+
+func (h *Handler) DeleteWebhook(c echo.Context) error {
+	claims := jwt.GetClaims(c)
+	if err := h.service.DeleteWebhook(c.Request().Context(), claims.UserID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,28 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// LogMailer only logs the emails. It's used until a real mail provider is configured.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg *Message) error {
+	log.FromContext(ctx).Info("email sent",
+		"to", msg.To,
+		"subject", msg.Subject)
+	return nil
+}
@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	TypeFolloweeReviewed      = "followee_reviewed"
+	TypeWatchlistMovieUpdated = "watchlist_movie_updated"
+)
+
+// Types are all the notification types, which users can set their preferences for.
+var Types = []string{TypeFolloweeReviewed, TypeWatchlistMovieUpdated}
+
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// defaultChannels deliver the notifications of the types the user has no preferences for.
+var defaultChannels = []string{ChannelInApp}
+
+// DeliverJob is the type of the job delivering a notification to its recipients.
+const DeliverJob = "notifications.deliver"
+
+type Notification struct {
+	ID        int             `json:"id"`
+	UserID    int             `json:"user_id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+}
+
+// Event is what happened that the audience is notified about.
+type Event struct {
+	Type     string
+	Title    string
+	Payload  any
+	Audience Audience
+	// ActorID is the user that caused the event, who isn't notified about it.
+	ActorID *int
+}
+
+type audienceKind int
+
+const (
+	audienceMovieWatchers audienceKind = iota + 1
+	audienceReviewReaders
+)
+
+// Audience defines the users notified about an event.
+type Audience struct {
+	kind audienceKind
+	id   int
+}
+
+// MovieWatchers are the users having the movie in their watchlist.
+func MovieWatchers(movieID int) Audience {
+	return Audience{kind: audienceMovieWatchers, id: movieID}
+}
+
+// ReviewReaders are the followers of the user unless the user made their reviews private.
+func ReviewReaders(userID int) Audience {
+	return Audience{kind: audienceReviewReaders, id: userID}
+}
+
+type DeliverPayload struct {
+	Type    string          `json:"type"`
+	Title   string          `json:"title"`
+	Payload json.RawMessage `json:"payload"`
+	UserIDs []int           `json:"user_ids"`
+}
+
+type DeliverResult struct {
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+}
+
+// Recipient is the user a notification is delivered to through their preferred channels.
+type Recipient struct {
+	UserID   int
+	Email    string
+	Channels []string
+	Webhook  *Webhook
+}
+
+// Webhook is the URL the notifications of the user are posted to. Their bodies are signed with Secret.
+type Webhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// Preference holds the channels delivering the notifications of the type, none disables them.
+type Preference struct {
+	Type     string   `json:"type"`
+	Channels []string `json:"channels"`
+}
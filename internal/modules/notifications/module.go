@@ -0,0 +1,30 @@
+package notifications
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jobs"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	Handler    *Handler
+	Service    *Service
+	Repository *Repository
+}
+
+func NewModule(
+	db *pgxpool.Pool,
+	cfg config.NotificationsConfig,
+	paginationConfig config.PaginationConfig,
+	scheduler jobs.Scheduler,
+	mailer Mailer,
+) *Module {
+	repo := NewRepository(db)
+	service := NewService(repo, cfg, scheduler, mailer)
+	handler := NewHandler(service, paginationConfig)
+	return &Module{
+		Handler:    handler,
+		Service:    service,
+		Repository: repo,
+	}
+}
@@ -0,0 +1,223 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var audienceQueries = map[audienceKind]string{
+	audienceMovieWatchers: `SELECT user_id FROM watchlist_items WHERE movie_id = $1 AND user_id <> $2`,
+	audienceReviewReaders: `SELECT f.follower_id
+		FROM follows f
+		INNER JOIN users u ON u.id = f.followee_id
+		WHERE f.followee_id = $1 AND f.follower_id <> $2 AND u.reviews_visibility <> 'private'`,
+}
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// GetAudience returns the ids of the users in the audience except for the actor.
+func (r *Repository) GetAudience(ctx context.Context, audience Audience, actorID *int) ([]int, error) {
+	query, ok := audienceQueries[audience.kind]
+	if !ok {
+		return nil, apperrors.Internal(fmt.Errorf("unknown audience %d", audience.kind))
+	}
+	excluded := 0
+	if actorID != nil {
+		excluded = *actorID
+	}
+	rows, err := r.db.Query(ctx, query, audience.id, excluded)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return userIDs, nil
+}
+
+// GetRecipients returns the existing users with their preferred channels for the notification type.
+func (r *Repository) GetRecipients(ctx context.Context, userIDs []int, notificationType string) ([]*Recipient, error) {
+	rows, err := r.db.Query(ctx, `SELECT u.id, u.email, COALESCE(p.channels, $3), w.url, w.secret
+		FROM users u
+		LEFT JOIN notification_preferences p ON p.user_id = u.id AND p.type = $2
+		LEFT JOIN notification_webhooks w ON w.user_id = u.id
+		WHERE u.id = ANY($1) AND u.deleted_at IS NULL
+		ORDER BY u.id`, userIDs, notificationType, defaultChannels)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var recipients []*Recipient
+	for rows.Next() {
+		var (
+			recipient   Recipient
+			url, secret *string
+		)
+		if err = rows.Scan(&recipient.UserID, &recipient.Email, &recipient.Channels, &url, &secret); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		if url != nil && secret != nil {
+			recipient.Webhook = &Webhook{URL: *url, Secret: *secret}
+		}
+		recipients = append(recipients, &recipient)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return recipients, nil
+}
+
+func (r *Repository) CreateNotification(ctx context.Context, userID int, n *Notification) error {
+	_, err := r.db.Exec(ctx, `INSERT INTO notifications (user_id, type, title, payload) VALUES ($1, $2, $3, $4)`,
+		userID, n.Type, n.Title, n.Payload)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetNotificationsPaginated(ctx context.Context, userID int, unreadOnly bool, offset int, limit int) ([]*Notification, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select("id, user_id, type, title, payload, created_at, read_at").
+		From("notifications").
+		Where("user_id = ?", userID).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	countQuery := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("notifications").
+		Where("user_id = ?", userID)
+	if unreadOnly {
+		selectQuery = selectQuery.Where("read_at IS NULL")
+		countQuery = countQuery.Where("read_at IS NULL")
+	}
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	notifications, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[Notification])
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return notifications, total, nil
+}
+
+func (r *Repository) CountUnread(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).
+		Scan(&count)
+	if err != nil {
+		return 0, apperrors.Internal(err)
+	}
+	return count, nil
+}
+
+func (r *Repository) MarkRead(ctx context.Context, userID, id int) error {
+	n, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`,
+		id, userID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("notification", "id", id)
+	}
+	return nil
+}
+
+func (r *Repository) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	n, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, apperrors.Internal(err)
+	}
+	return int(n.RowsAffected()), nil
+}
+
+// GetPreferences returns the channels of the notification types the user has set preferences for.
+func (r *Repository) GetPreferences(ctx context.Context, userID int) (map[string][]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT type, channels FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	preferences := make(map[string][]string)
+	for rows.Next() {
+		var (
+			notificationType string
+			channels         []string
+		)
+		if err = rows.Scan(&notificationType, &channels); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		preferences[notificationType] = channels
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return preferences, nil
+}
+
+func (r *Repository) SavePreferences(ctx context.Context, userID int, preferences []*Preference) error {
+	return dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		for _, preference := range preferences {
+			_, err := tx.Exec(ctx, `INSERT INTO notification_preferences (user_id, type, channels) VALUES ($1, $2, $3)
+				ON CONFLICT (user_id, type) DO UPDATE SET channels = EXCLUDED.channels`,
+				userID, preference.Type, preference.Channels)
+			if err != nil {
+				return apperrors.Internal(err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Repository) SaveWebhook(ctx context.Context, userID int, webhook *Webhook) error {
+	_, err := r.db.Exec(ctx, `INSERT INTO notification_webhooks (user_id, url, secret) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET url = EXCLUDED.url, secret = EXCLUDED.secret, created_at = NOW()`,
+		userID, webhook.URL, webhook.Secret)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, userID int) error {
+	n, err := r.db.Exec(ctx, `DELETE FROM notification_webhooks WHERE user_id = $1`, userID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("webhook", "user id", userID)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jobs"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+	"github.com/RadkevichAnn/movie-reviews/internal/signing"
+)
+
+type Service struct {
+	repo      *Repository
+	cfg       config.NotificationsConfig
+	scheduler jobs.Scheduler
+	channels  map[string]Channel
+}
+
+func NewService(repo *Repository, cfg config.NotificationsConfig, scheduler jobs.Scheduler, mailer Mailer) *Service {
+	channels := make(map[string]Channel)
+	for _, ch := range []Channel{
+		&inAppChannel{repo: repo},
+		&emailChannel{mailer: mailer},
+		&webhookChannel{client: newWebhookClient(cfg)},
+	} {
+		channels[ch.Name()] = ch
+	}
+	return &Service{
+		repo:      repo,
+		cfg:       cfg,
+		scheduler: scheduler,
+		channels:  channels,
+	}
+}
+
+// Subscribe publishes the notifications caused by the domain events relayed by the outbox.
+func (s *Service) Subscribe(subscribers *outbox.Subscribers) {
+	subscribers.Subscribe(reviews.EventCreated, func(ctx context.Context, e *outbox.Event) error {
+		var review reviews.Review
+		if err := json.Unmarshal(e.Payload, &review); err != nil {
+			return err
+		}
+		return s.Publish(ctx, &Event{
+			Type:  TypeFolloweeReviewed,
+			Title: "New review from a user you follow: " + review.Title,
+			Payload: map[string]int{
+				"review_id": review.ID,
+				"movie_id":  review.MovieID,
+				"user_id":   review.UserID,
+				"rating":    review.Rating,
+			},
+			Audience: ReviewReaders(review.UserID),
+			ActorID:  &review.UserID,
+		})
+	})
+	subscribers.Subscribe(movies.EventUpdated, func(ctx context.Context, e *outbox.Event) error {
+		var movie movies.Movie
+		if err := json.Unmarshal(e.Payload, &movie); err != nil {
+			return err
+		}
+		return s.Publish(ctx, &Event{
+			Type:     TypeWatchlistMovieUpdated,
+			Title:    fmt.Sprintf("%q on your watchlist was updated", movie.Title),
+			Payload:  map[string]int{"movie_id": movie.ID},
+			Audience: MovieWatchers(movie.ID),
+		})
+	})
+}
+
+// Publish queues the delivery of the event to its audience.
+func (s *Service) Publish(ctx context.Context, event *Event) error {
+	userIDs, err := s.repo.GetAudience(ctx, event.Audience, event.ActorID)
+	if err != nil || len(userIDs) == 0 {
+		return err
+	}
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+	return s.scheduler.Schedule(ctx, DeliverJob, &DeliverPayload{
+		Type:    event.Type,
+		Title:   event.Title,
+		Payload: payload,
+		UserIDs: userIDs,
+	}, time.Time{})
+}
+
+// RunDeliverJob delivers the notification through the channels preferred by every recipient.
+// A failed delivery isn't retried, so that the other recipients aren't notified twice.
+// It is the handler of DeliverJob.
+func (s *Service) RunDeliverJob(ctx context.Context, payload DeliverPayload) (any, error) {
+	recipients, err := s.repo.GetRecipients(ctx, payload.UserIDs, payload.Type)
+	if err != nil {
+		return nil, err
+	}
+	n := &Notification{
+		Type:      payload.Type,
+		Title:     payload.Title,
+		Payload:   payload.Payload,
+		CreatedAt: time.Now(),
+	}
+
+	result := &DeliverResult{}
+	for _, recipient := range recipients {
+		for _, name := range recipient.Channels {
+			ch, ok := s.channels[name]
+			if !ok {
+				continue
+			}
+			userN := *n
+			userN.UserID = recipient.UserID
+			if err = ch.Deliver(ctx, recipient, &userN); err != nil {
+				log.FromContext(ctx).Warn("notification delivery failed",
+					"userId", recipient.UserID,
+					"channel", name,
+					"err", err)
+				result.Failed++
+				continue
+			}
+			result.Delivered++
+		}
+	}
+	log.FromContext(ctx).Info("notification delivered",
+		"type", payload.Type,
+		"delivered", result.Delivered,
+		"failed", result.Failed)
+	return result, nil
+}
+
+func (s *Service) GetNotificationsPaginated(ctx context.Context, userID int, unreadOnly bool, offset int, limit int) ([]*Notification, int, error) {
+	return s.repo.GetNotificationsPaginated(ctx, userID, unreadOnly, offset, limit)
+}
+
+func (s *Service) CountUnread(ctx context.Context, userID int) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+func (s *Service) MarkRead(ctx context.Context, userID, id int) error {
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+func (s *Service) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// GetPreferences returns the preferences of the user for all the notification types,
+// the default channels for the types the user hasn't set.
+func (s *Service) GetPreferences(ctx context.Context, userID int) ([]*Preference, error) {
+	saved, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	preferences := make([]*Preference, 0, len(Types))
+	for _, notificationType := range Types {
+		channels, ok := saved[notificationType]
+		if !ok {
+			channels = defaultChannels
+		}
+		preferences = append(preferences, &Preference{Type: notificationType, Channels: channels})
+	}
+	return preferences, nil
+}
+
+func (s *Service) UpdatePreferences(ctx context.Context, userID int, preferences []*Preference) ([]*Preference, error) {
+	for _, preference := range preferences {
+		if !isType(preference.Type) {
+			return nil, apperrors.BadRequest(fmt.Errorf("unknown notification type %q", preference.Type))
+		}
+		seen := make(map[string]bool)
+		channels := make([]string, 0, len(preference.Channels))
+		for _, name := range preference.Channels {
+			if _, ok := s.channels[name]; !ok {
+				return nil, apperrors.BadRequest(fmt.Errorf("unknown channel %q", name))
+			}
+			if !seen[name] {
+				seen[name] = true
+				channels = append(channels, name)
+			}
+		}
+		preference.Channels = channels
+	}
+	if err := s.repo.SavePreferences(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// SetWebhook sets the webhook of the user with a new secret, which is only returned here.
+// The host of the webhook must resolve to public addresses only.
+func (s *Service) SetWebhook(ctx context.Context, userID int, rawURL string) (*Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, apperrors.BadRequest(fmt.Errorf("invalid webhook url %q", rawURL))
+	}
+	if !s.cfg.AllowPrivateWebhooks {
+		if err = checkPublicHost(ctx, u.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	secret, err := signing.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook := &Webhook{URL: u.String(), Secret: secret}
+	if err = s.repo.SaveWebhook(ctx, userID, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, userID int) error {
+	return s.repo.DeleteWebhook(ctx, userID)
+}
+
+func isType(notificationType string) bool {
+	for _, t := range Types {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event is a domain event. It's recorded in the outbox in the transaction of the change that caused it
+// and relayed to the sinks afterwards, in the order of recording for the same aggregate.
+type Event struct {
+	// ID identifies the event across redeliveries, so that sinks can drop duplicates.
+	ID            string          `json:"id"`
+	Seq           int64           `json:"seq"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// Sink receives the relayed events. An event is delivered to a sink at least once: it's delivered again
+// if the sink fails or if the relay stops before storing the outcome, so sinks must be idempotent.
+type Sink interface {
+	Name() string
+	Handle(ctx context.Context, event *Event) error
+}
+
+// claimedEvent is an event claimed by the relay with the state of its delivery.
+type claimedEvent struct {
+	Event
+	Attempts       int
+	DeliveredSinks []string
+}
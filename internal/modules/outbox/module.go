@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	Service     *Service
+	Repository  *Repository
+	Subscribers *Subscribers
+}
+
+// NewModule creates the outbox relaying the events to the in-process subscribers and the log.
+// Other sinks are added with Service.AddSink.
+func NewModule(db *pgxpool.Pool, cfg config.OutboxConfig) *Module {
+	repo := NewRepository(db)
+	service := NewService(repo, cfg)
+	subscribers := NewSubscribers()
+	service.AddSink(subscribers)
+	service.AddSink(LogSink{})
+	return &Module{
+		Service:     service,
+		Repository:  repo,
+		Subscribers: subscribers,
+	}
+}
@@ -0,0 +1,128 @@
+package outbox
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// CreateEvent appends the event to the outbox. It uses the transaction of ctx if any,
+// so that the event is only recorded if the change is committed.
+func (r *Repository) CreateEvent(ctx context.Context, event *Event) error {
+	q := dbx.FromContext(ctx, r.db)
+	err := q.QueryRow(ctx, `INSERT INTO outbox_events (id, aggregate_type, aggregate_id, type, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING seq, occurred_at`,
+		event.ID, event.AggregateType, event.AggregateID, event.Type, event.Payload).
+		Scan(&event.Seq, &event.OccurredAt)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+// ClaimEvents claims up to limit due events, counting their attempt, ordered by their position in the outbox.
+// Only the earliest unprocessed event of every aggregate can be claimed, so that the events of an aggregate
+// are relayed one after another in order. A claimed event is postponed by the lock timeout, so that it's
+// claimed again if its outcome isn't stored.
+func (r *Repository) ClaimEvents(ctx context.Context, limit int, lockTimeout time.Duration) ([]*claimedEvent, error) {
+	rows, err := r.db.Query(ctx, `UPDATE outbox_events
+		SET attempts = attempts + 1,
+			next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE seq IN (
+			SELECT e.seq FROM outbox_events e
+			WHERE e.processed_at IS NULL AND e.next_attempt_at <= NOW()
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox_events p
+					WHERE p.processed_at IS NULL
+						AND p.aggregate_type = e.aggregate_type
+						AND p.aggregate_id = e.aggregate_id
+						AND p.seq < e.seq
+				)
+			ORDER BY e.seq
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, seq, aggregate_type, aggregate_id, type, payload, occurred_at, attempts, delivered_sinks`,
+		limit, lockTimeout.Seconds())
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	var (
+		events []*claimedEvent
+		event  claimedEvent
+	)
+	_, err = pgx.ForEachRow(rows, []any{&event.ID, &event.Seq, &event.AggregateType, &event.AggregateID, &event.Type,
+		&event.Payload, &event.OccurredAt, &event.Attempts, &event.DeliveredSinks},
+		func() error {
+			e := event
+			events = append(events, &e)
+			return nil
+		})
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	sortBySeq(events)
+	return events, nil
+}
+
+// CompleteEvent marks the claimed event as processed.
+func (r *Repository) CompleteEvent(ctx context.Context, event *claimedEvent) error {
+	return r.updateClaimedEvent(ctx, event, `processed_at = NOW(), delivered_sinks = $3, last_error = NULL`,
+		event.DeliveredSinks)
+}
+
+// RetryEvent stores the sinks the claimed event was delivered to and postpones it by the delay.
+func (r *Repository) RetryEvent(ctx context.Context, event *claimedEvent, message string, delay time.Duration) error {
+	return r.updateClaimedEvent(ctx, event, `delivered_sinks = $3, last_error = $4,
+		next_attempt_at = NOW() + make_interval(secs => $5)`,
+		event.DeliveredSinks, message, delay.Seconds())
+}
+
+// DeadLetterEvent gives up on the claimed event: it's marked as processed so that the following events of
+// its aggregate are relayed, and as failed so that it's kept for inspection.
+func (r *Repository) DeadLetterEvent(ctx context.Context, event *claimedEvent, message string) error {
+	return r.updateClaimedEvent(ctx, event, `processed_at = NOW(), failed_at = NOW(), delivered_sinks = $3, last_error = $4`,
+		event.DeliveredSinks, message)
+}
+
+// updateClaimedEvent updates the event unless it was claimed again after its lock expired.
+func (r *Repository) updateClaimedEvent(ctx context.Context, event *claimedEvent, set string, args ...any) error {
+	args = append([]any{event.Seq, event.Attempts}, args...)
+	_, err := r.db.Exec(ctx, `UPDATE outbox_events SET `+set+` WHERE seq = $1 AND attempts = $2 AND processed_at IS NULL`,
+		args...)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+// DeleteProcessedEvents removes the events processed before the retention period and returns their number.
+// Dead-lettered events are kept.
+func (r *Repository) DeleteProcessedEvents(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := r.db.Exec(ctx, `DELETE FROM outbox_events
+		WHERE processed_at < NOW() - make_interval(secs => $1) AND failed_at IS NULL`,
+		retention.Seconds())
+	if err != nil {
+		return 0, apperrors.Internal(err)
+	}
+	return int(n.RowsAffected()), nil
+}
+
+func sortBySeq(events []*claimedEvent) {
+	sort.Slice(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+}
@@ -0,0 +1,164 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo  *Repository
+	cfg   config.OutboxConfig
+	sinks []Sink
+}
+
+func NewService(repo *Repository, cfg config.OutboxConfig) *Service {
+	return &Service{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// AddSink adds the sink the events are relayed to. Sinks must be added before the relay is started.
+func (s *Service) AddSink(sink Sink) {
+	s.sinks = append(s.sinks, sink)
+}
+
+// Record records the event of the aggregate in the outbox. It must be called in the transaction
+// of the change that caused the event, so that the event is recorded if and only if the change is committed.
+func (s *Service) Record(ctx context.Context, aggregateType string, aggregateID int, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	event := &Event{
+		ID:            uuid.NewString(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Type:          eventType,
+		Payload:       data,
+	}
+	return s.repo.CreateEvent(ctx, event)
+}
+
+// RunRelay relays the recorded events to the sinks every poll interval until ctx is done.
+func (s *Service) RunRelay(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.relay(ctx); err != nil && ctx.Err() == nil {
+				log.FromContext(ctx).Error("relay outbox events", "err", err)
+			}
+			if time.Since(lastCleanup) >= s.cfg.CleanupInterval {
+				lastCleanup = time.Now()
+				s.cleanup(ctx)
+			}
+		}
+	}
+}
+
+// relay delivers the due events batch by batch until there are none left.
+func (s *Service) relay(ctx context.Context) error {
+	for {
+		events, err := s.repo.ClaimEvents(ctx, s.cfg.BatchSize, s.cfg.LockTimeout)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err = s.deliver(ctx, event); err != nil {
+				return err
+			}
+		}
+		if len(events) < s.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// deliver hands the claimed event to the sinks it wasn't delivered to yet. If any of them fails,
+// the event is retried later with a backoff and the following events of its aggregate wait for it,
+// unless it has run out of attempts, in which case it's dead-lettered.
+func (s *Service) deliver(ctx context.Context, event *claimedEvent) error {
+	logger := log.FromContext(ctx).With(
+		"eventId", event.ID,
+		"type", event.Type,
+		"aggregate", fmt.Sprintf("%s:%d", event.AggregateType, event.AggregateID),
+		"attempt", event.Attempts)
+
+	delivered := make(map[string]bool, len(event.DeliveredSinks))
+	for _, name := range event.DeliveredSinks {
+		delivered[name] = true
+	}
+	var errs []error
+	for _, sink := range s.sinks {
+		if delivered[sink.Name()] {
+			continue
+		}
+		if err := s.handle(ctx, sink, &event.Event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+			continue
+		}
+		event.DeliveredSinks = append(event.DeliveredSinks, sink.Name())
+	}
+
+	if len(errs) == 0 {
+		return s.repo.CompleteEvent(ctx, event)
+	}
+	err := errors.Join(errs...)
+	if event.Attempts >= s.cfg.MaxAttempts {
+		logger.Error("outbox event delivery failed, giving up", "err", err)
+		return s.repo.DeadLetterEvent(ctx, event, err.Error())
+	}
+	delay := s.backoff(event.Attempts)
+	logger.Warn("outbox event delivery failed, retrying", "err", err, "delay", delay)
+	return s.repo.RetryEvent(ctx, event, err.Error(), delay)
+}
+
+// handle delivers the event to the sink turning its panic into an error.
+func (s *Service) handle(ctx context.Context, sink Sink, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sink panicked: %v", r)
+		}
+	}()
+	return sink.Handle(ctx, event)
+}
+
+// backoff returns the delay before the next attempt, which doubles with every attempt up to the maximum backoff.
+func (s *Service) backoff(attempt int) time.Duration {
+	delay := s.cfg.RetryBackoff
+	for i := 1; i < attempt && delay < s.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.cfg.MaxBackoff {
+		delay = s.cfg.MaxBackoff
+	}
+	return delay
+}
+
+func (s *Service) cleanup(ctx context.Context) {
+	n, err := s.repo.DeleteProcessedEvents(ctx, s.cfg.Retention)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.FromContext(ctx).Error("delete processed outbox events", "err", err)
+		}
+		return
+	}
+	if n > 0 {
+		log.FromContext(ctx).Info("processed outbox events deleted", "count", n)
+	}
+}
@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+// AllEvents subscribes a handler to the events of every type.
+const AllEvents = "*"
+
+// HandlerFunc handles an event delivered in-process.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Subscribers delivers the events to the in-process handlers subscribed to their types.
+// When any handler fails, the event is delivered again to all of them.
+type Subscribers struct {
+	handlers map[string][]HandlerFunc
+}
+
+func NewSubscribers() *Subscribers {
+	return &Subscribers{handlers: make(map[string][]HandlerFunc)}
+}
+
+// Subscribe adds the handler of the events of the type, or of all events for AllEvents.
+// Handlers must be subscribed before the relay is started.
+func (s *Subscribers) Subscribe(eventType string, handler HandlerFunc) {
+	s.handlers[eventType] = append(s.handlers[eventType], handler)
+}
+
+func (s *Subscribers) Name() string {
+	return "subscribers"
+}
+
+func (s *Subscribers) Handle(ctx context.Context, event *Event) error {
+	var errs []error
+	for _, eventType := range []string{event.Type, AllEvents} {
+		for _, handler := range s.handlers[eventType] {
+			if err := handler(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogSink logs every event.
+type LogSink struct{}
+
+func (LogSink) Name() string {
+	return "log"
+}
+
+func (LogSink) Handle(ctx context.Context, event *Event) error {
+	log.FromContext(ctx).Info("domain event",
+		"eventId", event.ID,
+		"type", event.Type,
+		"aggregateType", event.AggregateType,
+		"aggregateId", event.AggregateID)
+	return nil
+}
@@ -0,0 +1,35 @@
+package recommendations
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/labstack/echo/v4"
+)
+
+const defaultLimit = 10
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) GetRecommendations(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetRecommendationsRequest](c)
+	if err != nil {
+		return err
+	}
+	limit := defaultLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	recommendations, err := h.service.GetRecommendations(c.Request().Context(), req.UserID, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, recommendations)
+}
@@ -0,0 +1,33 @@
+package recommendations
+
+import "time"
+
+const (
+	// CollaborativeSource marks movies rated highly by users with a similar taste.
+	CollaborativeSource = "collaborative"
+	// ContentSource marks movies sharing genres and cast with the movies the user liked.
+	ContentSource = "content"
+	// PopularSource marks the best rated movies, used when nothing is known about the user.
+	PopularSource = "popular"
+)
+
+type Movie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+// Recommendation is a movie suggested to a user. Scores are comparable only within the same source.
+type Recommendation struct {
+	Movie  Movie   `json:"movie"`
+	Score  float64 `json:"score"`
+	Source string  `json:"source"`
+}
+
+// RefreshJob is the type of the periodic job recomputing the movie similarities.
+const RefreshJob = "recommendations.refresh"
+
+type RefreshResult struct {
+	Pairs int `json:"pairs"`
+}
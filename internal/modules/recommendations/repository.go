@@ -0,0 +1,154 @@
+package recommendations
+
+import (
+	"context"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// RefreshSimilarities recomputes the item-item similarity table from review ratings.
+// Similarity is the adjusted cosine over users who rated both movies, where ratings are
+// centered on each user's mean rating. Only pairs rated by at least minSupport users are kept,
+// and at most neighbors most similar movies are stored for each movie.
+func (r *Repository) RefreshSimilarities(ctx context.Context, minSupport, neighbors int) (int, error) {
+	var stored int64
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM movie_similarities`); err != nil {
+			return apperrors.Internal(err)
+		}
+		n, err := tx.Exec(ctx, `WITH centered AS (
+				SELECT r.user_id, r.movie_id, r.rating - AVG(r.rating) OVER (PARTITION BY r.user_id) AS dev
+				FROM reviews r
+				INNER JOIN movies m ON m.id = r.movie_id
+				WHERE r.deleted_at IS NULL AND m.deleted_at IS NULL
+			),
+			pairs AS (
+				SELECT a.movie_id,
+				       b.movie_id AS similar_movie_id,
+				       SUM(a.dev * b.dev) / NULLIF(SQRT(SUM(a.dev * a.dev)) * SQRT(SUM(b.dev * b.dev)), 0) AS score,
+				       COUNT(*) AS support
+				FROM centered a
+				INNER JOIN centered b ON b.user_id = a.user_id AND b.movie_id <> a.movie_id
+				GROUP BY a.movie_id, b.movie_id
+				HAVING COUNT(*) >= $1
+			),
+			ranked AS (
+				SELECT movie_id, similar_movie_id, score, support,
+				       ROW_NUMBER() OVER (PARTITION BY movie_id ORDER BY score DESC, similar_movie_id) AS rank
+				FROM pairs
+				WHERE score > 0
+			)
+			INSERT INTO movie_similarities (movie_id, similar_movie_id, score, support)
+			SELECT movie_id, similar_movie_id, score, support
+			FROM ranked
+			WHERE rank <= $2`,
+			minSupport, neighbors)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		stored = n.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, apperrors.EnsureInternal(err)
+	}
+	return int(stored), nil
+}
+
+// GetCollaborative predicts the user's rating of unreviewed movies as the similarity-weighted
+// average of the ratings the user gave to their neighbors.
+func (r *Repository) GetCollaborative(ctx context.Context, userID int, exclude []int, limit int) ([]*Recommendation, error) {
+	return r.query(ctx, CollaborativeSource, `SELECT m.id, m.title, m.release_date, m.avg_rating,
+			SUM(s.score * r.rating) / SUM(s.score) AS score
+		FROM reviews r
+		INNER JOIN movie_similarities s ON s.movie_id = r.movie_id
+		INNER JOIN movies m ON m.id = s.similar_movie_id
+		WHERE r.user_id = $1 AND r.deleted_at IS NULL AND m.deleted_at IS NULL
+		  AND m.id <> ALL($2)
+		  AND NOT EXISTS (SELECT 1 FROM reviews ur WHERE ur.user_id = $1 AND ur.movie_id = m.id AND ur.deleted_at IS NULL)
+		GROUP BY m.id
+		ORDER BY score DESC, m.id
+		LIMIT $3`,
+		userID, exclude, limit)
+}
+
+// GetContentBased scores unreviewed movies by the genres and stars they share with the movies
+// the user rated at least minRating, weighting every match by the user's rating.
+func (r *Repository) GetContentBased(ctx context.Context, userID int, minRating int, genreWeight, starWeight float64, exclude []int, limit int) ([]*Recommendation, error) {
+	return r.query(ctx, ContentSource, `WITH liked AS (
+			SELECT movie_id, rating
+			FROM reviews
+			WHERE user_id = $1 AND deleted_at IS NULL AND rating >= $2
+		),
+		matches AS (
+			SELECT mg.movie_id, l.rating * $3::DOUBLE PRECISION AS score
+			FROM liked l
+			INNER JOIN movie_genres lg ON lg.movie_id = l.movie_id
+			INNER JOIN movie_genres mg ON mg.genre_id = lg.genre_id
+			UNION ALL
+			SELECT ms.movie_id, l.rating * $4::DOUBLE PRECISION
+			FROM liked l
+			INNER JOIN (SELECT DISTINCT movie_id, star_id FROM movie_stars) ls ON ls.movie_id = l.movie_id
+			INNER JOIN (SELECT DISTINCT movie_id, star_id FROM movie_stars) ms ON ms.star_id = ls.star_id
+		)
+		SELECT m.id, m.title, m.release_date, m.avg_rating, SUM(x.score) AS score
+		FROM matches x
+		INNER JOIN movies m ON m.id = x.movie_id
+		WHERE m.deleted_at IS NULL
+		  AND m.id <> ALL($5)
+		  AND NOT EXISTS (SELECT 1 FROM reviews ur WHERE ur.user_id = $1 AND ur.movie_id = m.id AND ur.deleted_at IS NULL)
+		GROUP BY m.id
+		ORDER BY score DESC, m.id
+		LIMIT $6`,
+		userID, minRating, genreWeight, starWeight, exclude, limit)
+}
+
+// GetPopular returns the best rated movies the user hasn't reviewed yet.
+func (r *Repository) GetPopular(ctx context.Context, userID int, exclude []int, limit int) ([]*Recommendation, error) {
+	return r.query(ctx, PopularSource, `SELECT m.id, m.title, m.release_date, m.avg_rating,
+			COALESCE(m.avg_rating, 0)::DOUBLE PRECISION AS score
+		FROM movies m
+		WHERE m.deleted_at IS NULL
+		  AND m.id <> ALL($2)
+		  AND NOT EXISTS (SELECT 1 FROM reviews ur WHERE ur.user_id = $1 AND ur.movie_id = m.id AND ur.deleted_at IS NULL)
+		ORDER BY score DESC, m.rating_count DESC, m.id
+		LIMIT $3`,
+		userID, exclude, limit)
+}
+
+func (r *Repository) query(ctx context.Context, source string, sql string, args ...any) ([]*Recommendation, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var recommendations []*Recommendation
+	for rows.Next() {
+		recommendation := Recommendation{Source: source}
+		if err = rows.Scan(
+			&recommendation.Movie.ID,
+			&recommendation.Movie.Title,
+			&recommendation.Movie.ReleaseDate,
+			&recommendation.Movie.AvgRating,
+			&recommendation.Score); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		recommendations = append(recommendations, &recommendation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return recommendations, nil
+}
@@ -0,0 +1,73 @@
+package recommendations
+
+import (
+	"context"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+	cfg  config.RecommendationsConfig
+}
+
+func NewService(repo *Repository, cfg config.RecommendationsConfig) *Service {
+	return &Service{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// GetRecommendations returns up to limit movies for the user. Collaborative filtering results
+// come first; when there are not enough of them, e.g. for users with few reviews, the rest is
+// filled with content-based and then popular movies. Movies reviewed by the user are never returned.
+func (s *Service) GetRecommendations(ctx context.Context, userID int, limit int) ([]*Recommendation, error) {
+	recommendations, err := s.repo.GetCollaborative(ctx, userID, []int{}, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recommendations) < limit {
+		var content []*Recommendation
+		content, err = s.repo.GetContentBased(ctx, userID, s.cfg.MinLikedRating, s.cfg.GenreWeight, s.cfg.StarWeight,
+			movieIDs(recommendations), limit-len(recommendations))
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, content...)
+	}
+
+	if len(recommendations) < limit {
+		var popular []*Recommendation
+		popular, err = s.repo.GetPopular(ctx, userID, movieIDs(recommendations), limit-len(recommendations))
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, popular...)
+	}
+
+	return recommendations, nil
+}
+
+// RunRefreshJob recomputes the movie similarities as a periodic background job.
+func (s *Service) RunRefreshJob(ctx context.Context, _ struct{}) (any, error) {
+	start := time.Now()
+	stored, err := s.repo.RefreshSimilarities(ctx, s.cfg.MinSupport, s.cfg.Neighbors)
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("movie similarities refreshed",
+		"pairs", stored,
+		"duration", time.Since(start))
+	return &RefreshResult{Pairs: stored}, nil
+}
+
+func movieIDs(recommendations []*Recommendation) []int {
+	ids := make([]int, 0, len(recommendations))
+	for _, r := range recommendations {
+		ids = append(ids, r.Movie.ID)
+	}
+	return ids
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/RadkevichAnn/movie-reviews/internal/config"
 	"github.com/RadkevichAnn/movie-reviews/internal/echox"
 	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
 	"github.com/labstack/echo/v4"
 )
 
@@ -47,12 +48,14 @@ func (h *Handler) CreateReview(c echo.Context) error {
 }
 
 func (h *Handler) GetReviewByID(c echo.Context) error {
-	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+	viewer := privacy.GetViewer(c)
+	// The visible reviews depend on the viewer, so only the requests of the same viewer are shared
+	res, err, _ := h.reqGroup.Do(viewer.Key()+" "+c.Request().RequestURI, func() (any, error) {
 		req, err := echox.BindAndValidate[contracts.GetReviewRequest](c)
 		if err != nil {
 			return nil, err
 		}
-		review, err := h.service.GetReviewByID(c.Request().Context(), req.ReviewID)
+		review, err := h.service.GetReviewByID(c.Request().Context(), req.ReviewID, viewer)
 		if err != nil {
 			return nil, err
 		}
@@ -65,7 +68,8 @@ func (h *Handler) GetReviewByID(c echo.Context) error {
 }
 
 func (h *Handler) GetAllReviewsPaginated(c echo.Context) error {
-	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+	viewer := privacy.GetViewer(c)
+	res, err, _ := h.reqGroup.Do(viewer.Key()+" "+c.Request().RequestURI, func() (any, error) {
 		req, err := echox.BindAndValidate[contracts.GetReviewsRequest](c)
 		if err != nil {
 			return nil, err
@@ -75,7 +79,7 @@ func (h *Handler) GetAllReviewsPaginated(c echo.Context) error {
 		}
 		pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
 		offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
-		reviews, total, err := h.service.GetAllReviewsPaginated(c.Request().Context(), req.MovieID, req.UserID, offset, limit)
+		reviews, total, err := h.service.GetAllReviewsPaginated(c.Request().Context(), req.MovieID, req.UserID, viewer, offset, limit)
 		if err != nil {
 			return nil, err
 		}
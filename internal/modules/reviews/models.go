@@ -2,6 +2,16 @@ package reviews
 
 import "time"
 
+const MaxRating = 10
+
+// Domain events of reviews recorded in the outbox. Their payload is the review, or its id when it's deleted.
+const (
+	AggregateType = "review"
+	EventCreated  = "review.created"
+	EventUpdated  = "review.updated"
+	EventDeleted  = "review.deleted"
+)
+
 type Review struct {
 	ID        int        `json:"id"`
 	MovieID   int        `json:"movie_id"`
@@ -12,3 +22,57 @@ type Review struct {
 	CreatedAt time.Time  `json:"created_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
+
+// RatingAggregate is the incrementally maintained rating state of a movie.
+// Histogram[i] holds the number of reviews with rating i+1.
+type RatingAggregate struct {
+	Sum       int   `json:"sum"`
+	Count     int   `json:"count"`
+	Histogram []int `json:"histogram"`
+}
+
+func NewRatingAggregate(histogram []int) RatingAggregate {
+	a := RatingAggregate{Histogram: histogram}
+	for i, n := range histogram {
+		a.Sum += (i + 1) * n
+		a.Count += n
+	}
+	return a
+}
+
+func (a RatingAggregate) Avg() *float64 {
+	if a.Count == 0 {
+		return nil
+	}
+	avg := float64(a.Sum) / float64(a.Count)
+	return &avg
+}
+
+func (a RatingAggregate) Equal(other RatingAggregate) bool {
+	if a.Sum != other.Sum || a.Count != other.Count || len(a.Histogram) != len(other.Histogram) {
+		return false
+	}
+	for i := range a.Histogram {
+		if a.Histogram[i] != other.Histogram[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type RatingDrift struct {
+	MovieID int             `json:"movie_id"`
+	Stored  RatingAggregate `json:"stored"`
+	Actual  RatingAggregate `json:"actual"`
+}
+
+// ReconcileRatingsJob is the type of the job reconciling rating aggregates of all movies.
+const ReconcileRatingsJob = "ratings.reconcile"
+
+type ReconcileRatingsPayload struct {
+	DryRun bool `json:"dry_run"`
+}
+
+type ReconcileRatingsResult struct {
+	Drifts []*RatingDrift `json:"drifts"`
+}
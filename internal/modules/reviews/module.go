@@ -2,7 +2,7 @@ package reviews
 
 import (
 	"github.com/RadkevichAnn/movie-reviews/internal/config"
-	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,9 +12,9 @@ type Module struct {
 	Repository *Repository
 }
 
-func NewModule(db *pgxpool.Pool, moviesModule *movies.Module, paginationConfig config.PaginationConfig) *Module {
-	repo := NewRepository(db, moviesModule.Repository)
-	service := NewService(repo)
+func NewModule(db *pgxpool.Pool, paginationConfig config.PaginationConfig, outboxModule *outbox.Module) *Module {
+	repo := NewRepository(db)
+	service := NewService(repo, outboxModule.Service)
 	handler := NewHandler(service, paginationConfig)
 
 	return &Module{
@@ -6,44 +6,37 @@ import (
 
 	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
 	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
-	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Repository struct {
-	db         *pgxpool.Pool
-	moviesRepo *movies.Repository
+	dbx.Transactor
+	db *pgxpool.Pool
 }
 
-func NewRepository(db *pgxpool.Pool, moviesRepo *movies.Repository) *Repository {
+func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{
+		Transactor: dbx.NewTransactor(db),
 		db:         db,
-		moviesRepo: moviesRepo,
 	}
 }
 
 func (r *Repository) CreateReview(ctx context.Context, review *Review) error {
-	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
-		if err := r.moviesRepo.Lock(ctx, tx, review.MovieID); err != nil {
-			return err
-		}
-
-		// Insert Review
-		err := tx.QueryRow(ctx, `INSERT INTO reviews (movie_id, user_id, title, content, rating) VALUES ($1, $2, $3, $4, $5) returning id, created_at`,
-			review.MovieID, review.UserID, review.Title, review.Content, review.Rating).
-			Scan(&review.ID, &review.CreatedAt)
-		switch {
-		case dbx.IsUniqueViolation(err, ""):
-			return apperrors.AlreadyExists("review", "(movie_id,user_id)", fmt.Sprintf("(%d,%d)", review.MovieID, review.UserID))
-		case err != nil:
-			return apperrors.Internal(err)
-		}
-		return r.recalculateMovieRating(ctx, review.MovieID)
-	})
-	if err != nil {
-		return apperrors.EnsureInternal(err)
+	q := dbx.FromContext(ctx, r.db)
+	err := q.QueryRow(ctx, `INSERT INTO reviews (movie_id, user_id, title, content, rating) VALUES ($1, $2, $3, $4, $5) returning id, created_at`,
+		review.MovieID, review.UserID, review.Title, review.Content, review.Rating).
+		Scan(&review.ID, &review.CreatedAt)
+	switch {
+	case dbx.IsUniqueViolation(err, ""):
+		return apperrors.AlreadyExists("review", "(movie_id,user_id)", fmt.Sprintf("(%d,%d)", review.MovieID, review.UserID))
+	case dbx.IsForeignKeyViolation(err, "movie_id"):
+		return apperrors.NotFound("movie", "id", review.MovieID)
+	case dbx.IsForeignKeyViolation(err, "user_id"):
+		return apperrors.NotFound("user", "id", review.UserID)
+	case err != nil:
+		return apperrors.Internal(err)
 	}
 	return nil
 }
@@ -51,7 +44,8 @@ func (r *Repository) CreateReview(ctx context.Context, review *Review) error {
 func (r *Repository) GetReviewByID(ctx context.Context, reviewID int) (*Review, error) {
 	var review Review
 
-	err := r.db.QueryRow(ctx, `SELECT id, movie_id, user_id, title, content, rating, created_at FROM reviews where deleted_at is null and id = $1`,
+	q := dbx.FromContext(ctx, r.db)
+	err := q.QueryRow(ctx, `SELECT id, movie_id, user_id, title, content, rating, created_at FROM reviews where deleted_at is null and id = $1`,
 		reviewID).
 		Scan(&review.ID, &review.MovieID, &review.UserID, &review.Title, &review.Content, &review.Rating, &review.CreatedAt)
 
@@ -65,24 +59,56 @@ func (r *Repository) GetReviewByID(ctx context.Context, reviewID int) (*Review,
 	return &review, nil
 }
 
-func (r *Repository) GetAllReviewsPaginated(ctx context.Context, movieID *int, userID *int, offset int, limit int) ([]*Review, int, error) {
+// GetVisibleReviewByID returns the review if its author lets the viewer see it, and a not found error otherwise.
+func (r *Repository) GetVisibleReviewByID(ctx context.Context, reviewID int, viewer *privacy.Viewer) (*Review, error) {
+	query := dbx.StatementBuilder.
+		Select("r.id", "r.movie_id", "r.user_id", "r.title", "r.content", "r.rating", "r.created_at").
+		From("reviews r").
+		Join("users u ON u.id = r.user_id").
+		Where("r.deleted_at IS NULL").
+		Where("r.id = ?", reviewID).
+		Where(privacy.Visible(viewer, "r.user_id", "u.reviews_visibility"))
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	var review Review
+	err = r.db.QueryRow(ctx, sql, args...).
+		Scan(&review.ID, &review.MovieID, &review.UserID, &review.Title, &review.Content, &review.Rating, &review.CreatedAt)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("review", "id", reviewID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return &review, nil
+}
+
+// GetAllReviewsPaginated returns the reviews of the movie or the user that their authors let the viewer see.
+func (r *Repository) GetAllReviewsPaginated(ctx context.Context, movieID *int, userID *int, viewer *privacy.Viewer, offset int, limit int) ([]*Review, int, error) {
+	visible := privacy.Visible(viewer, "r.user_id", "u.reviews_visibility")
 	selectQuery := dbx.StatementBuilder.
-		Select("id", "movie_id", "user_id", "title", "content", "rating", "created_at").
-		From("reviews").
-		Where("deleted_at is NULL").
+		Select("r.id", "r.movie_id", "r.user_id", "r.title", "r.content", "r.rating", "r.created_at").
+		From("reviews r").
+		Join("users u ON u.id = r.user_id").
+		Where("r.deleted_at is NULL").
+		Where(visible).
 		Limit(uint64(limit)).
 		Offset(uint64(offset))
 	queryTotal := dbx.StatementBuilder.
 		Select("COUNT(*)").
-		From("reviews").
-		Where("deleted_at is NULL")
+		From("reviews r").
+		Join("users u ON u.id = r.user_id").
+		Where("r.deleted_at is NULL").
+		Where(visible)
 	if movieID != nil {
-		selectQuery = selectQuery.Where("movie_id = ?", *movieID)
-		queryTotal = queryTotal.Where("movie_id = ?", *movieID)
+		selectQuery = selectQuery.Where("r.movie_id = ?", *movieID)
+		queryTotal = queryTotal.Where("r.movie_id = ?", *movieID)
 	}
 	if userID != nil {
-		selectQuery = selectQuery.Where("user_id = ?", *userID)
-		queryTotal = queryTotal.Where("user_id = ?", *userID)
+		selectQuery = selectQuery.Where("r.user_id = ?", *userID)
+		queryTotal = queryTotal.Where("r.user_id = ?", *userID)
 	}
 	b := &pgx.Batch{}
 	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
@@ -126,59 +152,82 @@ func (r *Repository) GetAllReviewsPaginated(ctx context.Context, movieID *int, u
 	return reviews, total, err
 }
 
-func (r *Repository) UpdateReview(ctx context.Context, reviewID, userID int, title, content string, rating int) error {
-	review, err := r.GetReviewByID(ctx, reviewID)
-	if err != nil {
-		return err
-	}
-	err = dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
-		if err = r.moviesRepo.Lock(ctx, tx, review.MovieID); err != nil {
-			return err
-		}
-		var n pgconn.CommandTag
-		n, err = r.db.Exec(ctx, "UPDATE reviews SET title = $1, content = $2, rating = $3 WHERE deleted_at IS NULL AND id = $4 AND user_id = $5",
-			title, content, rating, reviewID, userID)
-		if err != nil {
-			return apperrors.Internal(err)
-		}
-		if n.RowsAffected() == 0 {
-			return r.specifyModificationError(ctx, reviewID, userID)
-		}
-
-		return r.recalculateMovieRating(ctx, review.MovieID)
-	})
+// GetLatestReviewsByMovieIDs returns the latest reviews of each of the movies that their authors let the viewer see,
+// at most limit per movie.
+func (r *Repository) GetLatestReviewsByMovieIDs(ctx context.Context, movieIDs []int, viewer *privacy.Viewer, limit int) (map[int][]*Review, error) {
+	numbered := dbx.StatementBuilder.
+		Select("r.*", "ROW_NUMBER() OVER (PARTITION BY r.movie_id ORDER BY r.created_at DESC, r.id DESC) AS row_no").
+		From("reviews r").
+		Join("users u ON u.id = r.user_id").
+		Where("r.deleted_at IS NULL").
+		Where("r.movie_id = ANY(?)", movieIDs).
+		Where(privacy.Visible(viewer, "r.user_id", "u.reviews_visibility"))
+	query := dbx.StatementBuilder.
+		Select("id", "movie_id", "user_id", "title", "content", "rating", "created_at").
+		FromSelect(numbered, "r").
+		Where("row_no <= ?", limit).
+		OrderBy("movie_id", "row_no")
+	sql, args, err := query.ToSql()
 	if err != nil {
-		return apperrors.Internal(err)
+		return nil, apperrors.Internal(err)
 	}
-	return nil
-}
-
-func (r *Repository) DeleteReview(ctx context.Context, reviewID, userID int) error {
-	review, err := r.GetReviewByID(ctx, reviewID)
+	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
-		return err
+		return nil, apperrors.Internal(err)
 	}
-	err = dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
-		if err = r.moviesRepo.Lock(ctx, tx, review.MovieID); err != nil {
-			return err
-		}
-		var n pgconn.CommandTag
-		n, err = r.db.Exec(ctx,
-			`UPDATE reviews SET deleted_at = now() WHERE deleted_at IS NULL AND id = $1 AND user_id = $2`,
-			reviewID, userID)
+	defer rows.Close()
 
-		if n.RowsAffected() == 0 {
-			return r.specifyModificationError(ctx, reviewID, userID)
+	reviews := make(map[int][]*Review, len(movieIDs))
+	for rows.Next() {
+		var review Review
+		if err = rows.Scan(&review.ID, &review.MovieID, &review.UserID, &review.Title, &review.Content, &review.Rating, &review.CreatedAt); err != nil {
+			return nil, apperrors.Internal(err)
 		}
+		reviews[review.MovieID] = append(reviews[review.MovieID], &review)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return reviews, nil
+}
 
-		return r.recalculateMovieRating(ctx, review.MovieID)
-	})
+// UpdateReview updates the review of the user and returns the id of its movie and its previous rating.
+// It must be called in a transaction, since the review is locked until the rating aggregates are updated.
+func (r *Repository) UpdateReview(ctx context.Context, reviewID, userID int, title, content string, rating int) (movieID, prevRating int, err error) {
+	q := dbx.FromContext(ctx, r.db)
+	// Lock only the review row: the previous rating is needed to compute the aggregates delta
+	err = q.QueryRow(ctx, `SELECT movie_id, rating FROM reviews WHERE deleted_at IS NULL AND id = $1 AND user_id = $2 FOR UPDATE`,
+		reviewID, userID).
+		Scan(&movieID, &prevRating)
+	switch {
+	case dbx.IsNoRows(err):
+		return 0, 0, r.specifyModificationError(ctx, reviewID, userID)
+	case err != nil:
+		return 0, 0, apperrors.Internal(err)
+	}
 
+	_, err = q.Exec(ctx, "UPDATE reviews SET title = $1, content = $2, rating = $3 WHERE id = $4",
+		title, content, rating, reviewID)
 	if err != nil {
-		return apperrors.EnsureInternal(err)
+		return 0, 0, apperrors.Internal(err)
 	}
+	return movieID, prevRating, nil
+}
 
-	return nil
+// DeleteReview deletes the review of the user and returns the id of its movie and its rating.
+func (r *Repository) DeleteReview(ctx context.Context, reviewID, userID int) (movieID, rating int, err error) {
+	q := dbx.FromContext(ctx, r.db)
+	err = q.QueryRow(ctx,
+		`UPDATE reviews SET deleted_at = now() WHERE deleted_at IS NULL AND id = $1 AND user_id = $2 RETURNING movie_id, rating`,
+		reviewID, userID).
+		Scan(&movieID, &rating)
+	switch {
+	case dbx.IsNoRows(err):
+		return 0, 0, r.specifyModificationError(ctx, reviewID, userID)
+	case err != nil:
+		return 0, 0, apperrors.Internal(err)
+	}
+	return movieID, rating, nil
 }
 
 func (r *Repository) specifyModificationError(ctx context.Context, reviewID, userID int) error {
@@ -198,9 +247,30 @@ func (r *Repository) specifyModificationError(ctx context.Context, reviewID, use
 	return apperrors.Internal(fmt.Errorf("unexpected error creating/updating review with id %d", reviewID))
 }
 
-func (r *Repository) recalculateMovieRating(ctx context.Context, movieID int) error {
+// ApplyRatingDelta folds a single review change into the movie rating aggregates.
+// A zero added or removed rating means that there is nothing to add or to remove respectively.
+// It locks the movie row until the end of the transaction, so it must be the last statement of it:
+// concurrent writes to the reviews of the movie then wait only for the commit of each other.
+func (r *Repository) ApplyRatingDelta(ctx context.Context, movieID, added, removed int) error {
+	var countDelta int
+	if added > 0 {
+		countDelta++
+	}
+	if removed > 0 {
+		countDelta--
+	}
+
 	q := dbx.FromContext(ctx, r.db)
-	n, err := q.Exec(ctx, `UPDATE movies SET avg_rating = (SELECT AVG(rating) FROM reviews WHERE deleted_at IS NULL and movie_id = $1) where id = $1`, movieID)
+	n, err := q.Exec(ctx, `UPDATE movies SET
+			rating_sum = rating_sum + $2 - $3,
+			rating_count = rating_count + $4,
+			rating_histogram = ARRAY(
+				SELECT h + (i = $2)::INTEGER - (i = $3)::INTEGER
+				FROM unnest(rating_histogram) WITH ORDINALITY AS t(h, i)
+				ORDER BY i),
+			avg_rating = (rating_sum + $2 - $3)::FLOAT4 / NULLIF(rating_count + $4, 0)
+		WHERE deleted_at IS NULL AND id = $1`,
+		movieID, added, removed, countDelta)
 	if err != nil {
 		return apperrors.Internal(err)
 	}
@@ -210,3 +280,108 @@ func (r *Repository) recalculateMovieRating(ctx context.Context, movieID int) er
 	}
 	return nil
 }
+
+// GetRatingDrifts compares stored rating aggregates of every movie with the ones computed from reviews
+// and returns the movies whose aggregates differ.
+func (r *Repository) GetRatingDrifts(ctx context.Context) ([]*RatingDrift, error) {
+	rows, err := r.db.Query(ctx, `SELECT m.id, m.rating_sum, m.rating_count, m.rating_histogram, COALESCE(a.histogram, $1)
+		FROM movies m
+		LEFT JOIN (
+			SELECT c.movie_id, ARRAY_AGG(c.cnt ORDER BY c.rating)::INTEGER[] AS histogram
+			FROM (
+				SELECT mr.movie_id, s.rating, COUNT(r.id) AS cnt
+				FROM (SELECT DISTINCT movie_id FROM reviews WHERE deleted_at IS NULL) mr
+				CROSS JOIN generate_series(1, $2::INTEGER) AS s(rating)
+				LEFT JOIN reviews r ON r.movie_id = mr.movie_id AND r.rating = s.rating AND r.deleted_at IS NULL
+				GROUP BY mr.movie_id, s.rating
+			) c
+			GROUP BY c.movie_id
+		) a ON a.movie_id = m.id
+		WHERE m.deleted_at IS NULL
+		ORDER BY m.id`,
+		make([]int, MaxRating), MaxRating)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var drifts []*RatingDrift
+	for rows.Next() {
+		var (
+			drift           RatingDrift
+			actualHistogram []int
+		)
+		if err = rows.Scan(
+			&drift.MovieID,
+			&drift.Stored.Sum,
+			&drift.Stored.Count,
+			&drift.Stored.Histogram,
+			&actualHistogram); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+
+		drift.Actual = NewRatingAggregate(actualHistogram)
+		if !drift.Stored.Equal(drift.Actual) {
+			drifts = append(drifts, &drift)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return drifts, nil
+}
+
+// ReconcileMovieRating recomputes rating aggregates of a single movie under the movie row lock
+// and overwrites the stored ones. It returns nil if no drift was found.
+func (r *Repository) ReconcileMovieRating(ctx context.Context, movieID int) (*RatingDrift, error) {
+	var drift *RatingDrift
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		stored := RatingAggregate{}
+		err := tx.QueryRow(ctx, `SELECT rating_sum, rating_count, rating_histogram FROM movies WHERE deleted_at IS NULL AND id = $1 FOR UPDATE`,
+			movieID).
+			Scan(&stored.Sum, &stored.Count, &stored.Histogram)
+		switch {
+		case dbx.IsNoRows(err):
+			return apperrors.NotFound("movie", "id", movieID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+
+		var histogram []int
+		err = tx.QueryRow(ctx, `SELECT ARRAY_AGG(h.cnt ORDER BY h.rating)::INTEGER[]
+			FROM (
+				SELECT s.rating, COUNT(r.id) AS cnt
+				FROM generate_series(1, $2::INTEGER) AS s(rating)
+				LEFT JOIN reviews r ON r.movie_id = $1 AND r.rating = s.rating AND r.deleted_at IS NULL
+				GROUP BY s.rating
+			) h`,
+			movieID, MaxRating).
+			Scan(&histogram)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+
+		actual := NewRatingAggregate(histogram)
+		if stored.Equal(actual) {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE movies SET
+				rating_sum = $2,
+				rating_count = $3,
+				rating_histogram = $4,
+				avg_rating = $5
+			WHERE id = $1`,
+			movieID, actual.Sum, actual.Count, actual.Histogram, actual.Avg())
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+
+		drift = &RatingDrift{MovieID: movieID, Stored: stored, Actual: actual}
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.EnsureInternal(err)
+	}
+	return drift, nil
+}
@@ -3,43 +3,74 @@ package reviews
 import (
 	"context"
 
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
 	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
 )
 
 type Service struct {
-	repo *Repository
+	repo          *Repository
+	outboxService *outbox.Service
 }
 
-func NewService(repo *Repository) *Service {
+func NewService(repo *Repository, outboxService *outbox.Service) *Service {
 	return &Service{
-		repo: repo,
+		repo:          repo,
+		outboxService: outboxService,
 	}
 }
 
 func (s *Service) CreateReview(ctx context.Context, review *Review) error {
-	if err := s.repo.CreateReview(ctx, review); err != nil {
-		return err
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateReview(ctx, review); err != nil {
+			return err
+		}
+		if err := s.outboxService.Record(ctx, AggregateType, review.ID, EventCreated, review); err != nil {
+			return err
+		}
+		return s.repo.ApplyRatingDelta(ctx, review.MovieID, review.Rating, 0)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
 	}
 	log.FromContext(ctx).Info(
 		"review created")
 	return nil
 }
 
-func (s *Service) GetReviewByID(ctx context.Context, id int) (*Review, error) {
-	r, err := s.repo.GetReviewByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	return r, err
+// GetReviewByID returns the review if its author lets the viewer see it.
+func (s *Service) GetReviewByID(ctx context.Context, id int, viewer *privacy.Viewer) (*Review, error) {
+	return s.repo.GetVisibleReviewByID(ctx, id, viewer)
+}
+
+// GetLatestReviewsByMovieIDs returns the latest reviews of each of the movies visible to the viewer,
+// at most limit per movie, in one query.
+func (s *Service) GetLatestReviewsByMovieIDs(ctx context.Context, movieIDs []int, viewer *privacy.Viewer, limit int) (map[int][]*Review, error) {
+	return s.repo.GetLatestReviewsByMovieIDs(ctx, movieIDs, viewer, limit)
 }
 
-func (s *Service) GetAllReviewsPaginated(ctx context.Context, movieID, userID *int, offset int, limit int) ([]*Review, int, error) {
-	return s.repo.GetAllReviewsPaginated(ctx, movieID, userID, offset, limit)
+func (s *Service) GetAllReviewsPaginated(ctx context.Context, movieID, userID *int, viewer *privacy.Viewer, offset int, limit int) ([]*Review, int, error) {
+	return s.repo.GetAllReviewsPaginated(ctx, movieID, userID, viewer, offset, limit)
 }
 
 func (s *Service) UpdateReview(ctx context.Context, reviewID, userID int, title, content string, rating int) error {
-	if err := s.repo.UpdateReview(ctx, reviewID, userID, title, content, rating); err != nil {
-		return err
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		movieID, prevRating, err := s.repo.UpdateReview(ctx, reviewID, userID, title, content, rating)
+		if err != nil {
+			return err
+		}
+		review, err := s.repo.GetReviewByID(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+		if err = s.outboxService.Record(ctx, AggregateType, reviewID, EventUpdated, review); err != nil {
+			return err
+		}
+		return s.repo.ApplyRatingDelta(ctx, movieID, rating, prevRating)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
 	}
 	log.FromContext(ctx).Info("review updated",
 		"id", reviewID)
@@ -47,10 +78,62 @@ func (s *Service) UpdateReview(ctx context.Context, reviewID, userID int, title,
 }
 
 func (s *Service) DeleteReview(ctx context.Context, reviewID, userID int) error {
-	if err := s.repo.DeleteReview(ctx, reviewID, userID); err != nil {
-		return err
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		movieID, rating, err := s.repo.DeleteReview(ctx, reviewID, userID)
+		if err != nil {
+			return err
+		}
+		payload := map[string]int{"id": reviewID, "user_id": userID, "movie_id": movieID}
+		if err = s.outboxService.Record(ctx, AggregateType, reviewID, EventDeleted, payload); err != nil {
+			return err
+		}
+		return s.repo.ApplyRatingDelta(ctx, movieID, 0, rating)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
 	}
 	log.FromContext(ctx).Info("review deleted",
 		"reviewId", reviewID)
 	return nil
 }
+
+// RunReconcileRatingsJob reconciles the ratings as a background job.
+func (s *Service) RunReconcileRatingsJob(ctx context.Context, payload ReconcileRatingsPayload) (any, error) {
+	drifts, err := s.ReconcileRatings(ctx, payload.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileRatingsResult{Drifts: drifts}, nil
+}
+
+// ReconcileRatings recomputes rating aggregates of all movies from their reviews and returns
+// the drift that was found. In dry run mode the drift is only reported and nothing is fixed.
+func (s *Service) ReconcileRatings(ctx context.Context, dryRun bool) ([]*RatingDrift, error) {
+	candidates, err := s.repo.GetRatingDrifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return candidates, nil
+	}
+
+	// Candidates are found without locks, so each of them is re-checked under the movie lock:
+	// a concurrent review write may have already made the aggregates consistent.
+	var drifts []*RatingDrift
+	for _, candidate := range candidates {
+		var drift *RatingDrift
+		drift, err = s.repo.ReconcileMovieRating(ctx, candidate.MovieID)
+		switch {
+		case apperrors.Is(err, apperrors.NotFoundCode):
+			continue
+		case err != nil:
+			return drifts, err
+		case drift != nil:
+			drifts = append(drifts, drift)
+		}
+	}
+	log.FromContext(ctx).Info("movie ratings reconciled",
+		"candidates", len(candidates),
+		"fixed", len(drifts))
+	return drifts, nil
+}
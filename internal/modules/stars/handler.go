@@ -49,6 +49,72 @@ func (h *Handler) CreateStar(c echo.Context) error {
 	return c.JSON(http.StatusCreated, star)
 }
 
+func (h *Handler) GetFilmography(c echo.Context) error {
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+		req, err := echox.BindAndValidate[contracts.GetFilmographyRequest](c)
+		if err != nil {
+			return nil, err
+		}
+		return h.service.GetFilmography(c.Request().Context(), req.ID, req.SortByRelease)
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) GetCareerStats(c echo.Context) error {
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+		req, err := echox.BindAndValidate[contracts.GetOrDeleteStarByIDRequest](c)
+		if err != nil {
+			return nil, err
+		}
+		return h.service.GetCareerStats(c.Request().Context(), req.ID)
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) GetCollaborators(c echo.Context) error {
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+		req, err := echox.BindAndValidate[contracts.GetCollaboratorsRequest](c)
+		if err != nil {
+			return nil, err
+		}
+		pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+		offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+		collaborators, total, err := h.service.GetCollaboratorsPaginated(c.Request().Context(), req.ID, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		return pagination.Response(&req.PaginatedRequest, total, collaborators), nil
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) GetStarPath(c echo.Context) error {
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+		req, err := echox.BindAndValidate[contracts.GetStarPathRequest](c)
+		if err != nil {
+			return nil, err
+		}
+		maxDegrees := MaxPathDegrees
+		if req.MaxDegrees != nil {
+			maxDegrees = *req.MaxDegrees
+		}
+		return h.service.FindPath(c.Request().Context(), req.From, req.To, maxDegrees)
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
 func (h *Handler) GetStarByID(c echo.Context) error {
 	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
 		req, err := echox.BindAndValidate[contracts.GetOrDeleteStarByIDRequest](c)
@@ -110,6 +176,24 @@ func (h *Handler) UpdateStar(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+func (h *Handler) UploadHeadshot(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UploadImageRequest](c)
+	if err != nil {
+		return err
+	}
+	file, err := echox.OpenFormFile(c, contracts.ImageFormField)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	img, err := h.service.UploadHeadshot(c.Request().Context(), req.ID, file)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, img)
+}
+
 func (h *Handler) DeleteStar(c echo.Context) error {
 	req, err := echox.BindAndValidate[contracts.GetOrDeleteStarByIDRequest](c)
 	if err != nil {
@@ -1,6 +1,18 @@
 package stars
 
-import "time"
+import (
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+)
+
+// Domain events of stars recorded in the outbox. Their payload is the star, or its id when it's deleted.
+const (
+	AggregateType = "star"
+	EventCreated  = "star.created"
+	EventUpdated  = "star.updated"
+	EventDeleted  = "star.deleted"
+)
 
 type Star struct {
 	ID        int        `json:"id"`
@@ -13,9 +25,11 @@ type Star struct {
 }
 type StarDetails struct {
 	Star
-	MiddleName *string `json:"middle_name,omitempty"`
-	BirthPlace *string `json:"birth_place,omitempty"`
-	Bio        *string `json:"bio,omitempty"`
+	MiddleName *string       `json:"middle_name,omitempty"`
+	BirthPlace *string       `json:"birth_place,omitempty"`
+	Bio        *string       `json:"bio,omitempty"`
+	HeadshotID *string       `json:"-"`
+	Headshot   *images.Image `json:"headshot,omitempty"`
 }
 
 type MovieCredit struct {
@@ -42,3 +56,54 @@ func (m MovieStarRelation) Key() any {
 		Role:    m.Role,
 	}
 }
+
+type FilmographyMovie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+type FilmographyCredit struct {
+	Movie   FilmographyMovie `json:"movie"`
+	Details *string          `json:"details,omitempty"`
+}
+
+// RoleFilmography lists the movies a star worked on in a single role.
+type RoleFilmography struct {
+	Role    string               `json:"role"`
+	Credits []*FilmographyCredit `json:"credits"`
+}
+
+type Collaborator struct {
+	Star         Star `json:"star"`
+	SharedMovies int  `json:"shared_movies"`
+}
+
+type CareerStats struct {
+	MovieCount    int             `json:"movie_count"`
+	RoleCounts    map[string]int  `json:"role_counts"`
+	AvgRating     *float64        `json:"avg_rating,omitempty"`
+	FirstRelease  *time.Time      `json:"first_release,omitempty"`
+	LastRelease   *time.Time      `json:"last_release,omitempty"`
+	Collaborators []*Collaborator `json:"collaborators"`
+}
+
+// CoCredit links two stars credited in the same movie.
+type CoCredit struct {
+	FromStarID int
+	ToStarID   int
+	MovieID    int
+}
+
+// PathStep is a star on a co-credit path. Movie is the movie shared with the previous star
+// and is nil for the first step.
+type PathStep struct {
+	Star  Star              `json:"star"`
+	Movie *FilmographyMovie `json:"movie,omitempty"`
+}
+
+type StarPath struct {
+	Degrees int         `json:"degrees"`
+	Steps   []*PathStep `json:"steps"`
+}
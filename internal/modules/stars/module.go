@@ -2,6 +2,8 @@ package stars
 
 import (
 	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -11,9 +13,9 @@ type Module struct {
 	Repository *Repository
 }
 
-func NewModule(db *pgxpool.Pool, paginationConfig config.PaginationConfig) *Module {
+func NewModule(db *pgxpool.Pool, paginationConfig config.PaginationConfig, imagesModule *images.Module, outboxModule *outbox.Module) *Module {
 	repo := NewRepository(db)
-	service := NewService(repo)
+	service := NewService(repo, imagesModule.Service, outboxModule.Service)
 	handler := NewHandler(service, paginationConfig)
 	return &Module{
 		Handler:    handler,
@@ -0,0 +1,128 @@
+package stars
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+)
+
+const (
+	// MaxPathDegrees bounds the length of the searched co-credit paths.
+	MaxPathDegrees = 6
+	// maxPathVisited bounds the number of stars explored by a single path search.
+	maxPathVisited = 10000
+)
+
+var errPathSearchTooWide = apperrors.BadRequest(errors.New("stars are too loosely connected to search a path between them"))
+
+// pathLink records how a star was reached during the search: via the movie shared with prev.
+type pathLink struct {
+	prev    int
+	movieID int
+	depth   int
+}
+
+type pathSide struct {
+	links    map[int]pathLink
+	frontier []int
+	depth    int
+}
+
+func newPathSide(starID int) *pathSide {
+	return &pathSide{
+		links:    map[int]pathLink{starID: {prev: starID}},
+		frontier: []int{starID},
+	}
+}
+
+// FindPath returns the shortest co-credit path between two stars with at most maxDegrees movies.
+// The search runs breadth-first from both ends, always expanding the smaller frontier.
+func (s *Service) FindPath(ctx context.Context, fromID, toID, maxDegrees int) (*StarPath, error) {
+	for _, id := range []int{fromID, toID} {
+		if _, err := s.repo.GetStarByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	forward, backward := newPathSide(fromID), newPathSide(toID)
+	meet, found := fromID, fromID == toID
+	for !found && forward.depth+backward.depth < maxDegrees {
+		side, other := forward, backward
+		if len(backward.frontier) < len(forward.frontier) {
+			side, other = backward, forward
+		}
+		if len(side.frontier) == 0 {
+			break
+		}
+
+		links, err := s.repo.GetCoCredits(ctx, side.frontier)
+		if err != nil {
+			return nil, err
+		}
+		side.depth++
+		side.frontier = side.frontier[:0]
+
+		bestDepth := -1
+		for _, link := range links {
+			if _, seen := side.links[link.ToStarID]; seen {
+				continue
+			}
+			side.links[link.ToStarID] = pathLink{prev: link.FromStarID, movieID: link.MovieID, depth: side.depth}
+			side.frontier = append(side.frontier, link.ToStarID)
+
+			if otherLink, ok := other.links[link.ToStarID]; ok && (bestDepth < 0 || otherLink.depth < bestDepth) {
+				meet, bestDepth, found = link.ToStarID, otherLink.depth, true
+			}
+		}
+		if len(forward.links)+len(backward.links) > maxPathVisited {
+			return nil, errPathSearchTooWide
+		}
+	}
+	if !found {
+		return nil, apperrors.NotFound("path", "(from,to)", fmt.Sprintf("(%d,%d)", fromID, toID))
+	}
+
+	return s.assemblePath(ctx, forward, backward, meet)
+}
+
+func (s *Service) assemblePath(ctx context.Context, forward, backward *pathSide, meet int) (*StarPath, error) {
+	// Walk from the meeting star back to the start, then forward to the end
+	starIDs := []int{meet}
+	var movieIDs []int
+	for id := meet; forward.links[id].prev != id; id = forward.links[id].prev {
+		starIDs = append([]int{forward.links[id].prev}, starIDs...)
+		movieIDs = append([]int{forward.links[id].movieID}, movieIDs...)
+	}
+	for id := meet; backward.links[id].prev != id; id = backward.links[id].prev {
+		starIDs = append(starIDs, backward.links[id].prev)
+		movieIDs = append(movieIDs, backward.links[id].movieID)
+	}
+
+	stars, err := s.repo.GetStarsByIDs(ctx, starIDs)
+	if err != nil {
+		return nil, err
+	}
+	movies, err := s.repo.GetFilmographyMoviesByIDs(ctx, movieIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// A star or a movie of the path may have been deleted since the search
+	path := &StarPath{Degrees: len(movieIDs)}
+	for i, id := range starIDs {
+		star, ok := stars[id]
+		if !ok {
+			return nil, apperrors.NotFound("star", "id", id)
+		}
+		step := &PathStep{Star: *star}
+		if i > 0 {
+			if step.Movie, ok = movies[movieIDs[i-1]]; !ok {
+				return nil, apperrors.NotFound("movie", "id", movieIDs[i-1])
+			}
+		}
+		path.Steps = append(path.Steps, step)
+	}
+	return path, nil
+}
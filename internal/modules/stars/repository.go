@@ -10,12 +10,14 @@ import (
 )
 
 type Repository struct {
+	dbx.Transactor
 	db *pgxpool.Pool
 }
 
 func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{
-		db: db,
+		Transactor: dbx.NewTransactor(db),
+		db:         db,
 	}
 }
 
@@ -25,7 +27,8 @@ func (r *Repository) CreateStar(ctx context.Context, star *StarDetails) error {
  VALUES ($1, $2, $3, $4, $5, $6, $7)
 	RETURNING
 	id, created_at, deleted_at`
-	row := r.db.QueryRow(ctx, queryString, star.FirstName, star.MiddleName, star.LastName, star.BirthDate,
+	q := dbx.FromContext(ctx, r.db)
+	row := q.QueryRow(ctx, queryString, star.FirstName, star.MiddleName, star.LastName, star.BirthDate,
 		star.BirthPlace, star.DeathDate, star.Bio)
 	err := row.Scan(&star.ID, &star.CreatedAt, &star.DeletedAt)
 	if err != nil {
@@ -37,7 +40,7 @@ func (r *Repository) CreateStar(ctx context.Context, star *StarDetails) error {
 func (r *Repository) GetStarByID(ctx context.Context, id int) (*StarDetails, error) {
 	var star StarDetails
 	queryString := `
-	SELECT id, first_name, middle_name, last_name, birth_date, birth_place, death_date, bio, created_at, deleted_at
+	SELECT id, first_name, middle_name, last_name, birth_date, birth_place, death_date, bio, created_at, deleted_at, headshot_id
 	FROM stars
 	WHERE id = $1 and deleted_at IS NULL;`
 	row := r.db.QueryRow(ctx, queryString, id)
@@ -50,7 +53,8 @@ func (r *Repository) GetStarByID(ctx context.Context, id int) (*StarDetails, err
 		&star.DeathDate,
 		&star.Bio,
 		&star.CreatedAt,
-		&star.DeletedAt)
+		&star.DeletedAt,
+		&star.HeadshotID)
 	if dbx.IsNoRows(err) {
 		return nil, apperrors.NotFound("star", "id", id)
 	}
@@ -162,6 +166,281 @@ func (r *Repository) GetCastByMovieID(ctx context.Context, movieID int) ([]*Movi
 	return cast, nil
 }
 
+func (r *Repository) GetCastByMovieIDs(ctx context.Context, movieIDs []int) (map[int][]*MovieCredit, error) {
+	rows, err := r.db.Query(ctx, `SELECT ms.movie_id, s.id, s.first_name, s.last_name, s.birth_date, s.death_date, s.created_at, ms.role, ms.details
+		FROM stars s
+		INNER JOIN movie_stars ms ON ms.star_id = s.id
+		WHERE ms.movie_id = ANY($1)
+		ORDER BY ms.movie_id, ms.order_no`, movieIDs)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	cast := make(map[int][]*MovieCredit, len(movieIDs))
+	for rows.Next() {
+		var (
+			movieID int
+			mc      MovieCredit
+		)
+		err = rows.Scan(
+			&movieID,
+			&mc.Star.ID,
+			&mc.Star.FirstName,
+			&mc.Star.LastName,
+			&mc.Star.BirthDate,
+			&mc.Star.DeathDate,
+			&mc.Star.CreatedAt,
+			&mc.Role,
+			&mc.Details,
+		)
+		if err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		cast[movieID] = append(cast[movieID], &mc)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return cast, nil
+}
+
+// GetFilmography returns the credits of the star grouped by role in the movie_role order.
+// Credits of each role are ordered by release date, oldest first unless sortByRelease is desc.
+func (r *Repository) GetFilmography(ctx context.Context, starID int, sortByRelease *string) ([]*RoleFilmography, error) {
+	query := dbx.StatementBuilder.
+		Select("ms.role, ms.details, m.id, m.title, m.release_date, m.avg_rating").
+		From("movie_stars ms").
+		Join("movies m ON m.id = ms.movie_id").
+		Where("ms.star_id = ?", starID).
+		Where("m.deleted_at IS NULL").
+		OrderBy("ms.role")
+	if sortByRelease != nil {
+		query = query.OrderByClause("m.release_date " + *sortByRelease)
+	} else {
+		query = query.OrderBy("m.release_date")
+	}
+	query = query.OrderBy("m.id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var filmography []*RoleFilmography
+	for rows.Next() {
+		var (
+			role   string
+			credit FilmographyCredit
+		)
+		if err = rows.Scan(
+			&role,
+			&credit.Details,
+			&credit.Movie.ID,
+			&credit.Movie.Title,
+			&credit.Movie.ReleaseDate,
+			&credit.Movie.AvgRating); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		if len(filmography) == 0 || filmography[len(filmography)-1].Role != role {
+			filmography = append(filmography, &RoleFilmography{Role: role})
+		}
+		last := filmography[len(filmography)-1]
+		last.Credits = append(last.Credits, &credit)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return filmography, nil
+}
+
+func (r *Repository) GetCareerStats(ctx context.Context, starID int) (*CareerStats, error) {
+	b := &pgx.Batch{}
+	b.Queue(`SELECT COUNT(*), AVG(m.avg_rating), MIN(m.release_date), MAX(m.release_date)
+		FROM movies m
+		WHERE m.deleted_at IS NULL
+		  AND m.id IN (SELECT movie_id FROM movie_stars WHERE star_id = $1)`, starID)
+	b.Queue(`SELECT ms.role, COUNT(*)
+		FROM movie_stars ms
+		INNER JOIN movies m ON m.id = ms.movie_id
+		WHERE ms.star_id = $1 AND m.deleted_at IS NULL
+		GROUP BY ms.role`, starID)
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	stats := CareerStats{RoleCounts: make(map[string]int)}
+	err := br.QueryRow().Scan(&stats.MovieCount, &stats.AvgRating, &stats.FirstRelease, &stats.LastRelease)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			role  string
+			count int
+		)
+		if err = rows.Scan(&role, &count); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		stats.RoleCounts[role] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return &stats, nil
+}
+
+// GetCollaboratorsPaginated returns the stars credited in the same movies as the star,
+// ranked by the number of shared movies.
+func (r *Repository) GetCollaboratorsPaginated(ctx context.Context, starID int, offset int, limit int) ([]*Collaborator, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select("s.id, s.first_name, s.last_name, s.birth_date, s.death_date, s.created_at, COUNT(DISTINCT o.movie_id) AS shared").
+		From("movie_stars ms").
+		Join("movies m ON m.id = ms.movie_id").
+		Join("movie_stars o ON o.movie_id = ms.movie_id AND o.star_id <> ms.star_id").
+		Join("stars s ON s.id = o.star_id").
+		Where("ms.star_id = ?", starID).
+		Where("m.deleted_at IS NULL AND s.deleted_at IS NULL").
+		GroupBy("s.id").
+		OrderBy("shared DESC", "s.id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(DISTINCT o.star_id)").
+		From("movie_stars ms").
+		Join("movies m ON m.id = ms.movie_id").
+		Join("movie_stars o ON o.movie_id = ms.movie_id AND o.star_id <> ms.star_id").
+		Join("stars s ON s.id = o.star_id").
+		Where("ms.star_id = ?", starID).
+		Where("m.deleted_at IS NULL AND s.deleted_at IS NULL")
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var collaborators []*Collaborator
+	for rows.Next() {
+		var collaborator Collaborator
+		if err = rows.Scan(
+			&collaborator.Star.ID,
+			&collaborator.Star.FirstName,
+			&collaborator.Star.LastName,
+			&collaborator.Star.BirthDate,
+			&collaborator.Star.DeathDate,
+			&collaborator.Star.CreatedAt,
+			&collaborator.SharedMovies); err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		collaborators = append(collaborators, &collaborator)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return collaborators, total, nil
+}
+
+// GetCoCredits returns the links from the given stars to every star credited in the same movie.
+func (r *Repository) GetCoCredits(ctx context.Context, starIDs []int) ([]CoCredit, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT ms.star_id, o.star_id, ms.movie_id
+		FROM movie_stars ms
+		INNER JOIN movies m ON m.id = ms.movie_id
+		INNER JOIN movie_stars o ON o.movie_id = ms.movie_id AND o.star_id <> ms.star_id
+		INNER JOIN stars s ON s.id = o.star_id
+		WHERE ms.star_id = ANY($1) AND m.deleted_at IS NULL AND s.deleted_at IS NULL
+		ORDER BY ms.star_id, o.star_id, ms.movie_id`, starIDs)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var links []CoCredit
+	for rows.Next() {
+		var link CoCredit
+		if err = rows.Scan(&link.FromStarID, &link.ToStarID, &link.MovieID); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		links = append(links, link)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return links, nil
+}
+
+func (r *Repository) GetStarsByIDs(ctx context.Context, ids []int) (map[int]*Star, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, first_name, last_name, birth_date, death_date, created_at
+		FROM stars
+		WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	stars := make(map[int]*Star, len(ids))
+	for rows.Next() {
+		var star Star
+		if err = rows.Scan(&star.ID, &star.FirstName, &star.LastName, &star.BirthDate, &star.DeathDate, &star.CreatedAt); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		stars[star.ID] = &star
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return stars, nil
+}
+
+func (r *Repository) GetFilmographyMoviesByIDs(ctx context.Context, ids []int) (map[int]*FilmographyMovie, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, title, release_date, avg_rating
+		FROM movies
+		WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	movies := make(map[int]*FilmographyMovie, len(ids))
+	for rows.Next() {
+		var movie FilmographyMovie
+		if err = rows.Scan(&movie.ID, &movie.Title, &movie.ReleaseDate, &movie.AvgRating); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		movies[movie.ID] = &movie
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return movies, nil
+}
+
 func (r *Repository) GetRelationsByMovieID(ctx context.Context, id int) ([]*MovieStarRelation, error) {
 	queryString := `
 	SELECT movie_id, star_id, role, details, order_no
@@ -192,7 +471,8 @@ func (r *Repository) GetRelationsByMovieID(ctx context.Context, id int) ([]*Movi
 }
 
 func (r *Repository) UpdateStar(ctx context.Context, star *StarDetails) error {
-	n, err := r.db.Exec(ctx, `UPDATE stars 
+	q := dbx.FromContext(ctx, r.db)
+	n, err := q.Exec(ctx, `UPDATE stars 
 			SET first_name = $1, 
 			middle_name = $2, 
 			last_name = $3, 
@@ -220,8 +500,29 @@ func (r *Repository) UpdateStar(ctx context.Context, star *StarDetails) error {
 	return nil
 }
 
+// UpdateHeadshot sets the headshot of the star and returns the id of the previous one.
+func (r *Repository) UpdateHeadshot(ctx context.Context, starID int, imageID string) (*string, error) {
+	var prevID *string
+	err := r.db.QueryRow(ctx, `WITH prev AS (
+			SELECT id, headshot_id FROM stars WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		)
+		UPDATE stars s SET headshot_id = $2
+		FROM prev
+		WHERE s.id = prev.id
+		RETURNING prev.headshot_id`, starID, imageID).
+		Scan(&prevID)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("star", "id", starID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return prevID, nil
+}
+
 func (r *Repository) DeleteStar(ctx context.Context, id int) error {
-	n, err := r.db.Exec(ctx, `UPDATE stars SET deleted_at  = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
+	q := dbx.FromContext(ctx, r.db)
+	n, err := q.Exec(ctx, `UPDATE stars SET deleted_at  = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
 	if err != nil {
 		return apperrors.Internal(err)
 	}
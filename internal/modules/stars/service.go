@@ -2,23 +2,40 @@ package stars
 
 import (
 	"context"
+	"io"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/log"
 )
 
+const topCollaboratorsLimit = 5
+
 type Service struct {
-	repo *Repository
+	repo          *Repository
+	imagesService *images.Service
+	outboxService *outbox.Service
 }
 
-func NewService(repo *Repository) *Service {
+func NewService(repo *Repository, imagesService *images.Service, outboxService *outbox.Service) *Service {
 	return &Service{
-		repo: repo,
+		repo:          repo,
+		imagesService: imagesService,
+		outboxService: outboxService,
 	}
 }
 
 func (s *Service) CreateStar(ctx context.Context, star *StarDetails) error {
-	if err := s.repo.CreateStar(ctx, star); err != nil {
-		return err
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateStar(ctx, star); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, star.ID, EventCreated, star)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
 	}
 	log.FromContext(ctx).Info(
 		"star created",
@@ -28,16 +45,80 @@ func (s *Service) CreateStar(ctx context.Context, star *StarDetails) error {
 }
 
 func (s *Service) GetStarByID(ctx context.Context, id int) (*StarDetails, error) {
-	return s.repo.GetStarByID(ctx, id)
+	star, err := s.repo.GetStarByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	star.Headshot = s.imagesService.GetImage(star.HeadshotID)
+	return star, nil
+}
+
+// UploadHeadshot stores the image as the headshot of the star replacing the previous one.
+func (s *Service) UploadHeadshot(ctx context.Context, starID int, r io.Reader) (*images.Image, error) {
+	if _, err := s.repo.GetStarByID(ctx, starID); err != nil {
+		return nil, err
+	}
+	img, err := s.imagesService.Upload(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	prevID, err := s.repo.UpdateHeadshot(ctx, starID, img.ID)
+	if err != nil {
+		s.imagesService.Delete(ctx, img.ID)
+		return nil, err
+	}
+	if prevID != nil {
+		s.imagesService.Delete(ctx, *prevID)
+	}
+	log.FromContext(ctx).Info(
+		"star headshot uploaded",
+		"starId", starID,
+		"imageId", img.ID)
+	return img, nil
 }
 
 func (s *Service) GetAllStarsPaginated(ctx context.Context, movieID *int, offset int, limit int) ([]*StarDetails, int, error) {
 	return s.repo.GetAllStarsPaginated(ctx, movieID, offset, limit)
 }
 
+func (s *Service) GetFilmography(ctx context.Context, id int, sortByRelease *string) ([]*RoleFilmography, error) {
+	if _, err := s.repo.GetStarByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFilmography(ctx, id, sortByRelease)
+}
+
+func (s *Service) GetCareerStats(ctx context.Context, id int) (*CareerStats, error) {
+	if _, err := s.repo.GetStarByID(ctx, id); err != nil {
+		return nil, err
+	}
+	stats, err := s.repo.GetCareerStats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stats.Collaborators, _, err = s.repo.GetCollaboratorsPaginated(ctx, id, 0, topCollaboratorsLimit)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *Service) GetCollaboratorsPaginated(ctx context.Context, id int, offset int, limit int) ([]*Collaborator, int, error) {
+	if _, err := s.repo.GetStarByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetCollaboratorsPaginated(ctx, id, offset, limit)
+}
+
 func (s *Service) UpdateStar(ctx context.Context, star *StarDetails) error {
-	if err := s.repo.UpdateStar(ctx, star); err != nil {
-		return err
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateStar(ctx, star); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, star.ID, EventUpdated, star)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
 	}
 	log.FromContext(ctx).Info(
 		"star updated",
@@ -46,8 +127,14 @@ func (s *Service) UpdateStar(ctx context.Context, star *StarDetails) error {
 }
 
 func (s *Service) DeleteStar(ctx context.Context, id int) error {
-	if err := s.repo.DeleteStar(ctx, id); err != nil {
-		return err
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteStar(ctx, id); err != nil {
+			return err
+		}
+		return s.outboxService.Record(ctx, AggregateType, id, EventDeleted, map[string]int{"id": id})
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
 	}
 	log.FromContext(ctx).Info(
 		"star deleted",
@@ -58,3 +145,8 @@ func (s *Service) DeleteStar(ctx context.Context, id int) error {
 func (s *Service) GetCastByMovieID(ctx context.Context, movieID int) ([]*MovieCredit, error) {
 	return s.repo.GetCastByMovieID(ctx, movieID)
 }
+
+// GetCastByMovieIDs returns the cast of each of the movies in one query.
+func (s *Service) GetCastByMovieIDs(ctx context.Context, movieIDs []int) (map[int][]*MovieCredit, error) {
+	return s.repo.GetCastByMovieIDs(ctx, movieIDs)
+}
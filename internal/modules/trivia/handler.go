@@ -0,0 +1,146 @@
+package trivia
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/users"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) GetMovieTrivia(c echo.Context) error {
+	return h.getItems(c, func(filter *Filter, id int) { filter.MovieID = &id })
+}
+
+func (h *Handler) GetStarTrivia(c echo.Context) error {
+	return h.getItems(c, func(filter *Filter, id int) { filter.StarID = &id })
+}
+
+func (h *Handler) SubmitMovieTrivia(c echo.Context) error {
+	return h.submitItem(c, func(item *Item, id int) { item.MovieID = &id })
+}
+
+func (h *Handler) SubmitStarTrivia(c echo.Context) error {
+	return h.submitItem(c, func(item *Item, id int) { item.StarID = &id })
+}
+
+func (h *Handler) ReviewItem(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.ReviewTriviaRequest](c)
+	if err != nil {
+		return err
+	}
+	item, err := h.service.ReviewItem(c.Request().Context(), req.ItemID, req.Status, jwt.GetClaims(c).UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *Handler) DeleteItem(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.TriviaItemRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.DeleteItem(c.Request().Context(), req.ItemID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) Vote(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.VoteTriviaRequest](c)
+	if err != nil {
+		return err
+	}
+	item, err := h.service.Vote(c.Request().Context(), req.ItemID, jwt.GetClaims(c).UserID, req.Value)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *Handler) Unvote(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.TriviaItemRequest](c)
+	if err != nil {
+		return err
+	}
+	item, err := h.service.Unvote(c.Request().Context(), req.ItemID, jwt.GetClaims(c).UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// getItems lists the items of the subject. Only editors can list items that are not approved yet.
+func (h *Handler) getItems(c echo.Context, setSubject func(filter *Filter, id int)) error {
+	req, err := echox.BindAndValidate[contracts.GetTriviaRequest](c)
+	if err != nil {
+		return err
+	}
+	filter := &Filter{
+		Kind:        req.Kind,
+		Status:      StatusApproved,
+		SortByScore: req.SortByScore,
+	}
+	if req.Status != nil && *req.Status != StatusApproved {
+		if !isEditor(c) {
+			return apperrors.Forbidden("only editors can list trivia items that are not approved")
+		}
+		filter.Status = *req.Status
+	}
+	setSubject(filter, req.ID)
+
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	items, total, err := h.service.GetItemsPaginated(c.Request().Context(), filter, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, items))
+}
+
+func (h *Handler) submitItem(c echo.Context, setSubject func(item *Item, id int)) error {
+	req, err := echox.BindAndValidate[contracts.SubmitTriviaRequest](c)
+	if err != nil {
+		return err
+	}
+	item := &Item{
+		Kind:    req.Kind,
+		UserID:  jwt.GetClaims(c).UserID,
+		Content: req.Content,
+	}
+	if req.Credit != nil {
+		item.Credit = &Credit{
+			StarID: req.Credit.StarID,
+			Role:   req.Credit.Role,
+		}
+	}
+	setSubject(item, req.ID)
+
+	if err = h.service.SubmitItem(c.Request().Context(), item); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+func isEditor(c echo.Context) bool {
+	claims := jwt.GetClaims(c)
+	return claims != nil && (claims.Role == users.EditorRole || claims.Role == users.AdminRole)
+}
@@ -0,0 +1,37 @@
+package trivia
+
+import "time"
+
+const (
+	KindTrivia = "trivia"
+	KindQuote  = "quote"
+	KindGoof   = "goof"
+
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Item is a piece of trivia, a quote or a goof submitted by a user about a movie or a star.
+// Exactly one of MovieID and StarID is set. Items become publicly visible once approved by an editor.
+type Item struct {
+	ID         int        `json:"id"`
+	Kind       string     `json:"kind"`
+	MovieID    *int       `json:"movie_id,omitempty"`
+	StarID     *int       `json:"star_id,omitempty"`
+	UserID     int        `json:"user_id"`
+	Content    string     `json:"content"`
+	Credit     *Credit    `json:"credit,omitempty"`
+	Status     string     `json:"status"`
+	Score      int        `json:"score"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// Credit attributes a quote to a cast credit of the movie.
+type Credit struct {
+	StarID    int    `json:"star_id"`
+	Role      string `json:"role"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
@@ -0,0 +1,309 @@
+package trivia
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const selectItemColumns = `t.id, t.kind, t.movie_id, t.star_id, t.user_id, t.content, t.credit_star_id, t.credit_role,
+	s.first_name, s.last_name, t.status, t.score, t.created_at, t.reviewed_at`
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// Filter narrows down the items returned by GetItemsPaginated. Exactly one of MovieID and StarID is expected.
+type Filter struct {
+	MovieID     *int
+	StarID      *int
+	Kind        *string
+	Status      string
+	SortByScore *string
+}
+
+func (r *Repository) CreateItem(ctx context.Context, item *Item) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		if err := r.ensureSubjectExists(ctx, item); err != nil {
+			return err
+		}
+
+		var creditStarID *int
+		var creditRole *string
+		if item.Credit != nil {
+			creditStarID, creditRole = &item.Credit.StarID, &item.Credit.Role
+		}
+		err := tx.QueryRow(ctx, `INSERT INTO trivia_items (kind, movie_id, star_id, user_id, content, credit_star_id, credit_role)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, status, score, created_at`,
+			item.Kind, item.MovieID, item.StarID, item.UserID, item.Content, creditStarID, creditRole).
+			Scan(&item.ID, &item.Status, &item.Score, &item.CreatedAt)
+		switch {
+		case dbx.IsForeignKeyViolation(err, "user_id"):
+			return apperrors.NotFound("user", "id", item.UserID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetItemByID(ctx context.Context, id int) (*Item, error) {
+	q := dbx.FromContext(ctx, r.db)
+	row := q.QueryRow(ctx, `SELECT `+selectItemColumns+`
+		FROM trivia_items t
+		LEFT JOIN stars s ON s.id = t.credit_star_id
+		WHERE t.deleted_at IS NULL AND t.id = $1`, id)
+	item, err := scanItem(row)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("trivia item", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return item, nil
+}
+
+func (r *Repository) GetItemsPaginated(ctx context.Context, filter *Filter, offset int, limit int) ([]*Item, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select(selectItemColumns).
+		From("trivia_items t").
+		LeftJoin("stars s ON s.id = t.credit_star_id").
+		Where("t.deleted_at IS NULL").
+		Where("t.status = ?", filter.Status).
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("trivia_items t").
+		Where("t.deleted_at IS NULL").
+		Where("t.status = ?", filter.Status)
+
+	if filter.MovieID != nil {
+		selectQuery = selectQuery.Where("t.movie_id = ?", *filter.MovieID)
+		queryTotal = queryTotal.Where("t.movie_id = ?", *filter.MovieID)
+	}
+	if filter.StarID != nil {
+		selectQuery = selectQuery.Where("t.star_id = ?", *filter.StarID)
+		queryTotal = queryTotal.Where("t.star_id = ?", *filter.StarID)
+	}
+	if filter.Kind != nil {
+		selectQuery = selectQuery.Where("t.kind = ?", *filter.Kind)
+		queryTotal = queryTotal.Where("t.kind = ?", *filter.Kind)
+	}
+	if filter.SortByScore != nil {
+		selectQuery = selectQuery.OrderByClause("t.score " + *filter.SortByScore)
+	}
+	selectQuery = selectQuery.OrderBy("t.id")
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var items []*Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return items, total, nil
+}
+
+// UpdateStatus records the decision of the editor about the item.
+func (r *Repository) UpdateStatus(ctx context.Context, id int, status string, reviewerID int) error {
+	n, err := r.db.Exec(ctx, `UPDATE trivia_items
+		SET status = $1, reviewed_at = NOW(), reviewed_by = $2
+		WHERE deleted_at IS NULL AND id = $3`, status, reviewerID, id)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("trivia item", "id", id)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteItem(ctx context.Context, id int) error {
+	n, err := r.db.Exec(ctx, `UPDATE trivia_items SET deleted_at = NOW() WHERE deleted_at IS NULL AND id = $1`, id)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("trivia item", "id", id)
+	}
+	return nil
+}
+
+// Vote sets the vote of the user for the item replacing the previous one and returns the new score of the item.
+func (r *Repository) Vote(ctx context.Context, itemID, userID, value int) (int, error) {
+	var score int
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		if err := r.lockItem(ctx, itemID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO trivia_votes (item_id, user_id, value) VALUES ($1, $2, $3)
+			ON CONFLICT (item_id, user_id) DO UPDATE SET value = EXCLUDED.value, created_at = NOW()`,
+			itemID, userID, value)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		score, err = r.recalculateScore(ctx, itemID)
+		return err
+	})
+	if err != nil {
+		return 0, apperrors.EnsureInternal(err)
+	}
+	return score, nil
+}
+
+// Unvote removes the vote of the user for the item and returns the new score of the item.
+func (r *Repository) Unvote(ctx context.Context, itemID, userID int) (int, error) {
+	var score int
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		if err := r.lockItem(ctx, itemID); err != nil {
+			return err
+		}
+		n, err := tx.Exec(ctx, `DELETE FROM trivia_votes WHERE item_id = $1 AND user_id = $2`, itemID, userID)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			return apperrors.NotFound("trivia vote", "(item_id,user_id)", fmt.Sprintf("(%d,%d)", itemID, userID))
+		}
+		score, err = r.recalculateScore(ctx, itemID)
+		return err
+	})
+	if err != nil {
+		return 0, apperrors.EnsureInternal(err)
+	}
+	return score, nil
+}
+
+// CreditExists reports whether the star is credited in the movie with the role.
+func (r *Repository) CreditExists(ctx context.Context, movieID int, credit *Credit) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (
+			SELECT 1 FROM movie_stars WHERE movie_id = $1 AND star_id = $2 AND role::TEXT = $3
+		)`, movieID, credit.StarID, credit.Role).
+		Scan(&exists)
+	if err != nil {
+		return false, apperrors.Internal(err)
+	}
+	return exists, nil
+}
+
+// lockItem locks the item until the end of the transaction, so that concurrent votes for it are applied one
+// at a time and each recalculation of the score sees the votes committed before it.
+func (r *Repository) lockItem(ctx context.Context, itemID int) error {
+	q := dbx.FromContext(ctx, r.db)
+	var id int
+	err := q.QueryRow(ctx, `SELECT id FROM trivia_items WHERE id = $1 FOR UPDATE`, itemID).Scan(&id)
+	switch {
+	case dbx.IsNoRows(err):
+		return apperrors.NotFound("trivia item", "id", itemID)
+	case err != nil:
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+func (r *Repository) recalculateScore(ctx context.Context, itemID int) (int, error) {
+	q := dbx.FromContext(ctx, r.db)
+	var score int
+	err := q.QueryRow(ctx, `UPDATE trivia_items
+		SET score = (SELECT COALESCE(SUM(value), 0) FROM trivia_votes WHERE item_id = $1)
+		WHERE id = $1
+		RETURNING score`, itemID).
+		Scan(&score)
+	if err != nil {
+		return 0, apperrors.Internal(err)
+	}
+	return score, nil
+}
+
+func (r *Repository) ensureSubjectExists(ctx context.Context, item *Item) error {
+	q := dbx.FromContext(ctx, r.db)
+	subject, table, id := "movie", "movies", item.MovieID
+	if item.StarID != nil {
+		subject, table, id = "star", "stars", item.StarID
+	}
+
+	var exists bool
+	err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)`, *id).
+		Scan(&exists)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if !exists {
+		return apperrors.NotFound(subject, "id", *id)
+	}
+	return nil
+}
+
+func scanItem(row pgx.Row) (*Item, error) {
+	var (
+		item                Item
+		creditStarID        *int
+		creditRole          *string
+		firstName, lastName *string
+	)
+	err := row.Scan(
+		&item.ID,
+		&item.Kind,
+		&item.MovieID,
+		&item.StarID,
+		&item.UserID,
+		&item.Content,
+		&creditStarID,
+		&creditRole,
+		&firstName,
+		&lastName,
+		&item.Status,
+		&item.Score,
+		&item.CreatedAt,
+		&item.ReviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	if creditStarID != nil && creditRole != nil {
+		item.Credit = &Credit{StarID: *creditStarID, Role: *creditRole}
+		if firstName != nil && lastName != nil {
+			item.Credit.FirstName, item.Credit.LastName = *firstName, *lastName
+		}
+	}
+	return &item, nil
+}
@@ -0,0 +1,151 @@
+package trivia
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// SubmitItem stores the item pending an approval by an editor.
+func (s *Service) SubmitItem(ctx context.Context, item *Item) error {
+	if err := s.validate(ctx, item); err != nil {
+		return err
+	}
+	if err := s.repo.CreateItem(ctx, item); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("trivia item submitted",
+		"itemId", item.ID,
+		"userId", item.UserID)
+	return nil
+}
+
+func (s *Service) GetItemsPaginated(ctx context.Context, filter *Filter, offset int, limit int) ([]*Item, int, error) {
+	if filter.Kind != nil && !isKind(*filter.Kind) {
+		return nil, 0, apperrors.BadRequest(fmt.Errorf("invalid trivia kind %q", *filter.Kind))
+	}
+	if !isStatus(filter.Status) {
+		return nil, 0, apperrors.BadRequest(fmt.Errorf("invalid trivia status %q", filter.Status))
+	}
+	return s.repo.GetItemsPaginated(ctx, filter, offset, limit)
+}
+
+// ReviewItem approves or rejects the item on behalf of the editor.
+func (s *Service) ReviewItem(ctx context.Context, id int, status string, reviewerID int) (*Item, error) {
+	if status != StatusApproved && status != StatusRejected {
+		return nil, apperrors.BadRequest(fmt.Errorf("trivia item can be only %s or %s", StatusApproved, StatusRejected))
+	}
+	if err := s.repo.UpdateStatus(ctx, id, status, reviewerID); err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("trivia item reviewed",
+		"itemId", id,
+		"status", status,
+		"reviewerId", reviewerID)
+	return s.repo.GetItemByID(ctx, id)
+}
+
+func (s *Service) DeleteItem(ctx context.Context, id int) error {
+	if err := s.repo.DeleteItem(ctx, id); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("trivia item deleted",
+		"itemId", id)
+	return nil
+}
+
+// Vote up-votes (value 1) or down-votes (value -1) the approved item. Voting again replaces the previous vote.
+func (s *Service) Vote(ctx context.Context, itemID, userID, value int) (*Item, error) {
+	if value != 1 && value != -1 {
+		return nil, apperrors.BadRequest(fmt.Errorf("vote must be 1 or -1"))
+	}
+	item, err := s.getApprovedItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Score, err = s.repo.Vote(ctx, itemID, userID, value); err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("trivia item voted",
+		"itemId", itemID,
+		"userId", userID,
+		"value", value)
+	return item, nil
+}
+
+func (s *Service) Unvote(ctx context.Context, itemID, userID int) (*Item, error) {
+	item, err := s.getApprovedItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Score, err = s.repo.Unvote(ctx, itemID, userID); err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("trivia item unvoted",
+		"itemId", itemID,
+		"userId", userID)
+	return item, nil
+}
+
+// getApprovedItem returns the item if it is publicly visible. Items that are not approved are reported as not found.
+func (s *Service) getApprovedItem(ctx context.Context, id int) (*Item, error) {
+	item, err := s.repo.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Status != StatusApproved {
+		return nil, apperrors.NotFound("trivia item", "id", id)
+	}
+	return item, nil
+}
+
+func (s *Service) validate(ctx context.Context, item *Item) error {
+	if !isKind(item.Kind) {
+		return apperrors.BadRequest(fmt.Errorf("invalid trivia kind %q", item.Kind))
+	}
+	if item.StarID != nil && item.Kind != KindTrivia {
+		return apperrors.BadRequest(fmt.Errorf("stars can only have %s items", KindTrivia))
+	}
+	if item.Credit == nil {
+		return nil
+	}
+
+	if item.Kind != KindQuote || item.MovieID == nil {
+		return apperrors.BadRequest(fmt.Errorf("only movie quotes can be attributed to a credit"))
+	}
+	exists, err := s.repo.CreditExists(ctx, *item.MovieID, item.Credit)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.BadRequest(fmt.Errorf("star with id %d is not credited as %s in movie with id %d",
+			item.Credit.StarID, item.Credit.Role, *item.MovieID))
+	}
+	return nil
+}
+
+func isKind(kind string) bool {
+	switch kind {
+	case KindTrivia, KindQuote, KindGoof:
+		return true
+	}
+	return false
+}
+
+func isStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusApproved, StatusRejected:
+		return true
+	}
+	return false
+}
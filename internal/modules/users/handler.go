@@ -2,9 +2,12 @@ package users
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
 	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
 	"github.com/labstack/echo/v4"
 )
 
@@ -21,27 +24,63 @@ func (h *Handler) Get(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	user, err := h.service.Get(c.Request().Context(), req.UserId)
+	profile, err := h.service.GetProfile(c.Request().Context(), req.UserId, privacy.GetViewer(c))
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, user)
+	return c.JSON(http.StatusOK, profile)
 }
 
 func (h *Handler) Delete(c echo.Context) error {
-	req, err := echox.BindAndValidate[contracts.DeleteOrGetRequest](c)
+	req, err := echox.BindAndValidate[contracts.DeleteUserRequest](c)
+	if err != nil {
+		return err
+	}
+	keepReviews := req.KeepReviews == nil || *req.KeepReviews
+	deletion, err := h.service.Delete(c.Request().Context(), req.UserId, keepReviews)
 	if err != nil {
 		return err
 	}
-	return h.service.Delete(c.Request().Context(), req.UserId)
+	return c.JSON(http.StatusOK, deletion)
 }
 
-func (h *Handler) UpdateBio(c echo.Context) error {
+func (h *Handler) UpdateProfile(c echo.Context) error {
 	req, err := echox.BindAndValidate[contracts.UpdateRequest](c)
 	if err != nil {
 		return err
 	}
-	return h.service.UpdateBio(c.Request().Context(), req.UserId, *req.Bio)
+	update := &ProfileUpdate{
+		Bio:              req.Bio,
+		DisplayName:      req.DisplayName,
+		Location:         req.Location,
+		FavoriteGenreIDs: req.FavoriteGenreIDs,
+	}
+	if req.Privacy != nil {
+		update.Privacy = &Privacy{
+			Email:   req.Privacy.Email,
+			Reviews: req.Privacy.Reviews,
+			Lists:   req.Privacy.Lists,
+		}
+	}
+	return h.service.UpdateProfile(c.Request().Context(), req.UserId, update)
+}
+
+func (h *Handler) UploadAvatar(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UploadAvatarRequest](c)
+	if err != nil {
+		return err
+	}
+	file, err := echox.OpenFormFile(c, contracts.ImageFormField)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	img, err := h.service.UploadAvatar(c.Request().Context(), req.UserID, file)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, img)
 }
 
 func (h *Handler) UpdateRole(c echo.Context) error {
@@ -57,11 +96,34 @@ func (h *Handler) GetByUserName(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-
-	user, err := h.service.GetExistingUserByUsername(c.Request().Context(), req.Username)
+	profile, err := h.service.GetProfileByUsername(c.Request().Context(), req.Username, privacy.GetViewer(c))
 	if err != nil {
 		return err
 	}
+	return c.JSON(http.StatusOK, profile)
+}
 
-	return c.JSON(http.StatusOK, user)
+// Visible rejects the requests for the section of the user's data that is hidden from the requester
+// by the user's privacy settings. The user is taken from the userId path or query parameter,
+// requests without it are passed through.
+func (h *Handler) Visible(section string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			param := c.Param("userId")
+			if param == "" {
+				param = c.QueryParam("userId")
+			}
+			if param == "" {
+				return next(c)
+			}
+			userId, err := strconv.Atoi(param)
+			if err != nil {
+				return apperrors.BadRequestHidden(err, "invalid user id")
+			}
+			if err = h.service.CheckVisible(c.Request().Context(), userId, section, privacy.GetViewer(c)); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
 }
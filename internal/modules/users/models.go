@@ -1,6 +1,11 @@
 package users
 
-import "time"
+import (
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+	"github.com/RadkevichAnn/movie-reviews/internal/privacy"
+)
 
 const (
 	UserRole   = "user"
@@ -8,6 +13,17 @@ const (
 	AdminRole  = "admin"
 )
 
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+	VisibilityPrivate   = "private"
+)
+
+const (
+	SectionReviews = "reviews"
+	SectionLists   = "lists"
+)
+
 type User struct {
 	ID        int        `json:"id"`
 	Username  string     `json:"username"`
@@ -28,3 +44,91 @@ func newUserWithPassword() *UserWithPassword {
 		User: &User{},
 	}
 }
+
+// Privacy holds the visibility of the parts of the profile to other users. Admins see everything.
+type Privacy struct {
+	Email   string `json:"email"`
+	Reviews string `json:"reviews"`
+	Lists   string `json:"lists"`
+}
+
+type FavoriteGenre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Stats struct {
+	ReviewCount    int      `json:"review_count"`
+	AvgRatingGiven *float64 `json:"avg_rating_given,omitempty"`
+}
+
+// Profile is the user as shown to the viewer. Email and Stats are set only when the privacy settings
+// allow the viewer to see them, Privacy is set only for the user themselves and admins.
+type Profile struct {
+	ID             int              `json:"id"`
+	Username       string           `json:"username"`
+	DisplayName    *string          `json:"display_name,omitempty"`
+	Email          string           `json:"email,omitempty"`
+	Role           string           `json:"role"`
+	Bio            *string          `json:"bio,omitempty"`
+	Location       *string          `json:"location,omitempty"`
+	AvatarID       *string          `json:"-"`
+	Avatar         *images.Image    `json:"avatar,omitempty"`
+	FavoriteGenres []*FavoriteGenre `json:"favorite_genres"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Stats          *Stats           `json:"stats,omitempty"`
+	Privacy        *Privacy         `json:"privacy,omitempty"`
+}
+
+// ProfileUpdate holds the changes of the profile: nil fields and empty privacy settings are left as is,
+// empty display name and location are removed.
+type ProfileUpdate struct {
+	Bio              *string
+	DisplayName      *string
+	Location         *string
+	FavoriteGenreIDs []int
+	Privacy          *Privacy
+}
+
+// Viewer is the user that requests the data of another user.
+type Viewer = privacy.Viewer
+
+// relation of the viewer to the user, which defines what parts of the profile the viewer can see.
+type relation int
+
+const (
+	relationNone relation = iota
+	relationFollower
+	relationSelf
+)
+
+func (r relation) canSee(visibility string) bool {
+	switch visibility {
+	case VisibilityPublic:
+		return true
+	case VisibilityFollowers:
+		return r >= relationFollower
+	default:
+		return r == relationSelf
+	}
+}
+
+// PurgeJob is the type of the job anonymizing a deleted account once its grace period is over.
+const PurgeJob = "users.purge"
+
+type PurgePayload struct {
+	UserID int `json:"user_id"`
+}
+
+type PurgeResult struct {
+	// Purged is false when the deletion was cancelled or rescheduled before the job ran.
+	Purged         bool `json:"purged"`
+	RemovedReviews int  `json:"removed_reviews"`
+}
+
+// Deletion is a scheduled account deletion. Until ScheduledAt the account can be restored,
+// afterwards its personal data is anonymized and its reviews are either kept or removed.
+type Deletion struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+	KeepReviews bool      `json:"keep_reviews"`
+}
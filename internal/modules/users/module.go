@@ -1,6 +1,12 @@
 package users
 
-import "github.com/jackc/pgx/v5/pgxpool"
+import (
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jobs"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
 
 type Module struct {
 	Handler    *Handler
@@ -8,9 +14,16 @@ type Module struct {
 	Repository *Repository
 }
 
-func NewModule(db *pgxpool.Pool) *Module {
+func NewModule(
+	db *pgxpool.Pool,
+	cfg config.UsersConfig,
+	scheduler jobs.Scheduler,
+	reviewsModule *reviews.Module,
+	imagesModule *images.Module,
+	exportsCleaner ExportsCleaner,
+) *Module {
 	repo := NewRepository(db)
-	service := NewService(repo)
+	service := NewService(repo, cfg, scheduler, reviewsModule.Repository, imagesModule.Service, exportsCleaner)
 	handler := NewHandler(service)
 	return &Module{
 		Handler:    handler,
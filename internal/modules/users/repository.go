@@ -2,18 +2,25 @@ package users
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
 	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Repository struct {
+	dbx.Transactor
 	db *pgxpool.Pool
 }
 
 func NewRepository(db *pgxpool.Pool) *Repository {
-	return &Repository{db: db}
+	return &Repository{
+		Transactor: dbx.NewTransactor(db),
+		db:         db,
+	}
 }
 
 func (r *Repository) Create(ctx context.Context, user *UserWithPassword) error {
@@ -59,17 +66,144 @@ func (r *Repository) GetExistingUserWithPassword(ctx context.Context, email stri
 	return user, nil
 }
 
-func (r *Repository) Delete(ctx context.Context, userId int) error {
-	n, err := r.db.Exec(ctx, "UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", userId)
+// ScheduleDeletion deactivates the user, revokes their tokens and schedules the anonymization after the grace period.
+func (r *Repository) ScheduleDeletion(ctx context.Context, userId int, keepReviews bool, gracePeriod time.Duration) (*Deletion, error) {
+	q := dbx.FromContext(ctx, r.db)
+	deletion := &Deletion{}
+	err := q.QueryRow(ctx, `UPDATE users SET
+			deleted_at = NOW(),
+			deletion_scheduled_at = NOW() + make_interval(secs => $3),
+			keep_reviews = $2,
+			tokens_valid_after = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING deletion_scheduled_at, keep_reviews`,
+		userId, keepReviews, gracePeriod.Seconds()).
+		Scan(&deletion.ScheduledAt, &deletion.KeepReviews)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("user", "id", userId)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return deletion, nil
+}
+
+func (r *Repository) GetUserPendingDeletion(ctx context.Context, email string) (*UserWithPassword, error) {
+	user := newUserWithPassword()
+	err := r.db.QueryRow(ctx, `SELECT id, username, email, pass_hash, role, created_at, deleted_at, bio
+		FROM users
+		WHERE email = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL`, email).
+		Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.DeletedAt, &user.Bio)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("deleted user", "email", email)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return user, nil
+}
+
+func (r *Repository) CancelDeletion(ctx context.Context, userId int) error {
+	n, err := r.db.Exec(ctx, `UPDATE users SET deleted_at = NULL, deletion_scheduled_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL`, userId)
 	if err != nil {
 		return apperrors.Internal(err)
 	}
 	if n.RowsAffected() == 0 {
-		return apperrors.NotFound("user", "id", userId)
+		return apperrors.NotFound("deleted user", "id", userId)
 	}
 	return nil
 }
 
+// anonymizeStatements erase the personal data of the user and the relations with other users.
+var anonymizeStatements = []string{
+	`UPDATE users SET
+		username = 'deleted_' || id,
+		email = 'deleted_' || id || '@deleted.invalid',
+		pass_hash = '',
+		bio = NULL,
+		display_name = NULL,
+		location = NULL,
+		avatar_id = NULL,
+		anonymized_at = NOW()
+	WHERE id = $1`,
+	`DELETE FROM user_favorite_genres WHERE user_id = $1`,
+	`DELETE FROM notifications WHERE user_id = $1`,
+	`DELETE FROM notification_preferences WHERE user_id = $1`,
+	`DELETE FROM notification_webhooks WHERE user_id = $1`,
+	`DELETE FROM follows WHERE follower_id = $1 OR followee_id = $1`,
+	`DELETE FROM watchlist_items WHERE user_id = $1`,
+	`DELETE FROM watched_movies WHERE user_id = $1`,
+	`UPDATE lists SET likes_count = likes_count - 1 WHERE id IN (SELECT list_id FROM list_likes WHERE user_id = $1)`,
+	`DELETE FROM list_likes WHERE user_id = $1`,
+	`UPDATE lists SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL`,
+}
+
+// purge is the outcome of the anonymization of the user.
+type purge struct {
+	// AvatarID is the removed avatar of the user.
+	AvatarID *string
+	// MovieIDs are the movie ids of the removed reviews, one per review.
+	MovieIDs []int
+}
+
+// Anonymize erases the personal data of the user whose deletion is due, which frees their username and email.
+// Their reviews are removed unless they chose to keep them. It returns nil if the deletion was cancelled
+// or isn't due yet. It must run in a transaction.
+func (r *Repository) Anonymize(ctx context.Context, userId int) (*purge, error) {
+	q := dbx.FromContext(ctx, r.db)
+	var (
+		keepReviews bool
+		res         purge
+	)
+	err := q.QueryRow(ctx, `SELECT keep_reviews, avatar_id FROM users
+		WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL AND deletion_scheduled_at <= NOW()
+		FOR UPDATE`, userId).
+		Scan(&keepReviews, &res.AvatarID)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, nil
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	for _, statement := range anonymizeStatements {
+		if _, err = q.Exec(ctx, statement, userId); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+	}
+	if keepReviews {
+		return &res, nil
+	}
+
+	rows, err := q.Query(ctx, `UPDATE reviews SET deleted_at = NOW()
+		WHERE user_id = $1 AND deleted_at IS NULL
+		RETURNING movie_id`, userId)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	if res.MovieIDs, err = pgx.CollectRows(rows, pgx.RowTo[int]); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return &res, nil
+}
+
+// IsTokenValid reports whether the user exists and the token issued at the time wasn't revoked.
+// Token times have a precision of a second, so a token issued in the same second as the revocation stays valid.
+func (r *Repository) IsTokenValid(ctx context.Context, userId int, issuedAt time.Time) (bool, error) {
+	var valid bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS(
+			SELECT 1 FROM users
+			WHERE id = $1 AND deleted_at IS NULL
+				AND (tokens_valid_after IS NULL OR date_trunc('second', tokens_valid_after) <= $2::TIMESTAMPTZ))`,
+		userId, issuedAt).
+		Scan(&valid)
+	if err != nil {
+		return false, apperrors.Internal(err)
+	}
+	return valid, nil
+}
+
 func (r *Repository) GetUserById(ctx context.Context, userId int) (*User, error) {
 	var user User
 	query := "SELECT id, username, email,  role, bio FROM users WHERE id = $1 AND deleted_at IS NULL  "
@@ -86,8 +220,8 @@ func (r *Repository) GetUserById(ctx context.Context, userId int) (*User, error)
 	return &user, nil
 }
 
-func (r *Repository) UpdateBio(ctx context.Context, userId int, bio string) error {
-	n, err := r.db.Exec(ctx, "UPDATE users SET bio = $1 WHERE id = $2 AND deleted_at IS NULL", bio, userId)
+func (r *Repository) UpdateRole(ctx context.Context, userId int, role string) error {
+	n, err := r.db.Exec(ctx, "UPDATE users SET role = $1 WHERE id = $2 AND deleted_at IS NULL", role, userId)
 	if err != nil {
 		return apperrors.Internal(err)
 	}
@@ -97,40 +231,181 @@ func (r *Repository) UpdateBio(ctx context.Context, userId int, bio string) erro
 	return nil
 }
 
-func (r *Repository) UpdateRole(ctx context.Context, userId int, role string) error {
-	n, err := r.db.Exec(ctx, "UPDATE users SET role = $1 WHERE id = $2 AND deleted_at IS NULL", role, userId)
+const selectProfile = `SELECT u.id, u.username, u.display_name, u.email, u.role, u.bio, u.location, u.avatar_id,
+		u.created_at, u.email_visibility, u.reviews_visibility, u.lists_visibility,
+		COALESCE((
+			SELECT json_agg(json_build_object('id', g.id, 'name', g.name) ORDER BY fg.order_no)
+			FROM user_favorite_genres fg
+			INNER JOIN genres g ON g.id = fg.genre_id
+			WHERE fg.user_id = u.id), '[]'),
+		(SELECT COUNT(*) FROM reviews r WHERE r.user_id = u.id AND r.deleted_at IS NULL),
+		(SELECT AVG(r.rating)::FLOAT8 FROM reviews r WHERE r.user_id = u.id AND r.deleted_at IS NULL)
+	FROM users u`
+
+func (r *Repository) GetProfileByID(ctx context.Context, userId int) (*Profile, error) {
+	profile, err := scanProfile(r.db.QueryRow(ctx, selectProfile+` WHERE u.id = $1 AND u.deleted_at IS NULL`, userId))
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("user", "id", userId)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return profile, nil
+}
+
+func (r *Repository) GetProfileByUsername(ctx context.Context, username string) (*Profile, error) {
+	profile, err := scanProfile(r.db.QueryRow(ctx, selectProfile+` WHERE u.username = $1 AND u.deleted_at IS NULL`, username))
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("user", "username", username)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return profile, nil
+}
+
+func (r *Repository) GetProfilesByIDs(ctx context.Context, ids []int) (map[int]*Profile, error) {
+	rows, err := r.db.Query(ctx, selectProfile+` WHERE u.id = ANY($1) AND u.deleted_at IS NULL`, ids)
 	if err != nil {
-		return apperrors.Internal(err)
+		return nil, apperrors.Internal(err)
 	}
-	if n.RowsAffected() == 0 {
-		return apperrors.NotFound("user", "id", userId)
+	defer rows.Close()
+
+	profiles := make(map[int]*Profile, len(ids))
+	for rows.Next() {
+		var profile *Profile
+		if profile, err = scanProfile(rows); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		profiles[profile.ID] = profile
 	}
-	return nil
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return profiles, nil
 }
 
-func (r *Repository) GetExistingUserByUsername(ctx context.Context, username string) (*User, error) {
-	queryString := `
-	SELECT id, username, email, role, created_at, deleted_at, bio
-	FROM users
-	WHERE username = $1 and deleted_at IS NULL;`
+func scanProfile(row pgx.Row) (*Profile, error) {
+	profile := &Profile{
+		Stats:   &Stats{},
+		Privacy: &Privacy{},
+	}
+	err := row.Scan(&profile.ID, &profile.Username, &profile.DisplayName, &profile.Email, &profile.Role, &profile.Bio,
+		&profile.Location, &profile.AvatarID, &profile.CreatedAt,
+		&profile.Privacy.Email, &profile.Privacy.Reviews, &profile.Privacy.Lists,
+		&profile.FavoriteGenres, &profile.Stats.ReviewCount, &profile.Stats.AvgRatingGiven)
+	return profile, err
+}
 
-	user := User{}
+func (r *Repository) GetPrivacy(ctx context.Context, userId int) (*Privacy, error) {
+	privacy := &Privacy{}
+	err := r.db.QueryRow(ctx, `SELECT email_visibility, reviews_visibility, lists_visibility
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL`, userId).
+		Scan(&privacy.Email, &privacy.Reviews, &privacy.Lists)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("user", "id", userId)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return privacy, nil
+}
 
-	row := r.db.QueryRow(ctx, queryString, username)
-	err := row.Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Role,
-		&user.CreatedAt,
-		&user.DeletedAt,
-		&user.Bio,
-	)
-	if dbx.IsNoRows(err) {
-		return nil, apperrors.NotFound("user", "username", username)
+func (r *Repository) IsFollower(ctx context.Context, followerID, followeeID int) (bool, error) {
+	var follows bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2)`,
+		followerID, followeeID).
+		Scan(&follows)
+	if err != nil {
+		return false, apperrors.Internal(err)
 	}
+	return follows, nil
+}
+
+// GetFollowedIDs returns which of the users are followed by the follower.
+func (r *Repository) GetFollowedIDs(ctx context.Context, followerID int, followeeIDs []int) (map[int]bool, error) {
+	rows, err := r.db.Query(ctx, `SELECT followee_id FROM follows WHERE follower_id = $1 AND followee_id = ANY($2)`,
+		followerID, followeeIDs)
 	if err != nil {
 		return nil, apperrors.Internal(err)
 	}
-	return &user, nil
+	defer rows.Close()
+
+	followed := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		followed[id] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return followed, nil
+}
+
+func (r *Repository) UpdateProfile(ctx context.Context, userId int, update *ProfileUpdate) error {
+	privacy := update.Privacy
+	if privacy == nil {
+		privacy = &Privacy{}
+	}
+	return dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := tx.Exec(ctx, `UPDATE users SET
+				bio = COALESCE($2, bio),
+				display_name = CASE WHEN $3::TEXT IS NULL THEN display_name ELSE NULLIF($3, '') END,
+				location = CASE WHEN $4::TEXT IS NULL THEN location ELSE NULLIF($4, '') END,
+				email_visibility = COALESCE(NULLIF($5, '')::visibility, email_visibility),
+				reviews_visibility = COALESCE(NULLIF($6, '')::visibility, reviews_visibility),
+				lists_visibility = COALESCE(NULLIF($7, '')::visibility, lists_visibility)
+			WHERE id = $1 AND deleted_at IS NULL`,
+			userId, update.Bio, update.DisplayName, update.Location, privacy.Email, privacy.Reviews, privacy.Lists)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			return apperrors.NotFound("user", "id", userId)
+		}
+		if update.FavoriteGenreIDs == nil {
+			return nil
+		}
+
+		if _, err = tx.Exec(ctx, `DELETE FROM user_favorite_genres WHERE user_id = $1`, userId); err != nil {
+			return apperrors.Internal(err)
+		}
+		for i, genreID := range update.FavoriteGenreIDs {
+			_, err = tx.Exec(ctx, `INSERT INTO user_favorite_genres (user_id, genre_id, order_no) VALUES ($1, $2, $3)`,
+				userId, genreID, i)
+			switch {
+			case dbx.IsForeignKeyViolation(err, "genre_id"):
+				return apperrors.NotFound("genre", "id", genreID)
+			case dbx.IsUniqueViolation(err, "user_favorite_genres_pkey"):
+				return apperrors.BadRequest(fmt.Errorf("genre %d is listed more than once", genreID))
+			case err != nil:
+				return apperrors.Internal(err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateAvatar sets the avatar of the user and returns the id of the previous one.
+func (r *Repository) UpdateAvatar(ctx context.Context, userId int, imageID string) (*string, error) {
+	var prevID *string
+	err := r.db.QueryRow(ctx, `WITH prev AS (
+			SELECT id, avatar_id FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		)
+		UPDATE users u SET avatar_id = $2
+		FROM prev
+		WHERE u.id = prev.id
+		RETURNING prev.avatar_id`, userId, imageID).
+		Scan(&prevID)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("user", "id", userId)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return prevID, nil
 }
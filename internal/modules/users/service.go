@@ -2,46 +2,276 @@ package users
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"time"
 
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
 	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jobs"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
 )
 
+// ExportsCleaner deletes the data exports of a user. It's implemented by exports.Service.
+type ExportsCleaner interface {
+	DeleteUserExports(ctx context.Context, userID int)
+}
+
 type Service struct {
-	repo *Repository
+	repo           *Repository
+	cfg            config.UsersConfig
+	scheduler      jobs.Scheduler
+	reviewsRepo    *reviews.Repository
+	imagesService  *images.Service
+	exportsCleaner ExportsCleaner
 }
 
 func (s *Service) Create(ctx context.Context, user *UserWithPassword) error {
 	return s.repo.Create(ctx, user)
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(
+	repo *Repository,
+	cfg config.UsersConfig,
+	scheduler jobs.Scheduler,
+	reviewsRepo *reviews.Repository,
+	imagesService *images.Service,
+	exportsCleaner ExportsCleaner,
+) *Service {
+	return &Service{
+		repo:           repo,
+		cfg:            cfg,
+		scheduler:      scheduler,
+		reviewsRepo:    reviewsRepo,
+		imagesService:  imagesService,
+		exportsCleaner: exportsCleaner,
+	}
 }
 
 func (s *Service) GetExistingUserWithPassword(ctx context.Context, email string) (*UserWithPassword, error) {
 	return s.repo.GetExistingUserWithPassword(ctx, email)
 }
 
-func (s *Service) Delete(ctx context.Context, userId int) error {
-	if err := s.repo.Delete(ctx, userId); err != nil {
+// Delete deactivates the account right away, revoking all its tokens, and schedules its anonymization
+// after the grace period. Until then the account can be restored.
+func (s *Service) Delete(ctx context.Context, userId int, keepReviews bool) (*Deletion, error) {
+	var deletion *Deletion
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		deletion, err = s.repo.ScheduleDeletion(ctx, userId, keepReviews, s.cfg.DeletionGracePeriod)
+		if err != nil {
+			return err
+		}
+		return s.scheduler.Schedule(ctx, PurgeJob, &PurgePayload{UserID: userId}, deletion.ScheduledAt)
+	})
+	if err != nil {
+		return nil, apperrors.EnsureInternal(err)
+	}
+	log.FromContext(ctx).Info("user deleted",
+		"userId", userId,
+		"scheduledAt", deletion.ScheduledAt,
+		"keepReviews", deletion.KeepReviews)
+	return deletion, nil
+}
+
+// GetUserPendingDeletion returns the deleted user with the email whose account can still be restored.
+func (s *Service) GetUserPendingDeletion(ctx context.Context, email string) (*UserWithPassword, error) {
+	return s.repo.GetUserPendingDeletion(ctx, email)
+}
+
+// Restore cancels the scheduled deletion of the account.
+func (s *Service) Restore(ctx context.Context, userId int) error {
+	if err := s.repo.CancelDeletion(ctx, userId); err != nil {
 		return err
 	}
-	log.FromContext(ctx).Info("user deleted", "userId", userId)
+	log.FromContext(ctx).Info("user restored", "userId", userId)
 	return nil
 }
 
-func (s *Service) Get(ctx context.Context, userId int) (user *User, err error) {
-	return s.repo.GetUserById(ctx, userId)
+// RunPurgeJob anonymizes the deleted account, deletes its data exports and recalculates the ratings of the movies
+// whose reviews were removed with it. It is the handler of PurgeJob.
+func (s *Service) RunPurgeJob(ctx context.Context, payload PurgePayload) (any, error) {
+	result := &PurgeResult{}
+	var avatarID *string
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		purged, err := s.repo.Anonymize(ctx, payload.UserID)
+		if err != nil || purged == nil {
+			return err
+		}
+		result.Purged = true
+		result.RemovedReviews = len(purged.MovieIDs)
+		avatarID = purged.AvatarID
+
+		reconciled := make(map[int]bool)
+		for _, movieID := range purged.MovieIDs {
+			if reconciled[movieID] {
+				continue
+			}
+			reconciled[movieID] = true
+			_, err = s.reviewsRepo.ReconcileMovieRating(ctx, movieID)
+			if err != nil && !apperrors.Is(err, apperrors.NotFoundCode) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.EnsureInternal(err)
+	}
+	if avatarID != nil {
+		s.imagesService.Delete(ctx, *avatarID)
+	}
+	if result.Purged {
+		s.exportsCleaner.DeleteUserExports(ctx, payload.UserID)
+		log.FromContext(ctx).Info("user purged",
+			"userId", payload.UserID,
+			"removedReviews", result.RemovedReviews)
+	}
+	return result, nil
+}
+
+// IsTokenValid reports whether the token of the user issued at the time is still valid:
+// the user exists and the token wasn't revoked.
+func (s *Service) IsTokenValid(ctx context.Context, userId int, issuedAt time.Time) (bool, error) {
+	return s.repo.IsTokenValid(ctx, userId, issuedAt)
+}
+
+// GetProfile returns the profile of the user with the parts that the viewer is allowed to see.
+func (s *Service) GetProfile(ctx context.Context, userId int, viewer *Viewer) (*Profile, error) {
+	profile, err := s.repo.GetProfileByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyPrivacy(ctx, profile, viewer)
+}
+
+func (s *Service) GetProfileByUsername(ctx context.Context, username string, viewer *Viewer) (*Profile, error) {
+	profile, err := s.repo.GetProfileByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyPrivacy(ctx, profile, viewer)
+}
+
+// GetProfiles returns the existing profiles among the ids, as GetProfile does, in a few queries.
+func (s *Service) GetProfiles(ctx context.Context, ids []int, viewer *Viewer) (map[int]*Profile, error) {
+	profiles, err := s.repo.GetProfilesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	var followed map[int]bool
+	if viewer != nil && !viewer.IsAdmin {
+		if followed, err = s.repo.GetFollowedIDs(ctx, viewer.UserID, ids); err != nil {
+			return nil, err
+		}
+	}
+	for id, profile := range profiles {
+		rel := relationNone
+		switch {
+		case viewer != nil && (viewer.IsAdmin || viewer.UserID == id):
+			rel = relationSelf
+		case followed[id]:
+			rel = relationFollower
+		}
+		s.hidePrivate(profile, rel)
+	}
+	return profiles, nil
+}
+
+func (s *Service) applyPrivacy(ctx context.Context, profile *Profile, viewer *Viewer) (*Profile, error) {
+	rel, err := s.relation(ctx, profile.ID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	s.hidePrivate(profile, rel)
+	return profile, nil
 }
 
-func (s *Service) UpdateBio(ctx context.Context, userId int, bio string) error {
-	if err := s.repo.UpdateBio(ctx, userId, bio); err != nil {
+// hidePrivate removes the parts of the profile the viewer with the relation can't see.
+func (s *Service) hidePrivate(profile *Profile, rel relation) {
+	profile.Avatar = s.imagesService.GetImage(profile.AvatarID)
+	if !rel.canSee(profile.Privacy.Email) {
+		profile.Email = ""
+	}
+	if !rel.canSee(profile.Privacy.Reviews) {
+		profile.Stats = nil
+	}
+	if rel != relationSelf {
+		profile.Privacy = nil
+	}
+}
+
+// CheckVisible returns a forbidden error if the privacy settings of the user hide the section from the viewer.
+func (s *Service) CheckVisible(ctx context.Context, userId int, section string, viewer *Viewer) error {
+	privacy, err := s.repo.GetPrivacy(ctx, userId)
+	if err != nil {
+		return err
+	}
+	visibility := privacy.Reviews
+	if section == SectionLists {
+		visibility = privacy.Lists
+	}
+	if visibility == VisibilityPublic {
+		return nil
+	}
+
+	rel, err := s.relation(ctx, userId, viewer)
+	if err != nil {
 		return err
 	}
-	log.FromContext(ctx).Info("user bio updated", "userId", userId, "bio", bio)
+	if !rel.canSee(visibility) {
+		return apperrors.Forbidden(fmt.Sprintf("%s of the user are not visible", section))
+	}
 	return nil
 }
 
+func (s *Service) relation(ctx context.Context, userId int, viewer *Viewer) (relation, error) {
+	switch {
+	case viewer == nil:
+		return relationNone, nil
+	case viewer.IsAdmin || viewer.UserID == userId:
+		return relationSelf, nil
+	}
+	follows, err := s.repo.IsFollower(ctx, viewer.UserID, userId)
+	if err != nil || !follows {
+		return relationNone, err
+	}
+	return relationFollower, nil
+}
+
+func (s *Service) UpdateProfile(ctx context.Context, userId int, update *ProfileUpdate) error {
+	if err := s.repo.UpdateProfile(ctx, userId, update); err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	log.FromContext(ctx).Info("user profile updated", "userId", userId)
+	return nil
+}
+
+// UploadAvatar stores the image as the avatar of the user replacing the previous one.
+func (s *Service) UploadAvatar(ctx context.Context, userId int, r io.Reader) (*images.Image, error) {
+	if _, err := s.repo.GetUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+	img, err := s.imagesService.Upload(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	prevID, err := s.repo.UpdateAvatar(ctx, userId, img.ID)
+	if err != nil {
+		s.imagesService.Delete(ctx, img.ID)
+		return nil, err
+	}
+	if prevID != nil {
+		s.imagesService.Delete(ctx, *prevID)
+	}
+	log.FromContext(ctx).Info("user avatar uploaded",
+		"userId", userId,
+		"imageId", img.ID)
+	return img, nil
+}
+
 func (s *Service) UpdateRole(ctx context.Context, userId int, role string) error {
 	if err := s.repo.UpdateRole(ctx, userId, role); err != nil {
 		return err
@@ -49,7 +279,3 @@ func (s *Service) UpdateRole(ctx context.Context, userId int, role string) error
 	log.FromContext(ctx).Info("user role updated", "userId", userId, "role", role)
 	return nil
 }
-
-func (s *Service) GetExistingUserByUsername(ctx context.Context, username string) (*User, error) {
-	return s.repo.GetExistingUserByUsername(ctx, username)
-}
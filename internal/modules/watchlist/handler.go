@@ -0,0 +1,128 @@
+package watchlist
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) GetWatchlist(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetWatchlistRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	items, total, err := h.service.GetItemsPaginated(c.Request().Context(), req.UserID, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, items))
+}
+
+func (h *Handler) AddToWatchlist(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.AddToWatchlistRequest](c)
+	if err != nil {
+		return err
+	}
+	item := &Item{
+		UserID:   req.UserID,
+		Movie:    Movie{ID: req.MovieID},
+		Notes:    req.Notes,
+		Priority: req.Priority,
+	}
+	if err = h.service.AddItem(c.Request().Context(), item); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+func (h *Handler) UpdateWatchlistItem(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UpdateWatchlistItemRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.UpdateItem(c.Request().Context(), req.UserID, req.MovieID, req.Notes, req.Priority); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) DeleteWatchlistItem(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DeleteWatchlistItemRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.DeleteItem(c.Request().Context(), req.UserID, req.MovieID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) ReorderWatchlist(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.ReorderWatchlistRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.ReorderItems(c.Request().Context(), req.UserID, req.MovieIDs); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) GetWatched(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetWatchedRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	entries, total, err := h.service.GetWatchedPaginated(c.Request().Context(), req.UserID, req.MovieID, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, entries))
+}
+
+func (h *Handler) AddWatched(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.AddWatchedRequest](c)
+	if err != nil {
+		return err
+	}
+	entry := &WatchedEntry{
+		UserID:    req.UserID,
+		Movie:     Movie{ID: req.MovieID},
+		WatchedOn: req.WatchedOn,
+	}
+	if err = h.service.AddWatched(c.Request().Context(), entry); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func (h *Handler) DeleteWatched(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DeleteWatchedRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.DeleteWatched(c.Request().Context(), req.UserID, req.EntryID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,32 @@
+package watchlist
+
+import "time"
+
+type Movie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+}
+
+type Item struct {
+	UserID   int       `json:"user_id"`
+	Movie    Movie     `json:"movie"`
+	Notes    *string   `json:"notes,omitempty"`
+	Priority *int      `json:"priority,omitempty"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+type WatchedEntry struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Movie     Movie     `json:"movie"`
+	WatchedOn time.Time `json:"watched_on"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MovieFlags describes the relation between a movie and the user that is looking at it.
+type MovieFlags struct {
+	Watchlisted bool `json:"watchlisted"`
+	Watched     bool `json:"watched"`
+}
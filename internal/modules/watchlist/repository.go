@@ -0,0 +1,273 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) AddItem(ctx context.Context, item *Item) error {
+	// New items are appended to the end of the watchlist
+	err := r.db.QueryRow(ctx, `WITH inserted AS (
+			INSERT INTO watchlist_items (user_id, movie_id, notes, priority, order_no)
+			SELECT $1, m.id, $3, $4, (SELECT COALESCE(MAX(order_no) + 1, 0) FROM watchlist_items WHERE user_id = $1)
+			FROM movies m
+			WHERE m.id = $2 AND m.deleted_at IS NULL
+			RETURNING movie_id, added_at
+		)
+		SELECT i.added_at, m.title, m.release_date, m.avg_rating
+		FROM inserted i
+		INNER JOIN movies m ON m.id = i.movie_id`,
+		item.UserID, item.Movie.ID, item.Notes, item.Priority).
+		Scan(&item.AddedAt, &item.Movie.Title, &item.Movie.ReleaseDate, &item.Movie.AvgRating)
+	switch {
+	case dbx.IsNoRows(err):
+		return apperrors.NotFound("movie", "id", item.Movie.ID)
+	case dbx.IsUniqueViolation(err, "pkey"):
+		return apperrors.AlreadyExists("watchlist item", "movie_id", item.Movie.ID)
+	case dbx.IsForeignKeyViolation(err, "user_id"):
+		return apperrors.NotFound("user", "id", item.UserID)
+	case err != nil:
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetItemsPaginated(ctx context.Context, userID int, offset int, limit int) ([]*Item, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select("w.user_id, m.id, m.title, m.release_date, m.avg_rating, w.notes, w.priority, w.added_at").
+		From("watchlist_items w").
+		Join("movies m ON m.id = w.movie_id").
+		Where("w.user_id = ?", userID).
+		Where("m.deleted_at IS NULL").
+		OrderBy("w.order_no", "w.added_at").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("watchlist_items w").
+		Join("movies m ON m.id = w.movie_id").
+		Where("w.user_id = ?", userID).
+		Where("m.deleted_at IS NULL")
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var items []*Item
+	for rows.Next() {
+		var item Item
+		if err = rows.Scan(
+			&item.UserID,
+			&item.Movie.ID,
+			&item.Movie.Title,
+			&item.Movie.ReleaseDate,
+			&item.Movie.AvgRating,
+			&item.Notes,
+			&item.Priority,
+			&item.AddedAt); err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		items = append(items, &item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return items, total, nil
+}
+
+func (r *Repository) UpdateItem(ctx context.Context, userID, movieID int, notes *string, priority *int) error {
+	n, err := r.db.Exec(ctx, `UPDATE watchlist_items SET notes = $1, priority = $2 WHERE user_id = $3 AND movie_id = $4`,
+		notes, priority, userID, movieID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("watchlist item", "movie_id", movieID)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteItem(ctx context.Context, userID, movieID int) error {
+	n, err := r.db.Exec(ctx, `DELETE FROM watchlist_items WHERE user_id = $1 AND movie_id = $2`, userID, movieID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("watchlist item", "movie_id", movieID)
+	}
+	return nil
+}
+
+// ReorderItems sets the watchlist order to the order of movieIDs.
+// movieIDs must contain every movie of the watchlist exactly once.
+func (r *Repository) ReorderItems(ctx context.Context, userID int, movieIDs []int) error {
+	errMismatch := apperrors.BadRequest(errors.New("movie_ids must contain every watchlist movie exactly once"))
+
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		var total int
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM watchlist_items WHERE user_id = $1`, userID).Scan(&total); err != nil {
+			return apperrors.Internal(err)
+		}
+		if total != len(movieIDs) {
+			return errMismatch
+		}
+
+		n, err := tx.Exec(ctx, `UPDATE watchlist_items w SET order_no = o.order_no - 1
+			FROM unnest($2::INTEGER[]) WITH ORDINALITY AS o(movie_id, order_no)
+			WHERE w.user_id = $1 AND w.movie_id = o.movie_id`,
+			userID, movieIDs)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() != int64(len(movieIDs)) {
+			return errMismatch
+		}
+		return nil
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+	return nil
+}
+
+func (r *Repository) AddWatched(ctx context.Context, entry *WatchedEntry) error {
+	err := r.db.QueryRow(ctx, `WITH inserted AS (
+			INSERT INTO watched_movies (user_id, movie_id, watched_on)
+			SELECT $1, m.id, $3
+			FROM movies m
+			WHERE m.id = $2 AND m.deleted_at IS NULL
+			RETURNING id, movie_id, created_at
+		)
+		SELECT i.id, i.created_at, m.title, m.release_date, m.avg_rating
+		FROM inserted i
+		INNER JOIN movies m ON m.id = i.movie_id`,
+		entry.UserID, entry.Movie.ID, entry.WatchedOn).
+		Scan(&entry.ID, &entry.CreatedAt, &entry.Movie.Title, &entry.Movie.ReleaseDate, &entry.Movie.AvgRating)
+	switch {
+	case dbx.IsNoRows(err):
+		return apperrors.NotFound("movie", "id", entry.Movie.ID)
+	case dbx.IsForeignKeyViolation(err, "user_id"):
+		return apperrors.NotFound("user", "id", entry.UserID)
+	case err != nil:
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetWatchedPaginated(ctx context.Context, userID int, movieID *int, offset int, limit int) ([]*WatchedEntry, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select("w.id, w.user_id, m.id, m.title, m.release_date, m.avg_rating, w.watched_on, w.created_at").
+		From("watched_movies w").
+		Join("movies m ON m.id = w.movie_id").
+		Where("w.user_id = ?", userID).
+		Where("m.deleted_at IS NULL").
+		OrderBy("w.watched_on DESC", "w.id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	queryTotal := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("watched_movies w").
+		Join("movies m ON m.id = w.movie_id").
+		Where("w.user_id = ?", userID).
+		Where("m.deleted_at IS NULL")
+	if movieID != nil {
+		selectQuery = selectQuery.Where("w.movie_id = ?", *movieID)
+		queryTotal = queryTotal.Where("w.movie_id = ?", *movieID)
+	}
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, queryTotal); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var entries []*WatchedEntry
+	for rows.Next() {
+		var entry WatchedEntry
+		if err = rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Movie.ID,
+			&entry.Movie.Title,
+			&entry.Movie.ReleaseDate,
+			&entry.Movie.AvgRating,
+			&entry.WatchedOn,
+			&entry.CreatedAt); err != nil {
+			return nil, 0, apperrors.Internal(err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return entries, total, nil
+}
+
+func (r *Repository) DeleteWatched(ctx context.Context, userID, entryID int) error {
+	n, err := r.db.Exec(ctx, `DELETE FROM watched_movies WHERE user_id = $1 AND id = $2`, userID, entryID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("watched entry", "id", entryID)
+	}
+	return nil
+}
+
+func (r *Repository) GetMovieFlags(ctx context.Context, userID, movieID int) (*MovieFlags, error) {
+	var flags MovieFlags
+	err := r.db.QueryRow(ctx, `SELECT
+			EXISTS(SELECT 1 FROM watchlist_items WHERE user_id = $1 AND movie_id = $2),
+			EXISTS(SELECT 1 FROM watched_movies WHERE user_id = $1 AND movie_id = $2)`,
+		userID, movieID).
+		Scan(&flags.Watchlisted, &flags.Watched)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return &flags, nil
+}
@@ -0,0 +1,90 @@
+package watchlist
+
+import (
+	"context"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) AddItem(ctx context.Context, item *Item) error {
+	if err := s.repo.AddItem(ctx, item); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("movie added to watchlist",
+		"userId", item.UserID,
+		"movieId", item.Movie.ID)
+	return nil
+}
+
+func (s *Service) GetItemsPaginated(ctx context.Context, userID int, offset int, limit int) ([]*Item, int, error) {
+	return s.repo.GetItemsPaginated(ctx, userID, offset, limit)
+}
+
+func (s *Service) UpdateItem(ctx context.Context, userID, movieID int, notes *string, priority *int) error {
+	if err := s.repo.UpdateItem(ctx, userID, movieID, notes, priority); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("watchlist item updated",
+		"userId", userID,
+		"movieId", movieID)
+	return nil
+}
+
+func (s *Service) DeleteItem(ctx context.Context, userID, movieID int) error {
+	if err := s.repo.DeleteItem(ctx, userID, movieID); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("movie removed from watchlist",
+		"userId", userID,
+		"movieId", movieID)
+	return nil
+}
+
+func (s *Service) ReorderItems(ctx context.Context, userID int, movieIDs []int) error {
+	if err := s.repo.ReorderItems(ctx, userID, movieIDs); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("watchlist reordered",
+		"userId", userID)
+	return nil
+}
+
+func (s *Service) AddWatched(ctx context.Context, entry *WatchedEntry) error {
+	if entry.WatchedOn.IsZero() {
+		entry.WatchedOn = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	if err := s.repo.AddWatched(ctx, entry); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("movie marked as watched",
+		"userId", entry.UserID,
+		"movieId", entry.Movie.ID)
+	return nil
+}
+
+func (s *Service) GetWatchedPaginated(ctx context.Context, userID int, movieID *int, offset int, limit int) ([]*WatchedEntry, int, error) {
+	return s.repo.GetWatchedPaginated(ctx, userID, movieID, offset, limit)
+}
+
+func (s *Service) DeleteWatched(ctx context.Context, userID, entryID int) error {
+	if err := s.repo.DeleteWatched(ctx, userID, entryID); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("watched entry deleted",
+		"userId", userID,
+		"entryId", entryID)
+	return nil
+}
+
+func (s *Service) GetMovieFlags(ctx context.Context, userID, movieID int) (*MovieFlags, error) {
+	return s.repo.GetMovieFlags(ctx, userID, movieID)
+}
@@ -0,0 +1,160 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/echox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
+	"github.com/RadkevichAnn/movie-reviews/internal/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) CreateWebhook(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.CreateWebhookRequest](c)
+	if err != nil {
+		return err
+	}
+	userID := jwt.GetClaims(c).UserID
+	sub := &Subscription{
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		IsActive:    true,
+		CreatedBy:   &userID,
+	}
+	if sub.EventTypes == nil {
+		sub.EventTypes = []string{}
+	}
+	if err = h.service.CreateSubscription(c.Request().Context(), sub); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, sub)
+}
+
+func (h *Handler) GetWebhooks(c echo.Context) error {
+	subs, err := h.service.GetSubscriptions(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, subs)
+}
+
+func (h *Handler) GetWebhookByID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetWebhookRequest](c)
+	if err != nil {
+		return err
+	}
+	sub, err := h.service.GetSubscriptionByID(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, sub)
+}
+
+func (h *Handler) UpdateWebhook(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UpdateWebhookRequest](c)
+	if err != nil {
+		return err
+	}
+	sub, err := h.service.UpdateSubscription(c.Request().Context(), req.ID, &SubscriptionUpdate{
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, sub)
+}
+
+func (h *Handler) DeleteWebhook(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetWebhookRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.service.DeleteSubscription(c.Request().Context(), req.ID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) PingWebhook(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetWebhookRequest](c)
+	if err != nil {
+		return err
+	}
+	delivery, err := h.service.Ping(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
+
+func (h *Handler) GetDeliveries(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetWebhookDeliveriesRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	deliveries, total, err := h.service.GetDeliveriesPaginated(c.Request().Context(), &req.ID, req.Status, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, deliveries))
+}
+
+// GetFailedDeliveries returns the dead letters, the deliveries of all webhooks that ran out of attempts.
+func (h *Handler) GetFailedDeliveries(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetFailedWebhookDeliveriesRequest](c)
+	if err != nil {
+		return err
+	}
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
+	status := StatusFailed
+	deliveries, total, err := h.service.GetDeliveriesPaginated(c.Request().Context(), nil, &status, offset, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, deliveries))
+}
+
+func (h *Handler) GetDeliveryByID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetWebhookDeliveryRequest](c)
+	if err != nil {
+		return err
+	}
+	delivery, err := h.service.GetDeliveryByID(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
+
+func (h *Handler) Redeliver(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetWebhookDeliveryRequest](c)
+	if err != nil {
+		return err
+	}
+	delivery, err := h.service.Redeliver(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusAccepted, delivery)
+}
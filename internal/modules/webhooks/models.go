@@ -0,0 +1,101 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+)
+
+// EventPing is only sent by the test ping of a subscription.
+const EventPing = "ping"
+
+// EventTypes are all the event types subscriptions can filter on. They are the
+// catalog events recorded to the outbox, named <subject>.<action>.
+var EventTypes = []string{
+	movies.EventCreated, movies.EventUpdated, movies.EventDeleted,
+	stars.EventCreated, stars.EventUpdated, stars.EventDeleted,
+	genres.EventCreated, genres.EventUpdated, genres.EventDeleted,
+	reviews.EventCreated, reviews.EventUpdated, reviews.EventDeleted,
+}
+
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Headers of the delivery requests.
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Subscription delivers the events of EventTypes, or all the events if it's empty, to URL.
+// The request bodies are signed with Secret.
+type Subscription struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret,omitempty"`
+	EventTypes  []string  `json:"event_types"`
+	Description *string   `json:"description,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedBy   *int      `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SubscriptionUpdate holds the changed fields of a subscription, nil fields are kept.
+type SubscriptionUpdate struct {
+	URL         *string
+	EventTypes  []string
+	Description *string
+	IsActive    *bool
+}
+
+// Event is the body of the delivery requests.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// Delivery is an event to be delivered to a subscription. It's attempted until it succeeds or
+// MaxAttempts is reached, when it fails and is kept as a dead letter.
+type Delivery struct {
+	ID             int             `json:"id"`
+	SubscriptionID int             `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	MaxAttempts    int             `json:"max_attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	FinishedAt     *time.Time      `json:"finished_at,omitempty"`
+	// Log holds the attempts of the delivery. It's only set when a single delivery is requested.
+	Log []*Attempt `json:"log,omitempty"`
+}
+
+// Attempt is the outcome of a single delivery request.
+type Attempt struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      *string   `json:"error,omitempty"`
+	DurationMs int       `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Target is where a claimed delivery is sent.
+type Target struct {
+	URL    string
+	Secret string
+}
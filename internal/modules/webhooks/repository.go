@@ -0,0 +1,319 @@
+package webhooks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	selectSubscriptionColumns = `id, url, secret, event_types, description, is_active, created_by, created_at, updated_at`
+	selectDeliveryColumns     = `id, subscription_id, event_id, event_type, payload, status, attempts, max_attempts,
+		next_attempt_at, last_status_code, last_error, created_at, finished_at`
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	err := r.db.QueryRow(ctx, `INSERT INTO webhook_subscriptions (url, secret, event_types, description, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`,
+		sub.URL, sub.Secret, sub.EventTypes, sub.Description, sub.IsActive, sub.CreatedBy).
+		Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetSubscriptions(ctx context.Context) ([]*Subscription, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+selectSubscriptionColumns+` FROM webhook_subscriptions ORDER BY id`)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	subs, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[Subscription])
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return subs, nil
+}
+
+func (r *Repository) GetSubscriptionByID(ctx context.Context, id int) (*Subscription, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+selectSubscriptionColumns+` FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	sub, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByPos[Subscription])
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("webhook", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return sub, nil
+}
+
+func (r *Repository) UpdateSubscription(ctx context.Context, id int, update *SubscriptionUpdate) (*Subscription, error) {
+	rows, err := r.db.Query(ctx, `UPDATE webhook_subscriptions SET
+			url = COALESCE($2, url),
+			event_types = COALESCE($3, event_types),
+			description = COALESCE($4, description),
+			is_active = COALESCE($5, is_active),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+selectSubscriptionColumns,
+		id, update.URL, update.EventTypes, update.Description, update.IsActive)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	sub, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByPos[Subscription])
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("webhook", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return sub, nil
+}
+
+func (r *Repository) DeleteSubscription(ctx context.Context, id int) error {
+	n, err := r.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("webhook", "id", id)
+	}
+	return nil
+}
+
+// CreateDeliveries queues the delivery of the event to every active subscription interested in its type,
+// unless the event is already queued for the subscription.
+func (r *Repository) CreateDeliveries(ctx context.Context, event *Event, payload []byte, maxAttempts int) (int, error) {
+	n, err := r.db.Exec(ctx, `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, max_attempts)
+		SELECT id, $1::UUID, $2::VARCHAR, $3::JSONB, $4::INTEGER
+		FROM webhook_subscriptions
+		WHERE is_active AND (event_types = '{}' OR $2::VARCHAR = ANY(event_types))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		event.ID, event.Type, payload, maxAttempts)
+	if err != nil {
+		return 0, apperrors.Internal(err)
+	}
+	return int(n.RowsAffected()), nil
+}
+
+// CreateClaimedDelivery creates the delivery of the event to the subscription claimed for its first attempt,
+// so that the caller makes the attempt right away.
+func (r *Repository) CreateClaimedDelivery(ctx context.Context, subscriptionID int, event *Event, payload []byte, maxAttempts int, lockTimeout time.Duration) (*Delivery, error) {
+	rows, err := r.db.Query(ctx, `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, max_attempts,
+			attempts, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, 1, NOW() + make_interval(secs => $6))
+		RETURNING `+selectDeliveryColumns,
+		subscriptionID, event.ID, event.Type, payload, maxAttempts, lockTimeout.Seconds())
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	delivery, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByPos[Delivery])
+	switch {
+	case dbx.IsForeignKeyViolation(err, "subscription_id"):
+		return nil, apperrors.NotFound("webhook", "id", subscriptionID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return delivery, nil
+}
+
+// ClaimDeliveries claims up to limit due pending deliveries of the active subscriptions, counting their attempt.
+// A claimed delivery is postponed by the lock timeout, so that it's claimed again if its outcome isn't stored.
+func (r *Repository) ClaimDeliveries(ctx context.Context, limit int, lockTimeout time.Duration) ([]*Delivery, map[int]*Target, error) {
+	var (
+		deliveries []*Delivery
+		targets    = make(map[int]*Target)
+	)
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `UPDATE webhook_deliveries
+			SET attempts = attempts + 1,
+				next_attempt_at = NOW() + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d
+				INNER JOIN webhook_subscriptions s ON s.id = d.subscription_id
+				WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND s.is_active
+				ORDER BY d.next_attempt_at, d.id
+				LIMIT $1
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING `+selectDeliveryColumns,
+			limit, lockTimeout.Seconds())
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		deliveries, err = pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[Delivery])
+		if err != nil || len(deliveries) == 0 {
+			return apperrors.EnsureInternal(err)
+		}
+		// RETURNING doesn't keep the order of the subquery, the deliveries are made in the order of the events
+		sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+
+		ids := make([]int, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.SubscriptionID)
+		}
+		rows, err = tx.Query(ctx, `SELECT id, url, secret FROM webhook_subscriptions WHERE id = ANY($1)`, ids)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		var (
+			id     int
+			target Target
+		)
+		_, err = pgx.ForEachRow(rows, []any{&id, &target.URL, &target.Secret}, func() error {
+			t := target
+			targets[id] = &t
+			return nil
+		})
+		return apperrors.EnsureInternal(err)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return deliveries, targets, nil
+}
+
+// RecordAttempt logs the attempt of the claimed delivery and stores its outcome. A failed delivery is retried
+// after the delay, unless retry is false. It does nothing if the delivery was claimed again meanwhile.
+func (r *Repository) RecordAttempt(ctx context.Context, delivery *Delivery, attempt *Attempt, succeeded, retry bool, delay time.Duration) error {
+	status := StatusPending
+	switch {
+	case succeeded:
+		status = StatusSucceeded
+	case !retry:
+		status = StatusFailed
+	}
+	return dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := tx.Exec(ctx, `UPDATE webhook_deliveries SET
+				status = $3::TEXT::webhook_delivery_status,
+				last_status_code = $4,
+				last_error = $5,
+				next_attempt_at = CASE WHEN $3::TEXT = 'pending' THEN NOW() + make_interval(secs => $6) ELSE next_attempt_at END,
+				finished_at = CASE WHEN $3::TEXT = 'pending' THEN NULL ELSE NOW() END
+			WHERE id = $1 AND attempts = $2 AND status = 'pending'`,
+			delivery.ID, delivery.Attempts, status, attempt.StatusCode, attempt.Error, delay.Seconds())
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			return nil
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms)
+			VALUES ($1, $2, $3, $4, $5)`,
+			delivery.ID, delivery.Attempts, attempt.StatusCode, attempt.Error, attempt.DurationMs)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		delivery.Status = status
+		return nil
+	})
+}
+
+// GetDeliveriesPaginated returns the deliveries of the subscription, or of all subscriptions if it's nil,
+// optionally filtered by status, newest first.
+func (r *Repository) GetDeliveriesPaginated(ctx context.Context, subscriptionID *int, status *string, offset int, limit int) ([]*Delivery, int, error) {
+	selectQuery := dbx.StatementBuilder.
+		Select(selectDeliveryColumns).
+		From("webhook_deliveries").
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+	countQuery := dbx.StatementBuilder.
+		Select("COUNT(*)").
+		From("webhook_deliveries")
+	if subscriptionID != nil {
+		selectQuery = selectQuery.Where("subscription_id = ?", *subscriptionID)
+		countQuery = countQuery.Where("subscription_id = ?", *subscriptionID)
+	}
+	if status != nil {
+		selectQuery = selectQuery.Where("status::TEXT = ?", *status)
+		countQuery = countQuery.Where("status::TEXT = ?", *status)
+	}
+
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	deliveries, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[Delivery])
+	if err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	var total int
+	if err = br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, apperrors.Internal(err)
+	}
+	return deliveries, total, nil
+}
+
+// GetDeliveryByID returns the delivery with the log of its attempts.
+func (r *Repository) GetDeliveryByID(ctx context.Context, id int) (*Delivery, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+selectDeliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	delivery, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByPos[Delivery])
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("delivery", "id", id)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	rows, err = r.db.Query(ctx, `SELECT attempt, status_code, error, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempt`, id)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	delivery.Log, err = pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[Attempt])
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	return delivery, nil
+}
+
+// Redeliver queues the failed delivery again with a new round of attempts.
+func (r *Repository) Redeliver(ctx context.Context, id int, maxAttempts int) error {
+	n, err := r.db.Exec(ctx, `UPDATE webhook_deliveries SET
+			status = 'pending',
+			max_attempts = attempts + $2,
+			next_attempt_at = NOW(),
+			finished_at = NULL
+		WHERE id = $1 AND status = 'failed'`, id, maxAttempts)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("failed delivery", "id", id)
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/config"
+	"github.com/RadkevichAnn/movie-reviews/internal/log"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
+	"github.com/RadkevichAnn/movie-reviews/internal/signing"
+)
+
+type Service struct {
+	repo   *Repository
+	cfg    config.WebhooksConfig
+	client *http.Client
+}
+
+func NewService(repo *Repository, cfg config.WebhooksConfig) *Service {
+	return &Service{
+		repo:   repo,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.RequestTimeout},
+	}
+}
+
+func (s *Service) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	if err := validateSubscription(&sub.URL, &sub.EventTypes); err != nil {
+		return err
+	}
+	secret, err := signing.NewSecret()
+	if err != nil {
+		return err
+	}
+	sub.Secret = secret
+	if err = s.repo.CreateSubscription(ctx, sub); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("webhook created",
+		"webhookId", sub.ID,
+		"url", sub.URL)
+	return nil
+}
+
+// GetSubscriptions returns the subscriptions without their secrets, which are only returned on creation.
+func (s *Service) GetSubscriptions(ctx context.Context) ([]*Subscription, error) {
+	subs, err := s.repo.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+func (s *Service) GetSubscriptionByID(ctx context.Context, id int) (*Subscription, error) {
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+func (s *Service) UpdateSubscription(ctx context.Context, id int, update *SubscriptionUpdate) (*Subscription, error) {
+	if update.URL != nil {
+		if err := validateURL(update.URL); err != nil {
+			return nil, err
+		}
+	}
+	if update.EventTypes != nil {
+		if err := validateEventTypes(&update.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	sub, err := s.repo.UpdateSubscription(ctx, id, update)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	log.FromContext(ctx).Info("webhook updated",
+		"webhookId", id)
+	return sub, nil
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, id int) error {
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("webhook deleted",
+		"webhookId", id)
+	return nil
+}
+
+// Name and Handle make the service a sink of the outbox, which queues the deliveries of the catalog events.
+func (s *Service) Name() string {
+	return "webhooks"
+}
+
+// Handle queues the delivery of the catalog event to the subscriptions interested in it. The outbox event id
+// is kept, so that an event relayed again is only delivered once to every subscription.
+func (s *Service) Handle(ctx context.Context, event *outbox.Event) error {
+	if !isEventType(event.Type) {
+		return nil
+	}
+	webhookEvent := &Event{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Payload,
+	}
+	payload, err := json.Marshal(webhookEvent)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	_, err = s.repo.CreateDeliveries(ctx, webhookEvent, payload, s.cfg.MaxAttempts)
+	return err
+}
+
+// Ping sends a test event to the subscription right away and returns the delivery with its outcome.
+// It isn't retried.
+func (s *Service) Ping(ctx context.Context, id int) (*Delivery, error) {
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	event := newEvent(EventPing, map[string]int{"webhook_id": id})
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	delivery, err := s.repo.CreateClaimedDelivery(ctx, id, event, payload, 1, s.cfg.LockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.deliver(ctx, delivery, &Target{URL: sub.URL, Secret: sub.Secret}); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDeliveryByID(ctx, delivery.ID)
+}
+
+func (s *Service) GetDeliveriesPaginated(ctx context.Context, subscriptionID *int, status *string, offset int, limit int) ([]*Delivery, int, error) {
+	if status != nil && *status != StatusPending && *status != StatusSucceeded && *status != StatusFailed {
+		return nil, 0, apperrors.BadRequest(fmt.Errorf("unknown delivery status %q", *status))
+	}
+	if subscriptionID != nil {
+		if _, err := s.repo.GetSubscriptionByID(ctx, *subscriptionID); err != nil {
+			return nil, 0, err
+		}
+	}
+	return s.repo.GetDeliveriesPaginated(ctx, subscriptionID, status, offset, limit)
+}
+
+func (s *Service) GetDeliveryByID(ctx context.Context, id int) (*Delivery, error) {
+	return s.repo.GetDeliveryByID(ctx, id)
+}
+
+// Redeliver queues the failed delivery again for a full round of attempts.
+func (s *Service) Redeliver(ctx context.Context, id int) (*Delivery, error) {
+	if err := s.repo.Redeliver(ctx, id, s.cfg.MaxAttempts); err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("webhook delivery queued again",
+		"deliveryId", id)
+	return s.repo.GetDeliveryByID(ctx, id)
+}
+
+// RunDispatcher delivers the due deliveries every poll interval until ctx is done.
+func (s *Service) RunDispatcher(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.dispatch(ctx); err != nil && ctx.Err() == nil {
+				log.FromContext(ctx).Error("dispatch webhook deliveries", "err", err)
+			}
+		}
+	}
+}
+
+// dispatch delivers the due deliveries batch by batch until there are none left. The deliveries of a batch
+// are made concurrently, so that a batch takes at most the request timeout to deliver, within the lock timeout.
+func (s *Service) dispatch(ctx context.Context) error {
+	for {
+		deliveries, targets, err := s.repo.ClaimDeliveries(ctx, s.cfg.BatchSize, s.cfg.LockTimeout)
+		if err != nil {
+			return err
+		}
+		var group errgroup.Group
+		for _, delivery := range deliveries {
+			delivery := delivery
+			group.Go(func() error {
+				return s.deliver(ctx, delivery, targets[delivery.SubscriptionID])
+			})
+		}
+		if err = group.Wait(); err != nil {
+			return err
+		}
+		if len(deliveries) < s.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// deliver makes an attempt of the claimed delivery and stores its outcome.
+func (s *Service) deliver(ctx context.Context, delivery *Delivery, target *Target) error {
+	start := time.Now()
+	statusCode, sendErr := s.send(ctx, delivery, target)
+	attempt := &Attempt{
+		Attempt:    delivery.Attempts,
+		DurationMs: int(time.Since(start).Milliseconds()),
+	}
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+	}
+	if sendErr != nil {
+		msg := sendErr.Error()
+		attempt.Error = &msg
+	}
+
+	retry := delivery.Attempts < delivery.MaxAttempts
+	delay := s.cfg.RetryBackoff * time.Duration(1<<(delivery.Attempts-1))
+	if err := s.repo.RecordAttempt(ctx, delivery, attempt, sendErr == nil, retry, delay); err != nil {
+		return err
+	}
+
+	logger := log.FromContext(ctx).With(
+		"deliveryId", delivery.ID,
+		"webhookId", delivery.SubscriptionID,
+		"type", delivery.EventType,
+		"attempt", delivery.Attempts)
+	switch {
+	case sendErr == nil:
+		logger.Info("webhook delivered")
+	case retry:
+		logger.Warn("webhook delivery failed, retrying", "err", sendErr, "delay", delay)
+	default:
+		logger.Error("webhook delivery failed", "err", sendErr)
+	}
+	return nil
+}
+
+// send posts the payload of the delivery signed with the secret of the target.
+// It returns the response status code, 0 if there was no response.
+func (s *Service) send(ctx context.Context, delivery *Delivery, target *Target) (int, error) {
+	if target == nil {
+		return 0, fmt.Errorf("webhook %d not found", delivery.SubscriptionID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.EventID)
+	req.Header.Set(SignatureHeader, "sha256="+signing.Sign(target.Secret, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func newEvent(eventType string, data any) *Event {
+	return &Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+func validateSubscription(rawURL *string, eventTypes *[]string) error {
+	if err := validateURL(rawURL); err != nil {
+		return err
+	}
+	return validateEventTypes(eventTypes)
+}
+
+func validateURL(rawURL *string) error {
+	u, err := url.Parse(*rawURL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apperrors.BadRequest(fmt.Errorf("invalid webhook url %q", *rawURL))
+	}
+	*rawURL = u.String()
+	return nil
+}
+
+// validateEventTypes checks the event types and removes the duplicates.
+func validateEventTypes(eventTypes *[]string) error {
+	seen := make(map[string]bool)
+	res := make([]string, 0, len(*eventTypes))
+	for _, eventType := range *eventTypes {
+		if !isEventType(eventType) {
+			return apperrors.BadRequest(fmt.Errorf("unknown event type %q", eventType))
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			res = append(res, eventType)
+		}
+	}
+	*eventTypes = res
+	return nil
+}
+
+func isEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
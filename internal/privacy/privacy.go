@@ -0,0 +1,60 @@
+// Package privacy filters the reviews and lists of users by the visibility the users set for them.
+package privacy
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/labstack/echo/v4"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
+)
+
+// adminRole is users.AdminRole, which can't be used directly since the users module depends on the modules
+// filtering by privacy.
+const adminRole = "admin"
+
+// Viewer is the user that requests the data of another user. A nil viewer is anonymous.
+type Viewer struct {
+	UserID  int
+	IsAdmin bool
+}
+
+// GetViewer returns the viewer of the request, or nil if the request is unauthenticated.
+func GetViewer(c echo.Context) *Viewer {
+	claims := jwt.GetClaims(c)
+	if claims == nil {
+		return nil
+	}
+	return &Viewer{
+		UserID:  claims.UserID,
+		IsAdmin: claims.Role == adminRole,
+	}
+}
+
+// Key identifies the viewer in the keys of the results shared between requests.
+func (v *Viewer) Key() string {
+	switch {
+	case v == nil:
+		return "anonymous"
+	case v.IsAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("user:%d", v.UserID)
+	}
+}
+
+// Visible is the condition for dbx.StatementBuilder that the content of the user whose id is in userColumn
+// is visible to the viewer. visibilityColumn is the users column with the visibility of the content,
+// e.g. u.reviews_visibility, so the query must join the users table.
+func Visible(viewer *Viewer, userColumn, visibilityColumn string) squirrel.Sqlizer {
+	switch {
+	case viewer == nil:
+		return squirrel.Expr(visibilityColumn + " = 'public'")
+	case viewer.IsAdmin:
+		return squirrel.Expr("TRUE")
+	}
+	return squirrel.Expr(fmt.Sprintf(`(%[1]s = 'public' OR %[2]s = ? OR (%[1]s = 'followers' AND EXISTS (
+			SELECT 1 FROM follows f WHERE f.follower_id = ? AND f.followee_id = %[2]s)))`, visibilityColumn, userColumn),
+		viewer.UserID, viewer.UserID)
+}
@@ -5,21 +5,40 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/bulk"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/collections"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/exports"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/feed"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/follows"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/lists"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/live"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/outbox"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/recommendations"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/reviews"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/watchlist"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/webhooks"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/movies"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/notifications"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/stars"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/trivia"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/genres"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/graph"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/images"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/jobs"
 
 	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+	"github.com/RadkevichAnn/movie-reviews/internal/blob"
 	"github.com/RadkevichAnn/movie-reviews/internal/config"
 	"github.com/RadkevichAnn/movie-reviews/internal/echox"
 	"github.com/RadkevichAnn/movie-reviews/internal/log"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/auth"
+	"github.com/RadkevichAnn/movie-reviews/internal/modules/awards"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/jwt"
 	"github.com/RadkevichAnn/movie-reviews/internal/modules/users"
 	"github.com/RadkevichAnn/movie-reviews/internal/validation"
@@ -38,6 +57,8 @@ const (
 type Server struct {
 	e       *echo.Echo
 	cfg     *config.Config
+	jobs    *jobs.Service
+	live    *live.Service
 	closers []func() error
 }
 
@@ -58,12 +79,69 @@ func New(ctx context.Context, cfg *config.Config) (*Server, error) {
 	closers = append(closers, func() error { db.Close(); return nil })
 
 	jwtService := jwt.NewService(cfg.JWT.Secret, cfg.JWT.AccessExpiration)
-	usersModule := users.NewModule(db)
+	blobStore, err := blob.NewLocalStore(cfg.Images.Dir, cfg.Images.BaseURL)
+	if err != nil {
+		return nil, withClosers(closers, fmt.Errorf("create blob store: %w", err))
+	}
+	imagesModule := images.NewModule(blobStore, cfg.Images)
+	jobsModule := jobs.NewModule(db, cfg.Jobs)
+	outboxModule := outbox.NewModule(db, cfg.Outbox)
+	notificationsModule := notifications.NewModule(db, cfg.Notifications, cfg.Pagination, jobsModule.Service, notifications.LogMailer{})
+	notificationsModule.Service.Subscribe(outboxModule.Subscribers)
+	jobs.Register(jobsModule.Service, notifications.DeliverJob, notificationsModule.Service.RunDeliverJob)
+	webhooksModule := webhooks.NewModule(db, cfg.Webhooks, cfg.Pagination)
+	outboxModule.Service.AddSink(webhooksModule.Service)
+	liveModule := live.NewModule(db, cfg.Live)
+	liveModule.Service.Subscribe(outboxModule.Subscribers)
+	reviewsModule := reviews.NewModule(db, cfg.Pagination, outboxModule)
+	jobs.RegisterManual(jobsModule.Service, reviews.ReconcileRatingsJob, reviewsModule.Service.RunReconcileRatingsJob)
+	exportStore, err := blob.NewLocalStore(cfg.Exports.Dir, "")
+	if err != nil {
+		return nil, withClosers(closers, fmt.Errorf("create export store: %w", err))
+	}
+	exportsModule, err := exports.NewModule(db, exportStore, jobsModule, cfg.Exports)
+	if err != nil {
+		return nil, withClosers(closers, err)
+	}
+	usersModule := users.NewModule(db, cfg.Users, jobsModule.Service, reviewsModule, imagesModule, exportsModule.Service)
+	jobs.Register(jobsModule.Service, users.PurgeJob, usersModule.Service.RunPurgeJob)
 	authModule := auth.NewModule(usersModule.Service, jwtService)
-	genresModule := genres.NewModule(db)
-	starsModule := stars.NewModule(db, cfg.Pagination)
-	moviesModule := movies.NewModule(db, cfg.Pagination, genresModule, starsModule)
-	reviewsModule := reviews.NewModule(db, moviesModule, cfg.Pagination)
+	genresModule := genres.NewModule(db, outboxModule)
+	starsModule := stars.NewModule(db, cfg.Pagination, imagesModule, outboxModule)
+	watchlistModule := watchlist.NewModule(db, cfg.Pagination)
+	collectionsModule := collections.NewModule(db, cfg.Pagination)
+	moviesModule := movies.NewModule(db, cfg.Pagination, cfg.SimilarMovies, genresModule, starsModule, watchlistModule, collectionsModule, imagesModule, outboxModule)
+	listsModule := lists.NewModule(db, cfg.Pagination)
+	followsModule := follows.NewModule(db, cfg.Pagination)
+	triviaModule := trivia.NewModule(db, cfg.Pagination)
+	awardsModule := awards.NewModule(db, cfg.Pagination)
+	feedModule := feed.NewModule(db, cfg.Pagination, cfg.Feed)
+	bulkModule := bulk.NewModule(db, cfg.Bulk, genresModule, starsModule, moviesModule, outboxModule)
+	graphModule := graph.NewModule(cfg.GraphQL, moviesModule, starsModule, genresModule, reviewsModule, usersModule)
+
+	if cfg.Feed.Precomputed() {
+		refreshCtx, cancelRefresh := context.WithCancel(context.Background())
+		go feedModule.Service.RunSnapshotRefresher(refreshCtx, cfg.Feed.SnapshotRefreshInterval)
+		closers = append(closers, func() error { cancelRefresh(); return nil })
+	}
+
+	relayCtx, cancelRelay := context.WithCancel(context.Background())
+	go outboxModule.Service.RunRelay(relayCtx)
+	closers = append(closers, func() error { cancelRelay(); return nil })
+
+	liveCtx, cancelLive := context.WithCancel(context.Background())
+	go liveModule.Service.Run(liveCtx)
+	closers = append(closers, func() error { cancelLive(); return nil })
+
+	dispatchCtx, cancelDispatch := context.WithCancel(context.Background())
+	go webhooksModule.Service.RunDispatcher(dispatchCtx)
+	closers = append(closers, func() error { cancelDispatch(); return nil })
+
+	recommendationsModule := recommendations.NewModule(db, cfg.Recommendations)
+	if cfg.Recommendations.RefreshInterval > 0 {
+		jobs.RegisterPeriodic(jobsModule.Service, recommendations.RefreshJob, cfg.Recommendations.RefreshInterval,
+			recommendationsModule.Service.RunRefreshJob)
+	}
 
 	if err = createInitialAdminUser(cfg.Admin, authModule.Service); err != nil {
 		return nil, withClosers(closers, fmt.Errorf("create initial admin user: %w", err))
@@ -76,50 +154,177 @@ func New(ctx context.Context, cfg *config.Config) (*Server, error) {
 	e.HideBanner = true
 	e.HidePort = true
 
+	// Images are served by the server unless they're hosted elsewhere
+	if strings.HasPrefix(cfg.Images.BaseURL, "/") {
+		e.Static(cfg.Images.BaseURL, cfg.Images.Dir)
+	}
+
+	// GraphQL is served outside of the REST API, authenticated the same way
+	e.POST("/graphql", graphModule.Handler.Query,
+		jwt.NewAuthMiddleware(cfg.JWT.Secret),
+		auth.RevokedTokens(usersModule.Service),
+		echox.Logger,
+		middleware.BodyLimit(cfg.GraphQL.MaxBodySize))
+
 	api := e.Group("/api")
 	api.Use(jwt.NewAuthMiddleware(cfg.JWT.Secret))
+	api.Use(auth.RevokedTokens(usersModule.Service))
 	api.Use(echox.Logger)
 
 	// Auth API routes
 	api.POST("/auth/register", authModule.Handler.Register)
 	api.POST("/auth/login", authModule.Handler.Login)
+	api.POST("/auth/restore", authModule.Handler.Restore)
 
 	// Users API routes
 	api.GET("/users/:userId", usersModule.Handler.Get)
 	api.GET("/users/username/:username", usersModule.Handler.GetByUserName)
 	api.DELETE("/users/:userId", usersModule.Handler.Delete, auth.Self)
-	api.PUT("/users/:userId", usersModule.Handler.UpdateBio, auth.Self)
+	api.PUT("/users/:userId", usersModule.Handler.UpdateProfile, auth.Self)
+	api.PUT("/users/:userId/avatar", usersModule.Handler.UploadAvatar, auth.Self)
 	api.PUT("/users/:userId/role/:role", usersModule.Handler.UpdateRole, auth.Admin)
 
 	// Genres API routes
 	api.GET("/genres", genresModule.Handler.GetAllGenres)
 	api.GET("/genres/:genreId", genresModule.Handler.GetGenreByID)
+	api.GET("/genres/:genreId/stats", genresModule.Handler.GetGenreStats)
 	api.POST("/genres", genresModule.Handler.CreateGenre, auth.Editor)
 	api.PUT("/genres/:genreId", genresModule.Handler.UpdateGenre, auth.Editor)
 	api.DELETE("/genres/:genreId", genresModule.Handler.DeleteGenre, auth.Editor)
 
 	// Stars API routes
 	api.GET("/stars", starsModule.Handler.GetAllStars)
+	api.GET("/stars/path", starsModule.Handler.GetStarPath)
 	api.GET("/stars/:id", starsModule.Handler.GetStarByID)
+	api.GET("/stars/:id/collaborators", starsModule.Handler.GetCollaborators)
+	api.GET("/stars/:id/filmography", starsModule.Handler.GetFilmography)
+	api.GET("/stars/:id/stats", starsModule.Handler.GetCareerStats)
 	api.POST("/stars", starsModule.Handler.CreateStar, auth.Editor)
 	api.PUT("/stars/:id", starsModule.Handler.UpdateStar, auth.Editor)
 	api.DELETE("/stars/:id", starsModule.Handler.DeleteStar, auth.Editor)
+	api.PUT("/stars/:id/headshot", starsModule.Handler.UploadHeadshot, auth.Editor)
+	api.GET("/stars/:id/awards", awardsModule.Handler.GetStarAwards)
+	api.GET("/stars/:id/trivia", triviaModule.Handler.GetStarTrivia)
+	api.POST("/stars/:id/trivia", triviaModule.Handler.SubmitStarTrivia, auth.User)
 
 	// Movies API routes
 	api.GET("/movies", moviesModule.Handler.GetAllMovies)
+	api.GET("/movies/external/:source/:externalId", moviesModule.Handler.GetMovieByExternalID)
 	api.GET("/movies/:id", moviesModule.Handler.GetMovieByID)
+	api.GET("/movies/:id/similar", moviesModule.Handler.GetSimilarMovies)
+	api.GET("/movies/:id/events", liveModule.Handler.StreamMovieEvents)
 	api.POST("/movies", moviesModule.Handler.CreateMovie, auth.Editor)
 	api.PUT("/movies/:id", moviesModule.Handler.UpdateMovie, auth.Editor)
 	api.DELETE("/movies/:id", moviesModule.Handler.DeleteMovie, auth.Editor)
+	api.PUT("/movies/:id/poster", moviesModule.Handler.UploadPoster, auth.Editor)
+	api.GET("/movies/:id/awards", awardsModule.Handler.GetMovieAwards)
+	api.GET("/movies/:id/trivia", triviaModule.Handler.GetMovieTrivia)
+	api.POST("/movies/:id/trivia", triviaModule.Handler.SubmitMovieTrivia, auth.User)
+
+	// Live events API routes
+	api.GET("/events", liveModule.Handler.StreamEvents, auth.Editor)
+
+	// Trivia API routes
+	api.PUT("/trivia/:itemId/status", triviaModule.Handler.ReviewItem, auth.Editor)
+	api.DELETE("/trivia/:itemId", triviaModule.Handler.DeleteItem, auth.Editor)
+	api.PUT("/trivia/:itemId/vote", triviaModule.Handler.Vote, auth.User)
+	api.DELETE("/trivia/:itemId/vote", triviaModule.Handler.Unvote, auth.User)
+
+	// Collections API routes
+	api.GET("/collections", collectionsModule.Handler.GetCollections)
+	api.GET("/collections/:id", collectionsModule.Handler.GetCollectionByID)
+	api.POST("/collections", collectionsModule.Handler.CreateCollection, auth.Editor)
+	api.PUT("/collections/:id", collectionsModule.Handler.UpdateCollection, auth.Editor)
+	api.DELETE("/collections/:id", collectionsModule.Handler.DeleteCollection, auth.Editor)
+
+	// Awards API routes
+	api.GET("/awards", awardsModule.Handler.GetAwards)
+	api.GET("/awards/:id", awardsModule.Handler.GetAwardByID)
+	api.POST("/awards", awardsModule.Handler.CreateAward, auth.Editor)
+	api.PUT("/awards/:id", awardsModule.Handler.UpdateAward, auth.Editor)
+	api.DELETE("/awards/:id", awardsModule.Handler.DeleteAward, auth.Editor)
+
+	// Jobs API routes
+	api.GET("/jobs/:id", jobsModule.Handler.GetJobByID, auth.User)
+	api.POST("/jobs", jobsModule.Handler.EnqueueJob, auth.Admin)
+
+	// Webhooks API routes
+	api.GET("/webhooks", webhooksModule.Handler.GetWebhooks, auth.Admin)
+	api.POST("/webhooks", webhooksModule.Handler.CreateWebhook, auth.Admin)
+	api.GET("/webhooks/deliveries/failed", webhooksModule.Handler.GetFailedDeliveries, auth.Admin)
+	api.GET("/webhooks/deliveries/:deliveryId", webhooksModule.Handler.GetDeliveryByID, auth.Admin)
+	api.POST("/webhooks/deliveries/:deliveryId/redeliver", webhooksModule.Handler.Redeliver, auth.Admin)
+	api.GET("/webhooks/:webhookId", webhooksModule.Handler.GetWebhookByID, auth.Admin)
+	api.PUT("/webhooks/:webhookId", webhooksModule.Handler.UpdateWebhook, auth.Admin)
+	api.DELETE("/webhooks/:webhookId", webhooksModule.Handler.DeleteWebhook, auth.Admin)
+	api.POST("/webhooks/:webhookId/ping", webhooksModule.Handler.PingWebhook, auth.Admin)
+	api.GET("/webhooks/:webhookId/deliveries", webhooksModule.Handler.GetDeliveries, auth.Admin)
+
+	// Exports API routes
+	api.POST("/exports", exportsModule.Handler.ExportCatalog, auth.Editor)
+	api.GET("/exports/:key", exportsModule.Handler.DownloadExport)
+	api.POST("/users/:userId/export", exportsModule.Handler.ExportUserData, auth.Self)
+
+	// Bulk API routes
+	api.POST("/bulk/genres", bulkModule.Handler.ImportGenres, auth.Editor)
+	api.POST("/bulk/stars", bulkModule.Handler.ImportStars, auth.Editor)
+	api.POST("/bulk/movies", bulkModule.Handler.ImportMovies, auth.Editor)
 
 	// Reviews API routes
-	api.GET("/reviews", reviewsModule.Handler.GetAllReviewsPaginated)
+	api.GET("/reviews", reviewsModule.Handler.GetAllReviewsPaginated, usersModule.Handler.Visible(users.SectionReviews))
 	api.GET("/reviews/:reviewId", reviewsModule.Handler.GetReviewByID)
 	api.POST("/users/:userId/reviews", reviewsModule.Handler.CreateReview, auth.Self)
 	api.PUT("/users/:userId/reviews/:reviewId", reviewsModule.Handler.UpdateReview, auth.Self)
 	api.DELETE("/users/:userId/reviews/:reviewId", reviewsModule.Handler.DeleteReview, auth.Self)
 
-	return &Server{e: e, cfg: cfg, closers: closers}, nil
+	// Watchlist API routes
+	api.GET("/users/:userId/watchlist", watchlistModule.Handler.GetWatchlist, auth.Self)
+	api.POST("/users/:userId/watchlist", watchlistModule.Handler.AddToWatchlist, auth.Self)
+	api.PUT("/users/:userId/watchlist/order", watchlistModule.Handler.ReorderWatchlist, auth.Self)
+	api.PUT("/users/:userId/watchlist/:movieId", watchlistModule.Handler.UpdateWatchlistItem, auth.Self)
+	api.DELETE("/users/:userId/watchlist/:movieId", watchlistModule.Handler.DeleteWatchlistItem, auth.Self)
+	api.GET("/users/:userId/watched", watchlistModule.Handler.GetWatched, auth.Self)
+	api.POST("/users/:userId/watched", watchlistModule.Handler.AddWatched, auth.Self)
+	api.DELETE("/users/:userId/watched/:entryId", watchlistModule.Handler.DeleteWatched, auth.Self)
+
+	// Lists API routes
+	api.GET("/lists", listsModule.Handler.GetLists)
+	api.GET("/lists/:listId", listsModule.Handler.GetListByID)
+	api.POST("/lists/:listId/likes", listsModule.Handler.LikeList, auth.User)
+	api.DELETE("/lists/:listId/likes", listsModule.Handler.UnlikeList, auth.User)
+	api.GET("/users/:userId/lists", listsModule.Handler.GetUserLists, usersModule.Handler.Visible(users.SectionLists))
+	api.POST("/users/:userId/lists", listsModule.Handler.CreateList, auth.Self)
+	api.PUT("/users/:userId/lists/:listId", listsModule.Handler.UpdateList, auth.Self)
+	api.DELETE("/users/:userId/lists/:listId", listsModule.Handler.DeleteList, auth.Self)
+
+	// Follows API routes
+	api.GET("/users/:userId/followers", followsModule.Handler.GetFollowers)
+	api.GET("/users/:userId/following", followsModule.Handler.GetFollowing)
+	api.POST("/users/:userId/following", followsModule.Handler.Follow, auth.Self)
+	api.DELETE("/users/:userId/following/:followeeId", followsModule.Handler.Unfollow, auth.Self)
+
+	// Feed API routes
+	api.GET("/feed", feedModule.Handler.GetFeed, auth.User)
+
+	// Notifications API routes
+	api.GET("/notifications", notificationsModule.Handler.GetNotifications, auth.User)
+	api.GET("/notifications/unread-count", notificationsModule.Handler.GetUnreadCount, auth.User)
+	api.PUT("/notifications/read", notificationsModule.Handler.MarkAllRead, auth.User)
+	api.PUT("/notifications/:notificationId/read", notificationsModule.Handler.MarkRead, auth.User)
+	api.GET("/notifications/preferences", notificationsModule.Handler.GetPreferences, auth.User)
+	api.PUT("/notifications/preferences", notificationsModule.Handler.UpdatePreferences, auth.User)
+	api.PUT("/notifications/webhook", notificationsModule.Handler.SetWebhook, auth.User)
+	api.DELETE("/notifications/webhook", notificationsModule.Handler.DeleteWebhook, auth.User)
+
+	// Recommendations API routes
+	api.GET("/users/:userId/recommendations", recommendationsModule.Handler.GetRecommendations, auth.Self)
+
+	if err = jobsModule.Service.Start(ctx); err != nil {
+		return nil, withClosers(closers, fmt.Errorf("start job workers: %w", err))
+	}
+	closers = append(closers, func() error { return jobsModule.Service.Stop(context.Background()) })
+
+	return &Server{e: e, cfg: cfg, jobs: jobsModule.Service, live: liveModule.Service, closers: closers}, nil
 }
 
 func (s *Server) Start() error {
@@ -128,8 +333,11 @@ func (s *Server) Start() error {
 	return s.e.Start(fmt.Sprintf(":%d", port))
 }
 
+// Shutdown stops accepting requests and stops the job workers, waiting for both to finish.
+// The live event streams are closed first, since they would keep their requests running.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.e.Shutdown(ctx)
+	s.live.Close()
+	return errors.Join(s.e.Shutdown(ctx), s.jobs.Stop(ctx))
 }
 
 func (s *Server) Close() error {
@@ -0,0 +1,27 @@
+// Package signing signs the payloads sent to and the links handed out to third parties.
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/RadkevichAnn/movie-reviews/internal/apperrors"
+)
+
+// Sign returns the hex encoded HMAC-SHA256 of the body made with the secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret returns a random hex encoded secret of 32 bytes.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", apperrors.Internal(err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -19,6 +19,7 @@ func SetupValidators() {
 		{"email", email},
 		{"role", role},
 		{"sort", sort},
+		{"visibility", visibility},
 	}
 
 	for _, v := range validators {
@@ -100,3 +101,15 @@ func sort(v interface{}, _ string) error {
 		return fmt.Errorf("sort validate only strings or pointers to strings")
 	}
 }
+
+func visibility(v interface{}, _ string) error {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("visibility only validates strings")
+	}
+	switch s {
+	case "", users.VisibilityPublic, users.VisibilityFollowers, users.VisibilityPrivate:
+		return nil
+	}
+	return fmt.Errorf("visibility must be one of public, followers or private")
+}
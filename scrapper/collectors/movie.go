@@ -3,6 +3,8 @@ package collectors
 import (
 	"encoding/json"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +17,11 @@ import (
 	"github.com/gocolly/colly/v2"
 )
 
+// certificationCountry is the country of the certifications IMDb shows for en-US requests
+const certificationCountry = "US"
+
+var runtimePattern = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?$`)
+
 type MovieCollector struct {
 	c *colly.Collector
 	l *slog.Logger
@@ -44,6 +51,8 @@ func NewMovieCollector(c *colly.Collector, castCollector *CastCollector, logger
 			Description   string   `json:"description"`
 			Genre         []string `json:"genre"`
 			DatePublished string   `json:"datePublished"`
+			Duration      string   `json:"duration"`
+			ContentRating string   `json:"contentRating"`
 		}
 
 		var info movieInfo
@@ -58,8 +67,24 @@ func NewMovieCollector(c *colly.Collector, castCollector *CastCollector, logger
 
 		movie.Title = info.Name
 		movie.Description = info.Description
+		movie.ImageURL = info.Image
 		movie.Genres = info.Genre
 		movie.ReleaseDate = mustParseDate(info.DatePublished)
+		movie.RuntimeMinutes = parseRuntime(info.Duration)
+		if info.ContentRating != "" {
+			movie.Certifications = map[string]string{certificationCountry: info.ContentRating}
+		}
+
+		// Details section links to searches filtered by the ISO codes of countries and languages
+		e.ForEach("li[data-testid='title-details-origin'] a", func(_ int, el *colly.HTMLElement) {
+			if country := getQueryParam(el.Attr("href"), "country_of_origin"); country != "" {
+				movie.Countries = append(movie.Countries, country)
+			}
+		})
+		e.ForEachWithBreak("li[data-testid='title-details-languages'] a", func(_ int, el *colly.HTMLElement) bool {
+			movie.OriginalLanguage = getQueryParam(el.Attr("href"), "primary_language")
+			return movie.OriginalLanguage == ""
+		})
 
 		collector.toAllGenres(movie.Genres)
 
@@ -128,3 +153,24 @@ func mustParseDate(date string) time.Time {
 
 	return t
 }
+
+// parseRuntime converts an ISO 8601 duration, e.g. PT2H1M, to minutes.
+func parseRuntime(duration string) int {
+	match := runtimePattern.FindStringSubmatch(duration)
+	if match == nil {
+		return 0
+	}
+
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	return hours*60 + minutes
+}
+
+func getQueryParam(link string, name string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return u.Query().Get(name)
+}
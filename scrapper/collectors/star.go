@@ -57,6 +57,7 @@ func NewStarCollector(c *colly.Collector, bioCollector *BioCollector, logger *sl
 		}
 
 		star.Name = info.Name
+		star.ImageURL = info.Image
 		star.FirstName, star.LastName = splitName(info.Name)
 		star.BirthDate = mustParseDate(info.MainEntity.BirthDate)
 		if info.MainEntity.DeathDate != "" {
@@ -0,0 +1,70 @@
+package ingesters
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/RadkevichAnn/movie-reviews/contracts"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// bulkBatchSize is the number of items sent in a single bulk import request
+	bulkBatchSize = 500
+	// uploadLimit is the number of images uploaded concurrently
+	uploadLimit = 8
+)
+
+// bulkImport imports the items in batches and returns the results in the order of the items.
+// Failed items are logged and don't stop the import.
+func bulkImport[T any](items []T, logger *slog.Logger, importFn func(batch []T) (*contracts.BulkReport, error)) ([]*contracts.BulkResult, error) {
+	results := make([]*contracts.BulkResult, 0, len(items))
+	for start := 0; start < len(items); start += bulkBatchSize {
+		end := start + bulkBatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+
+		report, err := importFn(items[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("bulk import: %w", err)
+		}
+		for _, result := range report.Results {
+			result.Index += start
+			if result.Status == contracts.BulkStatusFailed {
+				logger.With("index", result.Index).With("err", result.Error).Error("Cannot import item")
+			}
+		}
+		results = append(results, report.Results...)
+
+		logger.
+			With("created", report.Created).
+			With("existing", report.Existing).
+			With("failed", report.Failed).
+			Debug("Imported batch")
+	}
+	return results, nil
+}
+
+// uploadImages uploads the images of the created items concurrently.
+func uploadImages(results []*contracts.BulkResult, imageURL func(index int) string, upload func(id int, imageURL string) error, logger *slog.Logger) {
+	var group errgroup.Group
+	group.SetLimit(uploadLimit)
+
+	for _, result := range results {
+		result := result
+		url := imageURL(result.Index)
+		if result.Status != contracts.BulkStatusCreated || url == "" {
+			continue
+		}
+
+		group.Go(func() error {
+			if err := upload(result.ID, url); err != nil {
+				logger.With("id", result.ID).With("err", err).Warn("Cannot upload image")
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+}
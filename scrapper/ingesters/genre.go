@@ -1,17 +1,12 @@
 package ingesters
 
 import (
-	"context"
 	"fmt"
-	"sync"
 
 	"golang.org/x/exp/slog"
 
 	"github.com/RadkevichAnn/movie-reviews/client"
 	"github.com/RadkevichAnn/movie-reviews/contracts"
-	"github.com/RadkevichAnn/movie-reviews/internal/maps"
-	"github.com/RadkevichAnn/movie-reviews/internal/slices"
-	"golang.org/x/sync/errgroup"
 )
 
 type GenreIngester struct {
@@ -30,53 +25,25 @@ func NewGenreIngester(c *client.Client, token string, logger *slog.Logger) *Genr
 	}
 }
 
+// Ingest imports the genres. Genres that already exist aren't created again.
 func (i *GenreIngester) Ingest(genres []string) error {
-	existingGenres, err := i.c.GetAllGenres()
-	if err != nil {
-		return fmt.Errorf("get genres: %w", err)
-	}
-
-	getGenreName := func(g *contracts.Genre) string {
-		return g.Name
-	}
-
-	nameToGenreMap := slices.ToMap(existingGenres, getGenreName, slices.NoChangeFunc[*contracts.Genre]())
-	var mx sync.RWMutex
-
-	group, _ := errgroup.WithContext(context.Background())
-	group.SetLimit(8)
-
+	reqs := make([]*contracts.CreateGenreRequest, 0, len(genres))
 	for _, genre := range genres {
-		genre := genre
-
-		if maps.ExistsLocked(nameToGenreMap, genre, &mx) {
-			continue
-		}
-
-		group.Go(func() error {
-			var created bool
-			_, created, err = maps.GetOrCreateLocked(nameToGenreMap, genre, &mx, func(name string) (*contracts.Genre, error) {
-				req := &contracts.CreateGenreRequest{Name: name}
-				return i.c.CreateGenre(contracts.NewAuthenticated[*contracts.CreateGenreRequest](req, i.token))
-			})
-			if err != nil {
-				return fmt.Errorf("create genre %q: %w", genre, err)
-			}
-
-			if created {
-				i.logger.With("genre", genre).Debug("Created genre")
-			}
-			return nil
-		})
+		reqs = append(reqs, &contracts.CreateGenreRequest{Name: genre})
 	}
 
-	if err = group.Wait(); err != nil {
+	results, err := bulkImport(reqs, i.logger, func(batch []*contracts.CreateGenreRequest) (*contracts.BulkReport, error) {
+		return i.c.BulkImportGenres(contracts.NewAuthenticated(batch, i.token))
+	})
+	if err != nil {
 		return fmt.Errorf("ingest genres: %w", err)
 	}
 
-	i.conversionMap = make(map[string]int, len(nameToGenreMap))
-	for _, genre := range existingGenres {
-		i.conversionMap[genre.Name] = genre.ID
+	i.conversionMap = make(map[string]int, len(genres))
+	for _, result := range results {
+		if result.Status != contracts.BulkStatusFailed {
+			i.conversionMap[genres[result.Index]] = result.ID
+		}
 	}
 
 	i.logger.Info("Successfully ingested genres")
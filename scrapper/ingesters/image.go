@@ -0,0 +1,25 @@
+package ingesters
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const imageDownloadTimeout = 30 * time.Second
+
+var imageClient = &http.Client{Timeout: imageDownloadTimeout}
+
+func downloadImage(link string) ([]byte, error) {
+	resp, err := imageClient.Get(link)
+	if err != nil {
+		return nil, fmt.Errorf("download image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download image: unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -1,21 +1,19 @@
 package ingesters
 
 import (
-	"context"
 	"fmt"
-	"sync"
-	"time"
+	"path"
 
 	"golang.org/x/exp/slog"
 
 	"github.com/RadkevichAnn/movie-reviews/client"
 	"github.com/RadkevichAnn/movie-reviews/contracts"
-	"github.com/RadkevichAnn/movie-reviews/internal/maps"
-	"github.com/RadkevichAnn/movie-reviews/internal/slices"
 	"github.com/RadkevichAnn/movie-reviews/scrapper/models"
-	"golang.org/x/sync/errgroup"
 )
 
+// imdbSource is the external source of the ids of scrapped movies
+const imdbSource = "imdb"
+
 type MovieIngester struct {
 	c                *client.Client
 	token            string
@@ -34,110 +32,93 @@ func NewMovieIngester(c *client.Client, token string, genreIDConverter func(stri
 	}
 }
 
+// Ingest imports the movies identified by their IMDb ids. Movies that were already imported aren't created again.
 func (i *MovieIngester) Ingest(movies map[string]*models.Movie, casts map[string]*models.Cast) error {
-	existingMovies, err := client.Paginate(&contracts.GetMoviesRequest{}, i.c.GetMovies)
-	if err != nil {
-		return err
+	items := make([]*models.Movie, 0, len(movies))
+	reqs := make([]*contracts.CreateMovieRequest, 0, len(movies))
+	for _, movie := range movies {
+		items = append(items, movie)
+		reqs = append(reqs, i.toRequest(movie, casts))
 	}
 
-	type movieCommonIdentifier struct {
-		Title       string
-		ReleaseDate time.Time
+	results, err := bulkImport(reqs, i.logger, func(batch []*contracts.CreateMovieRequest) (*contracts.BulkReport, error) {
+		return i.c.BulkImportMovies(contracts.NewAuthenticated(batch, i.token))
+	})
+	if err != nil {
+		return fmt.Errorf("ingest movies: %w", err)
 	}
 
-	getID := func(m *contracts.Movie) movieCommonIdentifier {
-		return movieCommonIdentifier{
-			Title:       m.Title,
-			ReleaseDate: m.ReleaseDate,
-		}
+	uploadImages(results, func(index int) string { return items[index].ImageURL }, i.uploadPoster, i.logger)
+
+	i.logger.Info("Successfully ingested movies")
+	return nil
+}
+
+func (i *MovieIngester) toRequest(movie *models.Movie, casts map[string]*models.Cast) *contracts.CreateMovieRequest {
+	req := &contracts.CreateMovieRequest{
+		Title:               movie.Title,
+		ReleaseDate:         movie.ReleaseDate,
+		Description:         movie.Description,
+		ProductionCountries: movie.Countries,
+		Certifications:      movie.Certifications,
+		ExternalIDs:         map[string]string{imdbSource: movie.ID},
+	}
+	if movie.RuntimeMinutes > 0 {
+		req.RuntimeMinutes = &movie.RuntimeMinutes
+	}
+	if movie.OriginalLanguage != "" {
+		req.OriginalLanguage = &movie.OriginalLanguage
 	}
 
-	idToMovieMap := slices.ToMap(existingMovies, getID, slices.NoChangeFunc[*contracts.Movie]())
-	var mx sync.RWMutex
+	// Prepare genres
+	for _, genre := range movie.Genres {
+		genreID, ok := i.genreIDConverter(genre)
+		if !ok {
+			i.logger.With("genre", genre).Error("Cannot convert genre")
+			continue
+		}
 
-	group, _ := errgroup.WithContext(context.Background())
-	group.SetLimit(8)
+		req.Genres = append(req.Genres, genreID)
+	}
 
-	for _, movie := range movies {
-		movie := movie
-		commonID := movieCommonIdentifier{movie.Title, movie.ReleaseDate}
+	// Prepare cast
+	cast, ok := casts[movie.ID]
+	if !ok {
+		i.logger.With("movie_id", movie.ID).Error("Cast not found")
+		cast = &models.Cast{}
+	}
 
-		if maps.ExistsLocked(idToMovieMap, commonID, &mx) {
+	for _, credit := range cast.Cast {
+		starID, ok := i.starIDConverter(credit.StarID)
+		if !ok {
+			i.logger.With("star_id", credit.StarID).Warn("Cannot convert star id")
 			continue
 		}
 
-		group.Go(func() error {
-			var created bool
-			_, created, err = maps.GetOrCreateLocked(idToMovieMap, commonID, &mx, func(name movieCommonIdentifier) (*contracts.Movie, error) {
-				req := &contracts.CreateMovieRequest{
-					Title:       movie.Title,
-					ReleaseDate: movie.ReleaseDate,
-					Description: movie.Description,
-				}
-
-				// Prepare genres
-				for _, genre := range movie.Genres {
-					genreID, ok := i.genreIDConverter(genre)
-					if !ok {
-						i.logger.With("genre", genre).Error("Cannot convert genre")
-						continue
-					}
-
-					req.Genres = append(req.Genres, genreID)
-				}
-
-				// Prepare cast
-				cast, ok := casts[movie.ID]
-				if !ok {
-					i.logger.With("movie_id", movie.ID).Error("Cast not found")
-					cast = &models.Cast{}
-				}
-
-				for _, credit := range cast.Cast {
-					starID, ok := i.starIDConverter(credit.StarID)
-					if !ok {
-						i.logger.With("star_id", credit.StarID).Warn("Cannot convert star id")
-						continue
-					}
-
-					creditInfo := &contracts.MovieCreditInfo{
-						StarID: starID,
-						Role:   credit.Role,
-					}
-					if credit.Details != "" {
-						creditInfo.Details = &credit.Details
-					}
-
-					req.Cast = append(req.Cast, creditInfo)
-				}
-
-				var md *contracts.MovieDetails
-				md, err = i.c.CreateMovie(contracts.NewAuthenticated(req, i.token))
-				if err != nil {
-					return nil, fmt.Errorf("create movie: %w", err)
-				}
-
-				return &md.Movie, nil
-			})
-			if err != nil {
-				return err
-			}
-
-			if created {
-				i.logger.
-					With("movie_id", movie.ID).
-					With("movie_common_id", commonID).
-					Debug("Created movie")
-			}
-
-			return nil
-		})
+		creditInfo := &contracts.MovieCreditInfo{
+			StarID: starID,
+			Role:   credit.Role,
+		}
+		if credit.Details != "" {
+			creditInfo.Details = &credit.Details
+		}
+
+		req.Cast = append(req.Cast, creditInfo)
 	}
+	return req
+}
 
-	if err = group.Wait(); err != nil {
-		return fmt.Errorf("ingest movies: %w", err)
+func (i *MovieIngester) uploadPoster(movieID int, imageURL string) error {
+	content, err := downloadImage(imageURL)
+	if err != nil {
+		return err
 	}
 
-	i.logger.Info("Successfully ingested movies")
-	return nil
+	req := &contracts.UploadImageRequest{
+		ID:       movieID,
+		FileName: path.Base(imageURL),
+		Content:  content,
+	}
+	_, err = i.c.UploadMoviePoster(contracts.NewAuthenticated(req, i.token))
+	return err
 }
@@ -1,19 +1,14 @@
 package ingesters
 
 import (
-	"context"
 	"fmt"
-	"sync"
-	"time"
+	"path"
 
 	"golang.org/x/exp/slog"
 
 	"github.com/RadkevichAnn/movie-reviews/client"
 	"github.com/RadkevichAnn/movie-reviews/contracts"
-	"github.com/RadkevichAnn/movie-reviews/internal/maps"
-	"github.com/RadkevichAnn/movie-reviews/internal/slices"
 	"github.com/RadkevichAnn/movie-reviews/scrapper/models"
-	"golang.org/x/sync/errgroup"
 )
 
 type StarIngester struct {
@@ -32,101 +27,53 @@ func NewStarIngester(c *client.Client, token string, logger *slog.Logger) *StarI
 	}
 }
 
+// Ingest imports the stars identified by their IMDb ids. Stars that were already imported aren't created again.
 func (i *StarIngester) Ingest(stars map[string]*models.Star, bios map[string]*models.Bio) error {
-	existingStars, err := client.Paginate(&contracts.GetStarsRequest{}, i.c.GetStars)
-	if err != nil {
-		return err
-	}
-
-	type starCommonIdentifier struct {
-		FirstName string
-		LastName  string
-		BirthDate time.Time
-	}
-
-	getID := func(s *contracts.Star) starCommonIdentifier {
-		return starCommonIdentifier{
-			FirstName: s.FirstName,
-			LastName:  s.LastName,
-			BirthDate: s.BirthDate,
-		}
-	}
-
-	idToStarMap := slices.ToMap(existingStars, getID, slices.NoChangeFunc[*contracts.Star]())
-	var mx sync.RWMutex
-
-	group, _ := errgroup.WithContext(context.Background())
-	group.SetLimit(8)
-
+	items := make([]*models.Star, 0, len(stars))
+	reqs := make([]*contracts.BulkStar, 0, len(stars))
 	for _, star := range stars {
-		star := star
-		commonID := starCommonIdentifier{star.FirstName, star.LastName, star.BirthDate}
+		bio, ok := bios[star.ID]
+		if !ok {
+			i.logger.With("star_id", star.ID).Error("Bio not found")
+			bio = &models.Bio{}
+		}
 
-		if maps.ExistsLocked(idToStarMap, commonID, &mx) {
-			continue
+		req := &contracts.BulkStar{
+			CreateStarRequest: contracts.CreateStarRequest{
+				FirstName: star.FirstName,
+				LastName:  star.LastName,
+				BirthDate: star.BirthDate,
+				DeathDate: star.DeathDate,
+			},
+			ExternalIDs: map[string]string{imdbSource: star.ID},
+		}
+		if bio.Bio != "" {
+			req.Bio = &bio.Bio
+		}
+		if bio.BirthPlace != "" {
+			req.BirthPlace = &bio.BirthPlace
 		}
 
-		group.Go(func() error {
-			var created bool
-			_, created, err = maps.GetOrCreateLocked(idToStarMap, commonID, &mx, func(name starCommonIdentifier) (*contracts.Star, error) {
-				bio, ok := bios[star.ID]
-				if !ok {
-					i.logger.With("star_id", star.ID).Error("Bio not found")
-					bio = &models.Bio{}
-				}
-
-				req := &contracts.CreateStarRequest{
-					FirstName: star.FirstName,
-					LastName:  star.LastName,
-					BirthDate: star.BirthDate,
-					DeathDate: star.DeathDate,
-				}
-				if bio.Bio != "" {
-					req.Bio = &bio.Bio
-				}
-				if bio.BirthPlace != "" {
-					req.BirthPlace = &bio.BirthPlace
-				}
-
-				var sd *contracts.StarDetails
-				sd, err = i.c.CreateStar(contracts.NewAuthenticated(req, i.token))
-				if err != nil {
-					return nil, fmt.Errorf("create star %q: %w", name, err)
-				}
-
-				return &sd.Star, nil
-			})
-			if err != nil {
-				return err
-			}
-
-			if created {
-				i.logger.
-					With("star_id", star.ID).
-					With("star_common_id", commonID).
-					Debug("Created star")
-			}
-
-			return nil
-		})
+		items = append(items, star)
+		reqs = append(reqs, req)
 	}
 
-	if err = group.Wait(); err != nil {
+	results, err := bulkImport(reqs, i.logger, func(batch []*contracts.BulkStar) (*contracts.BulkReport, error) {
+		return i.c.BulkImportStars(contracts.NewAuthenticated(batch, i.token))
+	})
+	if err != nil {
 		return fmt.Errorf("ingest stars: %w", err)
 	}
 
-	i.conversionMap = make(map[string]int, len(idToStarMap))
-	for _, star := range stars {
-		commonID := starCommonIdentifier{star.FirstName, star.LastName, star.BirthDate}
-		s, ok := idToStarMap[commonID]
-		if !ok {
-			i.logger.With("star_id", star.ID).Error("Cannot find star for conversion map creation")
-			continue
+	i.conversionMap = make(map[string]int, len(stars))
+	for _, result := range results {
+		if result.Status != contracts.BulkStatusFailed {
+			i.conversionMap[items[result.Index].ID] = result.ID
 		}
-
-		i.conversionMap[star.ID] = s.ID
 	}
 
+	uploadImages(results, func(index int) string { return items[index].ImageURL }, i.uploadHeadshot, i.logger)
+
 	i.logger.Info("Successfully ingested stars")
 	return nil
 }
@@ -135,3 +82,18 @@ func (i *StarIngester) Converter(imdbID string) (int, bool) {
 	id, ok := i.conversionMap[imdbID]
 	return id, ok
 }
+
+func (i *StarIngester) uploadHeadshot(starID int, imageURL string) error {
+	content, err := downloadImage(imageURL)
+	if err != nil {
+		return err
+	}
+
+	req := &contracts.UploadImageRequest{
+		ID:       starID,
+		FileName: path.Base(imageURL),
+		Content:  content,
+	}
+	_, err = i.c.UploadStarHeadshot(contracts.NewAuthenticated(req, i.token))
+	return err
+}
@@ -3,11 +3,17 @@ package models
 import "time"
 
 type Movie struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	ReleaseDate time.Time `json:"release_date"`
-	Genres      []string  `json:"genres"`
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	Description      string    `json:"description"`
+	ReleaseDate      time.Time `json:"release_date"`
+	Genres           []string  `json:"genres"`
+	RuntimeMinutes   int       `json:"runtime_minutes"`
+	OriginalLanguage string    `json:"original_language"`
+	Countries        []string  `json:"countries"`
+	// Certifications maps country codes to age certifications
+	Certifications map[string]string `json:"certifications"`
+	ImageURL       string            `json:"image_url"`
 
 	Link string `json:"_link"`
 }
@@ -34,6 +40,7 @@ type Star struct {
 	LastName  string     `json:"last_name"`
 	BirthDate time.Time  `json:"birth_date"`
 	DeathDate *time.Time `json:"death_date"`
+	ImageURL  string     `json:"image_url"`
 
 	Link string `json:"_link"`
 }
@@ -0,0 +1,30 @@
+ALTER TABLE movies ADD COLUMN rating_sum BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE movies ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE movies ADD COLUMN rating_histogram INTEGER[] NOT NULL DEFAULT '{0,0,0,0,0,0,0,0,0,0}';
+
+UPDATE movies m SET
+    rating_sum = a.rating_sum,
+    rating_count = a.rating_count,
+    rating_histogram = a.rating_histogram,
+    avg_rating = a.rating_sum::FLOAT4 / NULLIF(a.rating_count, 0)
+FROM (
+    SELECT c.movie_id,
+           SUM(c.rating * c.cnt) AS rating_sum,
+           SUM(c.cnt) AS rating_count,
+           ARRAY_AGG(c.cnt ORDER BY c.rating)::INTEGER[] AS rating_histogram
+    FROM (
+        SELECT mr.movie_id, s.rating, COUNT(r.id) AS cnt
+        FROM (SELECT DISTINCT movie_id FROM reviews WHERE deleted_at IS NULL) mr
+        CROSS JOIN generate_series(1, 10) AS s(rating)
+        LEFT JOIN reviews r ON r.movie_id = mr.movie_id AND r.rating = s.rating AND r.deleted_at IS NULL
+        GROUP BY mr.movie_id, s.rating
+    ) c
+    GROUP BY c.movie_id
+) a
+WHERE a.movie_id = m.id;
+
+---- create above / drop below ----
+
+ALTER TABLE movies DROP COLUMN rating_histogram;
+ALTER TABLE movies DROP COLUMN rating_count;
+ALTER TABLE movies DROP COLUMN rating_sum;
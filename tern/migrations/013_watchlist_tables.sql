@@ -0,0 +1,26 @@
+CREATE TABLE watchlist_items (
+                                 user_id INTEGER NOT NULL REFERENCES users(id),
+                                 movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                 notes VARCHAR(1000),
+                                 priority SMALLINT,
+                                 order_no SMALLINT NOT NULL,
+                                 added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                 PRIMARY KEY (user_id, movie_id)
+);
+CREATE INDEX idx_watchlist_items_movie_id ON watchlist_items(movie_id);
+
+CREATE TABLE watched_movies (
+                                id SERIAL PRIMARY KEY,
+                                user_id INTEGER NOT NULL REFERENCES users(id),
+                                movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                watched_on DATE NOT NULL,
+                                created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_watched_movies_user_id ON watched_movies(user_id, movie_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_watched_movies_user_id;
+DROP TABLE watched_movies;
+DROP INDEX idx_watchlist_items_movie_id;
+DROP TABLE watchlist_items;
@@ -0,0 +1,38 @@
+CREATE TABLE lists (
+                       id SERIAL PRIMARY KEY,
+                       user_id INTEGER NOT NULL REFERENCES users(id),
+                       name VARCHAR(100) NOT NULL,
+                       description TEXT NOT NULL DEFAULT '',
+                       is_public BOOLEAN NOT NULL DEFAULT TRUE,
+                       likes_count INTEGER NOT NULL DEFAULT 0,
+                       created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                       updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                       deleted_at TIMESTAMP
+);
+CREATE INDEX idx_lists_user_id ON lists(user_id);
+CREATE INDEX idx_lists_likes_count ON lists(likes_count);
+
+CREATE TABLE list_items (
+                            list_id INTEGER NOT NULL REFERENCES lists(id),
+                            movie_id INTEGER NOT NULL REFERENCES movies(id),
+                            notes TEXT NOT NULL DEFAULT '',
+                            order_no SMALLINT NOT NULL,
+                            PRIMARY KEY (list_id, movie_id)
+);
+CREATE INDEX idx_list_items_movie_id ON list_items(movie_id);
+
+CREATE TABLE list_likes (
+                            list_id INTEGER NOT NULL REFERENCES lists(id),
+                            user_id INTEGER NOT NULL REFERENCES users(id),
+                            created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                            PRIMARY KEY (list_id, user_id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE list_likes;
+DROP INDEX idx_list_items_movie_id;
+DROP TABLE list_items;
+DROP INDEX idx_lists_likes_count;
+DROP INDEX idx_lists_user_id;
+DROP TABLE lists;
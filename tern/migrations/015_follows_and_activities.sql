@@ -0,0 +1,47 @@
+CREATE TABLE follows (
+                         follower_id INTEGER NOT NULL REFERENCES users(id),
+                         followee_id INTEGER NOT NULL REFERENCES users(id),
+                         created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                         PRIMARY KEY (follower_id, followee_id),
+                         CHECK (follower_id <> followee_id)
+);
+CREATE INDEX idx_follows_followee_id ON follows(followee_id);
+
+CREATE INDEX idx_reviews_user_id_created_at ON reviews(user_id, created_at DESC);
+CREATE INDEX idx_lists_user_id_updated_at ON lists(user_id, updated_at DESC);
+
+CREATE VIEW user_activities AS
+SELECT 'review'::VARCHAR(16) AS type,
+       r.id AS subject_id,
+       r.user_id,
+       r.movie_id,
+       r.rating,
+       r.title::VARCHAR(255) AS title,
+       r.created_at
+FROM reviews r
+WHERE r.deleted_at IS NULL
+UNION ALL
+SELECT 'list'::VARCHAR(16),
+       l.id,
+       l.user_id,
+       NULL::INTEGER,
+       NULL::INTEGER,
+       l.name::VARCHAR(255),
+       l.updated_at
+FROM lists l
+WHERE l.deleted_at IS NULL AND l.is_public;
+
+CREATE MATERIALIZED VIEW user_activities_snapshot AS SELECT * FROM user_activities;
+CREATE UNIQUE INDEX idx_user_activities_snapshot_subject ON user_activities_snapshot(type, subject_id);
+CREATE INDEX idx_user_activities_snapshot_user_id ON user_activities_snapshot(user_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX idx_user_activities_snapshot_user_id;
+DROP INDEX idx_user_activities_snapshot_subject;
+DROP MATERIALIZED VIEW user_activities_snapshot;
+DROP VIEW user_activities;
+DROP INDEX idx_lists_user_id_updated_at;
+DROP INDEX idx_reviews_user_id_created_at;
+DROP INDEX idx_follows_followee_id;
+DROP TABLE follows;
@@ -0,0 +1,11 @@
+CREATE TABLE movie_similarities (
+                                    movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                    similar_movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                    score DOUBLE PRECISION NOT NULL,
+                                    support INTEGER NOT NULL,
+                                    PRIMARY KEY (movie_id, similar_movie_id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE movie_similarities;
@@ -0,0 +1,25 @@
+ALTER TABLE genres ADD COLUMN parent_id INTEGER REFERENCES genres(id) ON DELETE SET NULL;
+ALTER TABLE genres ADD COLUMN slug VARCHAR(64);
+ALTER TABLE genres ADD COLUMN description TEXT;
+
+UPDATE genres SET slug = rtrim(left(trim(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), 50), '-');
+-- Names without latin letters or digits get an empty slug, and names that differ only in case or
+-- punctuation get the same one: these are made unique with the genre id.
+UPDATE genres g
+SET slug = CASE WHEN g.slug = '' THEN 'genre-' || g.id ELSE g.slug || '-' || g.id END
+WHERE g.slug = ''
+   OR EXISTS (SELECT 1 FROM genres o WHERE o.slug = g.slug AND o.id < g.id);
+
+ALTER TABLE genres ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE genres ADD CONSTRAINT genres_slug_key UNIQUE (slug);
+ALTER TABLE genres ADD CONSTRAINT genres_parent_id_check CHECK (parent_id <> id);
+CREATE INDEX idx_genres_parent_id ON genres(parent_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_genres_parent_id;
+ALTER TABLE genres DROP CONSTRAINT genres_parent_id_check;
+ALTER TABLE genres DROP CONSTRAINT genres_slug_key;
+ALTER TABLE genres DROP COLUMN description;
+ALTER TABLE genres DROP COLUMN slug;
+ALTER TABLE genres DROP COLUMN parent_id;
@@ -0,0 +1,23 @@
+CREATE TABLE collections (
+                             id SERIAL PRIMARY KEY,
+                             name VARCHAR(255) NOT NULL,
+                             description TEXT NOT NULL DEFAULT '',
+                             created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                             deleted_at TIMESTAMP
+);
+CREATE UNIQUE INDEX collections_name_key ON collections(name) WHERE deleted_at IS NULL;
+
+CREATE TABLE collection_movies (
+                                   collection_id INTEGER NOT NULL REFERENCES collections(id),
+                                   movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                   order_no SMALLINT NOT NULL,
+                                   PRIMARY KEY (collection_id, movie_id)
+);
+CREATE INDEX idx_collection_movies_movie_id ON collection_movies(movie_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_collection_movies_movie_id;
+DROP TABLE collection_movies;
+DROP INDEX collections_name_key;
+DROP TABLE collections;
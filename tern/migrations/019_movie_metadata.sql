@@ -0,0 +1,27 @@
+ALTER TABLE movies ADD COLUMN runtime_minutes INTEGER CHECK (runtime_minutes > 0);
+ALTER TABLE movies ADD COLUMN original_language VARCHAR(3);
+ALTER TABLE movies ADD COLUMN production_countries CHAR(2)[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE movie_certifications (
+                                      movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                      country CHAR(2) NOT NULL,
+                                      certification VARCHAR(16) NOT NULL,
+                                      PRIMARY KEY (movie_id, country)
+);
+
+CREATE TABLE movie_external_ids (
+                                    movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                    source VARCHAR(32) NOT NULL,
+                                    external_id VARCHAR(64) NOT NULL,
+                                    PRIMARY KEY (movie_id, source)
+);
+CREATE UNIQUE INDEX movie_external_ids_external_id_key ON movie_external_ids(source, external_id);
+
+---- create above / drop below ----
+
+DROP INDEX movie_external_ids_external_id_key;
+DROP TABLE movie_external_ids;
+DROP TABLE movie_certifications;
+ALTER TABLE movies DROP COLUMN production_countries;
+ALTER TABLE movies DROP COLUMN original_language;
+ALTER TABLE movies DROP COLUMN runtime_minutes;
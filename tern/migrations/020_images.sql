@@ -0,0 +1,7 @@
+ALTER TABLE movies ADD COLUMN poster_id UUID;
+ALTER TABLE stars ADD COLUMN headshot_id UUID;
+
+---- create above / drop below ----
+
+ALTER TABLE stars DROP COLUMN headshot_id;
+ALTER TABLE movies DROP COLUMN poster_id;
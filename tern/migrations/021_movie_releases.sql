@@ -0,0 +1,17 @@
+CREATE TYPE release_type AS ENUM ('premiere', 'theatrical_limited', 'theatrical', 'digital', 'physical', 'tv');
+CREATE TABLE movie_releases (
+                                movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                country CHAR(2) NOT NULL,
+                                type release_type NOT NULL,
+                                release_date DATE NOT NULL,
+                                note TEXT NOT NULL DEFAULT '',
+                                PRIMARY KEY (movie_id, country, type, release_date)
+);
+
+CREATE INDEX idx_movie_releases_country_release_date ON movie_releases(country, release_date);
+
+---- create above / drop below ----
+
+DROP INDEX idx_movie_releases_country_release_date;
+DROP TABLE movie_releases;
+DROP TYPE release_type;
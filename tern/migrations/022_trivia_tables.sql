@@ -0,0 +1,42 @@
+CREATE TYPE trivia_kind AS ENUM ('trivia', 'quote', 'goof');
+CREATE TYPE trivia_status AS ENUM ('pending', 'approved', 'rejected');
+CREATE TABLE trivia_items (
+                              id SERIAL PRIMARY KEY,
+                              kind trivia_kind NOT NULL,
+                              movie_id INTEGER REFERENCES movies(id),
+                              star_id INTEGER REFERENCES stars(id),
+                              user_id INTEGER NOT NULL REFERENCES users(id),
+                              content TEXT NOT NULL,
+                              credit_star_id INTEGER REFERENCES stars(id),
+                              credit_role movie_role,
+                              status trivia_status NOT NULL DEFAULT 'pending',
+                              score INTEGER NOT NULL DEFAULT 0,
+                              created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                              reviewed_at TIMESTAMPTZ,
+                              reviewed_by INTEGER REFERENCES users(id),
+                              deleted_at TIMESTAMPTZ,
+                              CONSTRAINT trivia_items_subject_check CHECK ((movie_id IS NULL) <> (star_id IS NULL)),
+                              CONSTRAINT trivia_items_star_kind_check CHECK (star_id IS NULL OR kind = 'trivia'),
+                              CONSTRAINT trivia_items_credit_check CHECK ((credit_star_id IS NULL) = (credit_role IS NULL)
+                                  AND (credit_star_id IS NULL OR kind = 'quote'))
+);
+
+CREATE INDEX idx_trivia_items_movie_id ON trivia_items(movie_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_trivia_items_star_id ON trivia_items(star_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE trivia_votes (
+                              item_id INTEGER NOT NULL REFERENCES trivia_items(id),
+                              user_id INTEGER NOT NULL REFERENCES users(id),
+                              value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+                              created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                              PRIMARY KEY (item_id, user_id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE trivia_votes;
+DROP INDEX idx_trivia_items_star_id;
+DROP INDEX idx_trivia_items_movie_id;
+DROP TABLE trivia_items;
+DROP TYPE trivia_status;
+DROP TYPE trivia_kind;
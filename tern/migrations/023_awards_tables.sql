@@ -0,0 +1,29 @@
+CREATE TABLE awards (
+                        id SERIAL PRIMARY KEY,
+                        ceremony VARCHAR(255) NOT NULL,
+                        year SMALLINT NOT NULL,
+                        category VARCHAR(255) NOT NULL,
+                        created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                        deleted_at TIMESTAMP
+);
+CREATE UNIQUE INDEX awards_ceremony_year_category_key ON awards(ceremony, year, category) WHERE deleted_at IS NULL;
+
+CREATE TABLE award_nominations (
+                                   award_id INTEGER NOT NULL REFERENCES awards(id),
+                                   movie_id INTEGER NOT NULL REFERENCES movies(id),
+                                   star_id INTEGER REFERENCES stars(id),
+                                   won BOOLEAN NOT NULL DEFAULT FALSE,
+                                   order_no SMALLINT NOT NULL
+);
+CREATE UNIQUE INDEX award_nominations_nominee_key ON award_nominations(award_id, movie_id, COALESCE(star_id, 0));
+CREATE INDEX idx_award_nominations_movie_id ON award_nominations(movie_id);
+CREATE INDEX idx_award_nominations_star_id ON award_nominations(star_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_award_nominations_star_id;
+DROP INDEX idx_award_nominations_movie_id;
+DROP INDEX award_nominations_nominee_key;
+DROP TABLE award_nominations;
+DROP INDEX awards_ceremony_year_category_key;
+DROP TABLE awards;
@@ -0,0 +1,12 @@
+CREATE TABLE star_external_ids (
+                                   star_id INTEGER NOT NULL REFERENCES stars(id),
+                                   source VARCHAR(32) NOT NULL,
+                                   external_id VARCHAR(64) NOT NULL,
+                                   PRIMARY KEY (star_id, source)
+);
+CREATE UNIQUE INDEX star_external_ids_external_id_key ON star_external_ids(source, external_id);
+
+---- create above / drop below ----
+
+DROP INDEX star_external_ids_external_id_key;
+DROP TABLE star_external_ids;
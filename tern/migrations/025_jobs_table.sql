@@ -0,0 +1,28 @@
+CREATE TYPE job_status AS ENUM ('queued', 'running', 'succeeded', 'failed');
+
+CREATE TABLE jobs (
+                      id SERIAL PRIMARY KEY,
+                      type VARCHAR(64) NOT NULL,
+                      payload JSONB NOT NULL DEFAULT '{}',
+                      status job_status NOT NULL DEFAULT 'queued',
+                      progress SMALLINT NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+                      result JSONB,
+                      error TEXT,
+                      attempts INTEGER NOT NULL DEFAULT 0,
+                      max_attempts INTEGER NOT NULL CHECK (max_attempts > 0),
+                      run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                      locked_until TIMESTAMP,
+                      created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                      created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                      started_at TIMESTAMP,
+                      finished_at TIMESTAMP
+);
+CREATE INDEX idx_jobs_queued_run_at ON jobs(run_at) WHERE status = 'queued';
+CREATE INDEX idx_jobs_running_locked_until ON jobs(locked_until) WHERE status = 'running';
+
+---- create above / drop below ----
+
+DROP INDEX idx_jobs_running_locked_until;
+DROP INDEX idx_jobs_queued_run_at;
+DROP TABLE jobs;
+DROP TYPE job_status;
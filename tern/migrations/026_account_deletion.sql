@@ -0,0 +1,11 @@
+ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN keep_reviews BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN anonymized_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMP;
+
+---- create above / drop below ----
+
+ALTER TABLE users DROP COLUMN tokens_valid_after;
+ALTER TABLE users DROP COLUMN anonymized_at;
+ALTER TABLE users DROP COLUMN keep_reviews;
+ALTER TABLE users DROP COLUMN deletion_scheduled_at;
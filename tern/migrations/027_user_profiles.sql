@@ -0,0 +1,26 @@
+CREATE TYPE visibility AS ENUM ('public', 'followers', 'private');
+
+ALTER TABLE users ADD COLUMN display_name VARCHAR(64);
+ALTER TABLE users ADD COLUMN location VARCHAR(100);
+ALTER TABLE users ADD COLUMN avatar_id UUID;
+ALTER TABLE users ADD COLUMN email_visibility visibility NOT NULL DEFAULT 'private';
+ALTER TABLE users ADD COLUMN reviews_visibility visibility NOT NULL DEFAULT 'public';
+ALTER TABLE users ADD COLUMN lists_visibility visibility NOT NULL DEFAULT 'public';
+
+CREATE TABLE user_favorite_genres (
+                                      user_id INTEGER NOT NULL REFERENCES users(id),
+                                      genre_id INTEGER NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+                                      order_no SMALLINT NOT NULL,
+                                      PRIMARY KEY (user_id, genre_id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE user_favorite_genres;
+ALTER TABLE users DROP COLUMN lists_visibility;
+ALTER TABLE users DROP COLUMN reviews_visibility;
+ALTER TABLE users DROP COLUMN email_visibility;
+ALTER TABLE users DROP COLUMN avatar_id;
+ALTER TABLE users DROP COLUMN location;
+ALTER TABLE users DROP COLUMN display_name;
+DROP TYPE visibility;
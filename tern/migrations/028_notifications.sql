@@ -0,0 +1,33 @@
+CREATE TABLE notifications (
+                               id SERIAL PRIMARY KEY,
+                               user_id INTEGER NOT NULL REFERENCES users(id),
+                               type VARCHAR(64) NOT NULL,
+                               title VARCHAR(255) NOT NULL,
+                               payload JSONB NOT NULL DEFAULT '{}',
+                               created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                               read_at TIMESTAMP
+);
+CREATE INDEX idx_notifications_user_id_created_at ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+CREATE TABLE notification_preferences (
+                                          user_id INTEGER NOT NULL REFERENCES users(id),
+                                          type VARCHAR(64) NOT NULL,
+                                          channels VARCHAR(16)[] NOT NULL,
+                                          PRIMARY KEY (user_id, type)
+);
+
+CREATE TABLE notification_webhooks (
+                                       user_id INTEGER PRIMARY KEY REFERENCES users(id),
+                                       url TEXT NOT NULL,
+                                       secret VARCHAR(64) NOT NULL,
+                                       created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+DROP TABLE notification_webhooks;
+DROP TABLE notification_preferences;
+DROP INDEX idx_notifications_unread;
+DROP INDEX idx_notifications_user_id_created_at;
+DROP TABLE notifications;
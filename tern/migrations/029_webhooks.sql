@@ -0,0 +1,54 @@
+CREATE TYPE webhook_delivery_status AS ENUM ('pending', 'succeeded', 'failed');
+
+CREATE TABLE webhook_subscriptions (
+                                       id SERIAL PRIMARY KEY,
+                                       url TEXT NOT NULL,
+                                       secret VARCHAR(64) NOT NULL,
+                                       event_types VARCHAR(64)[] NOT NULL DEFAULT '{}',
+                                       description VARCHAR(255),
+                                       is_active BOOLEAN NOT NULL DEFAULT TRUE,
+                                       created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                       created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                       updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE webhook_deliveries (
+                                    id SERIAL PRIMARY KEY,
+                                    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+                                    event_id UUID NOT NULL,
+                                    event_type VARCHAR(64) NOT NULL,
+                                    payload JSONB NOT NULL,
+                                    status webhook_delivery_status NOT NULL DEFAULT 'pending',
+                                    attempts INTEGER NOT NULL DEFAULT 0,
+                                    max_attempts INTEGER NOT NULL CHECK (max_attempts > 0),
+                                    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                    last_status_code INTEGER,
+                                    last_error TEXT,
+                                    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                    finished_at TIMESTAMP
+);
+CREATE INDEX idx_webhook_deliveries_pending_next_attempt_at ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_failed ON webhook_deliveries(created_at DESC) WHERE status = 'failed';
+
+CREATE TABLE webhook_delivery_attempts (
+                                           id SERIAL PRIMARY KEY,
+                                           delivery_id INTEGER NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+                                           attempt INTEGER NOT NULL,
+                                           status_code INTEGER,
+                                           error TEXT,
+                                           duration_ms INTEGER NOT NULL,
+                                           created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts(delivery_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_webhook_delivery_attempts_delivery_id;
+DROP TABLE webhook_delivery_attempts;
+DROP INDEX idx_webhook_deliveries_failed;
+DROP INDEX idx_webhook_deliveries_subscription_id;
+DROP INDEX idx_webhook_deliveries_pending_next_attempt_at;
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
+DROP TYPE webhook_delivery_status;
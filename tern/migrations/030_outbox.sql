@@ -0,0 +1,27 @@
+CREATE TABLE outbox_events (
+                               seq BIGSERIAL PRIMARY KEY,
+                               id UUID NOT NULL UNIQUE,
+                               aggregate_type VARCHAR(32) NOT NULL,
+                               aggregate_id INTEGER NOT NULL,
+                               type VARCHAR(64) NOT NULL,
+                               payload JSONB NOT NULL,
+                               occurred_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                               attempts INTEGER NOT NULL DEFAULT 0,
+                               next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                               delivered_sinks VARCHAR(32)[] NOT NULL DEFAULT '{}',
+                               last_error TEXT,
+                               processed_at TIMESTAMP
+);
+CREATE INDEX idx_outbox_events_pending_next_attempt_at ON outbox_events(next_attempt_at) WHERE processed_at IS NULL;
+CREATE INDEX idx_outbox_events_pending_aggregate ON outbox_events(aggregate_type, aggregate_id, seq) WHERE processed_at IS NULL;
+CREATE INDEX idx_outbox_events_processed_at ON outbox_events(processed_at) WHERE processed_at IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_webhook_deliveries_subscription_id_event_id ON webhook_deliveries(subscription_id, event_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_webhook_deliveries_subscription_id_event_id;
+DROP INDEX idx_outbox_events_processed_at;
+DROP INDEX idx_outbox_events_pending_aggregate;
+DROP INDEX idx_outbox_events_pending_next_attempt_at;
+DROP TABLE outbox_events;
@@ -0,0 +1,17 @@
+CREATE TABLE live_events (
+                             seq BIGSERIAL PRIMARY KEY,
+                             event_id UUID NOT NULL,
+                             type VARCHAR(64) NOT NULL,
+                             movie_id INTEGER NOT NULL,
+                             data JSONB NOT NULL,
+                             created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                             UNIQUE (event_id, type)
+);
+CREATE INDEX idx_live_events_movie_id_seq ON live_events(movie_id, seq);
+CREATE INDEX idx_live_events_created_at ON live_events(created_at);
+
+---- create above / drop below ----
+
+DROP INDEX idx_live_events_created_at;
+DROP INDEX idx_live_events_movie_id_seq;
+DROP TABLE live_events;
@@ -0,0 +1,11 @@
+CREATE TABLE export_files (
+                              key VARCHAR(255) PRIMARY KEY,
+                              user_id INTEGER REFERENCES users(id),
+                              expires_at TIMESTAMP NOT NULL
+);
+CREATE INDEX idx_export_files_user_id ON export_files(user_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_export_files_user_id;
+DROP TABLE export_files;
@@ -0,0 +1,5 @@
+ALTER TABLE live_events ADD COLUMN user_id INTEGER;
+
+---- create above / drop below ----
+
+ALTER TABLE live_events DROP COLUMN user_id;
@@ -0,0 +1,8 @@
+ALTER TABLE outbox_events ADD COLUMN failed_at TIMESTAMP;
+
+CREATE INDEX idx_outbox_events_failed_at ON outbox_events(failed_at) WHERE failed_at IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX idx_outbox_events_failed_at;
+ALTER TABLE outbox_events DROP COLUMN failed_at;
@@ -0,0 +1,8 @@
+ALTER TABLE jobs ADD COLUMN periodic BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX idx_jobs_periodic_type ON jobs(type) WHERE periodic;
+
+---- create above / drop below ----
+
+DROP INDEX idx_jobs_periodic_type;
+ALTER TABLE jobs DROP COLUMN periodic;
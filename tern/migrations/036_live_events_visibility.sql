@@ -0,0 +1,5 @@
+ALTER TABLE live_events ADD COLUMN visibility visibility;
+
+---- create above / drop below ----
+
+ALTER TABLE live_events DROP COLUMN visibility;